@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/moorara/flagit"
 
@@ -31,6 +33,9 @@ func main() {
 	// Update logger verbosity
 	if s.General.Verbose {
 		logger.ChangeVerbosity(log.Debug)
+	} else if s.General.ReleaseNotes != "" || s.General.NextVersion {
+		// Only errors are logged, so the release notes or the next version can be piped to other commands
+		logger.ChangeVerbosity(log.Fatal)
 	} else if !s.General.Print {
 		logger.ChangeVerbosity(log.Info)
 	}
@@ -48,6 +53,14 @@ func main() {
 	case s.Version:
 		fmt.Println(version.String())
 
+	case s.General.ReleaseNotes != "":
+		notes, err := generate.ReleaseNotes(s, logger)
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		fmt.Println(notes)
+
 	default:
 		// Retrieve git repo informatin
 
@@ -62,15 +75,91 @@ func main() {
 		}
 		s = s.WithRepo(domain, path)
 
+		ctx := context.Background()
+
+		if s.General.NextVersion {
+			// The future tag is always resolved to the next version, so a literal one is not checked against the release lines
+			s.Tags.Future = spec.FutureTagNext
+		}
+
+		// Every release line or component has its own changelog file
+		specs, err := s.ChangelogSpecs()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		// The repository is created once, so the data retrieved from it is shared by all changelogs
 		g, err := generate.New(s, logger)
 		if err != nil {
 			logger.Fatal(err)
 		}
 
-		ctx := context.Background()
+		// The issues and merges are retrieved once for all release lines or components
+		if len(specs) > 1 {
+			if err := g.Prefetch(ctx, specs); err != nil {
+				logger.Fatal(err)
+			}
+		}
 
-		if _, err := g.Generate(ctx, s); err != nil {
-			logger.Fatal(err)
+		if s.General.NextVersion {
+			var versions int
+
+			// With several release lines or components, every next version is printed along with the name of its line or component
+			names := s.ChangelogNames()
+
+			for i, vs := range specs {
+				gs, err := g.WithSpec(vs)
+				if err != nil {
+					logger.Fatal(err)
+				}
+
+				next, err := gs.NextVersion(ctx, vs)
+				if err != nil {
+					// A release line or component without any unreleased change does not have a next version
+					var e *generate.NoChangesError
+					if len(specs) == 1 || !errors.As(err, &e) {
+						logger.Fatal(err)
+					}
+					continue
+				}
+
+				if len(specs) > 1 {
+					fmt.Printf("%s %s\n", names[i], next)
+				} else {
+					fmt.Println(next)
+				}
+				versions++
+			}
+
+			if versions == 0 {
+				logger.Fatal("no unreleased changes for any release line or component")
+			}
+
+			return
+		}
+
+		// In check mode, all changelog files are checked before exiting, so the changes for every one of them are printed
+		var outOfDate []string
+
+		for _, cs := range specs {
+			gs, err := g.WithSpec(cs)
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			if _, err := gs.Generate(ctx, cs); err != nil {
+				var e *generate.OutOfDateError
+				if !errors.As(err, &e) {
+					logger.Fatal(err)
+				}
+
+				logger.Error(err)
+				outOfDate = append(outOfDate, e.File)
+			}
+		}
+
+		if len(outOfDate) > 0 {
+			logger.Fatalf("%d changelog file(s) out of date: %s", len(outOfDate), strings.Join(outOfDate, ", "))
 		}
 	}
 }
@@ -4,15 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/moorara/changelog/internal/changelog"
+	"github.com/moorara/changelog/internal/changelog/json"
+	"github.com/moorara/changelog/internal/changelog/keepachangelog"
 	"github.com/moorara/changelog/internal/changelog/markdown"
+	"github.com/moorara/changelog/internal/changelog/yaml"
+	"github.com/moorara/changelog/internal/diff"
 	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/internal/remote/azuredevops"
+	"github.com/moorara/changelog/internal/remote/bitbucket"
+	"github.com/moorara/changelog/internal/remote/bitbucketserver"
+	"github.com/moorara/changelog/internal/remote/gitea"
 	"github.com/moorara/changelog/internal/remote/github"
 	"github.com/moorara/changelog/internal/remote/gitlab"
+	"github.com/moorara/changelog/internal/remote/hybrid"
+	"github.com/moorara/changelog/internal/remote/local"
+	"github.com/moorara/changelog/internal/remote/memo"
+	"github.com/moorara/changelog/internal/semver"
 	"github.com/moorara/changelog/log"
 	"github.com/moorara/changelog/spec"
 )
@@ -24,12 +39,51 @@ type Generator struct {
 	processor  changelog.Processor
 }
 
+// OutOfDateError is returned in check mode if a changelog file is out of date.
+type OutOfDateError struct {
+	File string
+}
+
+func (e *OutOfDateError) Error() string {
+	return fmt.Sprintf("%s is out of date", e.File)
+}
+
+// NoChangesError is returned for the next version if there is no unreleased change after the latest tag.
+type NoChangesError struct {
+	Latest string
+}
+
+func (e *NoChangesError) Error() string {
+	return fmt.Sprintf("no unreleased changes after %s", e.Latest)
+}
+
 // New creates a new changelog generator.
 func New(s spec.Spec, logger log.Logger) (*Generator, error) {
 	if logger == nil {
 		logger = log.New(log.None)
 	}
 
+	if _, err := s.Commits.Types(); err != nil {
+		return nil, err
+	}
+
+	// The on-disk cache for remote API data is shared between runs
+	var cache *remote.Cache
+	if !s.General.NoCache {
+		dir := s.General.CacheDir
+		if dir == "" {
+			var err error
+			if dir, err = remote.DefaultCacheDir(); err != nil {
+				logger.Warnf("On-disk cache is disabled: %s", err)
+			}
+		}
+
+		if dir != "" {
+			cache = remote.NewCache(dir, s.Repo.Domain, s.Repo.Path)
+			logger.Debugf("Using on-disk cache: %s", cache.Dir())
+		}
+	}
+
 	var remoteRepo remote.Repo
 	switch s.Repo.Platform {
 	case spec.PlatformGitHub:
@@ -37,19 +91,184 @@ func New(s spec.Spec, logger log.Logger) (*Generator, error) {
 		if len(parts) != 2 {
 			return nil, errors.New("unexpected GitHub repository: cannot parse owner and repo")
 		}
-		remoteRepo = github.NewRepo(logger, parts[0], parts[1], s.Repo.AccessToken)
+		var err error
+		if s.Repo.GitHubAPI == spec.APIGraphQL {
+			remoteRepo, err = github.NewGraphQLRepo(logger, cache, s.Repo.APIURL, s.Repo.WebURL, parts[0], parts[1], s.Repo.AccessToken)
+		} else {
+			remoteRepo, err = github.NewRepo(logger, cache, s.Repo.APIURL, s.Repo.WebURL, parts[0], parts[1], s.Repo.AccessToken)
+		}
+		if err != nil {
+			return nil, err
+		}
 
 	case spec.PlatformGitLab:
-		remoteRepo = gitlab.NewRepo(logger, s.Repo.Path, s.Repo.AccessToken)
+		remoteRepo = gitlab.NewRepo(logger, cache, s.Repo.APIURL, s.Repo.WebURL, s.Repo.Path, s.Repo.AccessToken)
+
+	case spec.PlatformGitea:
+		parts := strings.Split(s.Repo.Path, "/")
+		if len(parts) != 2 {
+			return nil, errors.New("unexpected Gitea repository: cannot parse owner and repo")
+		}
+		remoteRepo = gitea.NewRepo(logger, cache, s.Repo.APIURL, s.Repo.WebURL, parts[0], parts[1], s.Repo.AccessToken)
+
+	case spec.PlatformBitbucket:
+		parts := strings.Split(s.Repo.Path, "/")
+		if len(parts) != 2 {
+			return nil, errors.New("unexpected Bitbucket repository: cannot parse workspace and repo")
+		}
+		remoteRepo = bitbucket.NewRepo(logger, cache, s.Repo.APIURL, s.Repo.WebURL, parts[0], parts[1], s.Repo.AccessToken)
+
+	case spec.PlatformBitbucketServer:
+		// HTTPS clone URLs have an extra scm/ prefix (i.e. https://bitbucket.example.com/scm/octo/hello-world.git),
+		// so the project key and repo slug are always the last two parts of the path.
+		parts := strings.Split(s.Repo.Path, "/")
+		if len(parts) < 2 {
+			return nil, errors.New("unexpected Bitbucket Server repository: cannot parse project and repo")
+		}
+		remoteRepo = bitbucketserver.NewRepo(logger, cache, s.Repo.APIURL, s.Repo.WebURL, parts[len(parts)-2], parts[len(parts)-1], s.Repo.AccessToken)
+
+	case spec.PlatformAzureDevOps:
+		org, project, repo, err := parseAzureDevOpsPath(s.Repo.Path)
+		if err != nil {
+			return nil, err
+		}
+		remoteRepo = azuredevops.NewRepo(logger, cache, s.Repo.APIURL, s.Repo.WebURL, org, project, repo, s.Repo.AccessToken)
+
+	default:
+		return nil, fmt.Errorf("unsupported platform %q for domain %q", s.Repo.Platform, s.Repo.Domain)
+	}
+
+	switch s.Repo.Mode {
+	case spec.ModeLocal:
+		// In local mode, the remote repository is only used for generating the web links
+		localRepo, err := local.NewRepo(logger, ".", remoteRepo)
+		if err != nil {
+			return nil, err
+		}
+		remoteRepo = localRepo
+
+	case spec.ModeHybrid:
+		// In hybrid mode, the remote repository is only used for issues, merges, and the web links
+		localRepo, err := local.NewRepo(logger, ".", remoteRepo)
+		if err != nil {
+			return nil, err
+		}
+		remoteRepo = hybrid.NewRepo(logger, localRepo, remoteRepo)
+	}
+
+	// The data retrieved from the repository is shared by all changelogs generated in one run
+	remoteRepo = memo.NewRepo(remoteRepo)
+
+	processor, err := newProcessor(s, logger, s.General.File)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Generator{
 		logger:     logger,
 		remoteRepo: remoteRepo,
-		processor:  markdown.NewProcessor(logger, s.General.Base, s.General.File),
+		processor:  processor,
+	}, nil
+}
+
+// WithSpec returns a new changelog generator for another changelog of the same repository (i.e. a component or a release line).
+// The new generator shares the same repository, so the data already retrieved is not retrieved again.
+func (g *Generator) WithSpec(s spec.Spec) (*Generator, error) {
+	if _, err := s.Commits.Types(); err != nil {
+		return nil, err
+	}
+
+	processor, err := newProcessor(s, g.logger, s.General.File)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Generator{
+		logger:     g.logger,
+		remoteRepo: g.remoteRepo,
+		processor:  processor,
 	}, nil
 }
 
+// newProcessor creates a new changelog processor for the format of the changelog.
+func newProcessor(s spec.Spec, logger log.Logger, changelogFile string) (changelog.Processor, error) {
+	switch format := resolveFormat(s.General); format {
+	case spec.FormatMarkdown:
+		templates := markdown.Templates{
+			Header:  s.Content.HeaderTemplate,
+			Release: s.Content.ReleaseTemplate,
+		}
+		return markdown.NewProcessor(logger, s.General.Base, changelogFile, templates)
+	case spec.FormatKeepAChangelog:
+		return keepachangelog.NewProcessor(logger, s.General.Base, changelogFile), nil
+	case spec.FormatJSON:
+		return json.NewProcessor(logger, s.General.Base, changelogFile), nil
+	case spec.FormatYAML:
+		return yaml.NewProcessor(logger, s.General.Base, changelogFile), nil
+	default:
+		return nil, fmt.Errorf("unsupported changelog format %q", format)
+	}
+}
+
+// ReleaseNotes returns the release notes of an existing release on the changelog for the s.General.ReleaseNotes tag.
+// For components or release lines, the changelog of the component or the release line the tag belongs to is used.
+// The changelog is only parsed and not generated again, so no API call is made.
+func ReleaseNotes(s spec.Spec, logger log.Logger) (string, error) {
+	if logger == nil {
+		logger = log.New(log.None)
+	}
+
+	if f := s.General.ReleaseNotesFormat; f != spec.NotesFormatMarkdown && f != spec.NotesFormatText {
+		return "", fmt.Errorf("unsupported release notes format %q", f)
+	}
+
+	specs, err := s.ChangelogSpecs()
+	if err != nil {
+		return "", err
+	}
+
+	if s, err = selectSpec(specs, s.General.ReleaseNotes); err != nil {
+		return "", err
+	}
+
+	processor, err := newProcessor(s, logger, s.General.File)
+	if err != nil {
+		return "", err
+	}
+
+	chlog, err := processor.Parse(parseOptions(s))
+	if err != nil {
+		return "", err
+	}
+
+	name := releaseName(s.Tags, s.General.ReleaseNotes)
+
+	var tagNames []string
+	for _, r := range chlog.Existing {
+		if r.TagName != name {
+			tagNames = append(tagNames, r.TagName)
+			continue
+		}
+
+		notes := r.Notes
+
+		// JSON and YAML changelogs do not have release notes, so they are rendered in the default Markdown format
+		if format := resolveFormat(s.General); format == spec.FormatJSON || format == spec.FormatYAML {
+			if notes, err = markdown.Notes(r); err != nil {
+				return "", err
+			}
+		}
+
+		if s.General.ReleaseNotesFormat == spec.NotesFormatText {
+			notes = toText(notes)
+		}
+
+		return notes, nil
+	}
+
+	return "", fmt.Errorf("release-notes can be one of %s", tagNames)
+}
+
 // resolveTags determines the new tags that should be added to the changelog.
 // sortedTags are expected to be sorted from the most recent to the least recent.
 // Similarly, chlog.Existing are expected to be sorted from the most recent to the least recent.
@@ -64,7 +283,7 @@ func (g *Generator) resolveTags(s spec.Tags, sortedTags remote.Tags, chlog *chan
 	// Select those tags that are not in changelog
 	newTags, _ := sortedTags.Select(func(t remote.Tag) bool {
 		for _, release := range chlog.Existing {
-			if t.Name == release.TagName {
+			if t.Name == tagName(s, release.TagName) {
 				return false
 			}
 		}
@@ -107,12 +326,67 @@ func (g *Generator) resolveTags(s spec.Tags, sortedTags remote.Tags, chlog *chan
 	return newTags, nil
 }
 
+// resolveRegenerateTags determines the existing tags that should be generated again.
+// sortedTags are expected to be sorted from the most recent to the least recent.
+// The return value is the list of existing tags for regenerating changelog for them.
+func (g *Generator) resolveRegenerateTags(s spec.Tags, sortedTags remote.Tags, chlog *changelog.Changelog) (remote.Tags, error) {
+	if !s.RegenerateAll && s.RegenerateFrom == "" && s.RegenerateTo == "" {
+		return remote.Tags{}, nil
+	}
+
+	g.logger.Debug("Resolving existing tags for regenerating changelog ...")
+
+	mapFunc := func(t remote.Tag) string {
+		return t.Name
+	}
+
+	// Select those tags that are in changelog
+	existingTags, _ := sortedTags.Select(func(t remote.Tag) bool {
+		for _, release := range chlog.Existing {
+			if t.Name == tagName(s, release.TagName) {
+				return true
+			}
+		}
+		return false
+	})
+
+	// Resolve the from tag
+	if from := s.RegenerateFrom; from != "" {
+		i := existingTags.Index(from)
+		if i == -1 {
+			return nil, fmt.Errorf("regenerate-from can be one of %s", existingTags.Map(mapFunc))
+		}
+		// existing tags are also sorted from the most recent to the least recent
+		existingTags = existingTags[:i+1]
+	}
+
+	// Resolve the to tag
+	if to := s.RegenerateTo; to != "" {
+		i := existingTags.Index(to)
+		if i == -1 {
+			return nil, fmt.Errorf("regenerate-to can be one of %s", existingTags.Map(mapFunc))
+		}
+		// existing tags are also sorted from the most recent to the least recent
+		existingTags = existingTags[i:]
+	}
+
+	g.logger.Infof("Resolved existing tags for regenerating changelog: %s", existingTags.Map(mapFunc))
+
+	return existingTags, nil
+}
+
 // resolveCommitMap returns a map of commit hashes to revisions.
-// A revision includes a branch name and a list of tags.
-// The resulting map lets us to know what is the branch and all the tags than any given commit falls into.
+// A revision includes a branch name and the least recent tag that a commit is released in.
+// The resulting map lets us to know what is the branch and the tag that any given commit falls into.
 func (g *Generator) resolveCommitMap(ctx context.Context, branch remote.Branch, sortedTags remote.Tags) (commitMap, error) {
 	commitMap := commitMap{}
 
+	// ==============================> BUILD THE COMMIT GRAPH <==============================
+
+	// graph is a map of commit hashes to commits shared by the branch and all tags.
+	// Every set of parent commits includes all ancestors, so the graph always has all ancestors of a commit in it.
+	graph := map[string]remote.Commit{}
+
 	// Resolve which commits are in the branch
 	branchCommits, err := g.remoteRepo.FetchParentCommits(ctx, branch.Commit.Hash)
 	if err != nil {
@@ -120,60 +394,122 @@ func (g *Generator) resolveCommitMap(ctx context.Context, branch remote.Branch,
 	}
 
 	for _, c := range branchCommits {
-		if rev, ok := commitMap[c.Hash]; ok {
-			rev.Branch = branch.Name
-		} else {
-			commitMap[c.Hash] = &revisions{
-				Branch: branch.Name,
-			}
+		graph[c.Hash] = c
+		commitMap[c.Hash] = &revisions{
+			Commit: c,
+			Branch: branch.Name,
 		}
 	}
 
-	// Resolve which commits are in the each tag
-	// sortedTags are sorted from the most recent to the least recent
+	// Only the ancestors of tags not already in the graph (i.e. tags not on the branch) need to be fetched
 	for _, tag := range sortedTags {
 		// The first tag can be a future tag without a commit
-		if !tag.Commit.IsZero() {
-			tagCommits, err := g.remoteRepo.FetchParentCommits(ctx, tag.Commit.Hash)
-			if err != nil {
-				return nil, err
+		if tag.Commit.IsZero() {
+			continue
+		}
+
+		if _, ok := graph[tag.Commit.Hash]; ok {
+			continue
+		}
+
+		tagCommits, err := g.remoteRepo.FetchParentCommits(ctx, tag.Commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range tagCommits {
+			graph[c.Hash] = c
+		}
+	}
+
+	// ==============================> RESOLVE TAGS FOR COMMITS <==============================
+
+	// sortedTags are sorted from the most recent to the least recent
+	// Walking the graph from the least recent tag to the most recent tag, every commit is visited only once.
+	// A commit already assigned to a less recent tag does not need to be visited again, since all of its ancestors are assigned too.
+	for i := len(sortedTags) - 1; i >= 0; i-- {
+		tag := sortedTags[i]
+		if tag.Commit.IsZero() {
+			continue
+		}
+
+		for stack := []string{tag.Commit.Hash}; len(stack) > 0; {
+			hash := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			rev, ok := commitMap[hash]
+			if ok && rev.Tag != "" {
+				continue
 			}
 
-			for _, c := range tagCommits {
-				if rev, ok := commitMap[c.Hash]; ok {
-					rev.Tags = append(rev.Tags, tag.Name)
-				} else {
-					commitMap[c.Hash] = &revisions{
-						Tags: []string{tag.Name},
-					}
-				}
+			c, ok := graph[hash]
+			if !ok {
+				continue
+			}
+
+			if rev == nil {
+				rev = &revisions{Commit: c}
+				commitMap[hash] = rev
 			}
+
+			rev.Tag = tag.Name
+			stack = append(stack, c.Parents...)
 		}
 	}
 
 	return commitMap, nil
 }
 
-func (g *Generator) resolveReleases(ctx context.Context, s spec.Spec, sortedTags remote.Tags, baseRev string, im issueMap, cm mergeMap) []changelog.Release {
+// filterByPaths selects the merges changing files in any of the paths (i.e. merges for a component of a monorepo).
+// The files changed by a merge are the files changed by its commit compared to the first parent.
+func (g *Generator) filterByPaths(ctx context.Context, paths []string, merges remote.Merges) (remote.Merges, error) {
+	if len(paths) == 0 {
+		return merges, nil
+	}
+
+	g.logger.Debugf("Filtering pull/merge requests by paths %s ...", paths)
+
+	selected := remote.Merges{}
+	for _, m := range merges {
+		// The files changed by a merge without a commit are not known
+		if m.Commit.IsZero() {
+			continue
+		}
+
+		files, err := g.remoteRepo.FetchCommitFiles(ctx, m.Commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if matchPaths(files, paths) {
+			selected = append(selected, m)
+		}
+	}
+
+	return selected, nil
+}
+
+func (g *Generator) resolveReleases(s spec.Spec, sortedTags remote.Tags, baseRev string, im issueMap, cm mergeMap, ccm conventionalMap) []changelog.Release {
 	releases := []changelog.Release{}
 
 	for i, tag := range sortedTags {
 		releaseURL := s.Content.GetReleaseURL(tag.Name)
 
 		var compareURL string
-		if j := i + 1; j < len(sortedTags) {
-			compareURL = g.remoteRepo.CompareURL(sortedTags[j].Name, tag.Name)
+		if prev, ok := previousTag(sortedTags, i); ok {
+			compareURL = g.remoteRepo.CompareURL(prev.Name, tag.Name)
 		} else {
 			compareURL = g.remoteRepo.CompareURL(baseRev, tag.Name)
 		}
 
 		// Every tag represents a new release
 		release := changelog.Release{
-			TagName:    tag.Name,
+			TagName:    releaseName(s.Tags, tag.Name),
 			TagURL:     tag.WebURL,
 			TagTime:    tag.Time,
 			ReleaseURL: releaseURL,
 			CompareURL: compareURL,
+			Unreleased: tag.Name == s.Tags.Future,
 		}
 
 		// Group issues for the current tag
@@ -272,6 +608,21 @@ func (g *Generator) resolveReleases(ctx context.Context, s spec.Spec, sortedTags
 			}
 		}
 
+		// Group conventional commits for the current tag
+		// Commits are filtered by their types, so every commit belongs to a label group
+		if commits, ok := ccm[tag.Name]; ok {
+			for _, group := range s.Commits.LabelGroups() {
+				f := func(c conventionalCommit) bool {
+					return c.Labels().Any(group.Labels...)
+				}
+
+				if selected, _ := commits.Select(f); len(selected) > 0 {
+					commitGroup := toCommitGroup(group.Title, selected, g.remoteRepo.CommitURL)
+					release.CommitGroups = append(release.CommitGroups, commitGroup)
+				}
+			}
+		}
+
 		releases = append(releases, release)
 	}
 
@@ -279,66 +630,223 @@ func (g *Generator) resolveReleases(ctx context.Context, s spec.Spec, sortedTags
 }
 
 // Generate generates changelogs for a Git repository.
+// In check mode, the changelog file is not written and an OutOfDateError is returned if it is out of date.
 func (g *Generator) Generate(ctx context.Context, s spec.Spec) (string, error) {
-	// Parse the existing changelog if any
-	chlog, err := g.processor.Parse(changelog.ParseOptions{})
-	if err != nil {
-		return "", err
+	if s.General.Check {
+		return "", g.check(ctx, s)
 	}
 
+	return g.generate(ctx, s, g.processor)
+}
+
+// NextVersion returns the next version suggested by the unreleased changes after the last git tag.
+// The changelog is not written, but the same API calls are made as generating the changelog.
+func (g *Generator) NextVersion(ctx context.Context, s spec.Spec) (string, error) {
+	s.General.NextVersion = true
+	s.Tags.Future = spec.FutureTagNext
+
+	return g.generate(ctx, s, g.processor)
+}
+
+// Prefetch retrieves the closed issues and merged pull/merge requests for the changelogs of several specs at once (i.e. components or release lines).
+// They are retrieved since the earliest time any of the changelogs needs them,
+// so generating the changelogs with this generator or the ones returned by WithSpec does not retrieve them again.
+// The changelogs that are up-to-date do not need any issue or merge.
+func (g *Generator) Prefetch(ctx context.Context, specs []spec.Spec) error {
 	if err := g.remoteRepo.CheckPermissions(ctx); err != nil {
-		return "", err
+		return err
 	}
 
-	// ==============================> FETCH RELEASE BRANCH <==============================
+	var earliest *time.Time
 
-	var branch remote.Branch
+	for _, s := range specs {
+		processor, err := newProcessor(s, g.logger, s.General.File)
+		if err != nil {
+			return err
+		}
 
-	if s.Merges.Branch == "" {
-		branch, err = g.remoteRepo.FetchDefaultBranch(ctx)
-	} else {
-		branch, err = g.remoteRepo.FetchBranch(ctx, s.Merges.Branch)
+		chlog, err := processor.Parse(parseOptions(s))
+		if err != nil {
+			return err
+		}
+
+		sortedTags, allTags, newTags, regenerateTags, err := g.resolveChangelogTags(ctx, s, chlog)
+		if err != nil {
+			return err
+		}
+
+		if len(newTags) == 0 && len(regenerateTags) == 0 {
+			continue
+		}
+
+		if since := resolveSince(s.Tags, chlog, sortedTags, allTags, regenerateTags); earliest == nil || since.Before(*earliest) {
+			earliest = &since
+		}
+	}
+
+	if earliest == nil {
+		return nil
+	}
+
+	_, _, err := g.remoteRepo.FetchIssuesAndMerges(ctx, *earliest)
+
+	return err
+}
+
+// check generates the changelog into a temporary copy of the changelog file and compares it against the changelog file.
+// The changes are printed as a unified diff if the changelog file is out of date.
+func (g *Generator) check(ctx context.Context, s spec.Spec) error {
+	dir, err := ioutil.TempDir("", "changelog-check-")
+	if err != nil {
+		return err
 	}
+	defer os.RemoveAll(dir)
 
+	checkFile := filepath.Join(dir, filepath.Base(s.General.File))
+
+	current, err := ioutil.ReadFile(s.General.File)
+	if err == nil {
+		if err := ioutil.WriteFile(checkFile, current, 0644); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	processor, err := newProcessor(s, g.logger, checkFile)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	// ==============================> FETCH AND FILTER TAGS <==============================
+	if _, err := g.generate(ctx, s, processor); err != nil {
+		return err
+	}
+
+	// The copy is not written if the changelog is up-to-date
+	expected, err := ioutil.ReadFile(checkFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if d := diff.Unified(s.General.File, s.General.File, string(current), string(expected)); d != "" {
+		fmt.Print(d)
+		return &OutOfDateError{File: s.General.File}
+	}
 
+	g.logger.Infof("%s is up-to-date", s.General.File)
+
+	return nil
+}
+
+// resolveChangelogTags fetches the tags and determines the tags of a changelog.
+// All return values are sorted from the most recent to the least recent.
+// The first return value is the list of tags included in the changelog and the second one also has the tags not included (i.e. tags on other release lines).
+// The last two return values are the lists of new tags and existing tags for generating and regenerating changelog for them.
+func (g *Generator) resolveChangelogTags(ctx context.Context, s spec.Spec, chlog *changelog.Changelog) (remote.Tags, remote.Tags, remote.Tags, remote.Tags, error) {
 	tags, err := g.remoteRepo.FetchTags(ctx)
 	if err != nil {
-		return "", err
+		return nil, nil, nil, nil, err
 	}
 
 	g.logger.Info("Sorting and filtering git tags ...")
 
-	sortedTags := tags.Sort()
+	sortedTags := sortTags(tags, s.Tags.Ordering)
+
+	// The tags without the prefix are ignored entirely (i.e. tags of other components in a monorepo)
+	if s.Tags.Prefix != "" {
+		sortedTags, _ = sortedTags.Select(func(t remote.Tag) bool {
+			return strings.HasPrefix(t.Name, s.Tags.Prefix)
+		})
+	}
+
 	sortedTags = sortedTags.Exclude(s.Tags.Exclude...)
 
 	if s.Tags.ExcludeRegex != "" {
 		re, err := regexp.CompilePOSIX(s.Tags.ExcludeRegex)
 		if err != nil {
-			return "", err
+			return nil, nil, nil, nil, err
 		}
 		sortedTags = sortedTags.ExcludeRegex(re)
 	}
 
+	// The tags not included still delimit changes, so their changes are not misassigned to the included tags (i.e. tags on other release lines)
+	allTags := sortedTags
+	if s.Tags.IncludeRegex != "" {
+		re, err := regexp.CompilePOSIX(s.Tags.IncludeRegex)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		sortedTags, _ = sortedTags.Select(func(t remote.Tag) bool {
+			return re.MatchString(t.Name)
+		})
+	}
+
 	newTags, err := g.resolveTags(s.Tags, sortedTags, chlog)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	regenerateTags, err := g.resolveRegenerateTags(s.Tags, sortedTags, chlog)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return sortedTags, allTags, newTags, regenerateTags, nil
+}
+
+// generate generates the changelog using a given processor for reading and writing the changelog file.
+func (g *Generator) generate(ctx context.Context, s spec.Spec, processor changelog.Processor) (string, error) {
+	// Parse the existing changelog if any
+	chlog, err := processor.Parse(parseOptions(s))
+	if err != nil {
+		return "", err
+	}
+
+	if err := g.remoteRepo.CheckPermissions(ctx); err != nil {
+		return "", err
+	}
+
+	// ==============================> FETCH RELEASE BRANCH <==============================
+
+	var branch remote.Branch
+
+	if s.Merges.Branch == "" {
+		branch, err = g.remoteRepo.FetchDefaultBranch(ctx)
+	} else {
+		branch, err = g.remoteRepo.FetchBranch(ctx, s.Merges.Branch)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	// ==============================> FETCH AND FILTER TAGS <==============================
+
+	sortedTags, allTags, newTags, regenerateTags, err := g.resolveChangelogTags(ctx, s, chlog)
 	if err != nil {
 		return "", err
 	}
 
-	if len(newTags) == 0 {
+	if len(newTags) == 0 && len(regenerateTags) == 0 {
 		g.logger.Info("Changelog is up-to-date (no new tag or a future tag)")
 		return "", nil
 	}
 
 	// ==============================> RESOLVE GIT REVISION FOR COMPARISON <==============================
 
+	// The least recent included tag is compared against the previous tag not included (i.e. the tag a release line is branched off from)
+	previousOtherTag := func(name string) (remote.Tag, bool) {
+		if i := allTags.Index(name); s.Tags.IncludeRegex != "" && i >= 0 {
+			return previousTag(allTags, i)
+		}
+		return remote.Tag{}, false
+	}
+
 	var baseRev string
 	if len(chlog.Existing) > 0 {
-		baseRev = chlog.Existing[0].TagName
+		baseRev = tagName(s.Tags, chlog.Existing[0].TagName)
+	} else if prev, ok := previousOtherTag(newTags[len(newTags)-1].Name); ok {
+		baseRev = prev.Name
 	} else {
 		firstCommit, err := g.remoteRepo.FetchFirstCommit(ctx)
 		if err != nil {
@@ -347,11 +855,29 @@ func (g *Generator) Generate(ctx context.Context, s spec.Spec) (string, error) {
 		baseRev = firstCommit.Hash
 	}
 
+	// The least recent regenerated tag is compared against its previous git tag
+	var regenerateBaseRev string
+	if len(regenerateTags) > 0 {
+		last := regenerateTags[len(regenerateTags)-1].Name
+		if prev, ok := previousTag(sortedTags, sortedTags.Index(last)); ok {
+			regenerateBaseRev = prev.Name
+		} else if prev, ok := previousOtherTag(last); ok {
+			regenerateBaseRev = prev.Name
+		} else {
+			// Regenerated tags are always on changelog, so the first commit is never fetched twice
+			firstCommit, err := g.remoteRepo.FetchFirstCommit(ctx)
+			if err != nil {
+				return "", err
+			}
+			regenerateBaseRev = firstCommit.Hash
+		}
+	}
+
 	// ==============================> FETCH COMMITS FOR BRANCH AND TAGS <==============================
 
 	// Construct a map of commit hashes to branch and tags names
 	// We need to resolve the commit map with all sorted tags, so commits will not be misassigned to new tags
-	commitMap, err := g.resolveCommitMap(ctx, branch, sortedTags)
+	commitMap, err := g.resolveCommitMap(ctx, branch, allTags)
 	if err != nil {
 		return "", err
 	}
@@ -359,31 +885,77 @@ func (g *Generator) Generate(ctx context.Context, s spec.Spec) (string, error) {
 	// ==============================> FETCH & ORGANIZE ISSUES AND MERGES <==============================
 
 	// Fetch issues and merges since the last tag on changelog
-	var since time.Time
-	if len(chlog.Existing) > 0 {
-		since = chlog.Existing[0].TagTime
-	}
-
+	since := resolveSince(s.Tags, chlog, sortedTags, allTags, regenerateTags)
 	issues, merges, err := g.remoteRepo.FetchIssuesAndMerges(ctx, since)
 	if err != nil {
 		return "", err
 	}
 
 	sortedIssues, sortedMerges := filterByLabels(s, issues, merges)
+
+	if sortedMerges, err = g.filterByPaths(ctx, s.Merges.Paths, sortedMerges); err != nil {
+		return "", err
+	}
+
 	g.logger.Infof("Filtered issues (%d) and pull/merge requests (%d)", len(sortedIssues), len(sortedMerges))
 
 	// We need to resolve the issue map with all sorted tags, so issues will not be misassigned to new tags
-	possibleFutureTag := newTags[0]
-	issueMap := resolveIssueMap(sortedIssues, sortedTags, possibleFutureTag)
+	var possibleFutureTag remote.Tag
+	if len(newTags) > 0 {
+		possibleFutureTag = newTags[0]
+	}
+
+	issueMap := resolveIssueMap(sortedIssues, allTags, possibleFutureTag)
 	mergeMap := resolveMergeMap(sortedMerges, commitMap, possibleFutureTag)
 	g.logger.Info("Partitioned issues and pull/merge requests by tag")
 
-	chlog.New = g.resolveReleases(ctx, s, newTags, baseRev, issueMap, mergeMap)
-	g.logger.Info("Grouped issues and pull/merge requests")
+	// ==============================> ORGANIZE CONVENTIONAL COMMITS <==============================
+
+	// The messages of all commits are already fetched for the commit map
+	sortedCommits := filterCommits(s, resolveConventionalCommits(commitMap))
+	g.logger.Infof("Filtered conventional commits (%d)", len(sortedCommits))
+
+	conventionalMap := resolveConventionalMap(sortedCommits, commitMap, possibleFutureTag)
+	g.logger.Info("Partitioned conventional commits by tag")
+
+	// ==============================> RESOLVE THE NEXT VERSION <==============================
+
+	if s.Tags.Future == spec.FutureTagNext {
+		bump := resolveBump(s, issueMap[spec.FutureTagNext], mergeMap[spec.FutureTagNext], conventionalMap[spec.FutureTagNext])
+		latest, next := resolveNextVersion(s.Tags.Prefix, sortedTags, commitMap, bump)
+
+		if s.General.NextVersion {
+			if bump == semver.BumpNone {
+				return "", &NoChangesError{Latest: latest}
+			}
+			return next, nil
+		}
+
+		if bump == semver.BumpNone {
+			g.logger.Infof("No unreleased changes after %s for the next version", latest)
+			if newTags = newTags[1:]; len(newTags) == 0 && len(regenerateTags) == 0 {
+				g.logger.Info("Changelog is up-to-date (no new tag or unreleased change)")
+				return "", nil
+			}
+		} else {
+			g.logger.Infof("Resolved the next version for unreleased changes: %s (%s)", next, bump)
+
+			// The unreleased changes are assigned to the next version
+			newTags[0] = g.remoteRepo.FutureTag(next)
+			issueMap[next] = issueMap[spec.FutureTagNext]
+			mergeMap[next] = mergeMap[spec.FutureTagNext]
+			conventionalMap[next] = conventionalMap[spec.FutureTagNext]
+			s.Tags.Future = next
+		}
+	}
+
+	chlog.New = g.resolveReleases(s, newTags, baseRev, issueMap, mergeMap, conventionalMap)
+	chlog.Regenerated = g.resolveReleases(s, regenerateTags, regenerateBaseRev, issueMap, mergeMap, conventionalMap)
+	g.logger.Info("Grouped issues, pull/merge requests, and conventional commits")
 
 	// ==============================> UPDATE THE CHANGELOG <==============================
 
-	content, err := g.processor.Render(chlog)
+	content, err := processor.Render(chlog)
 	if err != nil {
 		return "", err
 	}
@@ -3,6 +3,9 @@ package generate
 import (
 	"context"
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -10,6 +13,8 @@ import (
 
 	"github.com/moorara/changelog/internal/changelog"
 	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/internal/remote/memo"
+	"github.com/moorara/changelog/internal/remote/remotetest"
 	"github.com/moorara/changelog/log"
 	"github.com/moorara/changelog/spec"
 )
@@ -40,18 +45,21 @@ var (
 	}
 
 	commit2 = remote.Commit{
-		Hash: "0251a422d2038967eeaaaa5c8aa76c7067fdef05",
-		Time: t2,
+		Hash:    "0251a422d2038967eeaaaa5c8aa76c7067fdef05",
+		Time:    t2,
+		Parents: []string{"25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378"},
 	}
 
 	commit3 = remote.Commit{
-		Hash: "c414d1004154c6c324bd78c69d10ee101e676059",
-		Time: t3,
+		Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+		Time:    t3,
+		Parents: []string{"0251a422d2038967eeaaaa5c8aa76c7067fdef05"},
 	}
 
 	commit4 = remote.Commit{
-		Hash: "20c5414eccaa147f2d6644de4ca36f35293fa43e",
-		Time: t4,
+		Hash:    "20c5414eccaa147f2d6644de4ca36f35293fa43e",
+		Time:    t4,
+		Parents: []string{"c414d1004154c6c324bd78c69d10ee101e676059"},
 	}
 
 	branch = remote.Branch{
@@ -134,10 +142,27 @@ var (
 		Commit: commit4,
 	}
 
+	conventionalCommit1 = conventionalCommit{
+		Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+		Time:    t3,
+		Type:    "feat",
+		Scope:   "parser",
+		Subject: "support multi-line values",
+	}
+
+	conventionalCommit2 = conventionalCommit{
+		Hash:     "20c5414eccaa147f2d6644de4ca36f35293fa43e",
+		Time:     t4, // Unrleased change for future tag
+		Type:     "fix",
+		Subject:  "reject empty values",
+		Breaking: true,
+	}
+
 	changelogIssue1 = changelog.Issue{
 		Number: 1001,
 		Title:  "Found a bug",
 		URL:    "https://github.com/octocat/Hello-World/issues/1001",
+		Labels: []string{"bug"},
 		OpenedBy: changelog.User{
 			Name:     "The Octocat",
 			Username: "octocat",
@@ -154,6 +179,7 @@ var (
 		Number: 1002,
 		Title:  "Discovered a vulnerability",
 		URL:    "https://github.com/octocat/Hello-World/issues/1002",
+		Labels: []string{"invalid"},
 		OpenedBy: changelog.User{
 			Name:     "The Octocat",
 			Username: "octocat",
@@ -170,6 +196,7 @@ var (
 		Number: 1003,
 		Title:  "Added a feature",
 		URL:    "https://github.com/octocat/Hello-World/pull/1003",
+		Labels: []string{"enhancement"},
 		OpenedBy: changelog.User{
 			Name:     "The Octocat",
 			Username: "octocat",
@@ -197,6 +224,22 @@ var (
 			URL:      "https://github.com/octodog",
 		},
 	}
+
+	changelogCommit1 = changelog.Commit{
+		Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+		Type:    "feat",
+		Scope:   "parser",
+		Subject: "support multi-line values",
+		URL:     "https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059",
+	}
+
+	changelogCommit2 = changelog.Commit{
+		Hash:     "20c5414eccaa147f2d6644de4ca36f35293fa43e",
+		Type:     "fix",
+		Subject:  "reject empty values",
+		URL:      "https://github.com/octocat/Hello-World/commit/20c5414eccaa147f2d6644de4ca36f35293fa43e",
+		Breaking: true,
+	}
 )
 
 func parseGitHubTime(s string) time.Time {
@@ -215,6 +258,16 @@ func TestNew(t *testing.T) {
 		logger        log.Logger
 		expectedError string
 	}{
+		{
+			name: "InvalidCommitGroups",
+			s: spec.Spec{
+				Commits: spec.Commits{
+					Groups: []string{"feat"},
+				},
+			},
+			logger:        nil,
+			expectedError: `invalid commit group "feat": expected type:group with a group from summary|removed|breaking|deprecated|feature|enhancement|bug|security`,
+		},
 		{
 			name: "InvalidSpec",
 			s: spec.Spec{
@@ -226,12 +279,100 @@ func TestNew(t *testing.T) {
 			logger:        nil,
 			expectedError: "unexpected GitHub repository: cannot parse owner and repo",
 		},
+		{
+			name: "InvalidGiteaSpec",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitea,
+					Path:     "octocat/invalid/Hello-World",
+				},
+			},
+			logger:        nil,
+			expectedError: "unexpected Gitea repository: cannot parse owner and repo",
+		},
+		{
+			name: "InvalidBitbucketSpec",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformBitbucket,
+					Path:     "octocat/invalid/hello-world",
+				},
+			},
+			logger:        nil,
+			expectedError: "unexpected Bitbucket repository: cannot parse workspace and repo",
+		},
+		{
+			name: "InvalidBitbucketServerSpec",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformBitbucketServer,
+					Path:     "hello-world",
+				},
+			},
+			logger:        nil,
+			expectedError: "unexpected Bitbucket Server repository: cannot parse project and repo",
+		},
+		{
+			name: "InvalidAzureDevOpsSpec",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformAzureDevOps,
+					Path:     "octocat/hello-world",
+				},
+			},
+			logger:        nil,
+			expectedError: "unexpected Azure DevOps repository: cannot parse organization, project, and repo",
+		},
+		{
+			name: "UnsupportedPlatform",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.Platform(""),
+					Domain:   "git.example.com",
+					Path:     "octocat/Hello-World",
+				},
+			},
+			logger:        nil,
+			expectedError: `unsupported platform "" for domain "git.example.com"`,
+		},
 		{
 			name: "GitHub",
 			s: spec.Spec{
 				Repo: spec.Repo{
 					Platform: spec.PlatformGitHub,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "GitHubEnterprise",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Domain:   "github.example.com",
 					Path:     "octocat/Hello-World",
+					APIURL:   "https://github.example.com/api/v3",
+					WebURL:   "https://github.example.com",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "GitHubGraphQL",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform:  spec.PlatformGitHub,
+					GitHubAPI: spec.APIGraphQL,
+					Domain:    "github.example.com",
+					Path:      "octocat/Hello-World",
+					APIURL:    "https://github.example.com/api/v3",
+					WebURL:    "https://github.example.com",
 				},
 			},
 			logger:        log.New(log.None),
@@ -242,11 +383,208 @@ func TestNew(t *testing.T) {
 			s: spec.Spec{
 				Repo: spec.Repo{
 					Platform: spec.PlatformGitLab,
+					Domain:   "gitlab.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://gitlab.com/api/v4",
+					WebURL:   "https://gitlab.com",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "Gitea",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitea,
+					Domain:   "codeberg.org",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://codeberg.org/api/v1",
+					WebURL:   "https://codeberg.org",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "Bitbucket",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformBitbucket,
+					Domain:   "bitbucket.org",
+					Path:     "octocat/hello-world",
+					APIURL:   "https://api.bitbucket.org/2.0",
+					WebURL:   "https://bitbucket.org",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "BitbucketServer",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformBitbucketServer,
+					Domain:   "bitbucket.example.com",
+					Path:     "scm/octo/hello-world",
+					APIURL:   "https://bitbucket.example.com/rest/api/1.0",
+					WebURL:   "https://bitbucket.example.com",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "AzureDevOps",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformAzureDevOps,
+					Domain:   "dev.azure.com",
+					Path:     "octocat/hello-world/_git/hello-world",
+					APIURL:   "https://dev.azure.com",
+					WebURL:   "https://dev.azure.com",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "LocalMode",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Mode:     spec.ModeLocal,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "HybridMode",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Mode:     spec.ModeHybrid,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "CacheDir",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+				General: spec.General{
+					CacheDir: "/tmp/changelog",
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "NoCache",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+				General: spec.General{
+					NoCache: true,
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "JSONFormat",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+				General: spec.General{
+					File:    "CHANGELOG.json",
+					NoCache: true,
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "KeepAChangelogFormat",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+				General: spec.General{
+					File:    "CHANGELOG.md",
+					Format:  spec.FormatKeepAChangelog,
+					NoCache: true,
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: "",
+		},
+		{
+			name: "YAMLFormat",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+				General: spec.General{
+					File:    "CHANGELOG.yaml",
+					NoCache: true,
 				},
 			},
 			logger:        log.New(log.None),
 			expectedError: "",
 		},
+		{
+			name: "UnsupportedFormat",
+			s: spec.Spec{
+				Repo: spec.Repo{
+					Platform: spec.PlatformGitHub,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+				General: spec.General{
+					Format:  spec.Format("html"),
+					NoCache: true,
+				},
+			},
+			logger:        log.New(log.None),
+			expectedError: `unsupported changelog format "html"`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -266,6 +604,59 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestGenerator_WithSpec(t *testing.T) {
+	g := &Generator{
+		logger:     log.New(log.None),
+		remoteRepo: &remotetest.MockRemoteRepo{},
+		processor:  &MockChangelogProcessor{},
+	}
+
+	tests := []struct {
+		name          string
+		s             spec.Spec
+		expectedError string
+	}{
+		{
+			name: "InvalidCommitGroup",
+			s: spec.Spec{
+				General: spec.General{File: "CHANGELOG.md"},
+				Commits: spec.Commits{Groups: []string{"feat"}},
+			},
+			expectedError: `invalid commit group "feat": expected type:group with a group from summary|removed|breaking|deprecated|feature|enhancement|bug|security`,
+		},
+		{
+			name: "UnsupportedFormat",
+			s: spec.Spec{
+				General: spec.General{File: "CHANGELOG.md", Format: spec.Format("html")},
+			},
+			expectedError: `unsupported changelog format "html"`,
+		},
+		{
+			name: "Success",
+			s: spec.Spec{
+				General: spec.General{File: "api/CHANGELOG.md"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gs, err := g.WithSpec(tc.s)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, gs)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, g.logger, gs.logger)
+				assert.Equal(t, g.remoteRepo, gs.remoteRepo)
+				assert.NotNil(t, gs.processor)
+				assert.NotEqual(t, g.processor, gs.processor)
+			}
+		})
+	}
+}
+
 func TestGenerator_resolveTags(t *testing.T) {
 	futureTag1 := remote.Tag{
 		Name:   "v0.1.0",
@@ -291,6 +682,20 @@ func TestGenerator_resolveTags(t *testing.T) {
 		WebURL: "https://github.com/octocat/Hello-World/tree/v0.4.0",
 	}
 
+	apiTag1 := remote.Tag{
+		Name:   "api/v0.1.1",
+		Time:   tag1.Time,
+		Commit: tag1.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/api/v0.1.1",
+	}
+
+	apiTag2 := remote.Tag{
+		Name:   "api/v0.1.2",
+		Time:   tag2.Time,
+		Commit: tag2.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/api/v0.1.2",
+	}
+
 	tests := []struct {
 		name          string
 		g             *Generator
@@ -315,8 +720,8 @@ func TestGenerator_resolveTags(t *testing.T) {
 			name: "NoGitTag_NoChangelogTag_FutureTag",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FutureTagMocks: []FutureTagMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FutureTagMocks: []remotetest.FutureTagMock{
 						{OutTag: futureTag1},
 					},
 				},
@@ -344,8 +749,8 @@ func TestGenerator_resolveTags(t *testing.T) {
 			name: "GitTag_NoChangelogTag_FutureTag",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FutureTagMocks: []FutureTagMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FutureTagMocks: []remotetest.FutureTagMock{
 						{OutTag: futureTag2},
 					},
 				},
@@ -377,8 +782,8 @@ func TestGenerator_resolveTags(t *testing.T) {
 			name: "SameGitTag_SameChangelogTag_FutureTag",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FutureTagMocks: []FutureTagMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FutureTagMocks: []remotetest.FutureTagMock{
 						{OutTag: futureTag2},
 					},
 				},
@@ -410,12 +815,30 @@ func TestGenerator_resolveTags(t *testing.T) {
 			expectedTags:  remote.Tags{tag2},
 			expectedError: nil,
 		},
+		{
+			name: "NewGitTag_TrimmedChangelogTag_NoFutureTag",
+			g: &Generator{
+				logger: log.New(log.None),
+			},
+			s: spec.Tags{
+				Prefix:     "api/",
+				TrimPrefix: true,
+			},
+			sortedTags: remote.Tags{apiTag2, apiTag1},
+			chlog: &changelog.Changelog{
+				Existing: []changelog.Release{
+					{TagName: "v0.1.1"},
+				},
+			},
+			expectedTags:  remote.Tags{apiTag2},
+			expectedError: nil,
+		},
 		{
 			name: "NewGitTag_ChangelogTag_FutureTag",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FutureTagMocks: []FutureTagMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FutureTagMocks: []remotetest.FutureTagMock{
 						{OutTag: futureTag3},
 					},
 				},
@@ -541,8 +964,8 @@ func TestGenerator_resolveTags(t *testing.T) {
 			name: "InvalidFutureTag",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FutureTagMocks: []FutureTagMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FutureTagMocks: []remotetest.FutureTagMock{
 						{OutTag: futureTag4},
 					},
 				},
@@ -565,8 +988,8 @@ func TestGenerator_resolveTags(t *testing.T) {
 			name: "FromTag_ToTag_FutureTag",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FutureTagMocks: []FutureTagMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FutureTagMocks: []remotetest.FutureTagMock{
 						{OutTag: futureTag4},
 					},
 				},
@@ -597,73 +1020,192 @@ func TestGenerator_resolveTags(t *testing.T) {
 	}
 }
 
-func TestGenerator_resolveCommitMap(t *testing.T) {
+func TestGenerator_resolveRegenerateTags(t *testing.T) {
+	chlog := &changelog.Changelog{
+		Existing: []changelog.Release{
+			{TagName: "v0.1.3"},
+			{TagName: "v0.1.2"},
+			{TagName: "v0.1.1"},
+		},
+	}
+
 	tests := []struct {
-		name              string
-		g                 *Generator
-		ctx               context.Context
-		branch            remote.Branch
-		sortedTags        remote.Tags
-		expectedError     string
-		expectedCommitMap commitMap
-	}{
-		{
-			name: "FetchParentCommitsFails_Branch",
-			g: &Generator{
-				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
-						{OutError: errors.New("error on fetching parent commits for branch")},
-					},
+		name          string
+		g             *Generator
+		s             spec.Tags
+		sortedTags    remote.Tags
+		chlog         *changelog.Changelog
+		expectedTags  remote.Tags
+		expectedError error
+	}{
+		{
+			name: "NoRegenerate",
+			g: &Generator{
+				logger: log.New(log.None),
+			},
+			s:             spec.Tags{},
+			sortedTags:    remote.Tags{tag3, tag2, tag1},
+			chlog:         chlog,
+			expectedTags:  remote.Tags{},
+			expectedError: nil,
+		},
+		{
+			name: "RegenerateAll",
+			g: &Generator{
+				logger: log.New(log.None),
+			},
+			s: spec.Tags{
+				RegenerateAll: true,
+			},
+			sortedTags:    remote.Tags{tag3, tag2, tag1},
+			chlog:         chlog,
+			expectedTags:  remote.Tags{tag3, tag2, tag1},
+			expectedError: nil,
+		},
+		{
+			name: "RegenerateAll_NewTag",
+			g: &Generator{
+				logger: log.New(log.None),
+			},
+			s: spec.Tags{
+				RegenerateAll: true,
+			},
+			sortedTags: remote.Tags{tag3, tag2, tag1},
+			chlog: &changelog.Changelog{
+				Existing: []changelog.Release{
+					{TagName: "v0.1.1"},
 				},
 			},
-			ctx:           context.Background(),
-			branch:        branch,
-			sortedTags:    remote.Tags{tag2, tag1},
-			expectedError: "error on fetching parent commits for branch",
+			expectedTags:  remote.Tags{tag1},
+			expectedError: nil,
 		},
 		{
-			name: "FetchParentCommitsFails_FirstTag",
+			name: "InvalidRegenerateFrom",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
-						{OutCommits: remote.Commits{commit3, commit2, commit1}},
-						{OutError: errors.New("error on fetching parent commits for tag")},
+			},
+			s: spec.Tags{
+				RegenerateFrom: "v0.1.0",
+			},
+			sortedTags:    remote.Tags{tag3, tag2, tag1},
+			chlog:         chlog,
+			expectedTags:  nil,
+			expectedError: errors.New("regenerate-from can be one of [v0.1.3 v0.1.2 v0.1.1]"),
+		},
+		{
+			name: "InvalidRegenerateTo",
+			g: &Generator{
+				logger: log.New(log.None),
+			},
+			s: spec.Tags{
+				RegenerateTo: "v0.1.4",
+			},
+			sortedTags:    remote.Tags{tag3, tag2, tag1},
+			chlog:         chlog,
+			expectedTags:  nil,
+			expectedError: errors.New("regenerate-to can be one of [v0.1.3 v0.1.2 v0.1.1]"),
+		},
+		{
+			name: "RegenerateFrom",
+			g: &Generator{
+				logger: log.New(log.None),
+			},
+			s: spec.Tags{
+				RegenerateFrom: "v0.1.2",
+			},
+			sortedTags:    remote.Tags{tag3, tag2, tag1},
+			chlog:         chlog,
+			expectedTags:  remote.Tags{tag3, tag2},
+			expectedError: nil,
+		},
+		{
+			name: "RegenerateTo",
+			g: &Generator{
+				logger: log.New(log.None),
+			},
+			s: spec.Tags{
+				RegenerateTo: "v0.1.2",
+			},
+			sortedTags:    remote.Tags{tag3, tag2, tag1},
+			chlog:         chlog,
+			expectedTags:  remote.Tags{tag2, tag1},
+			expectedError: nil,
+		},
+		{
+			name: "RegenerateFromAndTo",
+			g: &Generator{
+				logger: log.New(log.None),
+			},
+			s: spec.Tags{
+				RegenerateFrom: "v0.1.2",
+				RegenerateTo:   "v0.1.2",
+			},
+			sortedTags:    remote.Tags{tag3, tag2, tag1},
+			chlog:         chlog,
+			expectedTags:  remote.Tags{tag2},
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, err := tc.g.resolveRegenerateTags(tc.s, tc.sortedTags, tc.chlog)
+
+			assert.Equal(t, tc.expectedTags, tags)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestGenerator_resolveCommitMap(t *testing.T) {
+	tests := []struct {
+		name              string
+		g                 *Generator
+		ctx               context.Context
+		branch            remote.Branch
+		sortedTags        remote.Tags
+		expectedError     string
+		expectedCommitMap commitMap
+		expectedCalls     int
+	}{
+		{
+			name: "FetchParentCommitsFails_Branch",
+			g: &Generator{
+				logger: log.New(log.None),
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutError: errors.New("error on fetching parent commits for branch")},
 					},
 				},
 			},
 			ctx:           context.Background(),
 			branch:        branch,
 			sortedTags:    remote.Tags{tag2, tag1},
-			expectedError: "error on fetching parent commits for tag",
+			expectedError: "error on fetching parent commits for branch",
 		},
 		{
-			name: "FetchParentCommitsFails_SecondTag",
+			name: "FetchParentCommitsFails_Tag",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
-						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
 						{OutCommits: remote.Commits{commit2, commit1}},
 						{OutError: errors.New("error on fetching parent commits for tag")},
 					},
 				},
 			},
 			ctx:           context.Background(),
-			branch:        branch,
-			sortedTags:    remote.Tags{tag2, tag1},
+			branch:        remote.Branch{Name: "main", Commit: commit2},
+			sortedTags:    remote.Tags{tag3, tag2, tag1},
 			expectedError: "error on fetching parent commits for tag",
 		},
 		{
 			name: "Success",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
 						{OutCommits: remote.Commits{commit3, commit2, commit1}},
-						{OutCommits: remote.Commits{commit2, commit1}},
-						{OutCommits: remote.Commits{commit1}},
 					},
 				},
 			},
@@ -672,17 +1214,53 @@ func TestGenerator_resolveCommitMap(t *testing.T) {
 			sortedTags: remote.Tags{tag2, tag1},
 			expectedCommitMap: commitMap{
 				"c414d1004154c6c324bd78c69d10ee101e676059": &revisions{
+					Commit: commit3,
+					Branch: "main",
+				},
+				"0251a422d2038967eeaaaa5c8aa76c7067fdef05": &revisions{
+					Commit: commit2,
+					Branch: "main",
+					Tag:    "v0.1.2",
+				},
+				"25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378": &revisions{
+					Commit: commit1,
 					Branch: "main",
+					Tag:    "v0.1.1",
+				},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name: "Success_TagNotOnBranch",
+			g: &Generator{
+				logger: log.New(log.None),
+				remoteRepo: &remotetest.MockRemoteRepo{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutCommits: remote.Commits{commit2, commit1}},
+						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+					},
+				},
+			},
+			ctx:        context.Background(),
+			branch:     remote.Branch{Name: "main", Commit: commit2},
+			sortedTags: remote.Tags{tag3, tag1},
+			expectedCommitMap: commitMap{
+				"c414d1004154c6c324bd78c69d10ee101e676059": &revisions{
+					Commit: commit3,
+					Tag:    "v0.1.3",
 				},
 				"0251a422d2038967eeaaaa5c8aa76c7067fdef05": &revisions{
+					Commit: commit2,
 					Branch: "main",
-					Tags:   []string{"v0.1.2"},
+					Tag:    "v0.1.3",
 				},
 				"25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378": &revisions{
+					Commit: commit1,
 					Branch: "main",
-					Tags:   []string{"v0.1.2", "v0.1.1"},
+					Tag:    "v0.1.1",
 				},
 			},
+			expectedCalls: 2,
 		},
 	}
 
@@ -692,7 +1270,8 @@ func TestGenerator_resolveCommitMap(t *testing.T) {
 
 			if tc.expectedError == "" {
 				assert.NoError(t, err)
-				assert.Equal(t, commitMap, tc.expectedCommitMap)
+				assert.Equal(t, tc.expectedCommitMap, commitMap)
+				assert.Equal(t, tc.expectedCalls, tc.g.remoteRepo.(*remotetest.MockRemoteRepo).FetchParentCommitsIndex)
 			} else {
 				assert.Nil(t, commitMap)
 				assert.EqualError(t, err, tc.expectedError)
@@ -710,28 +1289,34 @@ func TestGenerator_resolveReleases(t *testing.T) {
 		WebURL: "https://github.com/octocat/Hello-World/tree/v0.1.4",
 	}
 
+	apiTag := remote.Tag{
+		Name:   "api/v0.1.3",
+		Time:   t3,
+		Commit: tag3.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/api/v0.1.3",
+	}
+
 	tests := []struct {
 		name             string
 		g                *Generator
-		ctx              context.Context
 		s                spec.Spec
 		sortedTags       remote.Tags
 		baseRev          string
 		issueMap         issueMap
 		mergeMap         mergeMap
+		conventionalMap  conventionalMap
 		expectedReleases []changelog.Release
 	}{
 		{
 			name: "WithoutFutureTag_GroupingMilestone",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					CompareURLMocks: []CompareURLMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CompareURLMocks: []remotetest.CompareURLMock{
 						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.2...v0.1.3"},
 					},
 				},
 			},
-			ctx: context.Background(),
 			s: spec.Spec{
 				Issues: spec.Issues{
 					Grouping: spec.GroupingMilestone,
@@ -777,13 +1362,12 @@ func TestGenerator_resolveReleases(t *testing.T) {
 			name: "WithoutFutureTag_GroupingLabel",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					CompareURLMocks: []CompareURLMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CompareURLMocks: []remotetest.CompareURLMock{
 						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.2...v0.1.3"},
 					},
 				},
 			},
-			ctx: context.Background(),
 			s: spec.Spec{
 				Issues: spec.Issues{
 					Grouping:  spec.GroupingLabel,
@@ -831,15 +1415,17 @@ func TestGenerator_resolveReleases(t *testing.T) {
 			name: "WithFutureTag_GroupingMilestone",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					CompareURLMocks: []CompareURLMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CompareURLMocks: []remotetest.CompareURLMock{
 						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.3...v0.1.4"},
 						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.2...v0.1.3"},
 					},
 				},
 			},
-			ctx: context.Background(),
 			s: spec.Spec{
+				Tags: spec.Tags{
+					Future: "v0.1.4",
+				},
 				Issues: spec.Issues{
 					Grouping: spec.GroupingMilestone,
 				},
@@ -867,6 +1453,7 @@ func TestGenerator_resolveReleases(t *testing.T) {
 					TagTime:    now,
 					ReleaseURL: "https://storage.artifactory.com/project/releases/v0.1.4",
 					CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.3...v0.1.4",
+					Unreleased: true,
 					IssueGroups: []changelog.IssueGroup{
 						{
 							Title:  "Closed Issues",
@@ -905,15 +1492,17 @@ func TestGenerator_resolveReleases(t *testing.T) {
 			name: "WithFutureTag_GroupingLabel",
 			g: &Generator{
 				logger: log.New(log.None),
-				remoteRepo: &MockRemoteRepo{
-					CompareURLMocks: []CompareURLMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CompareURLMocks: []remotetest.CompareURLMock{
 						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.3...v0.1.4"},
 						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.2...v0.1.3"},
 					},
 				},
 			},
-			ctx: context.Background(),
 			s: spec.Spec{
+				Tags: spec.Tags{
+					Future: "v0.1.4",
+				},
 				Issues: spec.Issues{
 					Grouping:  spec.GroupingLabel,
 					BugLabels: []string{"bug"},
@@ -943,6 +1532,7 @@ func TestGenerator_resolveReleases(t *testing.T) {
 					TagTime:    now,
 					ReleaseURL: "https://storage.artifactory.com/project/releases/v0.1.4",
 					CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.3...v0.1.4",
+					Unreleased: true,
 					IssueGroups: []changelog.IssueGroup{
 						{
 							Title:  "Closed Issues",
@@ -977,11 +1567,113 @@ func TestGenerator_resolveReleases(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "WithFutureTag_ConventionalCommits",
+			g: &Generator{
+				logger: log.New(log.None),
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CompareURLMocks: []remotetest.CompareURLMock{
+						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.3...v0.1.4"},
+						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.2...v0.1.3"},
+					},
+					CommitURLMocks: []remotetest.CommitURLMock{
+						{OutString: "https://github.com/octocat/Hello-World/commit/20c5414eccaa147f2d6644de4ca36f35293fa43e"},
+						{OutString: "https://github.com/octocat/Hello-World/commit/20c5414eccaa147f2d6644de4ca36f35293fa43e"},
+						{OutString: "https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059"},
+					},
+				},
+			},
+			s: spec.Spec{
+				Tags: spec.Tags{
+					Future: "v0.1.4",
+				},
+				Commits: spec.Commits{
+					Groups: []string{"feat:feature", "fix:bug"},
+				},
+			},
+			sortedTags: remote.Tags{futureTag, tag3},
+			baseRev:    "v0.1.2",
+			conventionalMap: conventionalMap{
+				"v0.1.4": conventionalCommits{conventionalCommit2},
+				"v0.1.3": conventionalCommits{conventionalCommit1},
+			},
+			expectedReleases: []changelog.Release{
+				{
+					TagName:    "v0.1.4",
+					TagURL:     "https://github.com/octocat/Hello-World/tree/v0.1.4",
+					TagTime:    now,
+					CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.3...v0.1.4",
+					Unreleased: true,
+					CommitGroups: []changelog.CommitGroup{
+						{
+							Title:   "Breaking Changes",
+							Commits: []changelog.Commit{changelogCommit2},
+						},
+						{
+							Title:   "Fixed Bugs",
+							Commits: []changelog.Commit{changelogCommit2},
+						},
+					},
+				},
+				{
+					TagName:    "v0.1.3",
+					TagURL:     "https://github.com/octocat/Hello-World/tree/v0.1.3",
+					TagTime:    t3,
+					CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.2...v0.1.3",
+					CommitGroups: []changelog.CommitGroup{
+						{
+							Title:   "New Features",
+							Commits: []changelog.Commit{changelogCommit1},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "TrimPrefix",
+			g: &Generator{
+				logger: log.New(log.None),
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CompareURLMocks: []remotetest.CompareURLMock{
+						{OutString: "https://github.com/octocat/Hello-World/compare/api/v0.1.2...api/v0.1.3"},
+					},
+				},
+			},
+			s: spec.Spec{
+				Tags: spec.Tags{
+					Prefix:     "api/",
+					TrimPrefix: true,
+				},
+				Content: spec.Content{
+					ReleaseURL: "https://storage.artifactory.com/project/releases/{tag}",
+				},
+			},
+			sortedTags: remote.Tags{apiTag},
+			baseRev:    "api/v0.1.2",
+			mergeMap: mergeMap{
+				"api/v0.1.3": remote.Merges{merge1},
+			},
+			expectedReleases: []changelog.Release{
+				{
+					TagName:    "v0.1.3",
+					TagURL:     "https://github.com/octocat/Hello-World/tree/api/v0.1.3",
+					TagTime:    t3,
+					ReleaseURL: "https://storage.artifactory.com/project/releases/api/v0.1.3",
+					CompareURL: "https://github.com/octocat/Hello-World/compare/api/v0.1.2...api/v0.1.3",
+					MergeGroups: []changelog.MergeGroup{
+						{
+							Title:  "Merged Changes",
+							Merges: []changelog.Merge{changelogMerge1},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			releases := tc.g.resolveReleases(tc.ctx, tc.s, tc.sortedTags, tc.baseRev, tc.issueMap, tc.mergeMap)
+			releases := tc.g.resolveReleases(tc.s, tc.sortedTags, tc.baseRev, tc.issueMap, tc.mergeMap, tc.conventionalMap)
 
 			assert.Equal(t, tc.expectedReleases, releases)
 		})
@@ -989,6 +1681,27 @@ func TestGenerator_resolveReleases(t *testing.T) {
 }
 
 func TestGenerator_Generate(t *testing.T) {
+	webTag := remote.Tag{
+		Name:   "web/v0.2.0",
+		Time:   tag2.Time,
+		Commit: tag2.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/web/v0.2.0",
+	}
+
+	apiTag1 := remote.Tag{
+		Name:   "api/v0.1.1",
+		Time:   tag1.Time,
+		Commit: tag1.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/api/v0.1.1",
+	}
+
+	apiTag2 := remote.Tag{
+		Name:   "api/v0.1.2",
+		Time:   tag2.Time,
+		Commit: tag2.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/api/v0.1.2",
+	}
+
 	tests := []struct {
 		name            string
 		g               *Generator
@@ -1020,8 +1733,8 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: errors.New("error on checking permissions")},
 					},
 				},
@@ -1039,11 +1752,11 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchBranchMocks: []FetchBranchMock{
+					FetchBranchMocks: []remotetest.FetchBranchMock{
 						{OutError: errors.New("error on getting remote branch")},
 					},
 				},
@@ -1065,11 +1778,11 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutError: errors.New("error on getting default remote branch")},
 					},
 				},
@@ -1087,14 +1800,14 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutError: errors.New("error on getting remote tags")},
 					},
 				},
@@ -1112,14 +1825,14 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutTags: remote.Tags{}},
 					},
 				},
@@ -1137,17 +1850,17 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutTags: remote.Tags{tag1}},
 					},
-					FetchFirstCommitMocks: []FetchFirstCommitMock{
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
 						{OutError: errors.New("error on fetching first commit")},
 					},
 				},
@@ -1165,20 +1878,20 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutTags: remote.Tags{tag1}},
 					},
-					FetchFirstCommitMocks: []FetchFirstCommitMock{
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
 						{OutCommit: commit1},
 					},
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
 						{OutError: errors.New("error on fetching parent commits for branch")},
 					},
 				},
@@ -1196,21 +1909,21 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
-						{OutBranch: branch},
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+						{OutBranch: remote.Branch{Name: "main", Commit: commit2}},
 					},
-					FetchTagsMocks: []FetchTagsMock{
-						{OutTags: remote.Tags{tag1}},
+					FetchTagsMocks: []remotetest.FetchTagsMock{
+						{OutTags: remote.Tags{tag3, tag1}},
 					},
-					FetchFirstCommitMocks: []FetchFirstCommitMock{
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
 						{OutCommit: commit1},
 					},
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
-						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutCommits: remote.Commits{commit2, commit1}},
 						{OutError: errors.New("error on fetching parent commits for tag")},
 					},
 				},
@@ -1228,24 +1941,24 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutTags: remote.Tags{tag1}},
 					},
-					FetchFirstCommitMocks: []FetchFirstCommitMock{
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
 						{OutCommit: commit1},
 					},
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
 						{OutCommits: remote.Commits{commit3, commit2, commit1}},
 						{OutCommits: remote.Commits{commit2, commit1}},
 					},
-					FetchIssuesAndMergesMocks: []FetchIssuesAndMergesMock{
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
 						{OutError: errors.New("error on fetching issues and merges")},
 					},
 				},
@@ -1255,51 +1968,52 @@ func TestGenerator_Generate(t *testing.T) {
 			expectedError: "error on fetching issues and merges",
 		},
 		{
-			name: "RenderFails",
+			name: "FetchCommitFilesFails",
 			g: &Generator{
 				logger: log.New(log.None),
 				processor: &MockChangelogProcessor{
 					ParseMocks: []ParseMock{
 						{OutChangelog: &changelog.Changelog{}},
 					},
-					RenderMocks: []RenderMock{
-						{OutError: errors.New("error on rendering changelog")},
-					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutTags: remote.Tags{tag1}},
 					},
-					FetchFirstCommitMocks: []FetchFirstCommitMock{
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
 						{OutCommit: commit1},
 					},
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
 						{OutCommits: remote.Commits{commit3, commit2, commit1}},
 						{OutCommits: remote.Commits{commit2, commit1}},
 					},
-					FetchIssuesAndMergesMocks: []FetchIssuesAndMergesMock{
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
 						{
 							OutIssues: remote.Issues{},
-							OutMerges: remote.Merges{},
+							OutMerges: remote.Merges{merge1},
 						},
 					},
-					CompareURLMocks: []CompareURLMock{
-						{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...v0.1.1"},
+					FetchCommitFilesMocks: []remotetest.FetchCommitFilesMock{
+						{OutError: errors.New("error on fetching commit files")},
 					},
 				},
 			},
-			ctx:           context.Background(),
-			s:             spec.Spec{},
-			expectedError: "error on rendering changelog",
+			ctx: context.Background(),
+			s: spec.Spec{
+				Merges: spec.Merges{
+					Paths: []string{"api/"},
+				},
+			},
+			expectedError: "error on fetching commit files",
 		},
 		{
-			name: "Success_ToTag",
+			name: "RenderFails",
 			g: &Generator{
 				logger: log.New(log.None),
 				processor: &MockChangelogProcessor{
@@ -1307,52 +2021,96 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutChangelog: &changelog.Changelog{}},
 					},
 					RenderMocks: []RenderMock{
-						{OutContent: "changelog"},
+						{OutError: errors.New("error on rendering changelog")},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutTags: remote.Tags{tag1}},
 					},
-					FetchFirstCommitMocks: []FetchFirstCommitMock{
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
 						{OutCommit: commit1},
 					},
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
 						{OutCommits: remote.Commits{commit3, commit2, commit1}},
 						{OutCommits: remote.Commits{commit2, commit1}},
 					},
-					FetchIssuesAndMergesMocks: []FetchIssuesAndMergesMock{
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
 						{
 							OutIssues: remote.Issues{},
 							OutMerges: remote.Merges{},
 						},
 					},
-					CompareURLMocks: []CompareURLMock{
+					CompareURLMocks: []remotetest.CompareURLMock{
 						{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...v0.1.1"},
 					},
 				},
 			},
-			ctx:             context.Background(),
-			s:               spec.Spec{},
-			expectedContent: "changelog",
+			ctx:           context.Background(),
+			s:             spec.Spec{},
+			expectedError: "error on rendering changelog",
 		},
 		{
-			name: "Success_FromAndToTags",
+			name: "Success_ToTag",
 			g: &Generator{
 				logger: log.New(log.None),
 				processor: &MockChangelogProcessor{
 					ParseMocks: []ParseMock{
-						{
-							OutChangelog: &changelog.Changelog{
-								Existing: []changelog.Release{
-									{TagName: "v0.1.1"},
-								},
+						{OutChangelog: &changelog.Changelog{}},
+					},
+					RenderMocks: []RenderMock{
+						{OutContent: "changelog"},
+					},
+				},
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+						{OutError: nil},
+					},
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+						{OutBranch: branch},
+					},
+					FetchTagsMocks: []remotetest.FetchTagsMock{
+						{OutTags: remote.Tags{tag1}},
+					},
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
+						{OutCommit: commit1},
+					},
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+						{OutCommits: remote.Commits{commit2, commit1}},
+					},
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+						{
+							OutIssues: remote.Issues{},
+							OutMerges: remote.Merges{},
+						},
+					},
+					CompareURLMocks: []remotetest.CompareURLMock{
+						{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...v0.1.1"},
+					},
+				},
+			},
+			ctx:             context.Background(),
+			s:               spec.Spec{},
+			expectedContent: "changelog",
+		},
+		{
+			name: "Success_FromAndToTags",
+			g: &Generator{
+				logger: log.New(log.None),
+				processor: &MockChangelogProcessor{
+					ParseMocks: []ParseMock{
+						{
+							OutChangelog: &changelog.Changelog{
+								Existing: []changelog.Release{
+									{TagName: "v0.1.1"},
+								},
 							},
 						},
 					},
@@ -1360,29 +2118,29 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutContent: "changelog"},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutTags: remote.Tags{tag2, tag1}},
 					},
-					FetchFirstCommitMocks: []FetchFirstCommitMock{},
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{},
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
 						{OutCommits: remote.Commits{commit3, commit2, commit1}},
 						{OutCommits: remote.Commits{commit2, commit1}},
 						{OutCommits: remote.Commits{commit1}},
 					},
-					FetchIssuesAndMergesMocks: []FetchIssuesAndMergesMock{
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
 						{
 							OutIssues: remote.Issues{},
 							OutMerges: remote.Merges{},
 						},
 					},
-					CompareURLMocks: []CompareURLMock{
+					CompareURLMocks: []remotetest.CompareURLMock{
 						{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...v0.1.1"},
 						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.1...v0.1.2"},
 					},
@@ -1404,17 +2162,17 @@ func TestGenerator_Generate(t *testing.T) {
 						{OutContent: "changelog"},
 					},
 				},
-				remoteRepo: &MockRemoteRepo{
-					CheckPermissionsMocks: []CheckPermissionsMock{
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
 						{OutError: nil},
 					},
-					FetchDefaultBranchMocks: []FetchDefaultBranchMock{
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
 						{OutBranch: branch},
 					},
-					FetchTagsMocks: []FetchTagsMock{
+					FetchTagsMocks: []remotetest.FetchTagsMock{
 						{OutTags: remote.Tags{}},
 					},
-					FutureTagMocks: []FutureTagMock{
+					FutureTagMocks: []remotetest.FutureTagMock{
 						{
 							OutTag: remote.Tag{
 								Name:   "v0.1.0",
@@ -1423,20 +2181,20 @@ func TestGenerator_Generate(t *testing.T) {
 							},
 						},
 					},
-					FetchFirstCommitMocks: []FetchFirstCommitMock{
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
 						{OutCommit: commit1},
 					},
-					FetchParentCommitsMocks: []FetchParentCommitsMock{
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
 						{OutCommits: remote.Commits{commit3, commit2, commit1}},
 						{OutCommits: remote.Commits{commit2, commit1}},
 					},
-					FetchIssuesAndMergesMocks: []FetchIssuesAndMergesMock{
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
 						{
 							OutIssues: remote.Issues{},
 							OutMerges: remote.Merges{},
 						},
 					},
-					CompareURLMocks: []CompareURLMock{
+					CompareURLMocks: []remotetest.CompareURLMock{
 						{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...v0.1.0"},
 					},
 				},
@@ -1449,6 +2207,263 @@ func TestGenerator_Generate(t *testing.T) {
 			},
 			expectedContent: "changelog",
 		},
+		{
+			name: "Success_FutureTagNext",
+			g: &Generator{
+				logger: log.New(log.None),
+				processor: &MockChangelogProcessor{
+					ParseMocks: []ParseMock{
+						{OutChangelog: &changelog.Changelog{}},
+					},
+					RenderMocks: []RenderMock{
+						{OutContent: "changelog"},
+					},
+				},
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+						{OutError: nil},
+					},
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+						{OutBranch: branch},
+					},
+					FetchTagsMocks: []remotetest.FetchTagsMock{
+						{OutTags: remote.Tags{tag2, tag1}},
+					},
+					FutureTagMocks: []remotetest.FutureTagMock{
+						{OutTag: remote.Tag{Name: "{next}"}},
+						{
+							OutTag: remote.Tag{
+								Name:   "v0.2.0",
+								Time:   time.Now(),
+								WebURL: "https://github.com/octocat/Hello-World/tree/v0.2.0",
+							},
+						},
+					},
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
+						{OutCommit: commit1},
+					},
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+					},
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+						{
+							OutIssues: remote.Issues{},
+							OutMerges: remote.Merges{merge1},
+						},
+					},
+					CompareURLMocks: []remotetest.CompareURLMock{
+						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.2...v0.2.0"},
+						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.1...v0.1.2"},
+						{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...v0.1.1"},
+					},
+				},
+			},
+			ctx: context.Background(),
+			s: spec.Spec{
+				Tags: spec.Tags{
+					Future: "{next}",
+				},
+				Merges: spec.Merges{
+					FeatureLabels: []string{"enhancement"},
+				},
+			},
+			expectedContent: "changelog",
+		},
+		{
+			name: "Success_Component",
+			g: &Generator{
+				logger: log.New(log.None),
+				processor: &MockChangelogProcessor{
+					ParseMocks: []ParseMock{
+						{
+							OutChangelog: &changelog.Changelog{
+								Existing: []changelog.Release{
+									{TagName: "v0.1.1"},
+								},
+							},
+						},
+					},
+					RenderMocks: []RenderMock{
+						{OutContent: "changelog"},
+					},
+				},
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+						{OutError: nil},
+					},
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+						{OutBranch: branch},
+					},
+					FetchTagsMocks: []remotetest.FetchTagsMock{
+						{OutTags: remote.Tags{webTag, apiTag2, apiTag1}},
+					},
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+					},
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+						{
+							OutIssues: remote.Issues{},
+							OutMerges: remote.Merges{merge2, merge1},
+						},
+					},
+					FetchCommitFilesMocks: []remotetest.FetchCommitFilesMock{
+						{OutFiles: []string{"web/app.js"}},
+						{OutFiles: []string{"api/server.go"}},
+					},
+					CompareURLMocks: []remotetest.CompareURLMock{
+						{OutString: "https://github.com/octocat/Hello-World/compare/api/v0.1.1...api/v0.1.2"},
+					},
+				},
+			},
+			ctx: context.Background(),
+			s: spec.Spec{
+				Tags: spec.Tags{
+					Prefix:     "api/",
+					TrimPrefix: true,
+				},
+				Merges: spec.Merges{
+					Paths: []string{"api/"},
+				},
+			},
+			expectedContent: "changelog",
+		},
+		{
+			name: "Success_IncludeRegex",
+			g: &Generator{
+				logger: log.New(log.None),
+				processor: &MockChangelogProcessor{
+					ParseMocks: []ParseMock{
+						{OutChangelog: &changelog.Changelog{}},
+					},
+					RenderMocks: []RenderMock{
+						{OutContent: "changelog"},
+					},
+				},
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+						{OutError: nil},
+					},
+					FetchBranchMocks: []remotetest.FetchBranchMock{
+						{OutBranch: branch},
+					},
+					FetchTagsMocks: []remotetest.FetchTagsMock{
+						{OutTags: remote.Tags{tag2, tag1}},
+					},
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+					},
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+						{
+							OutIssues: remote.Issues{},
+							OutMerges: remote.Merges{merge1},
+						},
+					},
+					CompareURLMocks: []remotetest.CompareURLMock{
+						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.1...v0.1.2"},
+					},
+				},
+			},
+			ctx: context.Background(),
+			s: spec.Spec{
+				Tags: spec.Tags{
+					IncludeRegex: `^v0\.1\.2$`,
+				},
+				Merges: spec.Merges{
+					Branch: "main",
+				},
+			},
+			expectedContent: "changelog",
+		},
+		{
+			name: "InvalidRegenerateFrom",
+			g: &Generator{
+				logger: log.New(log.None),
+				processor: &MockChangelogProcessor{
+					ParseMocks: []ParseMock{
+						{
+							OutChangelog: &changelog.Changelog{
+								Existing: []changelog.Release{
+									{TagName: "v0.1.1"},
+								},
+							},
+						},
+					},
+				},
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+						{OutError: nil},
+					},
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+						{OutBranch: branch},
+					},
+					FetchTagsMocks: []remotetest.FetchTagsMock{
+						{OutTags: remote.Tags{tag1}},
+					},
+				},
+			},
+			ctx: context.Background(),
+			s: spec.Spec{
+				Tags: spec.Tags{
+					RegenerateFrom: "v0.1.0",
+				},
+			},
+			expectedError: "regenerate-from can be one of [v0.1.1]",
+		},
+		{
+			name: "Success_RegenerateAll",
+			g: &Generator{
+				logger: log.New(log.None),
+				processor: &MockChangelogProcessor{
+					ParseMocks: []ParseMock{
+						{
+							OutChangelog: &changelog.Changelog{
+								Existing: []changelog.Release{
+									{TagName: "v0.1.2"},
+									{TagName: "v0.1.1"},
+								},
+							},
+						},
+					},
+					RenderMocks: []RenderMock{
+						{OutContent: "changelog"},
+					},
+				},
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+						{OutError: nil},
+					},
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+						{OutBranch: branch},
+					},
+					FetchTagsMocks: []remotetest.FetchTagsMock{
+						{OutTags: remote.Tags{tag2, tag1}},
+					},
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
+						{OutCommit: commit1},
+					},
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+					},
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+						{
+							OutIssues: remote.Issues{},
+							OutMerges: remote.Merges{},
+						},
+					},
+					CompareURLMocks: []remotetest.CompareURLMock{
+						{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.1...v0.1.2"},
+						{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...v0.1.1"},
+					},
+				},
+			},
+			ctx: context.Background(),
+			s: spec.Spec{
+				Tags: spec.Tags{
+					RegenerateAll: true,
+				},
+			},
+			expectedContent: "changelog",
+		},
 	}
 
 	for _, tc := range tests {
@@ -1465,3 +2480,561 @@ func TestGenerator_Generate(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerator_NextVersion(t *testing.T) {
+	tests := []struct {
+		name                string
+		merges              remote.Merges
+		expectedNextVersion string
+		expectedError       string
+	}{
+		{
+			name:          "NoUnreleasedChange",
+			merges:        remote.Merges{},
+			expectedError: "no unreleased changes after v0.1.2",
+		},
+		{
+			name:                "Success",
+			merges:              remote.Merges{merge1},
+			expectedNextVersion: "v0.2.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &Generator{
+				logger: log.New(log.None),
+				processor: &MockChangelogProcessor{
+					ParseMocks: []ParseMock{
+						{OutChangelog: &changelog.Changelog{}},
+					},
+				},
+				remoteRepo: &remotetest.MockRemoteRepo{
+					CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+						{OutError: nil},
+					},
+					FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+						{OutBranch: branch},
+					},
+					FetchTagsMocks: []remotetest.FetchTagsMock{
+						{OutTags: remote.Tags{tag2, tag1}},
+					},
+					FutureTagMocks: []remotetest.FutureTagMock{
+						{OutTag: remote.Tag{Name: "{next}"}},
+					},
+					FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
+						{OutCommit: commit1},
+					},
+					FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+						{OutCommits: remote.Commits{commit3, commit2, commit1}},
+					},
+					FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+						{
+							OutIssues: remote.Issues{},
+							OutMerges: tc.merges,
+						},
+					},
+				},
+			}
+
+			s := spec.Spec{
+				Merges: spec.Merges{
+					FeatureLabels: []string{"enhancement"},
+				},
+			}
+
+			next, err := g.NextVersion(context.Background(), s)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedNextVersion, next)
+			} else {
+				assert.Empty(t, next)
+				assert.EqualError(t, err, tc.expectedError)
+				assert.IsType(t, &NoChangesError{}, err)
+			}
+
+			// The changelog is never rendered
+			assert.Equal(t, 0, g.processor.(*MockChangelogProcessor).RenderIndex)
+		})
+	}
+}
+
+func TestGenerator_Prefetch(t *testing.T) {
+	header := "# Changelog\n\n**DO NOT MODIFY THIS FILE!**\n*This changelog is automatically generated by [changelog](https://github.com/moorara/changelog)*\n\n\n"
+	apiRelease := "## [api/v0.1.1](https://github.com/octocat/Hello-World/tree/api/v0.1.1) (2020-10-02)\n\n"
+
+	apiTag1 := remote.Tag{
+		Name:   "api/v0.1.1",
+		Time:   tag1.Time,
+		Commit: tag1.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/api/v0.1.1",
+	}
+
+	apiTag2 := remote.Tag{
+		Name:   "api/v0.1.2",
+		Time:   tag2.Time,
+		Commit: tag2.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/api/v0.1.2",
+	}
+
+	webTag := remote.Tag{
+		Name:   "web/v0.1.0",
+		Time:   tag2.Time,
+		Commit: tag2.Commit,
+		WebURL: "https://github.com/octocat/Hello-World/tree/web/v0.1.0",
+	}
+
+	mockRepo := &remotetest.MockRemoteRepo{
+		CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+			{OutError: nil},
+		},
+		FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+			{OutBranch: branch},
+		},
+		FetchTagsMocks: []remotetest.FetchTagsMock{
+			{OutTags: remote.Tags{apiTag2, webTag, apiTag1}},
+		},
+		FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
+			{OutCommit: commit1},
+		},
+		FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+			{OutCommits: remote.Commits{commit3, commit2, commit1}},
+		},
+		FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+			{OutIssues: remote.Issues{}, OutMerges: remote.Merges{}},
+		},
+		CompareURLMocks: []remotetest.CompareURLMock{
+			{OutString: "https://github.com/octocat/Hello-World/compare/api/v0.1.1...api/v0.1.2"},
+			{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...web/v0.1.0"},
+		},
+	}
+
+	dir := t.TempDir()
+	apiFile := filepath.Join(dir, "api", "CHANGELOG.md")
+	webFile := filepath.Join(dir, "web", "CHANGELOG.md")
+
+	assert.NoError(t, os.MkdirAll(filepath.Dir(apiFile), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Dir(webFile), 0755))
+	assert.NoError(t, ioutil.WriteFile(apiFile, []byte(header+apiRelease), 0644))
+
+	// The api changelog needs the issues and merges since its last release and the web changelog needs all of them
+	specs := []spec.Spec{
+		{
+			General: spec.General{File: apiFile},
+			Tags:    spec.Tags{Prefix: "api/"},
+		},
+		{
+			General: spec.General{File: webFile},
+			Tags:    spec.Tags{Prefix: "web/"},
+		},
+	}
+
+	g := &Generator{
+		logger:     log.New(log.None),
+		remoteRepo: memo.NewRepo(mockRepo),
+	}
+
+	ctx := context.Background()
+
+	assert.NoError(t, g.Prefetch(ctx, specs))
+
+	for _, s := range specs {
+		gs, err := g.WithSpec(s)
+		assert.NoError(t, err)
+
+		content, err := gs.Generate(ctx, s)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, content)
+	}
+
+	// The issues and merges are fetched only once since the beginning
+	assert.Equal(t, 1, mockRepo.FetchIssuesAndMergesIndex)
+	assert.True(t, mockRepo.FetchIssuesAndMergesMocks[0].InSince.IsZero())
+}
+
+func TestGenerator_Generate_Check(t *testing.T) {
+	header := "# Changelog\n\n**DO NOT MODIFY THIS FILE!**\n*This changelog is automatically generated by [changelog](https://github.com/moorara/changelog)*\n\n\n"
+	release := "## [v0.1.1](https://github.com/octocat/Hello-World/tree/v0.1.1) (2020-10-02)\n\n"
+
+	tests := []struct {
+		name          string
+		remoteRepo    *remotetest.MockRemoteRepo
+		existing      string
+		expectedError string
+	}{
+		{
+			name: "UpToDate",
+			remoteRepo: &remotetest.MockRemoteRepo{
+				CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+					{OutError: nil},
+				},
+				FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+					{OutBranch: branch},
+				},
+				FetchTagsMocks: []remotetest.FetchTagsMock{
+					{OutTags: remote.Tags{tag1}},
+				},
+			},
+			existing:      header + release,
+			expectedError: "",
+		},
+		{
+			name: "OutOfDate",
+			remoteRepo: &remotetest.MockRemoteRepo{
+				CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+					{OutError: nil},
+				},
+				FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+					{OutBranch: branch},
+				},
+				FetchTagsMocks: []remotetest.FetchTagsMock{
+					{OutTags: remote.Tags{tag1}},
+				},
+				FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
+					{OutCommit: commit1},
+				},
+				FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+					{OutCommits: remote.Commits{commit3, commit2, commit1}},
+				},
+				FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+					{
+						OutIssues: remote.Issues{},
+						OutMerges: remote.Merges{},
+					},
+				},
+				CompareURLMocks: []remotetest.CompareURLMock{
+					{OutString: "https://github.com/octocat/Hello-World/compare/25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378...v0.1.1"},
+				},
+			},
+			existing:      header,
+			expectedError: "is out of date",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+			err := ioutil.WriteFile(changelogFile, []byte(tc.existing), 0644)
+			assert.NoError(t, err)
+
+			checkDirs, err := filepath.Glob(filepath.Join(os.TempDir(), "changelog-check-*"))
+			assert.NoError(t, err)
+
+			g := &Generator{
+				logger:     log.New(log.None),
+				remoteRepo: tc.remoteRepo,
+				processor:  &MockChangelogProcessor{},
+			}
+
+			s := spec.Spec{
+				General: spec.General{
+					File:  changelogFile,
+					Check: true,
+				},
+			}
+
+			content, err := g.Generate(context.Background(), s)
+			assert.Empty(t, content)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, changelogFile+" "+tc.expectedError)
+				assert.IsType(t, &OutOfDateError{}, err)
+			}
+
+			// The changelog file should never be written in check mode
+			b, err := ioutil.ReadFile(changelogFile)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.existing, string(b))
+
+			// The temporary copy of the changelog file should be removed
+			dirs, err := filepath.Glob(filepath.Join(os.TempDir(), "changelog-check-*"))
+			assert.NoError(t, err)
+			assert.Equal(t, checkDirs, dirs)
+		})
+	}
+}
+
+func TestReleaseNotes(t *testing.T) {
+	markdownChangelog := "# Changelog\n\n" +
+		"## [v0.1.1](https://github.com/octocat/Hello-World/tree/v0.1.1) (2020-10-11)\n\n" +
+		"[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1)\n\n" +
+		"**Fixed Bugs:**\n\n" +
+		"  - Fixed &lt;nil&gt; pointer dereference [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))\n\n\n" +
+		"## [v0.1.0](https://github.com/octocat/Hello-World/tree/v0.1.0) (2020-10-10)\n\n" +
+		"[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.0.1...v0.1.0)\n"
+
+	keepAChangelog := "# Changelog\n\n" +
+		"## [Unreleased]\n\n" +
+		"### Added\n\n" +
+		"- A feature that is not released yet\n\n" +
+		"## [0.1.1] - 2020-10-11\n\n" +
+		"### Fixed\n\n" +
+		"- Fixed a `nil` pointer dereference\n\n" +
+		"[unreleased]: https://github.com/octocat/Hello-World/compare/v0.1.1...HEAD\n" +
+		"[0.1.1]: https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1\n"
+
+	jsonChangelog := `{
+  "version": 1,
+  "title": "Changelog",
+  "releases": [
+    {
+      "tag_name": "v0.1.1",
+      "tag_url": "https://github.com/octocat/Hello-World/tree/v0.1.1",
+      "tag_time": "2020-10-11T00:00:00Z",
+      "compare_url": "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1",
+      "issue_groups": [],
+      "merge_groups": [
+        {
+          "title": "Merged Changes",
+          "merges": [
+            {
+              "number": 1002,
+              "title": "Add a feature",
+              "url": "https://github.com/octocat/Hello-World/pull/1002",
+              "opened_by": { "username": "octocat", "url": "https://github.com/octocat" },
+              "merged_by": { "username": "octodog", "url": "https://github.com/octodog" }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+
+	tests := []struct {
+		name          string
+		file          string
+		content       string
+		g             spec.General
+		expectedNotes string
+		expectedError string
+	}{
+		{
+			name:    "InvalidFormat",
+			file:    "CHANGELOG.md",
+			content: markdownChangelog,
+			g: spec.General{
+				ReleaseNotes:       "v0.1.1",
+				ReleaseNotesFormat: spec.NotesFormat("html"),
+			},
+			expectedError: `unsupported release notes format "html"`,
+		},
+		{
+			name:    "NotFound",
+			file:    "CHANGELOG.md",
+			content: markdownChangelog,
+			g: spec.General{
+				ReleaseNotes:       "v0.2.0",
+				ReleaseNotesFormat: spec.NotesFormatMarkdown,
+			},
+			expectedError: "release-notes can be one of [v0.1.1 v0.1.0]",
+		},
+		{
+			name:    "Markdown",
+			file:    "CHANGELOG.md",
+			content: markdownChangelog,
+			g: spec.General{
+				ReleaseNotes:       "v0.1.1",
+				ReleaseNotesFormat: spec.NotesFormatMarkdown,
+			},
+			expectedNotes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1)\n\n" +
+				"**Fixed Bugs:**\n\n" +
+				"  - Fixed &lt;nil&gt; pointer dereference [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))",
+		},
+		{
+			name:    "Markdown_Text",
+			file:    "CHANGELOG.md",
+			content: markdownChangelog,
+			g: spec.General{
+				ReleaseNotes:       "v0.1.1",
+				ReleaseNotesFormat: spec.NotesFormatText,
+			},
+			expectedNotes: "Compare Changes: https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1\n\n" +
+				"Fixed Bugs:\n\n" +
+				"  - Fixed <nil> pointer dereference #1001 (octocat)",
+		},
+		{
+			name:    "KeepAChangelog",
+			file:    "CHANGELOG.md",
+			content: keepAChangelog,
+			g: spec.General{
+				Format:             spec.FormatKeepAChangelog,
+				ReleaseNotes:       "v0.1.1",
+				ReleaseNotesFormat: spec.NotesFormatMarkdown,
+			},
+			expectedNotes: "### Fixed\n\n- Fixed a `nil` pointer dereference",
+		},
+		{
+			name:    "KeepAChangelog_Text",
+			file:    "CHANGELOG.md",
+			content: keepAChangelog,
+			g: spec.General{
+				Format:             spec.FormatKeepAChangelog,
+				ReleaseNotes:       "v0.1.1",
+				ReleaseNotesFormat: spec.NotesFormatText,
+			},
+			expectedNotes: "Fixed\n\n- Fixed a nil pointer dereference",
+		},
+		{
+			name:    "JSON",
+			file:    "CHANGELOG.json",
+			content: jsonChangelog,
+			g: spec.General{
+				ReleaseNotes:       "v0.1.1",
+				ReleaseNotesFormat: spec.NotesFormatMarkdown,
+			},
+			expectedNotes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1)\n\n" +
+				"**Merged Changes:**\n\n" +
+				"  - Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changelogFile := filepath.Join(t.TempDir(), tc.file)
+
+			err := ioutil.WriteFile(changelogFile, []byte(tc.content), 0644)
+			assert.NoError(t, err)
+
+			s := spec.Spec{General: tc.g}
+			s.General.File = changelogFile
+
+			notes, err := ReleaseNotes(s, log.New(log.None))
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedNotes, notes)
+			} else {
+				assert.Empty(t, notes)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			// The changelog file should never be written
+			b, err := ioutil.ReadFile(changelogFile)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.content, string(b))
+		})
+	}
+}
+
+func TestReleaseNotes_Specs(t *testing.T) {
+	apiChangelog := "# Changelog\n\n" +
+		"## [v0.2.0](https://github.com/octocat/Hello-World/tree/api/v0.2.0) (2020-10-11)\n\n" +
+		"[Compare Changes](https://github.com/octocat/Hello-World/compare/api/v0.1.0...api/v0.2.0)\n"
+
+	webChangelog := "# Changelog\n\n" +
+		"## [web/v0.3.0](https://github.com/octocat/Hello-World/tree/web/v0.3.0) (2020-10-12)\n\n" +
+		"[Compare Changes](https://github.com/octocat/Hello-World/compare/web/v0.2.0...web/v0.3.0)\n"
+
+	v1Changelog := "# Changelog\n\n" +
+		"## [v1.4.4](https://github.com/octocat/Hello-World/tree/v1.4.4) (2020-10-13)\n\n" +
+		"[Compare Changes](https://github.com/octocat/Hello-World/compare/v1.4.3...v1.4.4)\n"
+
+	v2Changelog := "# Changelog\n\n" +
+		"## [v2.1.0](https://github.com/octocat/Hello-World/tree/v2.1.0) (2020-10-14)\n\n" +
+		"[Compare Changes](https://github.com/octocat/Hello-World/compare/v2.0.0...v2.1.0)\n"
+
+	tests := []struct {
+		name          string
+		files         map[string]string
+		components    []spec.Component
+		lines         []spec.Line
+		tag           string
+		expectedNotes string
+		expectedError string
+	}{
+		{
+			name:  "TrimmedComponent",
+			files: map[string]string{"api.md": apiChangelog, "web.md": webChangelog},
+			components: []spec.Component{
+				{TagPrefix: "api/", TrimPrefix: true, Paths: []string{"api/"}, File: "api.md"},
+				{TagPrefix: "web/", Paths: []string{"web/"}, File: "web.md"},
+			},
+			tag:           "api/v0.2.0",
+			expectedNotes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/api/v0.1.0...api/v0.2.0)",
+		},
+		{
+			name:  "Component",
+			files: map[string]string{"api.md": apiChangelog, "web.md": webChangelog},
+			components: []spec.Component{
+				{TagPrefix: "api/", TrimPrefix: true, Paths: []string{"api/"}, File: "api.md"},
+				{TagPrefix: "web/", Paths: []string{"web/"}, File: "web.md"},
+			},
+			tag:           "web/v0.3.0",
+			expectedNotes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/web/v0.2.0...web/v0.3.0)",
+		},
+		{
+			name:  "NoComponent",
+			files: map[string]string{"api.md": apiChangelog, "web.md": webChangelog},
+			components: []spec.Component{
+				{TagPrefix: "api/", TrimPrefix: true, Paths: []string{"api/"}, File: "api.md"},
+				{TagPrefix: "web/", Paths: []string{"web/"}, File: "web.md"},
+			},
+			tag:           "cli/v0.1.0",
+			expectedError: "no component or release line for tag cli/v0.1.0",
+		},
+		{
+			name:  "Line",
+			files: map[string]string{"v1.md": v1Changelog, "v2.md": v2Changelog},
+			lines: []spec.Line{
+				{Branch: "release/1.x", TagsRegex: `^v1\.`, File: "v1.md"},
+				{Branch: "main", TagsRegex: `^v2\.`, File: "v2.md"},
+			},
+			tag:           "v1.4.4",
+			expectedNotes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/v1.4.3...v1.4.4)",
+		},
+		{
+			name:  "DefaultLine",
+			files: map[string]string{"v1.md": v1Changelog, "v2.md": v2Changelog},
+			lines: []spec.Line{
+				{Branch: "release/1.x", TagsRegex: `^v1\.`, File: "v1.md"},
+				{Branch: "main", File: "v2.md"},
+			},
+			tag:           "v2.1.0",
+			expectedNotes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/v2.0.0...v2.1.0)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			for name, content := range tc.files {
+				err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+				assert.NoError(t, err)
+			}
+
+			s := spec.Spec{
+				General: spec.General{
+					ReleaseNotes:       tc.tag,
+					ReleaseNotesFormat: spec.NotesFormatMarkdown,
+				},
+			}
+
+			for _, c := range tc.components {
+				c.File = filepath.Join(dir, c.File)
+				s.Components = append(s.Components, c)
+			}
+
+			for _, l := range tc.lines {
+				l.File = filepath.Join(dir, l.File)
+				s.Lines = append(s.Lines, l)
+			}
+
+			notes, err := ReleaseNotes(s, log.New(log.None))
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedNotes, notes)
+			} else {
+				assert.Empty(t, notes)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
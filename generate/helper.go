@@ -1,15 +1,48 @@
 package generate
 
 import (
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/moorara/changelog/internal/changelog"
 	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/internal/semver"
 	"github.com/moorara/changelog/spec"
 )
 
-// revisions refers to a branch name and list of tags sorted from the most recent to the least recent.
+// The following expressions match the Markdown syntax removed from plain text release notes.
+var (
+	headingRegex  = regexp.MustCompile(`(?m)^#+\s+`)
+	linkLineRegex = regexp.MustCompile(`(?m)^\[([^\]]+)\]\((\S+)\)$`)
+	linkRegex     = regexp.MustCompile(`\[([^\]]+)\]\(\S*?\)`)
+	strongRegex   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	codeRegex     = regexp.MustCompile("`([^`]+)`")
+)
+
+// The following expressions match the header and the breaking change footer of a Conventional Commits message.
+// See https://www.conventionalcommits.org/en/v1.0.0
+var (
+	headerRegex   = regexp.MustCompile(`^(\w+)(?:\(([^()\r\n]+)\))?(!)?: (\S.*)$`)
+	breakingRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+)
+
+// calverRegex matches a calendar version with an optional prefix (i.e. 2020.10.1, v20.10, or release-2020-10-22).
+// See https://calver.org
+var calverRegex = regexp.MustCompile(`^\D*?((?:\d{4}|\d{2})(?:[.\-_]\d+)+)$`)
+
+// revisions refers to a commit, a branch name, and the least recent tag that the commit is released in.
 type revisions struct {
+	Commit remote.Commit
 	Branch string
-	Tags   []string
+	Tag    string
 }
 
 // commitMap is a map of commit hashes to revisions (branch name and tags).
@@ -24,6 +57,123 @@ type issueMap map[string]remote.Issues
 // It allows us to look up all merges for a tatg.
 type mergeMap map[string]remote.Merges
 
+// conventionalMap is a map of tag names to conventional commits.
+// It allows us to look up all conventional commits for a tag.
+type conventionalMap map[string]conventionalCommits
+
+// conventionalCommit is a commit with a message following the Conventional Commits specification.
+type conventionalCommit struct {
+	Hash     string
+	Time     time.Time
+	Type     string
+	Scope    string
+	Subject  string
+	Breaking bool
+}
+
+// Labels returns the type and breaking for a breaking change as the labels of a conventional commit.
+func (c conventionalCommit) Labels() remote.Labels {
+	labels := remote.Labels{c.Type}
+	if c.Breaking {
+		labels = append(labels, "breaking")
+	}
+
+	return labels
+}
+
+// conventionalCommits is a collection of conventional commits.
+type conventionalCommits []conventionalCommit
+
+// Select partitions a collection of conventional commits by a given predicate.
+func (c conventionalCommits) Select(f func(conventionalCommit) bool) (conventionalCommits, conventionalCommits) {
+	selected := conventionalCommits{}
+	unselected := conventionalCommits{}
+
+	for _, commit := range c {
+		if f(commit) {
+			selected = append(selected, commit)
+		} else {
+			unselected = append(unselected, commit)
+		}
+	}
+
+	return selected, unselected
+}
+
+// parseConventionalCommit parses the message of a commit (i.e. feat(parser)!: support multi-line values).
+// A commit is a breaking change if its header has an exclamation mark or its footers have a BREAKING CHANGE token.
+func parseConventionalCommit(c remote.Commit) (conventionalCommit, bool) {
+	lines := strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)
+
+	sm := headerRegex.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if len(sm) != 5 {
+		return conventionalCommit{}, false
+	}
+
+	return conventionalCommit{
+		Hash:     c.Hash,
+		Time:     c.Time,
+		Type:     strings.ToLower(sm[1]),
+		Scope:    sm[2],
+		Subject:  sm[4],
+		Breaking: sm[3] == "!" || (len(lines) == 2 && breakingRegex.MatchString(lines[1])),
+	}, true
+}
+
+// parseAzureDevOpsPath parses the path of an Azure DevOps repository (i.e. octo/Octo%20Project/_git/changelog).
+// For Azure DevOps Server, the organization is the path to the project collection (i.e. tfs/DefaultCollection).
+func parseAzureDevOpsPath(path string) (string, string, string, error) {
+	err := errors.New("unexpected Azure DevOps repository: cannot parse organization, project, and repo")
+
+	i := strings.Index(path, "/_git/")
+	if i < 0 {
+		return "", "", "", err
+	}
+
+	j := strings.LastIndex(path[:i], "/")
+	if j < 0 {
+		return "", "", "", err
+	}
+
+	org := path[:j]
+	project, err1 := url.PathUnescape(path[j+1 : i])
+	repo, err2 := url.PathUnescape(path[i+len("/_git/"):])
+	if err1 != nil || err2 != nil {
+		return "", "", "", err
+	}
+
+	return org, project, repo, nil
+}
+
+// resolveFormat returns the format of the changelog file.
+// If no format is specified, it is resolved from the file extension and defaults to Markdown.
+func resolveFormat(g spec.General) spec.Format {
+	if g.Format != "" {
+		return g.Format
+	}
+
+	switch strings.ToLower(filepath.Ext(g.File)) {
+	case ".json":
+		return spec.FormatJSON
+	case ".yaml", ".yml":
+		return spec.FormatYAML
+	default:
+		return spec.FormatMarkdown
+	}
+}
+
+// toText converts Markdown release notes to plain text.
+// Links are replaced by their texts, unless a link is on its own line (i.e. [Compare Changes](https://...)) where the URL is kept.
+func toText(notes string) string {
+	notes = headingRegex.ReplaceAllString(notes, "")
+	notes = linkLineRegex.ReplaceAllString(notes, "$1: $2")
+	notes = linkRegex.ReplaceAllString(notes, "$1")
+	notes = strongRegex.ReplaceAllString(notes, "$1")
+	notes = codeRegex.ReplaceAllString(notes, "$1")
+
+	return html.UnescapeString(notes)
+}
+
 func filterByLabels(s spec.Spec, issues remote.Issues, merges remote.Merges) (remote.Issues, remote.Merges) {
 	switch s.Issues.Selection {
 	case spec.SelectionNone:
@@ -96,17 +246,301 @@ func filterByLabels(s spec.Spec, issues remote.Issues, merges remote.Merges) (re
 	return issues, merges
 }
 
+// resolveConventionalCommits returns the commits in a commit map following the Conventional Commits specification.
+// Commits are sorted from the most recent to the least recent.
+func resolveConventionalCommits(cm commitMap) conventionalCommits {
+	commits := conventionalCommits{}
+
+	for _, rev := range cm {
+		if c, ok := parseConventionalCommit(rev.Commit); ok {
+			commits = append(commits, c)
+		}
+	}
+
+	sort.Slice(commits, func(i, j int) bool {
+		if commits[i].Time.Equal(commits[j].Time) {
+			return commits[i].Hash < commits[j].Hash
+		}
+		return commits[i].Time.After(commits[j].Time)
+	})
+
+	return commits
+}
+
+// filterCommits selects the conventional commits with a type mapped to a label group.
+// Breaking changes are never filtered out.
+func filterCommits(s spec.Spec, commits conventionalCommits) conventionalCommits {
+	if s.Commits.Selection == spec.SelectionNone {
+		return conventionalCommits{}
+	}
+
+	types := []string{}
+	for _, group := range s.Commits.LabelGroups() {
+		types = append(types, group.Labels...)
+	}
+
+	commits, _ = commits.Select(func(c conventionalCommit) bool {
+		return c.Labels().Any(types...)
+	})
+
+	return commits
+}
+
+// matchPaths determines whether any of the files is in any of the paths.
+// A path is either a file or a directory with or without a trailing slash (i.e. api/ and api both match api/server.go).
+func matchPaths(files, paths []string) bool {
+	for _, f := range files {
+		for _, p := range paths {
+			if dir := strings.TrimSuffix(p, "/"); f == dir || strings.HasPrefix(f, dir+"/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseCalVer parses a tag name as a calendar version and returns its numeric components.
+func parseCalVer(tag string) ([]int, bool) {
+	sm := calverRegex.FindStringSubmatch(tag)
+	if len(sm) != 2 {
+		return nil, false
+	}
+
+	components := []int{}
+	for _, c := range strings.FieldsFunc(sm[1], func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	}) {
+		n, err := strconv.Atoi(c)
+		if err != nil {
+			return nil, false
+		}
+		components = append(components, n)
+	}
+
+	return components, true
+}
+
+// compareCalVer compares two calendar versions component by component.
+// A calendar version with more components is more recent if all other components are the same (i.e. 2020.10.1 after 2020.10).
+func compareCalVer(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sortTags sorts a list of tags from the most recent to the least recent by a given ordering.
+// For SemVer and CalVer orderings, the tags not following the versioning scheme are placed after all other tags.
+// For the tag-time ordering, the times of tags are the times they are created, so changes are also partitioned by them.
+func sortTags(tags remote.Tags, ordering spec.Ordering) remote.Tags {
+	switch ordering {
+	case spec.OrderingTagTime:
+		created := remote.Tags{}
+		for _, t := range tags {
+			if !t.Created.IsZero() {
+				t.Time = t.Created
+			}
+			created = append(created, t)
+		}
+		return created.Sort()
+
+	case spec.OrderingSemVer:
+		return tags.SortBy(func(t, u remote.Tag) bool {
+			v, ok1 := semver.Parse(t.Name)
+			w, ok2 := semver.Parse(u.Name)
+			if ok1 && ok2 {
+				return v.Compare(w) > 0
+			}
+			return ok1 && !ok2
+		})
+
+	case spec.OrderingCalVer:
+		return tags.SortBy(func(t, u remote.Tag) bool {
+			v, ok1 := parseCalVer(t.Name)
+			w, ok2 := parseCalVer(u.Name)
+			if ok1 && ok2 {
+				return compareCalVer(v, w) > 0
+			}
+			return ok1 && !ok2
+		})
+
+	default:
+		return tags.Sort()
+	}
+}
+
+// previousTag returns the tag that a given tag is compared against.
+// sortedTags are expected to be sorted from the most recent to the least recent.
+// The previous tag is the next tag in order created before the given tag.
+// When tags are sorted by versions, a release is not compared against a more recent release on another release line (i.e. v2.0.0 against a v1.4.3 hotfix).
+func previousTag(sortedTags remote.Tags, i int) (remote.Tag, bool) {
+	for _, t := range sortedTags[i+1:] {
+		// A future tag is always compared against the most recent tag
+		if sortedTags[i].Commit.IsZero() || !t.Time.After(sortedTags[i].Time) {
+			return t, true
+		}
+	}
+
+	return remote.Tag{}, false
+}
+
+// resolveSince returns the time since which issues and merges are fetched for a changelog.
+// It is the time of the tag before the least recent regenerated tag if any, otherwise the time of the last tag on changelog.
+// The zero time means since the beginning.
+func resolveSince(s spec.Tags, chlog *changelog.Changelog, sortedTags, allTags, regenerateTags remote.Tags) time.Time {
+	if len(regenerateTags) == 0 {
+		if len(chlog.Existing) > 0 {
+			return chlog.Existing[0].TagTime
+		}
+		return time.Time{}
+	}
+
+	last := regenerateTags[len(regenerateTags)-1].Name
+	if prev, ok := previousTag(sortedTags, sortedTags.Index(last)); ok {
+		return prev.Time
+	}
+
+	// The least recent included tag is compared against the previous tag not included (i.e. the tag a release line is branched off from)
+	if i := allTags.Index(last); s.IncludeRegex != "" && i >= 0 {
+		if prev, ok := previousTag(allTags, i); ok {
+			return prev.Time
+		}
+	}
+
+	return time.Time{}
+}
+
+// releaseName returns the name of a release on changelog for a git tag.
+// If the tag prefix is trimmed, a release is named without it (i.e. v1.3.0 for api/v1.3.0).
+func releaseName(s spec.Tags, tag string) string {
+	if s.TrimPrefix {
+		return strings.TrimPrefix(tag, s.Prefix)
+	}
+
+	return tag
+}
+
+// selectSpec returns the spec of the component or the release line a tag belongs to.
+// A component is selected by its tag prefix (the longest one if several match) and a release line by its tags regex.
+// A release line without any tags regex is selected only if no other one matches.
+func selectSpec(specs []spec.Spec, tag string) (spec.Spec, error) {
+	if len(specs) == 1 {
+		return specs[0], nil
+	}
+
+	selected := -1
+	fallback := -1
+
+	for i, s := range specs {
+		switch {
+		case s.Tags.Prefix != "":
+			if strings.HasPrefix(tag, s.Tags.Prefix) && (selected == -1 || len(s.Tags.Prefix) > len(specs[selected].Tags.Prefix)) {
+				selected = i
+			}
+
+		case s.Tags.IncludeRegex != "":
+			re, err := regexp.CompilePOSIX(s.Tags.IncludeRegex)
+			if err != nil {
+				return spec.Spec{}, err
+			}
+			if re.MatchString(tag) && selected == -1 {
+				selected = i
+			}
+
+		default:
+			if fallback == -1 {
+				fallback = i
+			}
+		}
+	}
+
+	if selected == -1 {
+		selected = fallback
+	}
+
+	if selected == -1 {
+		return spec.Spec{}, fmt.Errorf("no component or release line for tag %s", tag)
+	}
+
+	return specs[selected], nil
+}
+
+// groupTitles returns the titles of groups in the order they are rendered for a grouping style.
+func groupTitles(selection spec.Selection, grouping spec.Grouping, labelGroups []spec.LabelGroup, other string) []string {
+	if selection == spec.SelectionNone {
+		return nil
+	}
+
+	titles := []string{}
+
+	switch grouping {
+	case spec.GroupingMilestone:
+		titles = append(titles, "Milestone *")
+	case spec.GroupingLabel:
+		for _, group := range labelGroups {
+			titles = append(titles, group.Title)
+		}
+	}
+
+	return append(titles, other)
+}
+
+// parseOptions returns the options for parsing an existing changelog.
+// Release headings without the tag prefix are mapped to the tags with the prefix, unless the prefix is trimmed on changelog.
+// Groups of issues and merges are told apart by the titles of the configured groups.
+func parseOptions(s spec.Spec) changelog.ParseOptions {
+	opts := changelog.ParseOptions{
+		IssueGroups: groupTitles(s.Issues.Selection, s.Issues.Grouping, s.Issues.LabelGroups(), "Closed Issues"),
+		MergeGroups: groupTitles(s.Merges.Selection, s.Merges.Grouping, s.Merges.LabelGroups(), "Merged Changes"),
+	}
+
+	if !s.Tags.TrimPrefix {
+		opts.TagPrefix = s.Tags.Prefix
+	}
+
+	return opts
+}
+
+// tagName returns the git tag for a release on changelog.
+// It is the reverse of releaseName.
+func tagName(s spec.Tags, release string) string {
+	if s.TrimPrefix {
+		return s.Prefix + release
+	}
+
+	return release
+}
+
 // resolveIssueMap partitions a list of issues by tags.
 // It returns a map of tag names to issues.
 func resolveIssueMap(issues remote.Issues, sortedTags remote.Tags, futureTag remote.Tag) issueMap {
 	im := issueMap{}
 
 	for _, i := range issues {
-		// sortedTags are sorted from the most recent to the least recent
-		tag, ok := sortedTags.Last(func(tag remote.Tag) bool {
+		// An issue belongs to the earliest tag created after the issue was closed
+		// sortedTags are not necessarily sorted by their times (i.e. interleaved release lines sorted by versions)
+		var tag remote.Tag
+		var ok bool
+		for _, t := range sortedTags {
 			// If issue was closed before or at the time of tag
-			return i.Time.Before(tag.Time) || i.Time.Equal(tag.Time)
-		})
+			if !i.Time.After(t.Time) && (!ok || !t.Time.After(tag.Time)) {
+				tag, ok = t, true
+			}
+		}
 
 		if ok {
 			im[tag.Name] = append(im[tag.Name], i)
@@ -129,9 +563,8 @@ func resolveMergeMap(merges remote.Merges, cm commitMap, futureTag remote.Tag) m
 
 	for _, m := range merges {
 		if rev, ok := cm[m.Commit.Hash]; ok {
-			if len(rev.Tags) > 0 {
-				tagName := rev.Tags[len(rev.Tags)-1]
-				mm[tagName] = append(mm[tagName], m)
+			if rev.Tag != "" {
+				mm[rev.Tag] = append(mm[rev.Tag], m)
 			} else {
 				// The commit does not belong to any existing tag
 				// If there is a future tag, we should assign the merge to it
@@ -147,6 +580,97 @@ func resolveMergeMap(merges remote.Merges, cm commitMap, futureTag remote.Tag) m
 	return mm
 }
 
+// resolveConventionalMap partitions a list of conventional commits by tags.
+// It returns a map of tag names to conventional commits.
+func resolveConventionalMap(commits conventionalCommits, cm commitMap, futureTag remote.Tag) conventionalMap {
+	ccm := conventionalMap{}
+
+	for _, c := range commits {
+		if rev, ok := cm[c.Hash]; ok {
+			if rev.Tag != "" {
+				ccm[rev.Tag] = append(ccm[rev.Tag], c)
+			} else if futureTag.Commit.IsZero() {
+				// The commit does not belong to any existing tag
+				// If there is a future tag, we should assign the commit to it
+				ccm[futureTag.Name] = append(ccm[futureTag.Name], c)
+			}
+		}
+	}
+
+	return ccm
+}
+
+// labelBump returns the increment for a change with a list of labels.
+// Breaking changes increment the major version, features increment the minor version, and any other change increments the patch version.
+func labelBump(labels remote.Labels, breakingLabels, featureLabels []string) semver.Bump {
+	switch {
+	case labels.Any(breakingLabels...):
+		return semver.BumpMajor
+	case labels.Any(featureLabels...):
+		return semver.BumpMinor
+	default:
+		return semver.BumpPatch
+	}
+}
+
+// resolveBump determines the increment for the next version from the unreleased issues, merges, and conventional commits.
+// The breaking and feature label groups of each kind of change are used for deciding the increment.
+func resolveBump(s spec.Spec, issues remote.Issues, merges remote.Merges, commits conventionalCommits) semver.Bump {
+	bump := semver.BumpNone
+
+	for _, i := range issues {
+		if b := labelBump(i.Labels, s.Issues.BreakingLabels, s.Issues.FeatureLabels); b > bump {
+			bump = b
+		}
+	}
+
+	for _, m := range merges {
+		if b := labelBump(m.Labels, s.Merges.BreakingLabels, s.Merges.FeatureLabels); b > bump {
+			bump = b
+		}
+	}
+
+	// The breaking and feature groups of commits have the types mapped to them as labels
+	var breakingTypes, featureTypes []string
+	if types, err := s.Commits.Types(); err == nil {
+		breakingTypes = append([]string{"breaking"}, types["breaking"]...)
+		featureTypes = types["feature"]
+	}
+
+	for _, c := range commits {
+		if b := labelBump(c.Labels(), breakingTypes, featureTypes); b > bump {
+			bump = b
+		}
+	}
+
+	return bump
+}
+
+// resolveNextVersion returns the highest SemVer tag reachable from the branch and the next version after it for a given increment.
+// Tags not reachable from the branch (i.e. backports on a maintenance branch) are ignored even if they are more recent.
+// If there is no SemVer tag, the next version is computed from v0.0.0 with the tag prefix (i.e. api/v0.0.0 for a component).
+func resolveNextVersion(prefix string, sortedTags remote.Tags, cm commitMap, bump semver.Bump) (string, string) {
+	var latest remote.Tag
+	var version semver.Version
+
+	for _, tag := range sortedTags {
+		if rev, ok := cm[tag.Commit.Hash]; !ok || rev.Branch == "" {
+			continue
+		}
+
+		if v, ok := semver.Parse(tag.Name); ok && (latest.Name == "" || v.Compare(version) > 0) {
+			latest, version = tag, v
+		}
+	}
+
+	if latest.Name == "" {
+		v := semver.Version{Prefix: prefix + "v"}
+		return v.String(), v.Next(bump).String()
+	}
+
+	return latest.Name, version.Next(bump).String()
+}
+
 func toIssueGroup(title string, issues remote.Issues) changelog.IssueGroup {
 	issueGroup := changelog.IssueGroup{
 		Title: title,
@@ -157,6 +681,7 @@ func toIssueGroup(title string, issues remote.Issues) changelog.IssueGroup {
 			Number: i.Number,
 			Title:  i.Title,
 			URL:    i.WebURL,
+			Labels: i.Labels,
 			OpenedBy: changelog.User{
 				Name:     i.Author.Name,
 				Username: i.Author.Username,
@@ -183,6 +708,7 @@ func toMergeGroup(title string, merges remote.Merges) changelog.MergeGroup {
 			Number: m.Number,
 			Title:  m.Title,
 			URL:    m.WebURL,
+			Labels: m.Labels,
 			OpenedBy: changelog.User{
 				Name:     m.Author.Name,
 				Username: m.Author.Username,
@@ -198,3 +724,22 @@ func toMergeGroup(title string, merges remote.Merges) changelog.MergeGroup {
 
 	return mergeGroup
 }
+
+func toCommitGroup(title string, commits conventionalCommits, commitURL func(string) string) changelog.CommitGroup {
+	commitGroup := changelog.CommitGroup{
+		Title: title,
+	}
+
+	for _, c := range commits {
+		commitGroup.Commits = append(commitGroup.Commits, changelog.Commit{
+			Hash:     c.Hash,
+			Type:     c.Type,
+			Scope:    c.Scope,
+			Subject:  c.Subject,
+			URL:      commitURL(c.Hash),
+			Breaking: c.Breaking,
+		})
+	}
+
+	return commitGroup
+}
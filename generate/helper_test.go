@@ -6,10 +6,269 @@ import (
 
 	"github.com/moorara/changelog/internal/changelog"
 	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/internal/semver"
 	"github.com/moorara/changelog/spec"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestParseAzureDevOpsPath(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		expectedOrg   string
+		expectedProj  string
+		expectedRepo  string
+		expectedError string
+	}{
+		{
+			name:          "NoGitSegment",
+			path:          "octocat/hello-world",
+			expectedError: "unexpected Azure DevOps repository: cannot parse organization, project, and repo",
+		},
+		{
+			name:          "NoOrganization",
+			path:          "hello-world/_git/hello-world",
+			expectedError: "unexpected Azure DevOps repository: cannot parse organization, project, and repo",
+		},
+		{
+			name:          "InvalidEscape",
+			path:          "octocat/hello%zzworld/_git/hello-world",
+			expectedError: "unexpected Azure DevOps repository: cannot parse organization, project, and repo",
+		},
+		{
+			name:         "AzureDevOpsServices",
+			path:         "octocat/Hello%20World/_git/hello-world",
+			expectedOrg:  "octocat",
+			expectedProj: "Hello World",
+			expectedRepo: "hello-world",
+		},
+		{
+			name:         "AzureDevOpsServer",
+			path:         "tfs/DefaultCollection/Hello%20World/_git/hello-world",
+			expectedOrg:  "tfs/DefaultCollection",
+			expectedProj: "Hello World",
+			expectedRepo: "hello-world",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			org, project, repo, err := parseAzureDevOpsPath(tc.path)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedOrg, org)
+				assert.Equal(t, tc.expectedProj, project)
+				assert.Equal(t, tc.expectedRepo, repo)
+			}
+		})
+	}
+}
+
+func TestResolveFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		g              spec.General
+		expectedFormat spec.Format
+	}{
+		{
+			name:           "Explicit",
+			g:              spec.General{File: "CHANGELOG.md", Format: spec.FormatJSON},
+			expectedFormat: spec.FormatJSON,
+		},
+		{
+			name:           "Markdown",
+			g:              spec.General{File: "CHANGELOG.md"},
+			expectedFormat: spec.FormatMarkdown,
+		},
+		{
+			name:           "JSON",
+			g:              spec.General{File: "docs/CHANGELOG.JSON"},
+			expectedFormat: spec.FormatJSON,
+		},
+		{
+			name:           "YAML",
+			g:              spec.General{File: "CHANGELOG.yaml"},
+			expectedFormat: spec.FormatYAML,
+		},
+		{
+			name:           "YML",
+			g:              spec.General{File: "CHANGELOG.yml"},
+			expectedFormat: spec.FormatYAML,
+		},
+		{
+			name:           "NoExtension",
+			g:              spec.General{File: "CHANGELOG"},
+			expectedFormat: spec.FormatMarkdown,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedFormat, resolveFormat(tc.g))
+		})
+	}
+}
+
+func TestToText(t *testing.T) {
+	tests := []struct {
+		name         string
+		notes        string
+		expectedText string
+	}{
+		{
+			name:         "Empty",
+			notes:        "",
+			expectedText: "",
+		},
+		{
+			name: "Markdown",
+			notes: "https://storage.artifactory.com/project/releases/v0.1.0\n\n" +
+				"[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.0.1...v0.1.0)\n\n" +
+				"**Merged Changes:**\n\n" +
+				"  - Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))\n" +
+				"  - Fixed &lt;nil&gt; pointer dereference #1003 (octocat)",
+			expectedText: "https://storage.artifactory.com/project/releases/v0.1.0\n\n" +
+				"Compare Changes: https://github.com/octocat/Hello-World/compare/v0.0.1...v0.1.0\n\n" +
+				"Merged Changes:\n\n" +
+				"  - Add a feature #1002 (octocat, octodog)\n" +
+				"  - Fixed <nil> pointer dereference #1003 (octocat)",
+		},
+		{
+			name:         "KeepAChangelog",
+			notes:        "### Added\n\n- Support for `yaml` format\n- A **breaking** change",
+			expectedText: "Added\n\n- Support for yaml format\n- A breaking change",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedText, toText(tc.notes))
+		})
+	}
+}
+
+func TestConventionalCommit_Labels(t *testing.T) {
+	tests := []struct {
+		name           string
+		c              conventionalCommit
+		expectedLabels remote.Labels
+	}{
+		{
+			name:           "TypeOnly",
+			c:              conventionalCommit{Type: "fix"},
+			expectedLabels: remote.Labels{"fix"},
+		},
+		{
+			name:           "Breaking",
+			c:              conventionalCommit{Type: "feat", Scope: "parser", Breaking: true},
+			expectedLabels: remote.Labels{"feat", "breaking"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedLabels, tc.c.Labels())
+		})
+	}
+}
+
+func TestConventionalCommits_Select(t *testing.T) {
+	tests := []struct {
+		name               string
+		c                  conventionalCommits
+		f                  func(conventionalCommit) bool
+		expectedSelected   conventionalCommits
+		expectedUnselected conventionalCommits
+	}{
+		{
+			name: "OK",
+			c:    conventionalCommits{conventionalCommit1, conventionalCommit2},
+			f: func(c conventionalCommit) bool {
+				return c.Breaking
+			},
+			expectedSelected:   conventionalCommits{conventionalCommit2},
+			expectedUnselected: conventionalCommits{conventionalCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			selected, unselected := tc.c.Select(tc.f)
+
+			assert.Equal(t, tc.expectedSelected, selected)
+			assert.Equal(t, tc.expectedUnselected, unselected)
+		})
+	}
+}
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		c              remote.Commit
+		expectedOK     bool
+		expectedCommit conventionalCommit
+	}{
+		{
+			name:       "NoMessage",
+			c:          commit1,
+			expectedOK: false,
+		},
+		{
+			name:       "NotConventional",
+			c:          remote.Commit{Hash: "c414d1004154c6c324bd78c69d10ee101e676059", Message: "Merge pull request #1003 from octocat/feature"},
+			expectedOK: false,
+		},
+		{
+			name:       "WithScope",
+			c:          remote.Commit{Hash: "c414d1004154c6c324bd78c69d10ee101e676059", Time: t3, Message: "feat(parser): support multi-line values\n\nValues can span multiple lines.\n"},
+			expectedOK: true,
+			expectedCommit: conventionalCommit{
+				Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+				Time:    t3,
+				Type:    "feat",
+				Scope:   "parser",
+				Subject: "support multi-line values",
+			},
+		},
+		{
+			name:       "BreakingHeader",
+			c:          remote.Commit{Hash: "20c5414eccaa147f2d6644de4ca36f35293fa43e", Time: t4, Message: "Fix!: reject empty values"},
+			expectedOK: true,
+			expectedCommit: conventionalCommit{
+				Hash:     "20c5414eccaa147f2d6644de4ca36f35293fa43e",
+				Time:     t4,
+				Type:     "fix",
+				Subject:  "reject empty values",
+				Breaking: true,
+			},
+		},
+		{
+			name:       "BreakingFooter",
+			c:          remote.Commit{Hash: "20c5414eccaa147f2d6644de4ca36f35293fa43e", Time: t4, Message: "fix: reject empty values\r\n\r\nBREAKING-CHANGE: empty values are not allowed anymore\r\n"},
+			expectedOK: true,
+			expectedCommit: conventionalCommit{
+				Hash:     "20c5414eccaa147f2d6644de4ca36f35293fa43e",
+				Time:     t4,
+				Type:     "fix",
+				Subject:  "reject empty values",
+				Breaking: true,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := parseConventionalCommit(tc.c)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedCommit, c)
+		})
+	}
+}
+
 func TestFilterByLabels(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -114,6 +373,511 @@ func TestFilterByLabels(t *testing.T) {
 	}
 }
 
+func TestResolveConventionalCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		commitMap       commitMap
+		expectedCommits conventionalCommits
+	}{
+		{
+			name: "OK",
+			commitMap: commitMap{
+				"20c5414eccaa147f2d6644de4ca36f35293fa43e": &revisions{
+					Commit: remote.Commit{Hash: "20c5414eccaa147f2d6644de4ca36f35293fa43e", Time: t4, Message: "fix!: reject empty values"},
+				},
+				"c414d1004154c6c324bd78c69d10ee101e676059": &revisions{
+					Commit: remote.Commit{Hash: "c414d1004154c6c324bd78c69d10ee101e676059", Time: t3, Message: "feat(parser): support multi-line values"},
+				},
+				"25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378": &revisions{
+					Commit: commit1,
+				},
+			},
+			expectedCommits: conventionalCommits{conventionalCommit2, conventionalCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commits := resolveConventionalCommits(tc.commitMap)
+
+			assert.Equal(t, tc.expectedCommits, commits)
+		})
+	}
+}
+
+func TestFilterCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		s               spec.Spec
+		commits         conventionalCommits
+		expectedCommits conventionalCommits
+	}{
+		{
+			name: "None",
+			s: spec.Spec{
+				Commits: spec.Commits{
+					Selection: spec.SelectionNone,
+				},
+			},
+			commits:         conventionalCommits{conventionalCommit1, conventionalCommit2},
+			expectedCommits: conventionalCommits{},
+		},
+		{
+			name: "AllWithGroups",
+			s: spec.Spec{
+				Commits: spec.Commits{
+					Selection: spec.SelectionAll,
+					Groups:    []string{"feat:feature", "fix:bug"},
+				},
+			},
+			commits:         conventionalCommits{conventionalCommit1, conventionalCommit2},
+			expectedCommits: conventionalCommits{conventionalCommit1, conventionalCommit2},
+		},
+		{
+			name: "AllWithoutGroups",
+			s: spec.Spec{
+				Commits: spec.Commits{
+					Selection: spec.SelectionAll,
+				},
+			},
+			commits:         conventionalCommits{conventionalCommit1, conventionalCommit2},
+			expectedCommits: conventionalCommits{conventionalCommit2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commits := filterCommits(tc.s, tc.commits)
+
+			assert.Equal(t, tc.expectedCommits, commits)
+		})
+	}
+}
+
+func TestMatchPaths(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         []string
+		paths         []string
+		expectedMatch bool
+	}{
+		{
+			name:          "NoFile",
+			files:         []string{},
+			paths:         []string{"api/"},
+			expectedMatch: false,
+		},
+		{
+			name:          "Directory",
+			files:         []string{"web/app.js", "api/server.go"},
+			paths:         []string{"api/"},
+			expectedMatch: true,
+		},
+		{
+			name:          "DirectoryWithoutSlash",
+			files:         []string{"api/v1/server.go"},
+			paths:         []string{"api"},
+			expectedMatch: true,
+		},
+		{
+			name:          "File",
+			files:         []string{"go.mod"},
+			paths:         []string{"api/", "go.mod"},
+			expectedMatch: true,
+		},
+		{
+			name:          "SimilarDirectory",
+			files:         []string{"api-docs/index.md"},
+			paths:         []string{"api"},
+			expectedMatch: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedMatch, matchPaths(tc.files, tc.paths))
+		})
+	}
+}
+
+func TestParseCalVer(t *testing.T) {
+	tests := []struct {
+		name               string
+		tag                string
+		expectedOK         bool
+		expectedComponents []int
+	}{
+		{
+			name:       "Invalid",
+			tag:        "latest",
+			expectedOK: false,
+		},
+		{
+			name:       "SemVer",
+			tag:        "v1.2.3",
+			expectedOK: false,
+		},
+		{
+			name:               "FullYear",
+			tag:                "2020.10.1",
+			expectedOK:         true,
+			expectedComponents: []int{2020, 10, 1},
+		},
+		{
+			name:               "ShortYear",
+			tag:                "v20.10",
+			expectedOK:         true,
+			expectedComponents: []int{20, 10},
+		},
+		{
+			name:               "Date",
+			tag:                "release-2020-10-22",
+			expectedOK:         true,
+			expectedComponents: []int{2020, 10, 22},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			components, ok := parseCalVer(tc.tag)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedComponents, components)
+		})
+	}
+}
+
+func TestCompareCalVer(t *testing.T) {
+	tests := []struct {
+		name           string
+		a, b           []int
+		expectedResult int
+	}{
+		{
+			name:           "Equal",
+			a:              []int{2020, 10, 1},
+			b:              []int{2020, 10, 1},
+			expectedResult: 0,
+		},
+		{
+			name:           "Before",
+			a:              []int{2020, 9, 3},
+			b:              []int{2020, 10, 1},
+			expectedResult: -1,
+		},
+		{
+			name:           "After",
+			a:              []int{2020, 10, 2},
+			b:              []int{2020, 10, 1},
+			expectedResult: 1,
+		},
+		{
+			name:           "MoreComponents",
+			a:              []int{2020, 10, 1},
+			b:              []int{2020, 10},
+			expectedResult: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedResult, compareCalVer(tc.a, tc.b))
+		})
+	}
+}
+
+func TestSortTags(t *testing.T) {
+	// v2.0.0 is released on the main branch after v1.4.2,
+	// and then a hotfix v1.4.3 is released on a maintenance branch.
+	v142 := remote.Tag{Name: "v1.4.2", Time: t1, Commit: commit1}
+	v200 := remote.Tag{Name: "v2.0.0", Time: t2, Created: t4, Commit: commit2}
+	v143 := remote.Tag{Name: "v1.4.3", Time: t3, Commit: commit3}
+	nightly := remote.Tag{Name: "nightly", Time: t3.Add(time.Hour), Commit: commit3}
+
+	// 2020.10.1 is released after 2020.9.2, and then a hotfix 2020.9.3 is released.
+	cv092 := remote.Tag{Name: "2020.9.2", Time: t1, Commit: commit1}
+	cv101 := remote.Tag{Name: "2020.10.1", Time: t2, Commit: commit2}
+	cv093 := remote.Tag{Name: "2020.9.3", Time: t3, Commit: commit3}
+
+	tests := []struct {
+		name         string
+		tags         remote.Tags
+		ordering     spec.Ordering
+		expectedTags remote.Tags
+	}{
+		{
+			name:         "CommitTime",
+			tags:         remote.Tags{v142, v200, v143, nightly},
+			ordering:     spec.OrderingCommitTime,
+			expectedTags: remote.Tags{nightly, v143, v200, v142},
+		},
+		{
+			name:     "TagTime",
+			tags:     remote.Tags{v142, v200, v143, nightly},
+			ordering: spec.OrderingTagTime,
+			expectedTags: remote.Tags{
+				{Name: "v2.0.0", Time: t4, Created: t4, Commit: commit2},
+				nightly, v143, v142,
+			},
+		},
+		{
+			name:         "SemVer",
+			tags:         remote.Tags{v142, v200, v143, nightly},
+			ordering:     spec.OrderingSemVer,
+			expectedTags: remote.Tags{v200, v143, v142, nightly},
+		},
+		{
+			name:         "CalVer",
+			tags:         remote.Tags{cv092, cv101, cv093, nightly},
+			ordering:     spec.OrderingCalVer,
+			expectedTags: remote.Tags{cv101, cv093, cv092, nightly},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sortedTags := sortTags(tc.tags, tc.ordering)
+
+			assert.Equal(t, tc.expectedTags, sortedTags)
+		})
+	}
+}
+
+func TestPreviousTag(t *testing.T) {
+	futureTag := remote.Tag{Name: "v2.1.0"}
+	v142 := remote.Tag{Name: "v1.4.2", Time: t1, Commit: commit1}
+	v200 := remote.Tag{Name: "v2.0.0", Time: t2, Commit: commit2}
+	v143 := remote.Tag{Name: "v1.4.3", Time: t3, Commit: commit3}
+
+	tests := []struct {
+		name        string
+		sortedTags  remote.Tags
+		i           int
+		expectedOK  bool
+		expectedTag remote.Tag
+	}{
+		{
+			name:        "FutureTag",
+			sortedTags:  remote.Tags{futureTag, v200, v143, v142},
+			i:           0,
+			expectedOK:  true,
+			expectedTag: v200,
+		},
+		{
+			name:        "SameReleaseLine",
+			sortedTags:  remote.Tags{v200, v143, v142},
+			i:           1,
+			expectedOK:  true,
+			expectedTag: v142,
+		},
+		{
+			name:        "InterleavedReleaseLines",
+			sortedTags:  remote.Tags{v200, v143, v142},
+			i:           0,
+			expectedOK:  true,
+			expectedTag: v142,
+		},
+		{
+			name:       "LeastRecent",
+			sortedTags: remote.Tags{v200, v143, v142},
+			i:          2,
+			expectedOK: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tag, ok := previousTag(tc.sortedTags, tc.i)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestReleaseName(t *testing.T) {
+	tests := []struct {
+		name            string
+		s               spec.Tags
+		tag             string
+		expectedRelease string
+	}{
+		{
+			name:            "NoPrefix",
+			s:               spec.Tags{},
+			tag:             "v1.3.0",
+			expectedRelease: "v1.3.0",
+		},
+		{
+			name:            "Prefix",
+			s:               spec.Tags{Prefix: "api/"},
+			tag:             "api/v1.3.0",
+			expectedRelease: "api/v1.3.0",
+		},
+		{
+			name:            "TrimPrefix",
+			s:               spec.Tags{Prefix: "api/", TrimPrefix: true},
+			tag:             "api/v1.3.0",
+			expectedRelease: "v1.3.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			release := releaseName(tc.s, tc.tag)
+
+			assert.Equal(t, tc.expectedRelease, release)
+			assert.Equal(t, tc.tag, tagName(tc.s, release))
+		})
+	}
+}
+
+func TestSelectSpec(t *testing.T) {
+	components := []spec.Spec{
+		{General: spec.General{File: "api/CHANGELOG.md"}, Tags: spec.Tags{Prefix: "api/"}},
+		{General: spec.General{File: "api/v2/CHANGELOG.md"}, Tags: spec.Tags{Prefix: "api/v2/"}},
+		{General: spec.General{File: "web/CHANGELOG.md"}, Tags: spec.Tags{Prefix: "web/"}},
+	}
+
+	lines := []spec.Spec{
+		{General: spec.General{File: "CHANGELOG.md"}},
+		{General: spec.General{File: "CHANGELOG-1.x.md"}, Tags: spec.Tags{IncludeRegex: `^v1\.`}},
+	}
+
+	tests := []struct {
+		name          string
+		specs         []spec.Spec
+		tag           string
+		expectedFile  string
+		expectedError string
+	}{
+		{
+			name:         "OneSpec",
+			specs:        []spec.Spec{{General: spec.General{File: "CHANGELOG.md"}}},
+			tag:          "v0.1.0",
+			expectedFile: "CHANGELOG.md",
+		},
+		{
+			name:         "Component",
+			specs:        components,
+			tag:          "web/v0.1.0",
+			expectedFile: "web/CHANGELOG.md",
+		},
+		{
+			name:         "LongestPrefix",
+			specs:        components,
+			tag:          "api/v2/v2.0.0",
+			expectedFile: "api/v2/CHANGELOG.md",
+		},
+		{
+			name:          "NoComponent",
+			specs:         components,
+			tag:           "cli/v0.1.0",
+			expectedError: "no component or release line for tag cli/v0.1.0",
+		},
+		{
+			name:         "Line",
+			specs:        lines,
+			tag:          "v1.4.4",
+			expectedFile: "CHANGELOG-1.x.md",
+		},
+		{
+			name:         "DefaultLine",
+			specs:        lines,
+			tag:          "v2.0.0",
+			expectedFile: "CHANGELOG.md",
+		},
+		{
+			name: "InvalidRegex",
+			specs: []spec.Spec{
+				{General: spec.General{File: "CHANGELOG.md"}, Tags: spec.Tags{IncludeRegex: `^v2\.`}},
+				{General: spec.General{File: "CHANGELOG-1.x.md"}, Tags: spec.Tags{IncludeRegex: `^v1\.[`}},
+			},
+			tag:           "v1.4.4",
+			expectedError: "error parsing regexp: missing closing ]: `[`",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := selectSpec(tc.specs, tc.tag)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFile, s.General.File)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		s            spec.Spec
+		expectedOpts changelog.ParseOptions
+	}{
+		{
+			name: "NoGroups",
+			s: spec.Spec{
+				Issues: spec.Issues{Selection: spec.SelectionNone},
+				Merges: spec.Merges{Selection: spec.SelectionNone},
+			},
+			expectedOpts: changelog.ParseOptions{},
+		},
+		{
+			name: "Prefix",
+			s: spec.Spec{
+				Tags:   spec.Tags{Prefix: "v"},
+				Issues: spec.Issues{Selection: spec.SelectionAll, Grouping: spec.GroupingSimple},
+				Merges: spec.Merges{Selection: spec.SelectionNone},
+			},
+			expectedOpts: changelog.ParseOptions{
+				TagPrefix:   "v",
+				IssueGroups: []string{"Closed Issues"},
+			},
+		},
+		{
+			name: "TrimPrefix",
+			s: spec.Spec{
+				Tags:   spec.Tags{Prefix: "api/", TrimPrefix: true},
+				Issues: spec.Issues{Selection: spec.SelectionNone},
+				Merges: spec.Merges{Selection: spec.SelectionAll, Grouping: spec.GroupingMilestone},
+			},
+			expectedOpts: changelog.ParseOptions{
+				MergeGroups: []string{"Milestone *", "Merged Changes"},
+			},
+		},
+		{
+			name: "LabelGroups",
+			s: spec.Spec{
+				Issues: spec.Issues{
+					Selection:      spec.SelectionAll,
+					Grouping:       spec.GroupingLabel,
+					BugLabels:      []string{"bug"},
+					SecurityLabels: []string{"security"},
+				},
+				Merges: spec.Merges{
+					Selection:     spec.SelectionLabeled,
+					Grouping:      spec.GroupingLabel,
+					FeatureLabels: []string{"feature"},
+				},
+			},
+			expectedOpts: changelog.ParseOptions{
+				IssueGroups: []string{"Fixed Bugs", "Security Fixes", "Closed Issues"},
+				MergeGroups: []string{"New Features", "Merged Changes"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOpts, parseOptions(tc.s))
+		})
+	}
+}
+
 func TestResolveIssueMap(t *testing.T) {
 	futureTag := remote.Tag{
 		Name: "v0.1.4",
@@ -137,6 +901,20 @@ func TestResolveIssueMap(t *testing.T) {
 				"v0.1.3": remote.Issues{issue1},
 			},
 		},
+		{
+			name:   "InterleavedReleaseLines",
+			issues: remote.Issues{issue1, issue2},
+			sortedTags: remote.Tags{
+				{Name: "v2.0.0", Time: t3, Commit: commit3},
+				{Name: "v1.4.3", Time: t4, Commit: commit4},
+				{Name: "v1.4.2", Time: t2, Commit: commit2},
+			},
+			futureTag: futureTag,
+			expectedIssueMap: issueMap{
+				"v2.0.0": remote.Issues{issue1},
+				"v1.4.3": remote.Issues{issue2},
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -159,15 +937,15 @@ func TestResolveMergeMap(t *testing.T) {
 		},
 		"c414d1004154c6c324bd78c69d10ee101e676059": &revisions{
 			Branch: "main",
-			Tags:   []string{"v0.1.3"},
+			Tag:    "v0.1.3",
 		},
 		"0251a422d2038967eeaaaa5c8aa76c7067fdef05": &revisions{
 			Branch: "main",
-			Tags:   []string{"v0.1.3", "v0.1.2"},
+			Tag:    "v0.1.2",
 		},
 		"25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378": &revisions{
 			Branch: "main",
-			Tags:   []string{"v0.1.3", "v0.1.2", "v0.1.1"},
+			Tag:    "v0.1.1",
 		},
 	}
 
@@ -199,6 +977,210 @@ func TestResolveMergeMap(t *testing.T) {
 	}
 }
 
+func TestResolveConventionalMap(t *testing.T) {
+	futureTag := remote.Tag{
+		Name: "v0.1.4",
+	}
+
+	cm := commitMap{
+		"20c5414eccaa147f2d6644de4ca36f35293fa43e": &revisions{
+			Branch: "main",
+		},
+		"c414d1004154c6c324bd78c69d10ee101e676059": &revisions{
+			Branch: "main",
+			Tag:    "v0.1.3",
+		},
+	}
+
+	tests := []struct {
+		name                    string
+		commits                 conventionalCommits
+		commitMap               commitMap
+		futureTag               remote.Tag
+		expectedConventionalMap conventionalMap
+	}{
+		{
+			name:      "OK",
+			commits:   conventionalCommits{conventionalCommit2, conventionalCommit1},
+			commitMap: cm,
+			futureTag: futureTag,
+			expectedConventionalMap: conventionalMap{
+				"v0.1.4": conventionalCommits{conventionalCommit2},
+				"v0.1.3": conventionalCommits{conventionalCommit1},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conventionalMap := resolveConventionalMap(tc.commits, tc.commitMap, tc.futureTag)
+
+			assert.Equal(t, tc.expectedConventionalMap, conventionalMap)
+		})
+	}
+}
+
+func TestResolveBump(t *testing.T) {
+	s := spec.Spec{
+		Issues: spec.Issues{
+			BreakingLabels: []string{"breaking"},
+			FeatureLabels:  []string{"feature"},
+		},
+		Merges: spec.Merges{
+			BreakingLabels: []string{"breaking"},
+			FeatureLabels:  []string{"enhancement"},
+		},
+		Commits: spec.Commits{
+			Groups: []string{"feat:feature", "fix:bug"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		issues       remote.Issues
+		merges       remote.Merges
+		commits      conventionalCommits
+		expectedBump semver.Bump
+	}{
+		{
+			name:         "NoChange",
+			expectedBump: semver.BumpNone,
+		},
+		{
+			name:         "Patch",
+			issues:       remote.Issues{issue1, issue2},
+			expectedBump: semver.BumpPatch,
+		},
+		{
+			name:         "Minor",
+			issues:       remote.Issues{issue1},
+			merges:       remote.Merges{merge1, merge2},
+			expectedBump: semver.BumpMinor,
+		},
+		{
+			name:         "Major",
+			merges:       remote.Merges{merge1},
+			commits:      conventionalCommits{conventionalCommit1, conventionalCommit2},
+			expectedBump: semver.BumpMajor,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bump := resolveBump(s, tc.issues, tc.merges, tc.commits)
+
+			assert.Equal(t, tc.expectedBump, bump)
+		})
+	}
+}
+
+func TestResolveNextVersion(t *testing.T) {
+	backport := remote.Tag{Name: "v1.4.3", Commit: remote.Commit{Hash: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c"}}
+	major := remote.Tag{Name: "v2.0.0", Commit: remote.Commit{Hash: "20c5414eccaa147f2d6644de4ca36f35293fa43e"}}
+	minor := remote.Tag{Name: "v1.4.2", Commit: remote.Commit{Hash: "c414d1004154c6c324bd78c69d10ee101e676059"}}
+
+	tests := []struct {
+		name                string
+		prefix              string
+		sortedTags          remote.Tags
+		commitMap           commitMap
+		bump                semver.Bump
+		expectedLatest      string
+		expectedNextVersion string
+	}{
+		{
+			name:                "NoTag",
+			sortedTags:          remote.Tags{},
+			commitMap:           commitMap{},
+			bump:                semver.BumpMinor,
+			expectedLatest:      "v0.0.0",
+			expectedNextVersion: "v0.1.0",
+		},
+		{
+			name:                "NoTagWithPrefix",
+			prefix:              "api/",
+			sortedTags:          remote.Tags{},
+			commitMap:           commitMap{},
+			bump:                semver.BumpMinor,
+			expectedLatest:      "api/v0.0.0",
+			expectedNextVersion: "api/v0.1.0",
+		},
+		{
+			name: "SkipNonSemVerTags",
+			sortedTags: remote.Tags{
+				{Name: "nightly", Commit: commit3},
+				{Name: "v1.2.0-rc.1", Commit: commit2},
+				{Name: "v1.1.0", Commit: commit1},
+			},
+			commitMap: commitMap{
+				commit1.Hash: &revisions{Commit: commit1, Branch: "main"},
+				commit2.Hash: &revisions{Commit: commit2, Branch: "main"},
+				commit3.Hash: &revisions{Commit: commit3, Branch: "main"},
+			},
+			bump:                semver.BumpPatch,
+			expectedLatest:      "v1.2.0-rc.1",
+			expectedNextVersion: "v1.2.0-rc.2",
+		},
+		{
+			name:       "OK",
+			sortedTags: remote.Tags{tag3, tag2, tag1},
+			commitMap: commitMap{
+				commit1.Hash: &revisions{Commit: commit1, Branch: "main", Tag: "v0.1.1"},
+				commit2.Hash: &revisions{Commit: commit2, Branch: "main", Tag: "v0.1.2"},
+				commit3.Hash: &revisions{Commit: commit3, Branch: "main", Tag: "v0.1.3"},
+			},
+			bump:                semver.BumpMajor,
+			expectedLatest:      "v0.1.3",
+			expectedNextVersion: "v0.2.0",
+		},
+		{
+			name:       "HighestVersion",
+			sortedTags: remote.Tags{backport, major, minor},
+			commitMap: commitMap{
+				backport.Commit.Hash: &revisions{Branch: "main", Tag: "v1.4.3"},
+				major.Commit.Hash:    &revisions{Branch: "main", Tag: "v2.0.0"},
+				minor.Commit.Hash:    &revisions{Branch: "main", Tag: "v1.4.2"},
+			},
+			bump:                semver.BumpMinor,
+			expectedLatest:      "v2.0.0",
+			expectedNextVersion: "v2.1.0",
+		},
+		{
+			name:       "BackportNotOnBranch",
+			sortedTags: remote.Tags{backport, major, minor},
+			commitMap: commitMap{
+				backport.Commit.Hash: &revisions{Tag: "v1.4.3"},
+				major.Commit.Hash:    &revisions{Branch: "main", Tag: "v2.0.0"},
+				minor.Commit.Hash:    &revisions{Branch: "main", Tag: "v1.4.2"},
+			},
+			bump:                semver.BumpPatch,
+			expectedLatest:      "v2.0.0",
+			expectedNextVersion: "v2.0.1",
+		},
+		{
+			name:       "MaintenanceBranch",
+			sortedTags: remote.Tags{backport, major, minor},
+			commitMap: commitMap{
+				backport.Commit.Hash: &revisions{Branch: "release-1.4", Tag: "v1.4.3"},
+				major.Commit.Hash:    &revisions{Tag: "v2.0.0"},
+				minor.Commit.Hash:    &revisions{Branch: "release-1.4", Tag: "v1.4.2"},
+			},
+			bump:                semver.BumpPatch,
+			expectedLatest:      "v1.4.3",
+			expectedNextVersion: "v1.4.4",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			latest, next := resolveNextVersion(tc.prefix, tc.sortedTags, tc.commitMap, tc.bump)
+
+			assert.Equal(t, tc.expectedLatest, latest)
+			assert.Equal(t, tc.expectedNextVersion, next)
+		})
+	}
+}
+
 func TestToIssueGroup(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -252,3 +1234,34 @@ func TestToMergeGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestToCommitGroup(t *testing.T) {
+	tests := []struct {
+		name                string
+		title               string
+		commits             conventionalCommits
+		commitURL           func(string) string
+		expectedCommitGroup changelog.CommitGroup
+	}{
+		{
+			name:    "OK",
+			title:   "Fixed Bugs",
+			commits: conventionalCommits{conventionalCommit1, conventionalCommit2},
+			commitURL: func(hash string) string {
+				return "https://github.com/octocat/Hello-World/commit/" + hash
+			},
+			expectedCommitGroup: changelog.CommitGroup{
+				Title:   "Fixed Bugs",
+				Commits: []changelog.Commit{changelogCommit1, changelogCommit2},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commitGroup := toCommitGroup(tc.title, tc.commits, tc.commitURL)
+
+			assert.Equal(t, tc.expectedCommitGroup, commitGroup)
+		})
+	}
+}
@@ -9,13 +9,25 @@ type Processor interface {
 }
 
 // ParseOptions determines how a changelog file should be parsed.
-type ParseOptions struct{}
+type ParseOptions struct {
+	// TagPrefix is the prefix of git tags that can be left out of release headings (i.e. v for a 1.2.0 heading of the v1.2.0 tag).
+	TagPrefix string
+	// IssueGroups are the titles of issue groups in the order they are rendered.
+	// If there is no title, all groups of issues and merges are parsed as merge groups.
+	// A title ending with * matches any title with the same prefix (i.e. Milestone * for milestone groups).
+	IssueGroups []string
+	// MergeGroups are the titles of merge groups in the order they are rendered.
+	// If there is no title, all groups of issues and merges are parsed as issue groups.
+	MergeGroups []string
+}
 
 // Changelog represents the entire changelog of a repository.
 type Changelog struct {
 	Title    string
 	New      []Release
 	Existing []Release
+	// Regenerated are existing releases that are generated again and replace the existing ones with the same tag names in place.
+	Regenerated []Release
 }
 
 // Release represents a single release of a repository in a changelog.
@@ -27,6 +39,12 @@ type Release struct {
 	CompareURL  string
 	IssueGroups []IssueGroup
 	MergeGroups []MergeGroup
+	// CommitGroups are the groups of commits following the Conventional Commits specification.
+	CommitGroups []CommitGroup
+	// Unreleased is true for a future tag that has not been created yet.
+	Unreleased bool
+	// Notes is the content of an existing release in a Markdown changelog without the release heading.
+	Notes string
 }
 
 // IssueGroup represents a group of issues.
@@ -40,6 +58,7 @@ type Issue struct {
 	Number   int
 	Title    string
 	URL      string
+	Labels   []string
 	OpenedBy User
 	ClosedBy User
 }
@@ -55,10 +74,27 @@ type Merge struct {
 	Number   int
 	Title    string
 	URL      string
+	Labels   []string
 	OpenedBy User
 	MergedBy User
 }
 
+// CommitGroup represents a group of commits.
+type CommitGroup struct {
+	Title   string
+	Commits []Commit
+}
+
+// Commit represents a single commit following the Conventional Commits specification (https://www.conventionalcommits.org).
+type Commit struct {
+	Hash     string
+	Type     string
+	Scope    string
+	Subject  string
+	URL      string
+	Breaking bool
+}
+
 // User represents a user.
 type User struct {
 	Name     string
@@ -72,3 +108,30 @@ func NewChangelog() *Changelog {
 		Title: "Changelog",
 	}
 }
+
+// Releases returns the new releases followed by the existing releases.
+// Existing releases are replaced by the regenerated releases with the same tag names.
+func (c *Changelog) Releases() []Release {
+	releases := append([]Release{}, c.New...)
+
+	for _, e := range c.Existing {
+		for _, r := range c.Regenerated {
+			if r.TagName == e.TagName {
+				e = r
+				break
+			}
+		}
+		releases = append(releases, e)
+	}
+
+	return releases
+}
+
+// ShortHash returns the abbreviated hash of a commit.
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+
+	return c.Hash
+}
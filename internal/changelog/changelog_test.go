@@ -14,3 +14,69 @@ func TestNewChangelog(t *testing.T) {
 	assert.Len(t, changelog.New, 0)
 	assert.Len(t, changelog.Existing, 0)
 }
+
+func TestChangelog_Releases(t *testing.T) {
+	tests := []struct {
+		name             string
+		c                *Changelog
+		expectedReleases []Release
+	}{
+		{
+			name:             "Empty",
+			c:                &Changelog{},
+			expectedReleases: []Release{},
+		},
+		{
+			name: "NewAndExisting",
+			c: &Changelog{
+				New:      []Release{{TagName: "v0.1.2"}},
+				Existing: []Release{{TagName: "v0.1.1"}, {TagName: "v0.1.0"}},
+			},
+			expectedReleases: []Release{{TagName: "v0.1.2"}, {TagName: "v0.1.1"}, {TagName: "v0.1.0"}},
+		},
+		{
+			name: "Regenerated",
+			c: &Changelog{
+				New:         []Release{{TagName: "v0.1.2"}},
+				Existing:    []Release{{TagName: "v0.1.1"}, {TagName: "v0.1.0"}},
+				Regenerated: []Release{{TagName: "v0.1.1", CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1"}},
+			},
+			expectedReleases: []Release{
+				{TagName: "v0.1.2"},
+				{TagName: "v0.1.1", CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1"},
+				{TagName: "v0.1.0"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedReleases, tc.c.Releases())
+		})
+	}
+}
+
+func TestCommit_ShortHash(t *testing.T) {
+	tests := []struct {
+		name              string
+		c                 Commit
+		expectedShortHash string
+	}{
+		{
+			name:              "FullHash",
+			c:                 Commit{Hash: "c414d1004154c6c324bd78c69d10ee101e676059"},
+			expectedShortHash: "c414d10",
+		},
+		{
+			name:              "ShortHash",
+			c:                 Commit{Hash: "c414d"},
+			expectedShortHash: "c414d",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedShortHash, tc.c.ShortHash())
+		})
+	}
+}
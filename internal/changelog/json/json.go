@@ -0,0 +1,127 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/moorara/changelog/internal/changelog"
+	"github.com/moorara/changelog/internal/changelog/schema"
+	"github.com/moorara/changelog/log"
+)
+
+func encode(doc schema.Document) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decode(filename string) (*schema.Document, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := new(schema.Document)
+	if err := json.Unmarshal(b, doc); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+
+	return doc, nil
+}
+
+// processor implements the changelog.Processor interface for JSON format.
+type processor struct {
+	logger        log.Logger
+	baseFile      string
+	changelogFile string
+}
+
+// NewProcessor creates a new changelog processor for JSON format.
+func NewProcessor(logger log.Logger, baseFile, changelogFile string) changelog.Processor {
+	return &processor{
+		logger:        logger,
+		baseFile:      baseFile,
+		changelogFile: changelogFile,
+	}
+}
+
+func (p *processor) createChangelog() (*changelog.Changelog, error) {
+	chlog := changelog.NewChangelog()
+
+	p.logger.Warnf("%s not found", p.changelogFile)
+	p.logger.Info("A new changelog is created.")
+
+	return chlog, nil
+}
+
+func (p *processor) Parse(opts changelog.ParseOptions) (*changelog.Changelog, error) {
+	p.logger.Debugf("Parsing %s ...", p.changelogFile)
+
+	doc, err := decode(p.changelogFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p.createChangelog()
+		}
+		return nil, err
+	}
+
+	chlog := &changelog.Changelog{
+		Title:    doc.Title,
+		Existing: doc.ChangelogReleases(),
+	}
+
+	p.logger.Infof("Successfully parsed %s", p.changelogFile)
+
+	return chlog, nil
+}
+
+// Render writes all releases of the changelog to the changelog file, and returns a JSON document with the new releases only.
+func (p *processor) Render(chlog *changelog.Changelog) (string, error) {
+	p.logger.Debug("Updating the changelog ...")
+
+	releases := chlog.Releases()
+
+	// Add the releases of an optional base file if generating the changelog for the first time
+	if len(chlog.Existing) == 0 && p.baseFile != "" {
+		p.logger.Infof("Adding the base file releases to the changelog: %s", p.baseFile)
+
+		base, err := decode(p.baseFile)
+		if err != nil {
+			return "", err
+		}
+
+		releases = append(releases, base.ChangelogReleases()...)
+	}
+
+	b, err := encode(schema.NewDocument(chlog.Title, releases))
+	if err != nil {
+		return "", err
+	}
+
+	if err := ioutil.WriteFile(p.changelogFile, b, 0644); err != nil {
+		return "", err
+	}
+
+	newContent, err := encode(schema.NewDocument(chlog.Title, append(append([]changelog.Release{}, chlog.New...), chlog.Regenerated...)))
+	if err != nil {
+		return "", err
+	}
+
+	p.logger.Infof("Successfully updated the changelog: %s", p.changelogFile)
+
+	return string(newContent), nil
+}
@@ -0,0 +1,533 @@
+package keepachangelog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/moorara/changelog/internal/changelog"
+	"github.com/moorara/changelog/log"
+)
+
+const (
+	timeLayout = "2006-01-02"
+	unreleased = "Unreleased"
+	// The release summary is not a section and is listed right under the release heading.
+	summaryTitle = "Release Summary"
+)
+
+const emptyTemplate = `# {{.Title}}
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+*This changelog is automatically generated by [changelog](https://github.com/moorara/changelog)*
+
+`
+
+const changelogTemplate = `{{range .}}## [{{.Name}}]{{if .Date}} - {{.Date}}{{end}}
+{{if .ReleaseURL}}
+{{.ReleaseURL}}
+{{end}}{{if .Summary}}
+{{range .Summary}}{{template "entry" .}}
+{{end}}{{end}}{{range .Sections}}
+### {{.Title}}
+
+{{range .Entries}}{{template "entry" .}}
+{{end}}{{end}}
+{{end}}{{/* Changes and users without a web URL (i.e. read from a local git repository) are not linked */}}
+{{- define "entry"}}- {{if .Hash}}{{template "commit" .}}{{else}}{{.Title}} {{if .URL}}[#{{.Number}}]({{.URL}}){{else}}#{{.Number}}{{end}} ({{range $i, $u := .Users}}{{if $i}}, {{end}}{{if $u.URL}}[{{$u.Username}}]({{$u.URL}}){{else}}{{$u.Username}}{{end}}{{end}}){{end}}{{end}}
+{{- define "commit"}}{{if .Scope}}**{{.Scope}}:** {{end}}{{.Title}} ({{if .URL}}[{{.Hash}}]({{.URL}}){{else}}{{.Hash}}{{end}}){{end}}`
+
+// Sections of a release in the order recommended by Keep a Changelog.
+const (
+	sectionAdded      = "Added"
+	sectionChanged    = "Changed"
+	sectionDeprecated = "Deprecated"
+	sectionRemoved    = "Removed"
+	sectionFixed      = "Fixed"
+	sectionSecurity   = "Security"
+)
+
+var (
+	sectionOrder = []string{sectionAdded, sectionChanged, sectionDeprecated, sectionRemoved, sectionFixed, sectionSecurity}
+
+	// sectionMap maps the titles of label groups (see spec.Issues.LabelGroups, spec.Merges.LabelGroups, and spec.Commits.LabelGroups) to sections.
+	// Changes in any other group (i.e. milestones or unlabeled changes) are listed under the Changed section.
+	sectionMap = map[string]string{
+		"Removed":          sectionRemoved,
+		"Breaking Changes": sectionChanged,
+		"Deprecated":       sectionDeprecated,
+		"New Features":     sectionAdded,
+		"Enhancements":     sectionChanged,
+		"Fixed Bugs":       sectionFixed,
+		"Security Fixes":   sectionSecurity,
+	}
+
+	h1Regex         = regexp.MustCompile(`^# (.+)$`)
+	h2Regex         = regexp.MustCompile(`^## `)
+	unreleasedRegex = regexp.MustCompile(`(?i)^## \[?unreleased\]?\s*$`)
+	releaseRegex    = regexp.MustCompile(`^## \[?([^\]\s]+)\]?(?: - (\d{4}-\d{2}-\d{2}))?(?: \[YANKED\])?\s*$`)
+	linkRegex       = regexp.MustCompile(`^\[([^\]]+)\]:\s*(\S+)\s*$`)
+	// versionRegex matches a version-like token in the name of a release (i.e. 1.2.0, v2, or api/v2.0.0).
+	versionRegex = regexp.MustCompile(`\bv?\d+(\.\d+)+|\bv\d+\b`)
+)
+
+type (
+	// entry is either an issue, a merge, or a commit (with a hash).
+	entry struct {
+		Number int
+		Hash   string
+		Scope  string
+		Title  string
+		URL    string
+		Users  []changelog.User
+	}
+
+	section struct {
+		Title   string
+		Entries []entry
+	}
+
+	release struct {
+		Name       string
+		Date       string
+		ReleaseURL string
+		Summary    []entry
+		Sections   []section
+	}
+)
+
+func toRelease(r changelog.Release) release {
+	rel := release{
+		Name:       r.TagName,
+		Date:       r.TagTime.Format(timeLayout),
+		ReleaseURL: r.ReleaseURL,
+	}
+
+	if r.Unreleased {
+		rel.Name, rel.Date = unreleased, ""
+	}
+
+	entries := map[string][]entry{}
+	add := func(title string, e entry) {
+		if title == summaryTitle {
+			rel.Summary = append(rel.Summary, e)
+			return
+		}
+
+		s, ok := sectionMap[title]
+		if !ok {
+			s = sectionChanged
+		}
+		entries[s] = append(entries[s], e)
+	}
+
+	for _, g := range r.IssueGroups {
+		for _, i := range g.Issues {
+			users := []changelog.User{i.OpenedBy, i.ClosedBy}
+			if i.OpenedBy.Username == i.ClosedBy.Username {
+				users = users[1:]
+			}
+			add(g.Title, entry{Number: i.Number, Title: i.Title, URL: i.URL, Users: users})
+		}
+	}
+
+	for _, g := range r.MergeGroups {
+		for _, m := range g.Merges {
+			users := []changelog.User{m.OpenedBy, m.MergedBy}
+			if m.OpenedBy.Username == m.MergedBy.Username {
+				users = users[1:]
+			}
+			add(g.Title, entry{Number: m.Number, Title: m.Title, URL: m.URL, Users: users})
+		}
+	}
+
+	for _, g := range r.CommitGroups {
+		for _, c := range g.Commits {
+			add(g.Title, entry{Hash: c.ShortHash(), Scope: c.Scope, Title: c.Subject, URL: c.URL})
+		}
+	}
+
+	for _, title := range sectionOrder {
+		if len(entries[title]) > 0 {
+			rel.Sections = append(rel.Sections, section{Title: title, Entries: entries[title]})
+		}
+	}
+
+	return rel
+}
+
+// splitLinks separates the trailing link reference definitions from the rest of a changelog.
+func splitLinks(content string) (string, []string) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	i := len(lines)
+	for i > 0 && (linkRegex.MatchString(lines[i-1]) || strings.TrimSpace(lines[i-1]) == "") {
+		i--
+	}
+
+	var links []string
+	for _, line := range lines[i:] {
+		if linkRegex.MatchString(line) {
+			links = append(links, line)
+		}
+	}
+
+	return strings.Join(lines[:i], "\n") + "\n", links
+}
+
+// removeUnreleased removes the Unreleased section from a changelog, so it can be generated again.
+func removeUnreleased(body string) string {
+	lines := strings.SplitAfter(body, "\n")
+
+	for i, line := range lines {
+		if unreleasedRegex.MatchString(strings.TrimRight(line, "\n")) {
+			j := i + 1
+			for j < len(lines) && !h2Regex.MatchString(lines[j]) {
+				j++
+			}
+			return strings.Join(lines[:i], "") + strings.Join(lines[j:], "")
+		}
+	}
+
+	return body
+}
+
+// replaceReleases replaces the sections of existing releases with their regenerated content.
+// A section ends at the next second-level heading, so any hand-written content outside of the sections is kept.
+func replaceReleases(body string, regenerated map[string]string) string {
+	if len(regenerated) == 0 {
+		return body
+	}
+
+	var b strings.Builder
+	skip := false
+
+	for _, line := range strings.SplitAfter(body, "\n") {
+		heading := strings.TrimRight(line, "\n")
+
+		if h2Regex.MatchString(heading) {
+			skip = false
+			if sm := releaseRegex.FindStringSubmatch(heading); len(sm) == 3 {
+				if c, ok := regenerated[sm[1]]; ok {
+					// A yanked release remains yanked
+					if strings.Contains(heading, "[YANKED]") {
+						i := strings.Index(c, "\n")
+						c = c[:i] + " [YANKED]" + c[i:]
+					}
+
+					b.WriteString(c)
+					skip = true
+					continue
+				}
+			}
+		}
+
+		if !skip {
+			b.WriteString(line)
+		}
+	}
+
+	return b.String()
+}
+
+// resolveTagName recovers the tag name of a release when only the version is used in the heading (i.e. 1.2.0 for the v1.2.0 tag).
+// The link of a release points to a comparison or a tag, so the tag name is the last revision in the link.
+func resolveTagName(version, link string) string {
+	rev := link
+	if i := strings.LastIndex(rev, "..."); i >= 0 {
+		rev = rev[i+3:]
+	} else if i := strings.LastIndex(rev, "/"); i >= 0 {
+		rev = rev[i+1:]
+	}
+
+	if r, err := url.PathUnescape(rev); err == nil {
+		rev = r
+	}
+
+	if rev != version && strings.HasSuffix(rev, version) {
+		return rev
+	}
+
+	return version
+}
+
+// processor implements the changelog.Processor interface for Keep a Changelog format.
+// See https://keepachangelog.com/en/1.1.0
+type processor struct {
+	logger        log.Logger
+	baseFile      string
+	changelogFile string
+	content       string
+	// names maps the tag names of existing releases to the names used in their headings (i.e. v1.2.0 to 1.2.0).
+	names map[string]string
+}
+
+// NewProcessor creates a new changelog processor for Keep a Changelog format.
+func NewProcessor(logger log.Logger, baseFile, changelogFile string) changelog.Processor {
+	return &processor{
+		logger:        logger,
+		baseFile:      baseFile,
+		changelogFile: changelogFile,
+	}
+}
+
+func (p *processor) createChangelog() (*changelog.Changelog, error) {
+	chlog := changelog.NewChangelog()
+
+	tmpl, _ := template.New("changelog").Parse(emptyTemplate)
+	buf := new(bytes.Buffer)
+	_ = tmpl.Execute(buf, chlog)
+	p.content = buf.String()
+
+	p.logger.Warnf("%s not found", p.changelogFile)
+	p.logger.Info("A new changelog is created.")
+
+	return chlog, nil
+}
+
+func (p *processor) Parse(opts changelog.ParseOptions) (*changelog.Changelog, error) {
+	p.logger.Debugf("Opening %s ...", p.changelogFile)
+
+	f, err := os.Open(p.changelogFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p.createChangelog()
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	p.logger.Debugf("Parsing %s ...", p.changelogFile)
+
+	content := ""
+	chlog := new(changelog.Changelog)
+	links := map[string]string{}
+
+	// notes is nil outside of a release section
+	var notes []string
+
+	// flush sets the notes of the release being parsed
+	flush := func() {
+		if notes != nil {
+			chlog.Existing[len(chlog.Existing)-1].Notes = strings.Trim(strings.Join(notes, "\n"), "\n")
+		}
+		notes = nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		content += fmt.Sprintln(line)
+
+		if sm := h1Regex.FindStringSubmatch(line); len(sm) == 2 {
+			flush()
+			chlog.Title = sm[1]
+		} else if unreleasedRegex.MatchString(line) {
+			// The unreleased changes are generated again every time
+			flush()
+		} else if sm := releaseRegex.FindStringSubmatch(line); len(sm) == 3 {
+			flush()
+
+			var t time.Time
+			if sm[2] != "" {
+				if t, err = time.Parse(timeLayout, sm[2]); err != nil {
+					return nil, err
+				}
+			}
+
+			chlog.Existing = append(chlog.Existing, changelog.Release{
+				TagName: sm[1],
+				TagTime: t,
+			})
+			notes = []string{}
+		} else if sm := linkRegex.FindStringSubmatch(line); len(sm) == 3 {
+			links[strings.ToLower(sm[1])] = sm[2]
+		} else if h2Regex.MatchString(line) {
+			flush()
+		} else if notes != nil {
+			notes = append(notes, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+
+	// A second-level heading is only a release if it has a version-like name or a link (i.e. ## Notes is not a release)
+	var existing []changelog.Release
+	names := map[string]string{}
+	for _, r := range chlog.Existing {
+		name := r.TagName
+		link, ok := links[strings.ToLower(name)]
+
+		if ok {
+			r.TagName = resolveTagName(name, link)
+			r.CompareURL = link
+		} else if !versionRegex.MatchString(name) {
+			continue
+		} else if opts.TagPrefix != "" && !strings.HasPrefix(name, opts.TagPrefix) {
+			// Without a link, the tag name is the version with the tag prefix (i.e. v1.2.0 for 1.2.0)
+			r.TagName = opts.TagPrefix + name
+		}
+
+		existing = append(existing, r)
+		names[r.TagName] = name
+	}
+
+	chlog.Existing = existing
+	p.content = content
+	p.names = names
+
+	p.logger.Infof("Successfully parsed %s", p.changelogFile)
+
+	return chlog, nil
+}
+
+func (p *processor) Render(chlog *changelog.Changelog) (string, error) {
+	p.logger.Debug("Updating the changelog ...")
+
+	// ==============================> RENDER THE CONTENT FOR NEW RELEASES <==============================
+
+	tmpl, err := template.New("changelog").Parse(changelogTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	releases := []release{}
+	newLinks := []string{}
+
+	for _, r := range chlog.New {
+		rel := toRelease(r)
+		releases = append(releases, rel)
+
+		if r.CompareURL != "" {
+			newLinks = append(newLinks, fmt.Sprintf("[%s]: %s", rel.Name, r.CompareURL))
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err = tmpl.Execute(buf, releases); err != nil {
+		return "", err
+	}
+
+	newContent := buf.String()
+
+	// ==============================> RENDER THE CONTENT FOR REGENERATED RELEASES <==============================
+
+	regenerated := map[string]string{}
+	regeneratedLinks := map[string]string{}
+	regeneratedContent := ""
+
+	for _, r := range chlog.Regenerated {
+		rel := toRelease(r)
+
+		// Keep the name used in the existing heading
+		if name, ok := p.names[r.TagName]; ok {
+			rel.Name = name
+		}
+
+		b := new(bytes.Buffer)
+		if err = tmpl.Execute(b, []release{rel}); err != nil {
+			return "", err
+		}
+
+		regenerated[rel.Name] = b.String()
+		regeneratedContent += b.String()
+
+		if r.CompareURL != "" {
+			regeneratedLinks[strings.ToLower(rel.Name)] = fmt.Sprintf("[%s]: %s", rel.Name, r.CompareURL)
+		}
+	}
+
+	// ==============================> UPDATE THE CHANGELOG FILE <==============================
+
+	// The existing Unreleased section is only replaced if the changes for a future tag are generated into it
+	future := false
+	for _, r := range chlog.New {
+		future = future || r.Unreleased
+	}
+
+	body, links := splitLinks(p.content)
+	if future {
+		body = removeUnreleased(body)
+	}
+	body = replaceReleases(body, regenerated)
+
+	oldLinks := []string{}
+	for _, link := range links {
+		// The link to the previous unreleased changes is not valid anymore
+		sm := linkRegex.FindStringSubmatch(link)
+		if future && strings.EqualFold(sm[1], unreleased) {
+			continue
+		}
+
+		if l, ok := regeneratedLinks[strings.ToLower(sm[1])]; ok {
+			link = l
+		}
+		oldLinks = append(oldLinks, link)
+	}
+
+	if i := indexRelease(body); i >= 0 {
+		body = body[:i] + newContent + body[i:]
+	} else {
+		// Add the content of an optional base file if generating the changelog for the first time
+		var baseBody string
+		if p.baseFile != "" {
+			p.logger.Infof("Adding the base file content to the changelog: %s", p.baseFile)
+
+			b, err := ioutil.ReadFile(p.baseFile)
+			if err != nil {
+				return "", err
+			}
+
+			var baseLinks []string
+			baseBody, baseLinks = splitLinks(string(b))
+			oldLinks = append(oldLinks, baseLinks...)
+		}
+
+		body = strings.TrimRight(body, "\n") + "\n\n" + newContent + baseBody
+	}
+
+	p.content = strings.TrimRight(body, "\n") + "\n"
+	if allLinks := append(newLinks, oldLinks...); len(allLinks) > 0 {
+		p.content += "\n" + strings.Join(allLinks, "\n") + "\n"
+	}
+
+	if err := ioutil.WriteFile(p.changelogFile, []byte(p.content), 0644); err != nil {
+		return "", err
+	}
+
+	p.logger.Infof("Successfully updated the changelog: %s", p.changelogFile)
+
+	newContent += regeneratedContent
+	if len(newLinks) > 0 {
+		newContent += strings.Join(newLinks, "\n") + "\n"
+	}
+
+	return newContent, nil
+}
+
+// indexRelease returns the index of the first release heading in a changelog or -1 if there is no release.
+// An Unreleased section is not a release and stays on top of the releases.
+func indexRelease(body string) int {
+	offset := 0
+	for _, line := range strings.SplitAfter(body, "\n") {
+		if h2Regex.MatchString(line) && !unreleasedRegex.MatchString(strings.TrimRight(line, "\n")) {
+			return offset
+		}
+		offset += len(line)
+	}
+
+	return -1
+}
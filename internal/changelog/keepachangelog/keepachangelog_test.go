@@ -0,0 +1,698 @@
+package keepachangelog
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/changelog"
+	"github.com/moorara/changelog/log"
+)
+
+var (
+	octocat = changelog.User{
+		Name:     "The Octocat",
+		Username: "octocat",
+		URL:      "https://github.com/octocat",
+	}
+
+	octodog = changelog.User{
+		Name:     "The Octodog",
+		Username: "octodog",
+		URL:      "https://github.com/octodog",
+	}
+
+	tagTime, _ = time.Parse(time.RFC3339, "2020-11-02T22:00:00-04:00")
+	chlog      = &changelog.Changelog{
+		Title: "Changelog",
+		New: []changelog.Release{
+			{
+				TagName:    "v0.3.0",
+				TagURL:     "https://github.com/octocat/Hello-World/tree/v0.3.0",
+				TagTime:    tagTime.Add(24 * time.Hour),
+				CompareURL: "https://github.com/octocat/Hello-World/compare/v0.2.0...v0.3.0",
+				Unreleased: true,
+				MergeGroups: []changelog.MergeGroup{
+					{
+						Title: "Merged Changes",
+						Merges: []changelog.Merge{
+							{Number: 1004, Title: "Refactor the parser", OpenedBy: changelog.User{Username: "octocat"}, MergedBy: changelog.User{Username: "octocat"}},
+						},
+					},
+				},
+				CommitGroups: []changelog.CommitGroup{
+					{
+						Title: "Fixed Bugs",
+						Commits: []changelog.Commit{
+							{Hash: "c414d1004154c6c324bd78c69d10ee101e676059", Type: "fix", Scope: "parser", Subject: "handle empty lines"},
+						},
+					},
+				},
+			},
+			{
+				TagName:    "v0.2.0",
+				TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+				TagTime:    tagTime,
+				ReleaseURL: "https://storage.artifactory.com/project/releases/v0.2.0",
+				CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.1...v0.2.0",
+				IssueGroups: []changelog.IssueGroup{
+					{
+						Title: "Release Summary",
+						Issues: []changelog.Issue{
+							{Number: 1000, Title: "Release v0.2.0", URL: "https://github.com/octocat/Hello-World/issues/1000", OpenedBy: octocat, ClosedBy: octocat},
+						},
+					},
+					{
+						Title: "Fixed Bugs",
+						Issues: []changelog.Issue{
+							{Number: 1001, Title: "Fixed a bug", URL: "https://github.com/octocat/Hello-World/issues/1001", OpenedBy: octocat, ClosedBy: octocat},
+						},
+					},
+				},
+				MergeGroups: []changelog.MergeGroup{
+					{
+						Title: "New Features",
+						Merges: []changelog.Merge{
+							{Number: 1002, Title: "Add a feature", URL: "https://github.com/octocat/Hello-World/pull/1002", OpenedBy: octocat, MergedBy: octodog},
+						},
+					},
+					{
+						Title: "Merged Changes",
+						Merges: []changelog.Merge{
+							{Number: 1003, Title: "Update the docs", URL: "https://github.com/octocat/Hello-World/pull/1003", OpenedBy: octodog, MergedBy: octodog},
+						},
+					},
+				},
+			},
+		},
+	}
+)
+
+const expectedNewContent = `## [Unreleased]
+
+### Changed
+
+- Refactor the parser #1004 (octocat)
+
+### Fixed
+
+- **parser:** handle empty lines (c414d10)
+
+## [v0.2.0] - 2020-11-02
+
+https://storage.artifactory.com/project/releases/v0.2.0
+
+- Release v0.2.0 [#1000](https://github.com/octocat/Hello-World/issues/1000) ([octocat](https://github.com/octocat))
+
+### Added
+
+- Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))
+
+### Changed
+
+- Update the docs [#1003](https://github.com/octocat/Hello-World/pull/1003) ([octodog](https://github.com/octodog))
+
+### Fixed
+
+- Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))
+
+[Unreleased]: https://github.com/octocat/Hello-World/compare/v0.2.0...v0.3.0
+[v0.2.0]: https://github.com/octocat/Hello-World/compare/v0.1.1...v0.2.0
+`
+
+const expectedReleasedContent = `## [v0.2.0] - 2020-11-02
+
+https://storage.artifactory.com/project/releases/v0.2.0
+
+- Release v0.2.0 [#1000](https://github.com/octocat/Hello-World/issues/1000) ([octocat](https://github.com/octocat))
+
+### Added
+
+- Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))
+
+### Changed
+
+- Update the docs [#1003](https://github.com/octocat/Hello-World/pull/1003) ([octodog](https://github.com/octodog))
+
+### Fixed
+
+- Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))
+
+[v0.2.0]: https://github.com/octocat/Hello-World/compare/v0.1.1...v0.2.0
+`
+
+const expectedChangelog = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+*This changelog is automatically generated by [changelog](https://github.com/moorara/changelog)*
+
+## [Unreleased]
+
+### Changed
+
+- Refactor the parser #1004 (octocat)
+
+### Fixed
+
+- **parser:** handle empty lines (c414d10)
+
+## [v0.2.0] - 2020-11-02
+
+https://storage.artifactory.com/project/releases/v0.2.0
+
+- Release v0.2.0 [#1000](https://github.com/octocat/Hello-World/issues/1000) ([octocat](https://github.com/octocat))
+
+### Added
+
+- Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))
+
+### Changed
+
+- Update the docs [#1003](https://github.com/octocat/Hello-World/pull/1003) ([octodog](https://github.com/octodog))
+
+### Fixed
+
+- Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))
+
+[Unreleased]: https://github.com/octocat/Hello-World/compare/v0.2.0...v0.3.0
+[v0.2.0]: https://github.com/octocat/Hello-World/compare/v0.1.1...v0.2.0
+`
+
+const expectedChangelogWithBase = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+*This changelog is automatically generated by [changelog](https://github.com/moorara/changelog)*
+
+## [Unreleased]
+
+### Changed
+
+- Refactor the parser #1004 (octocat)
+
+### Fixed
+
+- **parser:** handle empty lines (c414d10)
+
+## [v0.2.0] - 2020-11-02
+
+https://storage.artifactory.com/project/releases/v0.2.0
+
+- Release v0.2.0 [#1000](https://github.com/octocat/Hello-World/issues/1000) ([octocat](https://github.com/octocat))
+
+### Added
+
+- Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))
+
+### Changed
+
+- Update the docs [#1003](https://github.com/octocat/Hello-World/pull/1003) ([octodog](https://github.com/octodog))
+
+### Fixed
+
+- Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))
+
+## [v0.1.0] - 2020-10-10
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/octocat/Hello-World/compare/v0.2.0...v0.3.0
+[v0.2.0]: https://github.com/octocat/Hello-World/compare/v0.1.1...v0.2.0
+[v0.1.0]: https://github.com/octocat/Hello-World/releases/tag/v0.1.0
+`
+
+const expectedExistingChangelog = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+
+### Changed
+
+- Refactor the parser #1004 (octocat)
+
+### Fixed
+
+- **parser:** handle empty lines (c414d10)
+
+## [v0.2.0] - 2020-11-02
+
+https://storage.artifactory.com/project/releases/v0.2.0
+
+- Release v0.2.0 [#1000](https://github.com/octocat/Hello-World/issues/1000) ([octocat](https://github.com/octocat))
+
+### Added
+
+- Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))
+
+### Changed
+
+- Update the docs [#1003](https://github.com/octocat/Hello-World/pull/1003) ([octodog](https://github.com/octodog))
+
+### Fixed
+
+- Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))
+
+## [0.1.1] - 2020-10-11
+
+### Fixed
+
+- Fixed a bug
+
+## [0.1.0] - 2020-10-10 [YANKED]
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/octocat/Hello-World/compare/v0.2.0...v0.3.0
+[v0.2.0]: https://github.com/octocat/Hello-World/compare/v0.1.1...v0.2.0
+[0.1.1]: https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1
+[0.1.0]: https://github.com/octocat/Hello-World/releases/tag/v0.1.0
+`
+
+func TestResolveTagName(t *testing.T) {
+	tests := []struct {
+		name            string
+		version         string
+		link            string
+		expectedTagName string
+	}{
+		{
+			name:            "NoLink",
+			version:         "1.2.0",
+			link:            "",
+			expectedTagName: "1.2.0",
+		},
+		{
+			name:            "SameTag",
+			version:         "v1.2.0",
+			link:            "https://github.com/octocat/Hello-World/compare/v1.1.0...v1.2.0",
+			expectedTagName: "v1.2.0",
+		},
+		{
+			name:            "CompareLink",
+			version:         "1.2.0",
+			link:            "https://github.com/octocat/Hello-World/compare/v1.1.0...v1.2.0",
+			expectedTagName: "v1.2.0",
+		},
+		{
+			name:            "TagLink",
+			version:         "1.0.0",
+			link:            "https://github.com/octocat/Hello-World/releases/tag/v1.0.0",
+			expectedTagName: "v1.0.0",
+		},
+		{
+			name:            "EscapedTagLink",
+			version:         "1.0.0",
+			link:            "https://gitlab.com/octocat/hello-world/-/tags/release%2F1.0.0",
+			expectedTagName: "release/1.0.0",
+		},
+		{
+			name:            "UnrelatedLink",
+			version:         "1.2.0",
+			link:            "https://github.com/octocat/Hello-World/compare/v1.1.0...HEAD",
+			expectedTagName: "1.2.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedTagName, resolveTagName(tc.version, tc.link))
+		})
+	}
+}
+
+func TestNewProcessor(t *testing.T) {
+	tests := []struct {
+		name          string
+		logger        log.Logger
+		baseFile      string
+		changelogFile string
+	}{
+		{
+			name:          "OK",
+			logger:        log.New(log.None),
+			baseFile:      "HISTORY.md",
+			changelogFile: "CHANGELOG.md",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewProcessor(tc.logger, tc.baseFile, tc.changelogFile)
+			assert.NotNil(t, p)
+
+			kp, ok := p.(*processor)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.logger, kp.logger)
+			assert.Equal(t, tc.baseFile, kp.baseFile)
+			assert.Equal(t, tc.changelogFile, kp.changelogFile)
+			assert.Empty(t, kp.content)
+		})
+	}
+}
+
+func TestProcessor_createChangelog(t *testing.T) {
+	p := &processor{
+		logger: log.New(log.None),
+	}
+
+	chlog, err := p.createChangelog()
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, p.content)
+	assert.Equal(t, &changelog.Changelog{Title: "Changelog"}, chlog)
+}
+
+func TestProcessor_Parse(t *testing.T) {
+	tests := []struct {
+		name              string
+		p                 *processor
+		opts              changelog.ParseOptions
+		expectedChangelog *changelog.Changelog
+		expectedError     string
+	}{
+		{
+			name: "FileNotExist",
+			p: &processor{
+				logger:        log.New(log.None),
+				changelogFile: "test/missing.md",
+			},
+			opts: changelog.ParseOptions{},
+			expectedChangelog: &changelog.Changelog{
+				Title: "Changelog",
+			},
+			expectedError: "",
+		},
+		{
+			name: "Success",
+			p: &processor{
+				logger:        log.New(log.None),
+				changelogFile: "test/CHANGELOG.md",
+			},
+			opts: changelog.ParseOptions{},
+			expectedChangelog: &changelog.Changelog{
+				Title: "Changelog",
+				Existing: []changelog.Release{
+					{
+						TagName:    "v0.1.1",
+						TagTime:    time.Date(2020, time.October, 11, 0, 0, 0, 0, time.UTC),
+						CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1",
+						Notes:      "### Fixed\n\n- Fixed a bug",
+					},
+					{
+						TagName:    "v0.1.0",
+						TagTime:    time.Date(2020, time.October, 10, 0, 0, 0, 0, time.UTC),
+						CompareURL: "https://github.com/octocat/Hello-World/releases/tag/v0.1.0",
+						Notes:      "### Added\n\n- Initial release",
+					},
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name: "NoLinks",
+			p: &processor{
+				logger:        log.New(log.None),
+				changelogFile: "test/NOLINKS.md",
+			},
+			opts: changelog.ParseOptions{},
+			expectedChangelog: &changelog.Changelog{
+				Title: "Changelog",
+				Existing: []changelog.Release{
+					{
+						TagName: "1.2.0",
+						TagTime: time.Date(2020, time.October, 11, 0, 0, 0, 0, time.UTC),
+						Notes:   "### Fixed\n\n- Fixed a bug",
+					},
+					{
+						TagName: "1.1.0",
+						TagTime: time.Date(2020, time.October, 10, 0, 0, 0, 0, time.UTC),
+						Notes:   "### Added\n\n- Initial release",
+					},
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name: "NoLinks_TagPrefix",
+			p: &processor{
+				logger:        log.New(log.None),
+				changelogFile: "test/NOLINKS.md",
+			},
+			opts: changelog.ParseOptions{
+				TagPrefix: "v",
+			},
+			expectedChangelog: &changelog.Changelog{
+				Title: "Changelog",
+				Existing: []changelog.Release{
+					{
+						TagName: "v1.2.0",
+						TagTime: time.Date(2020, time.October, 11, 0, 0, 0, 0, time.UTC),
+						Notes:   "### Fixed\n\n- Fixed a bug",
+					},
+					{
+						TagName: "v1.1.0",
+						TagTime: time.Date(2020, time.October, 10, 0, 0, 0, 0, time.UTC),
+						Notes:   "### Added\n\n- Initial release",
+					},
+				},
+			},
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chlog, err := tc.p.Parse(tc.opts)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, tc.p.content)
+				assert.Equal(t, tc.expectedChangelog, chlog)
+			} else {
+				assert.Nil(t, chlog)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestProcessor_Render(t *testing.T) {
+	tests := []struct {
+		name              string
+		p                 *processor
+		existingFile      string
+		chlog             *changelog.Changelog
+		expectedContent   string
+		expectedChangelog string
+	}{
+		{
+			name: "NewChangelog",
+			p: &processor{
+				logger: log.New(log.None),
+			},
+			chlog:             chlog,
+			expectedContent:   expectedNewContent,
+			expectedChangelog: expectedChangelog,
+		},
+		{
+			name: "WithBaseFile",
+			p: &processor{
+				logger:   log.New(log.None),
+				baseFile: "test/HISTORY.md",
+			},
+			chlog:             chlog,
+			expectedContent:   expectedNewContent,
+			expectedChangelog: expectedChangelogWithBase,
+		},
+		{
+			name: "ExistingChangelog",
+			p: &processor{
+				logger:   log.New(log.None),
+				baseFile: "test/HISTORY.md",
+			},
+			existingFile:      "test/CHANGELOG.md",
+			chlog:             chlog,
+			expectedContent:   expectedNewContent,
+			expectedChangelog: expectedExistingChangelog,
+		},
+		{
+			name: "ExistingChangelog_NoFutureTag",
+			p: &processor{
+				logger: log.New(log.None),
+			},
+			existingFile: "test/CHANGELOG.md",
+			chlog: &changelog.Changelog{
+				Title: "Changelog",
+				New:   chlog.New[1:],
+			},
+			expectedContent:   expectedReleasedContent,
+			expectedChangelog: expectedExistingChangelogWithUnreleased,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.p.changelogFile = filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+			if tc.existingFile != "" {
+				b, err := ioutil.ReadFile(tc.existingFile)
+				assert.NoError(t, err)
+				assert.NoError(t, ioutil.WriteFile(tc.p.changelogFile, b, 0644))
+			}
+
+			_, err := tc.p.Parse(changelog.ParseOptions{})
+			assert.NoError(t, err)
+
+			content, err := tc.p.Render(tc.chlog)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedContent, content)
+
+			b, err := ioutil.ReadFile(tc.p.changelogFile)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedChangelog, string(b))
+
+			// The new releases should be parsed back except for the unreleased changes
+			parsed, err := tc.p.Parse(changelog.ParseOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, "v0.2.0", parsed.Existing[0].TagName)
+			assert.Equal(t, "https://github.com/octocat/Hello-World/compare/v0.1.1...v0.2.0", parsed.Existing[0].CompareURL)
+		})
+	}
+}
+
+func TestProcessor_Render_Regenerated(t *testing.T) {
+	p := &processor{
+		logger:        log.New(log.None),
+		changelogFile: filepath.Join(t.TempDir(), "CHANGELOG.md"),
+	}
+
+	b, err := ioutil.ReadFile("test/CHANGELOG.md")
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(p.changelogFile, b, 0644))
+
+	chlog, err := p.Parse(changelog.ParseOptions{})
+	assert.NoError(t, err)
+
+	chlog.Regenerated = []changelog.Release{
+		{
+			TagName:    "v0.1.0",
+			TagTime:    time.Date(2020, time.October, 10, 0, 0, 0, 0, time.UTC),
+			CompareURL: "https://github.com/octocat/Hello-World/compare/v0.0.1...v0.1.0",
+			IssueGroups: []changelog.IssueGroup{
+				{
+					Title: "New Features",
+					Issues: []changelog.Issue{
+						{
+							Number:   1,
+							Title:    "Initial release",
+							URL:      "https://github.com/octocat/Hello-World/issues/1",
+							OpenedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+							ClosedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	_, err = p.Render(chlog)
+	assert.NoError(t, err)
+
+	b, err = ioutil.ReadFile(p.changelogFile)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRegeneratedChangelog, string(b))
+}
+
+const expectedExistingChangelogWithUnreleased = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+
+### Added
+
+- A feature that is not released yet
+
+## [v0.2.0] - 2020-11-02
+
+https://storage.artifactory.com/project/releases/v0.2.0
+
+- Release v0.2.0 [#1000](https://github.com/octocat/Hello-World/issues/1000) ([octocat](https://github.com/octocat))
+
+### Added
+
+- Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))
+
+### Changed
+
+- Update the docs [#1003](https://github.com/octocat/Hello-World/pull/1003) ([octodog](https://github.com/octodog))
+
+### Fixed
+
+- Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))
+
+## [0.1.1] - 2020-10-11
+
+### Fixed
+
+- Fixed a bug
+
+## [0.1.0] - 2020-10-10 [YANKED]
+
+### Added
+
+- Initial release
+
+[v0.2.0]: https://github.com/octocat/Hello-World/compare/v0.1.1...v0.2.0
+[unreleased]: https://github.com/octocat/Hello-World/compare/v0.1.1...HEAD
+[0.1.1]: https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1
+[0.1.0]: https://github.com/octocat/Hello-World/releases/tag/v0.1.0
+`
+
+const expectedRegeneratedChangelog = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+
+### Added
+
+- A feature that is not released yet
+
+## [0.1.1] - 2020-10-11
+
+### Fixed
+
+- Fixed a bug
+
+## [0.1.0] - 2020-10-10 [YANKED]
+
+### Added
+
+- Initial release [#1](https://github.com/octocat/Hello-World/issues/1) ([octocat](https://github.com/octocat))
+
+[unreleased]: https://github.com/octocat/Hello-World/compare/v0.1.1...HEAD
+[0.1.1]: https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1
+[0.1.0]: https://github.com/octocat/Hello-World/compare/v0.0.1...v0.1.0
+`
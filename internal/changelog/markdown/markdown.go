@@ -3,12 +3,13 @@ package markdown
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
-	"html/template"
 	"io/ioutil"
 	"os"
 	"regexp"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/moorara/changelog/internal/changelog"
@@ -17,7 +18,7 @@ import (
 
 const timeLayout = "2006-01-02"
 
-const emptyTemplate = `# {{title .Title}}
+const headerTemplate = `# {{title .Title}}
 
 **DO NOT MODIFY THIS FILE!**
 *This changelog is automatically generated by [changelog](https://github.com/moorara/changelog)*
@@ -25,7 +26,7 @@ const emptyTemplate = `# {{title .Title}}
 
 `
 
-const changelogTemplate = `{{range .}}## [{{.TagName}}]({{.TagURL}}) ({{time .TagTime}})
+const releaseTemplate = `## [{{.TagName}}]({{.TagURL}}) ({{time .TagTime}})
 {{if .ReleaseURL}}
 {{.ReleaseURL}}
 {{end}}
@@ -33,50 +34,219 @@ const changelogTemplate = `{{range .}}## [{{.TagName}}]({{.TagURL}}) ({{time .Ta
 
 {{range .IssueGroups}}**{{title .Title}}:**
 
-{{range .Issues}}  - {{.Title}} [#{{.Number}}]({{.URL}}) ({{if ne .OpenedBy.Username .ClosedBy.Username}}[{{.OpenedBy.Username}}]({{.OpenedBy.URL}}), {{end}}[{{.ClosedBy.Username}}]({{.ClosedBy.URL}}))
+{{range .Issues}}  - {{.Title}} {{number .}} ({{if ne .OpenedBy.Username .ClosedBy.Username}}{{user .OpenedBy}}, {{end}}{{user .ClosedBy}})
 {{end}}
 {{end}}{{range .MergeGroups}}**{{title .Title}}:**
 
-{{range .Merges}}  - {{.Title}} [#{{.Number}}]({{.URL}}) ({{if ne .OpenedBy.Username .MergedBy.Username}}[{{.OpenedBy.Username}}]({{.OpenedBy.URL}}), {{end}}[{{.MergedBy.Username}}]({{.MergedBy.URL}}))
+{{range .Merges}}  - {{.Title}} {{number .}} ({{if ne .OpenedBy.Username .MergedBy.Username}}{{user .OpenedBy}}, {{end}}{{user .MergedBy}})
+{{end}}
+{{end}}{{range .CommitGroups}}**{{title .Title}}:**
+
+{{range .Commits}}  - {{if .Scope}}**{{.Scope}}:** {{end}}{{.Subject}} ({{commit .}})
 {{end}}
 {{end}}
-{{end}}`
+`
 
 var (
 	h1Regex = regexp.MustCompile(`^# ([0-9A-Za-z-_]+)$`)
-	h2Regex = regexp.MustCompile(`^## \[([0-9A-Za-z-.]+)\]\(([0-9A-Za-z-.:/]+)\) \((\d{4}-\d{2}-\d{2})\)$`)
+
+	// h2Regex matches release headings for any tag name allowed by git (i.e. v1.2.0+build.5, api/v2.0.0, or unicode names).
+	// Git does not allow whitespaces, backslashes, and opening square brackets in tag names.
+	// URLs may have escaped characters (i.e. %2F for tag names with slashes).
+	h2Regex = regexp.MustCompile(`^## \[([^\s\[\\]+)\]\((\S*)\) \((\d{4}-\d{2}-\d{2})\)$`)
+
+	// releaseLikeRegex matches headings that look like release headings.
+	releaseLikeRegex = regexp.MustCompile(`^##\s*\[`)
+
+	// sectionRegex matches the top-level and second-level headings that end a release section.
+	sectionRegex = regexp.MustCompile(`^#{1,2}(\s|\[)`)
 
 	funcMap = template.FuncMap{
 		"title": strings.Title,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
 		"time": func(t time.Time) string {
 			return t.Format(timeLayout)
 		},
+		"date": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		"join": func(sep string, elems []string) string {
+			return strings.Join(elems, sep)
+		},
+		"link": link,
+		// Changes and users without a web URL (i.e. read from a local git repository) are not linked
+		"number": func(change interface{}) (string, error) {
+			switch c := change.(type) {
+			case changelog.Issue:
+				return link(fmt.Sprintf("#%d", c.Number), c.URL), nil
+			case changelog.Merge:
+				return link(fmt.Sprintf("#%d", c.Number), c.URL), nil
+			default:
+				return "", fmt.Errorf("number: unexpected %T", change)
+			}
+		},
+		"user": func(u changelog.User) string {
+			return link(u.Username, u.URL)
+		},
+		"commit": func(c changelog.Commit) string {
+			return link(c.ShortHash(), c.URL)
+		},
+	}
+
+	// sampleRelease is used for validating custom templates at startup.
+	sampleRelease = changelog.Release{
+		TagName:    "v0.1.0",
+		TagURL:     "https://github.com/octocat/Hello-World/tree/v0.1.0",
+		TagTime:    time.Date(2020, time.October, 10, 0, 0, 0, 0, time.UTC),
+		ReleaseURL: "https://storage.artifactory.com/project/releases/v0.1.0",
+		CompareURL: "https://github.com/octocat/Hello-World/compare/v0.0.1...v0.1.0",
+		IssueGroups: []changelog.IssueGroup{
+			{
+				Title: "Fixed Bugs",
+				Issues: []changelog.Issue{
+					{
+						Number:   1001,
+						Title:    "Fixed a bug",
+						URL:      "https://github.com/octocat/Hello-World/issues/1001",
+						Labels:   []string{"bug"},
+						OpenedBy: changelog.User{Name: "The Octocat", Username: "octocat", URL: "https://github.com/octocat"},
+						ClosedBy: changelog.User{Name: "The Octocat", Username: "octocat", URL: "https://github.com/octocat"},
+					},
+				},
+			},
+		},
+		MergeGroups: []changelog.MergeGroup{
+			{
+				Title: "Merged Changes",
+				Merges: []changelog.Merge{
+					{
+						Number:   1002,
+						Title:    "Add a feature",
+						URL:      "https://github.com/octocat/Hello-World/pull/1002",
+						Labels:   []string{"feature"},
+						OpenedBy: changelog.User{Name: "The Octocat", Username: "octocat", URL: "https://github.com/octocat"},
+						MergedBy: changelog.User{Name: "The Octodog", Username: "octodog", URL: "https://github.com/octodog"},
+					},
+				},
+			},
+		},
+		CommitGroups: []changelog.CommitGroup{
+			{
+				Title: "Enhancements",
+				Commits: []changelog.Commit{
+					{
+						Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+						Type:    "perf",
+						Scope:   "parser",
+						Subject: "cache the compiled expressions",
+						URL:     "https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059",
+					},
+				},
+			},
+		},
 	}
 )
 
+// link returns a Markdown link if the URL is not empty.
+func link(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// Templates has optional template files for customizing the changelog.
+// The header template is executed with the changelog and the release template is executed with every new release.
+type Templates struct {
+	Header  string
+	Release string
+}
+
+// parseTemplate parses a template file if it is specified, otherwise it parses the default template text.
+func parseTemplate(name, file, text string) (*template.Template, error) {
+	if file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		text = string(b)
+	}
+
+	return template.New(name).Funcs(funcMap).Parse(text)
+}
+
+// Notes renders a release with the default release template and returns it without the release heading.
+// It is used for the releases of changelogs in other formats that do not have release notes.
+func Notes(r changelog.Release) (string, error) {
+	release, err := parseTemplate("release", "", releaseTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := release.Execute(buf, r); err != nil {
+		return "", err
+	}
+
+	notes := strings.SplitN(buf.String(), "\n", 2)[1]
+
+	return strings.Trim(notes, "\n"), nil
+}
+
 // processor implements the changelog.Processor interface for Markdown format.
 type processor struct {
 	logger        log.Logger
 	baseFile      string
 	changelogFile string
+	header        *template.Template
+	release       *template.Template
 	content       string
 }
 
 // NewProcessor creates a new changelog processor for Markdown format.
-func NewProcessor(logger log.Logger, baseFile, changelogFile string) changelog.Processor {
+// Custom templates are validated by rendering a sample release, so errors are reported before making any API call.
+func NewProcessor(logger log.Logger, baseFile, changelogFile string, templates Templates) (changelog.Processor, error) {
+	header, err := parseTemplate("header", templates.Header, headerTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := parseTemplate("release", templates.Release, releaseTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := header.Execute(ioutil.Discard, changelog.NewChangelog()); err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := release.Execute(buf, sampleRelease); err != nil {
+		return nil, err
+	}
+
+	// The release headings are required for finding the existing releases in the changelog
+	if !h2Regex.MatchString(strings.SplitN(buf.String(), "\n", 2)[0]) {
+		return nil, errors.New("release template should start with the release heading: ## [{{.TagName}}]({{.TagURL}}) ({{time .TagTime}})")
+	}
+
 	return &processor{
 		logger:        logger,
 		baseFile:      baseFile,
 		changelogFile: changelogFile,
-	}
+		header:        header,
+		release:       release,
+	}, nil
 }
 
 func (p *processor) createChangelog() (*changelog.Changelog, error) {
 	chlog := changelog.NewChangelog()
 
-	tmpl, _ := template.New("changelog").Funcs(funcMap).Parse(emptyTemplate)
 	buf := new(bytes.Buffer)
-	_ = tmpl.Execute(buf, chlog)
+	if err := p.header.Execute(buf, chlog); err != nil {
+		return nil, err
+	}
 	p.content = buf.String()
 
 	p.logger.Warnf("%s not found", p.changelogFile)
@@ -102,6 +272,20 @@ func (p *processor) Parse(opts changelog.ParseOptions) (*changelog.Changelog, er
 	content := ""
 	chlog := new(changelog.Changelog)
 
+	var release *changelog.Release
+	var groups []group
+	var notes []string
+
+	// flush adds the release being parsed to the changelog
+	flush := func() {
+		if release != nil {
+			setGroups(release, groups, opts)
+			release.Notes = strings.Trim(strings.Join(notes, "\n"), "\n")
+			chlog.Existing = append(chlog.Existing, *release)
+		}
+		release, groups, notes = nil, nil, nil
+	}
+
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -115,11 +299,35 @@ func (p *processor) Parse(opts changelog.ParseOptions) (*changelog.Changelog, er
 				return nil, err
 			}
 
-			chlog.Existing = append(chlog.Existing, changelog.Release{
+			flush()
+			release = &changelog.Release{
 				TagName: sm[1],
 				TagURL:  sm[2],
 				TagTime: t,
-			})
+			}
+		} else if sectionRegex.MatchString(line) {
+			// A release that cannot be parsed would be generated again as a duplicate
+			if releaseLikeRegex.MatchString(line) {
+				p.logger.Warnf("Cannot parse the release heading: %s", line)
+			}
+			// Any other top-level or second-level heading ends the release
+			flush()
+		} else if release == nil {
+			continue
+		} else {
+			notes = append(notes, line)
+
+			if sm := compareRegex.FindStringSubmatch(line); len(sm) == 2 && len(groups) == 0 {
+				release.CompareURL = sm[1]
+			} else if urlRegex.MatchString(line) && len(groups) == 0 && release.CompareURL == "" {
+				release.ReleaseURL = line
+			} else if sm := groupRegex.FindStringSubmatch(line); len(sm) == 2 {
+				groups = append(groups, group{Title: sm[1]})
+			} else if c, ok := parseCommit(line); ok && len(groups) > 0 {
+				groups[len(groups)-1].Commits = append(groups[len(groups)-1].Commits, c)
+			} else if e, ok := parseEntry(line); ok && len(groups) > 0 {
+				groups[len(groups)-1].Entries = append(groups[len(groups)-1].Entries, e)
+			}
 		}
 	}
 
@@ -127,6 +335,8 @@ func (p *processor) Parse(opts changelog.ParseOptions) (*changelog.Changelog, er
 		return nil, err
 	}
 
+	flush()
+
 	p.content = content
 
 	p.logger.Infof("Successfully parsed %s", p.changelogFile)
@@ -139,28 +349,37 @@ func (p *processor) Render(chlog *changelog.Changelog) (string, error) {
 
 	// ==============================> RENDER THE CONTENT FOR NEW RELEASES <==============================
 
-	tmpl, err := template.New("changelog").Funcs(funcMap).Parse(changelogTemplate)
-	if err != nil {
-		return "", err
-	}
-
 	buf := new(bytes.Buffer)
-	if err = tmpl.Execute(buf, chlog.New); err != nil {
-		return "", err
+	for _, release := range chlog.New {
+		if err := p.release.Execute(buf, release); err != nil {
+			return "", err
+		}
 	}
 
 	newContent := buf.String()
 
-	// ==============================> UPDATE THE CHANGELOG FILE <==============================
+	// ==============================> RENDER THE CONTENT FOR REGENERATED RELEASES <==============================
 
-	f, err := os.OpenFile(p.changelogFile, os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return "", err
+	regenerated := map[string]string{}
+	regeneratedContent := ""
+
+	for _, release := range chlog.Regenerated {
+		b := new(bytes.Buffer)
+		if err := p.release.Execute(b, release); err != nil {
+			return "", err
+		}
+
+		regenerated[release.TagName] = b.String()
+		regeneratedContent += b.String()
 	}
-	defer f.Close()
 
-	if i := strings.Index(p.content, "##"); i >= 0 {
-		p.content = p.content[:i] + newContent + p.content[i:]
+	// ==============================> UPDATE THE CHANGELOG FILE <==============================
+
+	// The changelog file is only written after the entire content is built, so it is kept intact on errors
+	content := replaceReleases(p.content, regenerated)
+
+	if i := strings.Index(content, "##"); i >= 0 {
+		content = content[:i] + newContent + content[i:]
 	} else {
 		// Add the content of an optional base file if generating the changelog for the first time
 		var baseContent string
@@ -175,14 +394,48 @@ func (p *processor) Render(chlog *changelog.Changelog) (string, error) {
 			baseContent = string(b)
 		}
 
-		p.content += newContent + baseContent
+		content += newContent + baseContent
 	}
 
-	if _, err = f.WriteString(p.content); err != nil {
+	if err := ioutil.WriteFile(p.changelogFile, []byte(content), 0644); err != nil {
 		return "", err
 	}
 
+	p.content = content
+
 	p.logger.Infof("Successfully updated the changelog: %s", p.changelogFile)
 
-	return newContent, nil
+	return newContent + regeneratedContent, nil
+}
+
+// replaceReleases replaces the sections of existing releases with their regenerated content.
+// A section ends at the next top-level or second-level heading, so any hand-written content outside of the sections is kept.
+func replaceReleases(content string, regenerated map[string]string) string {
+	if len(regenerated) == 0 {
+		return content
+	}
+
+	var b strings.Builder
+	skip := false
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		heading := strings.TrimRight(line, "\n")
+
+		if sectionRegex.MatchString(heading) {
+			skip = false
+			if sm := h2Regex.FindStringSubmatch(heading); len(sm) == 4 {
+				if c, ok := regenerated[sm[1]]; ok {
+					b.WriteString(c)
+					skip = true
+					continue
+				}
+			}
+		}
+
+		if !skip {
+			b.WriteString(line)
+		}
+	}
+
+	return b.String()
 }
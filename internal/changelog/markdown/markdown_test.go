@@ -3,7 +3,9 @@ package markdown
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"text/template"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +15,15 @@ import (
 )
 
 var (
+	defaultHeader  = template.Must(parseTemplate("header", "", headerTemplate))
+	defaultRelease = template.Must(parseTemplate("release", "", releaseTemplate))
+
+	// defaultParseOptions has the titles of groups for the default spec.
+	defaultParseOptions = changelog.ParseOptions{
+		IssueGroups: []string{"Release Summary", "Removed", "Breaking Changes", "Deprecated", "New Features", "Enhancements", "Fixed Bugs", "Security Fixes", "Closed Issues"},
+		MergeGroups: []string{"Merged Changes"},
+	}
+
 	tagTime, _ = time.Parse(time.RFC3339, "2020-11-02T22:00:00-04:00")
 	chlog      = &changelog.Changelog{
 		New: []changelog.Release{
@@ -69,8 +80,92 @@ var (
 			},
 		},
 	}
+
+	unlinkedChlog = &changelog.Changelog{
+		New: []changelog.Release{
+			{
+				TagName:    "v0.2.0",
+				TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+				TagTime:    tagTime,
+				CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+				MergeGroups: []changelog.MergeGroup{
+					{
+						Title: "Merged Changes",
+						Merges: []changelog.Merge{
+							{
+								Number: 1002,
+								Title:  "Add a feature",
+								OpenedBy: changelog.User{
+									Username: "octocat",
+								},
+								MergedBy: changelog.User{
+									Name:     "The Octodog",
+									Username: "The Octodog",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
 )
 
+var customChlog = &changelog.Changelog{
+	New: []changelog.Release{
+		{
+			TagName:    "v0.2.0",
+			TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+			TagTime:    tagTime,
+			CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+			IssueGroups: []changelog.IssueGroup{
+				{
+					Title: "Fixed Bugs",
+					Issues: []changelog.Issue{
+						{
+							Number:   1001,
+							Title:    "Fixed a bug",
+							URL:      "https://github.com/octocat/Hello-World/issues/1001",
+							Labels:   []string{"bug", "critical"},
+							OpenedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+							ClosedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+						},
+					},
+				},
+			},
+			MergeGroups: []changelog.MergeGroup{
+				{
+					Title: "New Features",
+					Merges: []changelog.Merge{
+						{
+							Number:   1002,
+							Title:    "Add a feature",
+							OpenedBy: changelog.User{Username: "octocat"},
+							MergedBy: changelog.User{Username: "octodog"},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+const expectedCustomChangelog = `# 📦 Changelog
+
+## [v0.2.0](https://github.com/octocat/Hello-World/tree/v0.2.0) (2020-11-02)
+
+_Released on November 2, 2020_
+
+### 🐛 Fixed Bugs
+
+* Fixed a bug ([#1001](https://github.com/octocat/Hello-World/issues/1001)) _bug, critical_
+
+### ✨ New Features
+
+* Add a feature (#1002) by octocat
+
+`
+
 const expectedChangelog = `# Changelog
 
 **DO NOT MODIFY THIS FILE!**
@@ -92,6 +187,23 @@ https://storage.artifactory.com/project/releases/v0.2.0
   - Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))
 
 
+`
+
+const expectedUnlinkedChangelog = `# Changelog
+
+**DO NOT MODIFY THIS FILE!**
+*This changelog is automatically generated by [changelog](https://github.com/moorara/changelog)*
+
+
+## [v0.2.0](https://github.com/octocat/Hello-World/tree/v0.2.0) (2020-11-02)
+
+[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0)
+
+**Merged Changes:**
+
+  - Add a feature #1002 (octocat, The Octodog)
+
+
 `
 
 const expectedChangelogWithBase = `# Changelog
@@ -125,31 +237,236 @@ func TestNewProcessor(t *testing.T) {
 		logger        log.Logger
 		baseFile      string
 		changelogFile string
+		templates     Templates
+		expectedError string
 	}{
 		{
-			name:          "OK",
+			name:          "DefaultTemplates",
+			logger:        log.New(log.None),
+			baseFile:      "HISTORY.md",
+			changelogFile: "CHANGELOG.md",
+			templates:     Templates{},
+		},
+		{
+			name:          "CustomTemplates",
 			logger:        log.New(log.None),
 			baseFile:      "HISTORY.md",
 			changelogFile: "CHANGELOG.md",
+			templates: Templates{
+				Header:  "test/header.tmpl",
+				Release: "test/release.tmpl",
+			},
+		},
+		{
+			name:          "HeaderTemplateNotFound",
+			logger:        log.New(log.None),
+			changelogFile: "CHANGELOG.md",
+			templates: Templates{
+				Header: "test/missing.tmpl",
+			},
+			expectedError: "open test/missing.tmpl: no such file or directory",
+		},
+		{
+			name:          "ReleaseTemplateNotFound",
+			logger:        log.New(log.None),
+			changelogFile: "CHANGELOG.md",
+			templates: Templates{
+				Release: "test/missing.tmpl",
+			},
+			expectedError: "open test/missing.tmpl: no such file or directory",
+		},
+		{
+			name:          "InvalidTemplate",
+			logger:        log.New(log.None),
+			changelogFile: "CHANGELOG.md",
+			templates: Templates{
+				Release: "test/invalid.tmpl",
+			},
+			expectedError: `template: release:1: function "unknown" not defined`,
+		},
+		{
+			name:          "HeaderTemplateExecutionFails",
+			logger:        log.New(log.None),
+			changelogFile: "CHANGELOG.md",
+			templates: Templates{
+				Header: "test/unknown-field.tmpl",
+			},
+			expectedError: `template: header:1:5: executing "header" at <.Unknown>: can't evaluate field Unknown in type *changelog.Changelog`,
+		},
+		{
+			name:          "ReleaseTemplateExecutionFails",
+			logger:        log.New(log.None),
+			changelogFile: "CHANGELOG.md",
+			templates: Templates{
+				Release: "test/unknown-field.tmpl",
+			},
+			expectedError: `template: release:1:5: executing "release" at <.Unknown>: can't evaluate field Unknown in type changelog.Release`,
+		},
+		{
+			name:          "NoReleaseHeading",
+			logger:        log.New(log.None),
+			changelogFile: "CHANGELOG.md",
+			templates: Templates{
+				Release: "test/no-heading.tmpl",
+			},
+			expectedError: "release template should start with the release heading: ## [{{.TagName}}]({{.TagURL}}) ({{time .TagTime}})",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			p := NewProcessor(tc.logger, tc.baseFile, tc.changelogFile)
-			assert.NotNil(t, p)
+			p, err := NewProcessor(tc.logger, tc.baseFile, tc.changelogFile, tc.templates)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, p)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, p)
+
+				mp, ok := p.(*processor)
+				assert.True(t, ok)
+
+				assert.Equal(t, tc.logger, mp.logger)
+				assert.Equal(t, tc.baseFile, mp.baseFile)
+				assert.Equal(t, tc.changelogFile, mp.changelogFile)
+				assert.NotNil(t, mp.header)
+				assert.NotNil(t, mp.release)
+				assert.Empty(t, mp.content)
+			}
+		})
+	}
+}
+
+func TestH2Regex(t *testing.T) {
+	tests := []struct {
+		name            string
+		line            string
+		expectedMatch   bool
+		expectedTagName string
+		expectedTagURL  string
+	}{
+		{
+			name:            "SemVer",
+			line:            "## [v1.2.0](https://github.com/octocat/Hello-World/tree/v1.2.0) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "v1.2.0",
+			expectedTagURL:  "https://github.com/octocat/Hello-World/tree/v1.2.0",
+		},
+		{
+			name:            "PreRelease",
+			line:            "## [v1.2.0-rc.1](https://github.com/octocat/Hello-World/tree/v1.2.0-rc.1) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "v1.2.0-rc.1",
+			expectedTagURL:  "https://github.com/octocat/Hello-World/tree/v1.2.0-rc.1",
+		},
+		{
+			name:            "BuildMetadata",
+			line:            "## [v1.2.0+build.5](https://github.com/octocat/Hello-World/tree/v1.2.0+build.5) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "v1.2.0+build.5",
+			expectedTagURL:  "https://github.com/octocat/Hello-World/tree/v1.2.0+build.5",
+		},
+		{
+			name:            "Slash",
+			line:            "## [api/v2.0.0](https://github.com/octocat/Hello-World/tree/api/v2.0.0) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "api/v2.0.0",
+			expectedTagURL:  "https://github.com/octocat/Hello-World/tree/api/v2.0.0",
+		},
+		{
+			name:            "EscapedSlash",
+			line:            "## [api/v2.0.0](https://gitlab.com/octocat/Hello-World/-/tags/api%2Fv2.0.0) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "api/v2.0.0",
+			expectedTagURL:  "https://gitlab.com/octocat/Hello-World/-/tags/api%2Fv2.0.0",
+		},
+		{
+			name:            "Underscore",
+			line:            "## [release_1](https://dev.azure.com/octocat/Hello_World/_git/Hello_World?version=GTrelease_1) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "release_1",
+			expectedTagURL:  "https://dev.azure.com/octocat/Hello_World/_git/Hello_World?version=GTrelease_1",
+		},
+		{
+			name:            "Unicode",
+			line:            "## [v2.0.0-β.1](https://github.com/octocat/Hello-World/tree/v2.0.0-β.1) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "v2.0.0-β.1",
+			expectedTagURL:  "https://github.com/octocat/Hello-World/tree/v2.0.0-β.1",
+		},
+		{
+			name:            "Symbols",
+			line:            "## [v1.0!@#$%&=,;'](https://github.com/octocat/Hello-World/tree/v1.0) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "v1.0!@#$%&=,;'",
+			expectedTagURL:  "https://github.com/octocat/Hello-World/tree/v1.0",
+		},
+		{
+			name:            "ClosingBracket",
+			line:            "## [v1.0]](https://github.com/octocat/Hello-World/tree/v1.0%5D) (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "v1.0]",
+			expectedTagURL:  "https://github.com/octocat/Hello-World/tree/v1.0%5D",
+		},
+		{
+			name:            "NoURL",
+			line:            "## [v1.0.0]() (2020-10-10)",
+			expectedMatch:   true,
+			expectedTagName: "v1.0.0",
+			expectedTagURL:  "",
+		},
+		{
+			name:          "Whitespace",
+			line:          "## [v1.0 beta](https://github.com/octocat/Hello-World/tree/v1.0) (2020-10-10)",
+			expectedMatch: false,
+		},
+		{
+			name:          "NoDate",
+			line:          "## [v1.0.0](https://github.com/octocat/Hello-World/tree/v1.0.0)",
+			expectedMatch: false,
+		},
+		{
+			name:          "InvalidDate",
+			line:          "## [v1.0.0](https://github.com/octocat/Hello-World/tree/v1.0.0) (10/10/2020)",
+			expectedMatch: false,
+		},
+		{
+			name:          "Group",
+			line:          "## Fixed Bugs",
+			expectedMatch: false,
+		},
+	}
 
-			mp, ok := p.(*processor)
-			assert.True(t, ok)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sm := h2Regex.FindStringSubmatch(tc.line)
 
-			assert.Equal(t, tc.logger, mp.logger)
-			assert.Equal(t, tc.baseFile, mp.baseFile)
-			assert.Equal(t, tc.changelogFile, mp.changelogFile)
-			assert.Empty(t, mp.content)
+			if tc.expectedMatch {
+				assert.Len(t, sm, 4)
+				assert.Equal(t, tc.expectedTagName, sm[1])
+				assert.Equal(t, tc.expectedTagURL, sm[2])
+			} else {
+				assert.Nil(t, sm)
+			}
 		})
 	}
 }
 
+func TestNotes(t *testing.T) {
+	notes, err := Notes(sampleRelease)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://storage.artifactory.com/project/releases/v0.1.0\n\n"+
+		"[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.0.1...v0.1.0)\n\n"+
+		"**Fixed Bugs:**\n\n"+
+		"  - Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))\n\n"+
+		"**Merged Changes:**\n\n"+
+		"  - Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))\n\n"+
+		"**Enhancements:**\n\n"+
+		"  - **parser:** cache the compiled expressions ([c414d10](https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059))", notes)
+}
+
 func TestProcessor_createChangelog(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -160,7 +477,9 @@ func TestProcessor_createChangelog(t *testing.T) {
 		{
 			name: "OK",
 			p: &processor{
-				logger: log.New(log.None),
+				logger:  log.New(log.None),
+				header:  defaultHeader,
+				release: defaultRelease,
 			},
 			expectedChangelog: &changelog.Changelog{
 				Title: "Changelog",
@@ -197,7 +516,9 @@ func TestProcessor_Parse(t *testing.T) {
 		{
 			name: "FileNotExist",
 			p: &processor{
-				logger: log.New(log.None),
+				logger:  log.New(log.None),
+				header:  defaultHeader,
+				release: defaultRelease,
 			},
 			opts: changelog.ParseOptions{},
 			expectedChangelog: &changelog.Changelog{
@@ -209,16 +530,75 @@ func TestProcessor_Parse(t *testing.T) {
 			name: "Success",
 			p: &processor{
 				logger:        log.New(log.None),
+				header:        defaultHeader,
+				release:       defaultRelease,
 				changelogFile: "test/CHANGELOG.md",
 			},
-			opts: changelog.ParseOptions{},
+			opts: defaultParseOptions,
 			expectedChangelog: &changelog.Changelog{
 				Title: "Changelog",
 				Existing: []changelog.Release{
 					{
-						TagName: "v0.1.1",
-						TagURL:  "https://github.com/octocat/Hello-World/tree/v0.1.1",
-						TagTime: time.Date(2020, time.October, 11, 0, 0, 0, 0, time.UTC),
+						TagName:    "v0.1.1",
+						TagURL:     "https://github.com/octocat/Hello-World/tree/v0.1.1",
+						TagTime:    time.Date(2020, time.October, 11, 0, 0, 0, 0, time.UTC),
+						ReleaseURL: "https://storage.artifactory.com/project/releases/v0.1.1",
+						CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1",
+						IssueGroups: []changelog.IssueGroup{
+							{
+								Title: "Fixed Bugs",
+								Issues: []changelog.Issue{
+									{
+										Number:   1001,
+										Title:    "Fixed a bug",
+										URL:      "https://github.com/octocat/Hello-World/issues/1001",
+										OpenedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+										ClosedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+									},
+									{
+										Number:   1003,
+										Title:    "Fixed &lt;nil&gt; pointer dereference",
+										URL:      "https://github.com/octocat/Hello-World/issues/1003",
+										OpenedBy: changelog.User{Username: "octodog", URL: "https://github.com/octodog"},
+										ClosedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+									},
+								},
+							},
+						},
+						MergeGroups: []changelog.MergeGroup{
+							{
+								Title: "Enhancements",
+								Merges: []changelog.Merge{
+									{
+										Number:   1004,
+										Title:    "Improved performance",
+										URL:      "https://github.com/octocat/Hello-World/pull/1004",
+										OpenedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+										MergedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+									},
+								},
+							},
+							{
+								Title: "Merged Changes",
+								Merges: []changelog.Merge{
+									{
+										Number:   1002,
+										Title:    "Add a feature",
+										OpenedBy: changelog.User{Username: "octocat"},
+										MergedBy: changelog.User{Username: "The Octodog"},
+									},
+								},
+							},
+						},
+						Notes: "https://storage.artifactory.com/project/releases/v0.1.1\n\n" +
+							"[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1)\n\n" +
+							"**Fixed Bugs:**\n\n" +
+							"  - Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))\n" +
+							"  - Fixed &lt;nil&gt; pointer dereference [#1003](https://github.com/octocat/Hello-World/issues/1003) ([octodog](https://github.com/octodog), [octocat](https://github.com/octocat))\n\n" +
+							"**Enhancements:**\n\n" +
+							"  - Improved performance [#1004](https://github.com/octocat/Hello-World/pull/1004) ([octocat](https://github.com/octocat))\n\n" +
+							"**Merged Changes:**\n\n" +
+							"  - Add a feature #1002 (octocat, The Octodog)",
 					},
 					{
 						TagName: "v0.1.0",
@@ -229,6 +609,65 @@ func TestProcessor_Parse(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "TrickyTags",
+			p: &processor{
+				logger:        log.New(log.None),
+				header:        defaultHeader,
+				release:       defaultRelease,
+				changelogFile: "test/TAGS.md",
+			},
+			opts: defaultParseOptions,
+			expectedChangelog: &changelog.Changelog{
+				Title: "Changelog",
+				Existing: []changelog.Release{
+					{
+						TagName:    "v1.2.0+build.5",
+						TagURL:     "https://github.com/octocat/Hello-World/tree/v1.2.0+build.5",
+						TagTime:    time.Date(2020, time.October, 16, 0, 0, 0, 0, time.UTC),
+						CompareURL: "https://github.com/octocat/Hello-World/compare/api%2Fv2.0.0...v1.2.0+build.5",
+						IssueGroups: []changelog.IssueGroup{
+							{
+								Title: "Fixed Bugs",
+								Issues: []changelog.Issue{
+									{
+										Number:   1001,
+										Title:    "Fixed a bug",
+										URL:      "https://github.com/octocat/Hello-World/issues/1001",
+										OpenedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+										ClosedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+									},
+								},
+							},
+						},
+						Notes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/api%2Fv2.0.0...v1.2.0+build.5)\n\n" +
+							"**Fixed Bugs:**\n\n" +
+							"  - Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))",
+					},
+					{
+						TagName: "api/v2.0.0",
+						TagURL:  "https://gitlab.com/octocat/Hello-World/-/tags/api%2Fv2.0.0",
+						TagTime: time.Date(2020, time.October, 15, 0, 0, 0, 0, time.UTC),
+					},
+					{
+						TagName: "v2.0.0-β.1",
+						TagURL:  "https://github.com/octocat/Hello-World/tree/v2.0.0-%CE%B2.1",
+						TagTime: time.Date(2020, time.October, 14, 0, 0, 0, 0, time.UTC),
+					},
+					{
+						TagName: "release_2020.10",
+						TagURL:  "https://github.com/octocat/Hello-World/tree/release_2020.10",
+						TagTime: time.Date(2020, time.October, 13, 0, 0, 0, 0, time.UTC),
+					},
+					{
+						TagName: "v0.1.0",
+						TagURL:  "",
+						TagTime: time.Date(2020, time.October, 10, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			expectedError: "",
+		},
 	}
 
 	for _, tc := range tests {
@@ -259,7 +698,9 @@ func TestProcessor_Render(t *testing.T) {
 		{
 			name: "WithoutBaseFile",
 			p: &processor{
-				logger: log.New(log.None),
+				logger:  log.New(log.None),
+				header:  defaultHeader,
+				release: defaultRelease,
 			},
 			chlog:             chlog,
 			expectedError:     nil,
@@ -269,12 +710,36 @@ func TestProcessor_Render(t *testing.T) {
 			name: "WithBaseFile",
 			p: &processor{
 				logger:   log.New(log.None),
+				header:   defaultHeader,
+				release:  defaultRelease,
 				baseFile: "test/HISTORY.md",
 			},
 			chlog:             chlog,
 			expectedError:     nil,
 			expectedChangelog: expectedChangelogWithBase,
 		},
+		{
+			name: "CustomTemplates",
+			p: &processor{
+				logger:  log.New(log.None),
+				header:  template.Must(parseTemplate("header", "test/header.tmpl", "")),
+				release: template.Must(parseTemplate("release", "test/release.tmpl", "")),
+			},
+			chlog:             customChlog,
+			expectedError:     nil,
+			expectedChangelog: expectedCustomChangelog,
+		},
+		{
+			name: "WithoutURLs",
+			p: &processor{
+				logger:  log.New(log.None),
+				header:  defaultHeader,
+				release: defaultRelease,
+			},
+			chlog:             unlinkedChlog,
+			expectedError:     nil,
+			expectedChangelog: expectedUnlinkedChangelog,
+		},
 	}
 
 	for _, tc := range tests {
@@ -297,3 +762,318 @@ func TestProcessor_Render(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessor_Render_BaseFileError(t *testing.T) {
+	p := &processor{
+		logger:        log.New(log.None),
+		baseFile:      "test/NOT_EXIST.md",
+		changelogFile: filepath.Join(t.TempDir(), "CHANGELOG.md"),
+		header:        defaultHeader,
+		release:       defaultRelease,
+	}
+
+	err := ioutil.WriteFile(p.changelogFile, []byte("# Changelog\n"), 0644)
+	assert.NoError(t, err)
+
+	_, err = p.Parse(defaultParseOptions)
+	assert.NoError(t, err)
+
+	_, err = p.Render(chlog)
+	assert.Error(t, err)
+
+	// The changelog file should not be changed if the content cannot be built
+	b, err := ioutil.ReadFile(p.changelogFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Changelog\n", string(b))
+}
+
+func TestProcessor_Render_Regenerated(t *testing.T) {
+	p := &processor{
+		logger:        log.New(log.None),
+		changelogFile: filepath.Join(t.TempDir(), "CHANGELOG.md"),
+		header:        defaultHeader,
+		release:       defaultRelease,
+	}
+
+	err := ioutil.WriteFile(p.changelogFile, []byte(handWrittenChangelog), 0644)
+	assert.NoError(t, err)
+
+	chlog, err := p.Parse(defaultParseOptions)
+	assert.NoError(t, err)
+
+	chlog.Regenerated = []changelog.Release{
+		chlog.Existing[0],
+		{
+			TagName:    "v0.2.0",
+			TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+			TagTime:    tagTime,
+			CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+			MergeGroups: []changelog.MergeGroup{
+				{
+					Title: "Enhancements",
+					Merges: []changelog.Merge{
+						{
+							Number:   1002,
+							Title:    "Add a feature",
+							URL:      "https://github.com/octocat/Hello-World/pull/1002",
+							OpenedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+							MergedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+						},
+					},
+				},
+			},
+		},
+	}
+	chlog.Regenerated[0].IssueGroups[0].Title = "Security Fixes"
+
+	_, err = p.Render(chlog)
+	assert.NoError(t, err)
+
+	b, err := ioutil.ReadFile(p.changelogFile)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRegeneratedChangelog, string(b))
+}
+
+func TestProcessor_Render_Parse(t *testing.T) {
+	tests := []struct {
+		name             string
+		chlog            *changelog.Changelog
+		expectedReleases []changelog.Release
+	}{
+		{
+			name:  "Linked",
+			chlog: chlog,
+			expectedReleases: []changelog.Release{
+				{
+					TagName:    "v0.2.0",
+					TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+					TagTime:    time.Date(2020, time.November, 2, 0, 0, 0, 0, time.UTC),
+					ReleaseURL: "https://storage.artifactory.com/project/releases/v0.2.0",
+					CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+					IssueGroups: []changelog.IssueGroup{
+						{
+							Title: "Fixed Bugs",
+							Issues: []changelog.Issue{
+								{
+									Number:   1001,
+									Title:    "Fixed a bug",
+									URL:      "https://github.com/octocat/Hello-World/issues/1001",
+									OpenedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+									ClosedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+								},
+							},
+						},
+					},
+					MergeGroups: []changelog.MergeGroup{
+						{
+							Title: "Merged Changes",
+							Merges: []changelog.Merge{
+								{
+									Number:   1002,
+									Title:    "Add a feature",
+									URL:      "https://github.com/octocat/Hello-World/pull/1002",
+									OpenedBy: changelog.User{Username: "octocat", URL: "https://github.com/octocat"},
+									MergedBy: changelog.User{Username: "octodog", URL: "https://github.com/octodog"},
+								},
+							},
+						},
+					},
+					Notes: "https://storage.artifactory.com/project/releases/v0.2.0\n\n" +
+						"[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0)\n\n" +
+						"**Fixed Bugs:**\n\n" +
+						"  - Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))\n\n" +
+						"**Merged Changes:**\n\n" +
+						"  - Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat), [octodog](https://github.com/octodog))",
+				},
+			},
+		},
+		{
+			name:  "Unlinked",
+			chlog: unlinkedChlog,
+			expectedReleases: []changelog.Release{
+				{
+					TagName:    "v0.2.0",
+					TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+					TagTime:    time.Date(2020, time.November, 2, 0, 0, 0, 0, time.UTC),
+					CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+					MergeGroups: []changelog.MergeGroup{
+						{
+							Title: "Merged Changes",
+							Merges: []changelog.Merge{
+								{
+									Number:   1002,
+									Title:    "Add a feature",
+									OpenedBy: changelog.User{Username: "octocat"},
+									MergedBy: changelog.User{Username: "The Octodog"},
+								},
+							},
+						},
+					},
+					Notes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0)\n\n" +
+						"**Merged Changes:**\n\n" +
+						"  - Add a feature #1002 (octocat, The Octodog)",
+				},
+			},
+		},
+		{
+			name: "Commits",
+			chlog: &changelog.Changelog{
+				New: []changelog.Release{
+					{
+						TagName:    "v0.2.0",
+						TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+						TagTime:    tagTime,
+						CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+						CommitGroups: []changelog.CommitGroup{
+							{
+								Title: "New Features",
+								Commits: []changelog.Commit{
+									{
+										Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+										Type:    "feat",
+										Scope:   "cli",
+										Subject: "add a flag",
+										URL:     "https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059",
+									},
+								},
+							},
+							{
+								Title: "Fixed Bugs",
+								Commits: []changelog.Commit{
+									{Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e", Type: "fix", Subject: "handle empty lines"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedReleases: []changelog.Release{
+				{
+					TagName:    "v0.2.0",
+					TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+					TagTime:    time.Date(2020, time.November, 2, 0, 0, 0, 0, time.UTC),
+					CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+					CommitGroups: []changelog.CommitGroup{
+						{
+							Title: "New Features",
+							Commits: []changelog.Commit{
+								{
+									Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+									Scope:   "cli",
+									Subject: "add a flag",
+									URL:     "https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059",
+								},
+							},
+						},
+						{
+							Title: "Fixed Bugs",
+							Commits: []changelog.Commit{
+								{Hash: "6dcb09b", Subject: "handle empty lines"},
+							},
+						},
+					},
+					Notes: "[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0)\n\n" +
+						"**New Features:**\n\n" +
+						"  - **cli:** add a flag ([c414d10](https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059))\n\n" +
+						"**Fixed Bugs:**\n\n" +
+						"  - handle empty lines (6dcb09b)",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changelogFile := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+			p := &processor{
+				logger:        log.New(log.None),
+				changelogFile: changelogFile,
+				header:        defaultHeader,
+				release:       defaultRelease,
+			}
+
+			_, err := p.Parse(changelog.ParseOptions{})
+			assert.NoError(t, err)
+
+			_, err = p.Render(tc.chlog)
+			assert.NoError(t, err)
+
+			p = &processor{
+				logger:        log.New(log.None),
+				changelogFile: changelogFile,
+				header:        defaultHeader,
+				release:       defaultRelease,
+			}
+
+			parsed, err := p.Parse(defaultParseOptions)
+			assert.NoError(t, err)
+			assert.Equal(t, "Changelog", parsed.Title)
+			assert.Equal(t, tc.expectedReleases, parsed.Existing)
+		})
+	}
+}
+
+const handWrittenChangelog = `# Changelog
+
+This changelog has some hand-written content.
+
+
+## [v0.3.0](https://github.com/octocat/Hello-World/tree/v0.3.0) (2020-11-03)
+
+[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.2.0...v0.3.0)
+
+**Fixed Bugs:**
+
+  - Fixed a bug [#1003](https://github.com/octocat/Hello-World/issues/1003) ([octocat](https://github.com/octocat))
+
+
+## Upgrade Notes
+
+Read this before upgrading to v0.3.0.
+
+## [v0.2.0](https://github.com/octocat/Hello-World/tree/v0.2.0) (2020-11-02)
+
+[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0)
+
+**Merged Changes:**
+
+  - Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat))
+
+
+## [v0.1.0](https://github.com/octocat/Hello-World/tree/v0.1.0) (2020-10-10)
+
+The first release.
+`
+
+const expectedRegeneratedChangelog = `# Changelog
+
+This changelog has some hand-written content.
+
+
+## [v0.3.0](https://github.com/octocat/Hello-World/tree/v0.3.0) (2020-11-03)
+
+[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.2.0...v0.3.0)
+
+**Security Fixes:**
+
+  - Fixed a bug [#1003](https://github.com/octocat/Hello-World/issues/1003) ([octocat](https://github.com/octocat))
+
+
+## Upgrade Notes
+
+Read this before upgrading to v0.3.0.
+
+## [v0.2.0](https://github.com/octocat/Hello-World/tree/v0.2.0) (2020-11-02)
+
+[Compare Changes](https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0)
+
+**Enhancements:**
+
+  - Add a feature [#1002](https://github.com/octocat/Hello-World/pull/1002) ([octocat](https://github.com/octocat))
+
+
+## [v0.1.0](https://github.com/octocat/Hello-World/tree/v0.1.0) (2020-10-10)
+
+The first release.
+`
@@ -0,0 +1,230 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/moorara/changelog/internal/changelog"
+)
+
+// The following expressions match the lines rendered by the default release template.
+var (
+	compareRegex = regexp.MustCompile(`^\[Compare Changes\]\((\S+)\)$`)
+	urlRegex     = regexp.MustCompile(`^https?://\S+$`)
+	groupRegex   = regexp.MustCompile(`^\*\*(.+):\*\*$`)
+	entryRegex   = regexp.MustCompile(`^  - (.*) (?:\[#(\d+)\]\((\S+)\)|#(\d+)) \((.*)\)$`)
+	userRegex    = regexp.MustCompile(`\s*(?:\[([^\]]+)\]\(([^)\s]+)\)|([^,]+))`)
+	// The web URL of a commit distinguishes a linked commit hash from a linked user.
+	commitRegex = regexp.MustCompile(`^  - (?:\*\*([^*]+):\*\* )?(.*) \((?:\[([0-9a-f]{7,40})\]\((\S+/commits?/[0-9a-f]+)\)|([0-9a-f]{7,40}))\)$`)
+)
+
+// The following paths tell the web URLs of merges and issues apart on the supported platforms.
+var (
+	mergePaths = []string{"/pull/", "/pulls/", "/merge_requests/", "/pull-requests/", "/pullrequest/"}
+	issuePaths = []string{"/issues/", "/_workitems/"}
+)
+
+// entry is an issue or a merge parsed from a changelog.
+type entry struct {
+	Number int
+	Title  string
+	URL    string
+	Users  []changelog.User
+}
+
+// group is a group of issues, merges, or commits parsed from a changelog.
+type group struct {
+	Title   string
+	Entries []entry
+	Commits []changelog.Commit
+}
+
+// matchTitle determines whether or not a rendered group title matches a configured one.
+// Templates may change the case of titles, and a configured title ending with * matches any title with the same prefix.
+func matchTitle(configured, title string) bool {
+	if prefix := strings.TrimSuffix(configured, "*"); prefix != configured {
+		return len(title) >= len(prefix) && strings.EqualFold(title[:len(prefix)], prefix)
+	}
+
+	return strings.EqualFold(title, configured)
+}
+
+// indexTitle returns the position of a group title in the configured titles of issue groups.
+// The search starts from a given position, since issue groups are rendered in the configured order.
+// A title ending with * can match more than one group, so the next search starts from the same title.
+func indexTitle(titles []string, from int, title string) (int, bool) {
+	for i := from; i < len(titles); i++ {
+		if matchTitle(titles[i], title) {
+			if strings.HasSuffix(titles[i], "*") {
+				return i, true
+			}
+			return i + 1, true
+		}
+	}
+
+	return from, false
+}
+
+// parseUsers parses a list of linked or unlinked users (i.e. [octocat](https://github.com/octocat), The Octodog).
+func parseUsers(s string) []changelog.User {
+	users := []changelog.User{}
+	for _, sm := range userRegex.FindAllStringSubmatch(s, -1) {
+		if sm[1] != "" {
+			users = append(users, changelog.User{Username: sm[1], URL: sm[2]})
+		} else if username := strings.TrimSpace(sm[3]); username != "" {
+			users = append(users, changelog.User{Username: username})
+		}
+	}
+
+	return users
+}
+
+// parseEntry parses an issue or a merge line (i.e.   - Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))).
+func parseEntry(line string) (entry, bool) {
+	sm := entryRegex.FindStringSubmatch(line)
+	if len(sm) != 6 {
+		return entry{}, false
+	}
+
+	num := sm[2]
+	if num == "" {
+		num = sm[4]
+	}
+
+	number, err := strconv.Atoi(num)
+	if err != nil {
+		return entry{}, false
+	}
+
+	return entry{
+		Number: number,
+		Title:  sm[1],
+		URL:    sm[3],
+		Users:  parseUsers(sm[5]),
+	}, true
+}
+
+// parseCommit parses a commit line (i.e.   - **parser:** handle empty lines ([c414d10](https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059))).
+// Only the abbreviated hash of a commit is rendered, so the full hash is recovered from the web URL if possible.
+func parseCommit(line string) (changelog.Commit, bool) {
+	sm := commitRegex.FindStringSubmatch(line)
+	if len(sm) != 6 {
+		return changelog.Commit{}, false
+	}
+
+	hash, url := sm[3], sm[4]
+	if hash == "" {
+		hash = sm[5]
+	} else if i := strings.LastIndex(url, "/"); strings.HasPrefix(url[i+1:], hash) {
+		hash = url[i+1:]
+	}
+
+	return changelog.Commit{
+		Hash:    hash,
+		Scope:   sm[1],
+		Subject: sm[2],
+		URL:     url,
+	}, true
+}
+
+// isMergeURL determines whether an entry is a merge or an issue by its URL.
+// The second return value is false if the URL is not recognized as either.
+func isMergeURL(url string) (bool, bool) {
+	for _, p := range mergePaths {
+		if strings.Contains(url, p) {
+			return true, true
+		}
+	}
+
+	for _, p := range issuePaths {
+		if strings.Contains(url, p) {
+			return false, true
+		}
+	}
+
+	return false, false
+}
+
+// setGroups adds the parsed groups to a release.
+// Each entry is classified as an issue or a merge by its URL (i.e. /issues/ or /pull/).
+// If the URL of an entry is not recognized, the entry is classified by the title of its group.
+// Issue groups are always rendered before merge groups and in the order of the configured titles.
+// So, the first group that does not follow the previous issue group in the configured titles is a merge group,
+// and all groups after the first merge group are merge groups too.
+// Groups of commits are told apart by their entries, since they are rendered differently.
+// The opener of an issue or a merge is only rendered if it is different from the closer or the merger.
+func setGroups(r *changelog.Release, groups []group, opts changelog.ParseOptions) {
+	merges := len(opts.IssueGroups) == 0
+	pos := 0
+
+	for _, g := range groups {
+		if len(g.Commits) > 0 {
+			r.CommitGroups = append(r.CommitGroups, changelog.CommitGroup{Title: g.Title, Commits: g.Commits})
+			continue
+		}
+
+		if !merges && len(opts.MergeGroups) > 0 {
+			var ok bool
+			pos, ok = indexTitle(opts.IssueGroups, pos, g.Title)
+			merges = !ok
+		}
+
+		ig := changelog.IssueGroup{Title: g.Title}
+		mg := changelog.MergeGroup{Title: g.Title}
+
+		for _, e := range g.Entries {
+			isMerge, ok := isMergeURL(e.URL)
+			if !ok {
+				isMerge = merges
+			}
+
+			if isMerge {
+				opener, merger := users(e.Users)
+				mg.Merges = append(mg.Merges, changelog.Merge{
+					Number:   e.Number,
+					Title:    e.Title,
+					URL:      e.URL,
+					OpenedBy: opener,
+					MergedBy: merger,
+				})
+			} else {
+				opener, closer := users(e.Users)
+				ig.Issues = append(ig.Issues, changelog.Issue{
+					Number:   e.Number,
+					Title:    e.Title,
+					URL:      e.URL,
+					OpenedBy: opener,
+					ClosedBy: closer,
+				})
+			}
+		}
+
+		// The entries take precedence over the titles for telling the following groups apart
+		if len(mg.Merges) > 0 {
+			merges = true
+		} else if len(ig.Issues) > 0 {
+			merges = false
+		}
+
+		if len(ig.Issues) > 0 || (len(g.Entries) == 0 && !merges) {
+			r.IssueGroups = append(r.IssueGroups, ig)
+		}
+
+		if len(mg.Merges) > 0 || (len(g.Entries) == 0 && merges) {
+			r.MergeGroups = append(r.MergeGroups, mg)
+		}
+	}
+}
+
+// users returns the opener and the closer (or the merger) of an issue (or a merge).
+func users(us []changelog.User) (changelog.User, changelog.User) {
+	switch len(us) {
+	case 0:
+		return changelog.User{}, changelog.User{}
+	case 1:
+		return us[0], us[0]
+	default:
+		return us[0], us[1]
+	}
+}
@@ -0,0 +1,439 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/changelog"
+)
+
+func TestMatchTitle(t *testing.T) {
+	tests := []struct {
+		name          string
+		configured    string
+		title         string
+		expectedMatch bool
+	}{
+		{
+			name:          "Equal",
+			configured:    "Fixed Bugs",
+			title:         "Fixed Bugs",
+			expectedMatch: true,
+		},
+		{
+			name:          "DifferentCase",
+			configured:    "Fixed Bugs",
+			title:         "FIXED BUGS",
+			expectedMatch: true,
+		},
+		{
+			name:          "NotEqual",
+			configured:    "Fixed Bugs",
+			title:         "Fixed",
+			expectedMatch: false,
+		},
+		{
+			name:          "Prefix",
+			configured:    "Milestone *",
+			title:         "Milestone V1.0",
+			expectedMatch: true,
+		},
+		{
+			name:          "NoPrefix",
+			configured:    "Milestone *",
+			title:         "Milestone",
+			expectedMatch: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedMatch, matchTitle(tc.configured, tc.title))
+		})
+	}
+}
+
+func TestIndexTitle(t *testing.T) {
+	titles := []string{"Milestone *", "Fixed Bugs", "Closed Issues"}
+
+	tests := []struct {
+		name          string
+		from          int
+		title         string
+		expectedIndex int
+		expectedOK    bool
+	}{
+		{
+			name:          "Prefix",
+			from:          0,
+			title:         "Milestone v1.0",
+			expectedIndex: 0,
+			expectedOK:    true,
+		},
+		{
+			name:          "After",
+			from:          0,
+			title:         "Fixed Bugs",
+			expectedIndex: 2,
+			expectedOK:    true,
+		},
+		{
+			name:          "Before",
+			from:          2,
+			title:         "Fixed Bugs",
+			expectedIndex: 2,
+			expectedOK:    false,
+		},
+		{
+			name:          "NotFound",
+			from:          0,
+			title:         "Merged Changes",
+			expectedIndex: 0,
+			expectedOK:    false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			index, ok := indexTitle(titles, tc.from, tc.title)
+
+			assert.Equal(t, tc.expectedIndex, index)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestIsMergeURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		expectedMerge bool
+		expectedOK    bool
+	}{
+		{
+			name:       "NoURL",
+			url:        "",
+			expectedOK: false,
+		},
+		{
+			name:          "GitHubIssue",
+			url:           "https://github.com/octocat/Hello-World/issues/1001",
+			expectedMerge: false,
+			expectedOK:    true,
+		},
+		{
+			name:          "GitHubPullRequest",
+			url:           "https://github.com/octocat/Hello-World/pull/1002",
+			expectedMerge: true,
+			expectedOK:    true,
+		},
+		{
+			name:          "GitLabMergeRequest",
+			url:           "https://gitlab.com/octocat/Hello-World/-/merge_requests/1002",
+			expectedMerge: true,
+			expectedOK:    true,
+		},
+		{
+			name:          "GiteaPullRequest",
+			url:           "https://gitea.com/octocat/Hello-World/pulls/1002",
+			expectedMerge: true,
+			expectedOK:    true,
+		},
+		{
+			name:          "BitbucketPullRequest",
+			url:           "https://bitbucket.org/octocat/hello-world/pull-requests/1002",
+			expectedMerge: true,
+			expectedOK:    true,
+		},
+		{
+			name:          "AzureDevOpsWorkItem",
+			url:           "https://dev.azure.com/octocat/Hello/_workitems/edit/1001",
+			expectedMerge: false,
+			expectedOK:    true,
+		},
+		{
+			name:          "AzureDevOpsPullRequest",
+			url:           "https://dev.azure.com/octocat/Hello/_git/Hello-World/pullrequest/1002",
+			expectedMerge: true,
+			expectedOK:    true,
+		},
+		{
+			name:       "Unknown",
+			url:        "https://example.com/octocat/Hello-World/1001",
+			expectedOK: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merge, ok := isMergeURL(tc.url)
+			assert.Equal(t, tc.expectedMerge, merge)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestSetGroups(t *testing.T) {
+	groups := []group{
+		{Title: "Fixed Bugs", Entries: []entry{{Number: 1001}}},
+		{Title: "Fixed Bugs", Entries: []entry{{Number: 1002}}},
+		{Title: "Merged Changes", Entries: []entry{{Number: 1003}}},
+		{Title: "Features", Commits: []changelog.Commit{{Hash: "c414d10"}}},
+	}
+
+	tests := []struct {
+		name            string
+		groups          []group
+		opts            changelog.ParseOptions
+		expectedRelease changelog.Release
+	}{
+		{
+			name:   "IssuesAndMerges",
+			groups: groups,
+			opts: changelog.ParseOptions{
+				IssueGroups: []string{"Fixed Bugs", "Closed Issues"},
+				MergeGroups: []string{"Fixed Bugs", "Merged Changes"},
+			},
+			expectedRelease: changelog.Release{
+				IssueGroups: []changelog.IssueGroup{
+					{Title: "Fixed Bugs", Issues: []changelog.Issue{{Number: 1001}}},
+				},
+				MergeGroups: []changelog.MergeGroup{
+					{Title: "Fixed Bugs", Merges: []changelog.Merge{{Number: 1002}}},
+					{Title: "Merged Changes", Merges: []changelog.Merge{{Number: 1003}}},
+				},
+				CommitGroups: []changelog.CommitGroup{
+					{Title: "Features", Commits: []changelog.Commit{{Hash: "c414d10"}}},
+				},
+			},
+		},
+		{
+			name:   "OnlyIssues",
+			groups: groups,
+			opts: changelog.ParseOptions{
+				IssueGroups: []string{"Fixed Bugs", "Closed Issues"},
+			},
+			expectedRelease: changelog.Release{
+				IssueGroups: []changelog.IssueGroup{
+					{Title: "Fixed Bugs", Issues: []changelog.Issue{{Number: 1001}}},
+					{Title: "Fixed Bugs", Issues: []changelog.Issue{{Number: 1002}}},
+					{Title: "Merged Changes", Issues: []changelog.Issue{{Number: 1003}}},
+				},
+				CommitGroups: []changelog.CommitGroup{
+					{Title: "Features", Commits: []changelog.Commit{{Hash: "c414d10"}}},
+				},
+			},
+		},
+		{
+			name:   "OnlyMerges",
+			groups: groups,
+			opts: changelog.ParseOptions{
+				MergeGroups: []string{"Fixed Bugs", "Merged Changes"},
+			},
+			expectedRelease: changelog.Release{
+				MergeGroups: []changelog.MergeGroup{
+					{Title: "Fixed Bugs", Merges: []changelog.Merge{{Number: 1001}}},
+					{Title: "Fixed Bugs", Merges: []changelog.Merge{{Number: 1002}}},
+					{Title: "Merged Changes", Merges: []changelog.Merge{{Number: 1003}}},
+				},
+				CommitGroups: []changelog.CommitGroup{
+					{Title: "Features", Commits: []changelog.Commit{{Hash: "c414d10"}}},
+				},
+			},
+		},
+		{
+			// The merge group follows the issue group in the configured titles of issue groups
+			name: "MergeGroupAfterIssueGroup",
+			groups: []group{
+				{Title: "New Features", Entries: []entry{{Number: 1, URL: "https://github.com/octocat/Hello-World/issues/1"}}},
+				{Title: "Fixed Bugs", Entries: []entry{{Number: 2, URL: "https://github.com/octocat/Hello-World/pull/2"}}},
+			},
+			opts: changelog.ParseOptions{
+				IssueGroups: []string{"New Features", "Fixed Bugs"},
+				MergeGroups: []string{"New Features", "Fixed Bugs"},
+			},
+			expectedRelease: changelog.Release{
+				IssueGroups: []changelog.IssueGroup{
+					{Title: "New Features", Issues: []changelog.Issue{{Number: 1, URL: "https://github.com/octocat/Hello-World/issues/1"}}},
+				},
+				MergeGroups: []changelog.MergeGroup{
+					{Title: "Fixed Bugs", Merges: []changelog.Merge{{Number: 2, URL: "https://github.com/octocat/Hello-World/pull/2"}}},
+				},
+			},
+		},
+		{
+			// Entries with unrecognized URLs follow the last group told apart by its entries
+			name: "UnrecognizedURLs",
+			groups: []group{
+				{Title: "Fixed Bugs", Entries: []entry{{Number: 1001, URL: "https://gitlab.com/octocat/Hello-World/-/merge_requests/1001"}}},
+				{Title: "Closed Issues", Entries: []entry{{Number: 1002, URL: "https://example.com/1002"}}},
+			},
+			opts: changelog.ParseOptions{
+				IssueGroups: []string{"Fixed Bugs", "Closed Issues"},
+				MergeGroups: []string{"Fixed Bugs", "Closed Issues"},
+			},
+			expectedRelease: changelog.Release{
+				MergeGroups: []changelog.MergeGroup{
+					{Title: "Fixed Bugs", Merges: []changelog.Merge{{Number: 1001, URL: "https://gitlab.com/octocat/Hello-World/-/merge_requests/1001"}}},
+					{Title: "Closed Issues", Merges: []changelog.Merge{{Number: 1002, URL: "https://example.com/1002"}}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			release := changelog.Release{}
+			setGroups(&release, tc.groups, tc.opts)
+			assert.Equal(t, tc.expectedRelease, release)
+		})
+	}
+}
+
+func TestParseUsers(t *testing.T) {
+	tests := []struct {
+		name          string
+		s             string
+		expectedUsers []changelog.User
+	}{
+		{
+			name:          "Empty",
+			s:             "",
+			expectedUsers: []changelog.User{},
+		},
+		{
+			name: "Linked",
+			s:    "[octocat](https://github.com/octocat), [octodog](https://github.com/octodog)",
+			expectedUsers: []changelog.User{
+				{Username: "octocat", URL: "https://github.com/octocat"},
+				{Username: "octodog", URL: "https://github.com/octodog"},
+			},
+		},
+		{
+			name: "Unlinked",
+			s:    "octocat, The Octodog",
+			expectedUsers: []changelog.User{
+				{Username: "octocat"},
+				{Username: "The Octodog"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedUsers, parseUsers(tc.s))
+		})
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		name          string
+		line          string
+		expectedOK    bool
+		expectedEntry entry
+	}{
+		{
+			name:       "NoEntry",
+			line:       "**Fixed Bugs:**",
+			expectedOK: false,
+		},
+		{
+			name:       "Linked",
+			line:       "  - Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))",
+			expectedOK: true,
+			expectedEntry: entry{
+				Number: 1001,
+				Title:  "Fixed a bug",
+				URL:    "https://github.com/octocat/Hello-World/issues/1001",
+				Users: []changelog.User{
+					{Username: "octocat", URL: "https://github.com/octocat"},
+				},
+			},
+		},
+		{
+			name:       "Unlinked",
+			line:       "  - Add a feature (#1000) #1002 (octocat, The Octodog)",
+			expectedOK: true,
+			expectedEntry: entry{
+				Number: 1002,
+				Title:  "Add a feature (#1000)",
+				Users: []changelog.User{
+					{Username: "octocat"},
+					{Username: "The Octodog"},
+				},
+			},
+		},
+		{
+			name:       "Escaped",
+			line:       "  - Fixed &lt;nil&gt; pointer &amp; &#34;panic&#34; #1003 (octocat)",
+			expectedOK: true,
+			expectedEntry: entry{
+				Number: 1003,
+				Title:  "Fixed &lt;nil&gt; pointer &amp; &#34;panic&#34;",
+				Users: []changelog.User{
+					{Username: "octocat"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e, ok := parseEntry(tc.line)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedEntry, e)
+		})
+	}
+}
+
+func TestParseCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		line           string
+		expectedOK     bool
+		expectedCommit changelog.Commit
+	}{
+		{
+			name:       "NoCommit",
+			line:       "  - Fixed a bug [#1001](https://github.com/octocat/Hello-World/issues/1001) ([octocat](https://github.com/octocat))",
+			expectedOK: false,
+		},
+		{
+			name:       "LinkedUser",
+			line:       "  - Fixed a bug #1001 ([c0ffee1](https://github.com/c0ffee1))",
+			expectedOK: false,
+		},
+		{
+			name:       "Linked",
+			line:       "  - **parser:** handle empty lines ([c414d10](https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059))",
+			expectedOK: true,
+			expectedCommit: changelog.Commit{
+				Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+				Scope:   "parser",
+				Subject: "handle empty lines",
+				URL:     "https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059",
+			},
+		},
+		{
+			name:       "Unlinked",
+			line:       "  - handle &lt;nil&gt; values (#1004) (c414d10)",
+			expectedOK: true,
+			expectedCommit: changelog.Commit{
+				Hash:    "c414d10",
+				Subject: "handle &lt;nil&gt; values (#1004)",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := parseCommit(tc.line)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedCommit, c)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Package schema defines the versioned schema for changelog files in data formats (JSON and YAML).
+package schema
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moorara/changelog/internal/changelog"
+)
+
+// Version is the version of the schema for changelog files in data formats.
+// It is only incremented for backward-incompatible changes, so consumers can safely ignore unknown fields.
+const Version = 1
+
+type (
+	// Document is the root of a changelog file.
+	Document struct {
+		Version  int       `json:"version" yaml:"version"`
+		Title    string    `json:"title" yaml:"title"`
+		Releases []Release `json:"releases" yaml:"releases"`
+	}
+
+	// Release is a single release in a changelog file.
+	Release struct {
+		TagName     string       `json:"tag_name" yaml:"tag_name"`
+		TagURL      string       `json:"tag_url,omitempty" yaml:"tag_url,omitempty"`
+		TagTime     time.Time    `json:"tag_time" yaml:"tag_time"`
+		ReleaseURL  string       `json:"release_url,omitempty" yaml:"release_url,omitempty"`
+		CompareURL  string       `json:"compare_url,omitempty" yaml:"compare_url,omitempty"`
+		IssueGroups []IssueGroup `json:"issue_groups" yaml:"issue_groups"`
+		MergeGroups []MergeGroup `json:"merge_groups" yaml:"merge_groups"`
+		// CommitGroups are only encoded when commits are selected for the changelog.
+		CommitGroups []CommitGroup `json:"commit_groups,omitempty" yaml:"commit_groups,omitempty"`
+		Unreleased   bool          `json:"unreleased,omitempty" yaml:"unreleased,omitempty"`
+	}
+
+	// IssueGroup is a group of issues in a release.
+	IssueGroup struct {
+		Title  string  `json:"title" yaml:"title"`
+		Issues []Issue `json:"issues" yaml:"issues"`
+	}
+
+	// Issue is a closed issue in a release.
+	Issue struct {
+		Number   int      `json:"number" yaml:"number"`
+		Title    string   `json:"title" yaml:"title"`
+		URL      string   `json:"url,omitempty" yaml:"url,omitempty"`
+		Labels   []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+		OpenedBy User     `json:"opened_by" yaml:"opened_by"`
+		ClosedBy User     `json:"closed_by" yaml:"closed_by"`
+	}
+
+	// MergeGroup is a group of merges in a release.
+	MergeGroup struct {
+		Title  string  `json:"title" yaml:"title"`
+		Merges []Merge `json:"merges" yaml:"merges"`
+	}
+
+	// Merge is a merged pull/merge request in a release.
+	Merge struct {
+		Number   int      `json:"number" yaml:"number"`
+		Title    string   `json:"title" yaml:"title"`
+		URL      string   `json:"url,omitempty" yaml:"url,omitempty"`
+		Labels   []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+		OpenedBy User     `json:"opened_by" yaml:"opened_by"`
+		MergedBy User     `json:"merged_by" yaml:"merged_by"`
+	}
+
+	// CommitGroup is a group of conventional commits in a release.
+	CommitGroup struct {
+		Title   string   `json:"title" yaml:"title"`
+		Commits []Commit `json:"commits" yaml:"commits"`
+	}
+
+	// Commit is a conventional commit in a release.
+	Commit struct {
+		Hash     string `json:"hash" yaml:"hash"`
+		Type     string `json:"type" yaml:"type"`
+		Scope    string `json:"scope,omitempty" yaml:"scope,omitempty"`
+		Subject  string `json:"subject" yaml:"subject"`
+		URL      string `json:"url,omitempty" yaml:"url,omitempty"`
+		Breaking bool   `json:"breaking,omitempty" yaml:"breaking,omitempty"`
+	}
+
+	// User is the author of an issue or a merge.
+	User struct {
+		Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+		Username string `json:"username" yaml:"username"`
+		URL      string `json:"url,omitempty" yaml:"url,omitempty"`
+	}
+)
+
+// NewDocument creates a new document with the current schema version for a list of changelog releases.
+// Empty lists are encoded as empty arrays rather than null, so consumers do not need to handle both.
+func NewDocument(title string, releases []changelog.Release) Document {
+	rs := []Release{}
+	for _, r := range releases {
+		issueGroups := []IssueGroup{}
+		for _, g := range r.IssueGroups {
+			issues := []Issue{}
+			for _, i := range g.Issues {
+				issues = append(issues, Issue{
+					Number:   i.Number,
+					Title:    i.Title,
+					URL:      i.URL,
+					Labels:   i.Labels,
+					OpenedBy: User(i.OpenedBy),
+					ClosedBy: User(i.ClosedBy),
+				})
+			}
+			issueGroups = append(issueGroups, IssueGroup{Title: g.Title, Issues: issues})
+		}
+
+		mergeGroups := []MergeGroup{}
+		for _, g := range r.MergeGroups {
+			merges := []Merge{}
+			for _, m := range g.Merges {
+				merges = append(merges, Merge{
+					Number:   m.Number,
+					Title:    m.Title,
+					URL:      m.URL,
+					Labels:   m.Labels,
+					OpenedBy: User(m.OpenedBy),
+					MergedBy: User(m.MergedBy),
+				})
+			}
+			mergeGroups = append(mergeGroups, MergeGroup{Title: g.Title, Merges: merges})
+		}
+
+		var commitGroups []CommitGroup
+		for _, g := range r.CommitGroups {
+			commits := []Commit{}
+			for _, c := range g.Commits {
+				commits = append(commits, Commit(c))
+			}
+			commitGroups = append(commitGroups, CommitGroup{Title: g.Title, Commits: commits})
+		}
+
+		rs = append(rs, Release{
+			TagName:      r.TagName,
+			TagURL:       r.TagURL,
+			TagTime:      r.TagTime,
+			ReleaseURL:   r.ReleaseURL,
+			CompareURL:   r.CompareURL,
+			IssueGroups:  issueGroups,
+			MergeGroups:  mergeGroups,
+			CommitGroups: commitGroups,
+			Unreleased:   r.Unreleased,
+		})
+	}
+
+	return Document{
+		Version:  Version,
+		Title:    title,
+		Releases: rs,
+	}
+}
+
+// Validate checks whether or not the document has a supported schema version.
+func (d Document) Validate() error {
+	if d.Version < 1 || d.Version > Version {
+		return fmt.Errorf("unsupported schema version %d", d.Version)
+	}
+
+	return nil
+}
+
+// ChangelogReleases converts the releases of the document back to changelog releases.
+func (d Document) ChangelogReleases() []changelog.Release {
+	var rs []changelog.Release
+	for _, r := range d.Releases {
+		var issueGroups []changelog.IssueGroup
+		for _, g := range r.IssueGroups {
+			var issues []changelog.Issue
+			for _, i := range g.Issues {
+				issues = append(issues, changelog.Issue{
+					Number:   i.Number,
+					Title:    i.Title,
+					URL:      i.URL,
+					Labels:   i.Labels,
+					OpenedBy: changelog.User(i.OpenedBy),
+					ClosedBy: changelog.User(i.ClosedBy),
+				})
+			}
+			issueGroups = append(issueGroups, changelog.IssueGroup{Title: g.Title, Issues: issues})
+		}
+
+		var mergeGroups []changelog.MergeGroup
+		for _, g := range r.MergeGroups {
+			var merges []changelog.Merge
+			for _, m := range g.Merges {
+				merges = append(merges, changelog.Merge{
+					Number:   m.Number,
+					Title:    m.Title,
+					URL:      m.URL,
+					Labels:   m.Labels,
+					OpenedBy: changelog.User(m.OpenedBy),
+					MergedBy: changelog.User(m.MergedBy),
+				})
+			}
+			mergeGroups = append(mergeGroups, changelog.MergeGroup{Title: g.Title, Merges: merges})
+		}
+
+		var commitGroups []changelog.CommitGroup
+		for _, g := range r.CommitGroups {
+			var commits []changelog.Commit
+			for _, c := range g.Commits {
+				commits = append(commits, changelog.Commit(c))
+			}
+			commitGroups = append(commitGroups, changelog.CommitGroup{Title: g.Title, Commits: commits})
+		}
+
+		rs = append(rs, changelog.Release{
+			TagName:      r.TagName,
+			TagURL:       r.TagURL,
+			TagTime:      r.TagTime,
+			ReleaseURL:   r.ReleaseURL,
+			CompareURL:   r.CompareURL,
+			IssueGroups:  issueGroups,
+			MergeGroups:  mergeGroups,
+			CommitGroups: commitGroups,
+			Unreleased:   r.Unreleased,
+		})
+	}
+
+	return rs
+}
@@ -0,0 +1,222 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/changelog"
+)
+
+var (
+	tagTime, _ = time.Parse(time.RFC3339, "2020-11-02T22:00:00-04:00")
+
+	releases = []changelog.Release{
+		{
+			TagName:    "v0.2.0",
+			TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+			TagTime:    tagTime,
+			ReleaseURL: "https://storage.artifactory.com/project/releases/v0.2.0",
+			CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+			IssueGroups: []changelog.IssueGroup{
+				{
+					Title: "Fixed Bugs",
+					Issues: []changelog.Issue{
+						{
+							Number:   1001,
+							Title:    "Fixed a bug",
+							URL:      "https://github.com/octocat/Hello-World/issues/1001",
+							OpenedBy: changelog.User{Name: "The Octocat", Username: "octocat", URL: "https://github.com/octocat"},
+							ClosedBy: changelog.User{Name: "The Octocat", Username: "octocat", URL: "https://github.com/octocat"},
+						},
+					},
+				},
+			},
+			MergeGroups: []changelog.MergeGroup{
+				{
+					Title: "Merged Changes",
+					Merges: []changelog.Merge{
+						{
+							Number:   1002,
+							Title:    "Add a feature",
+							OpenedBy: changelog.User{Username: "octocat"},
+							MergedBy: changelog.User{Username: "octodog"},
+						},
+					},
+				},
+			},
+			CommitGroups: []changelog.CommitGroup{
+				{
+					Title: "New Features",
+					Commits: []changelog.Commit{
+						{
+							Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+							Type:    "feat",
+							Scope:   "cli",
+							Subject: "add a flag",
+							URL:     "https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059",
+						},
+					},
+				},
+			},
+		},
+		{
+			TagName: "v0.1.0",
+			TagTime: tagTime.Add(-24 * time.Hour),
+		},
+	}
+
+	document = Document{
+		Version: 1,
+		Title:   "Changelog",
+		Releases: []Release{
+			{
+				TagName:    "v0.2.0",
+				TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+				TagTime:    tagTime,
+				ReleaseURL: "https://storage.artifactory.com/project/releases/v0.2.0",
+				CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+				IssueGroups: []IssueGroup{
+					{
+						Title: "Fixed Bugs",
+						Issues: []Issue{
+							{
+								Number:   1001,
+								Title:    "Fixed a bug",
+								URL:      "https://github.com/octocat/Hello-World/issues/1001",
+								OpenedBy: User{Name: "The Octocat", Username: "octocat", URL: "https://github.com/octocat"},
+								ClosedBy: User{Name: "The Octocat", Username: "octocat", URL: "https://github.com/octocat"},
+							},
+						},
+					},
+				},
+				MergeGroups: []MergeGroup{
+					{
+						Title: "Merged Changes",
+						Merges: []Merge{
+							{
+								Number:   1002,
+								Title:    "Add a feature",
+								OpenedBy: User{Username: "octocat"},
+								MergedBy: User{Username: "octodog"},
+							},
+						},
+					},
+				},
+				CommitGroups: []CommitGroup{
+					{
+						Title: "New Features",
+						Commits: []Commit{
+							{
+								Hash:    "c414d1004154c6c324bd78c69d10ee101e676059",
+								Type:    "feat",
+								Scope:   "cli",
+								Subject: "add a flag",
+								URL:     "https://github.com/octocat/Hello-World/commit/c414d1004154c6c324bd78c69d10ee101e676059",
+							},
+						},
+					},
+				},
+			},
+			{
+				TagName:     "v0.1.0",
+				TagTime:     tagTime.Add(-24 * time.Hour),
+				IssueGroups: []IssueGroup{},
+				MergeGroups: []MergeGroup{},
+			},
+		},
+	}
+)
+
+func TestNewDocument(t *testing.T) {
+	tests := []struct {
+		name             string
+		title            string
+		releases         []changelog.Release
+		expectedDocument Document
+	}{
+		{
+			name:  "NoRelease",
+			title: "Changelog",
+			expectedDocument: Document{
+				Version:  1,
+				Title:    "Changelog",
+				Releases: []Release{},
+			},
+		},
+		{
+			name:             "OK",
+			title:            "Changelog",
+			releases:         releases,
+			expectedDocument: document,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDocument, NewDocument(tc.title, tc.releases))
+		})
+	}
+}
+
+func TestDocument_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		d             Document
+		expectedError string
+	}{
+		{
+			name:          "NoVersion",
+			d:             Document{},
+			expectedError: "unsupported schema version 0",
+		},
+		{
+			name:          "FutureVersion",
+			d:             Document{Version: Version + 1},
+			expectedError: "unsupported schema version 2",
+		},
+		{
+			name:          "OK",
+			d:             Document{Version: Version},
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.d.Validate()
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestDocument_ChangelogReleases(t *testing.T) {
+	tests := []struct {
+		name             string
+		d                Document
+		expectedReleases []changelog.Release
+	}{
+		{
+			name:             "NoRelease",
+			d:                Document{Version: 1, Releases: []Release{}},
+			expectedReleases: nil,
+		},
+		{
+			name:             "OK",
+			d:                document,
+			expectedReleases: releases,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedReleases, tc.d.ChangelogReleases())
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package yaml
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/changelog"
+	"github.com/moorara/changelog/log"
+)
+
+var (
+	tagTime, _  = time.Parse(time.RFC3339, "2020-11-02T22:00:00-04:00")
+	tagTime1, _ = time.Parse(time.RFC3339, "2020-10-11T20:00:00-04:00")
+	tagTime0, _ = time.Parse(time.RFC3339, "2020-10-10T20:00:00-04:00")
+
+	chlog = &changelog.Changelog{
+		Title: "Changelog",
+		New: []changelog.Release{
+			{
+				TagName:    "v0.2.0",
+				TagURL:     "https://github.com/octocat/Hello-World/tree/v0.2.0",
+				TagTime:    tagTime,
+				ReleaseURL: "https://storage.artifactory.com/project/releases/v0.2.0",
+				CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+				IssueGroups: []changelog.IssueGroup{
+					{
+						Title: "Fixed Bugs",
+						Issues: []changelog.Issue{
+							{
+								Number: 1001,
+								Title:  "Fixed a bug",
+								URL:    "https://github.com/octocat/Hello-World/issues/1001",
+								OpenedBy: changelog.User{
+									Name:     "The Octocat",
+									Username: "octocat",
+									URL:      "https://github.com/octocat",
+								},
+								ClosedBy: changelog.User{
+									Name:     "The Octocat",
+									Username: "octocat",
+									URL:      "https://github.com/octocat",
+								},
+							},
+						},
+					},
+				},
+				MergeGroups: []changelog.MergeGroup{
+					{
+						Title: "Merged Changes",
+						Merges: []changelog.Merge{
+							{
+								Number: 1002,
+								Title:  "Add a feature",
+								URL:    "https://github.com/octocat/Hello-World/pull/1002",
+								OpenedBy: changelog.User{
+									Name:     "The Octocat",
+									Username: "octocat",
+									URL:      "https://github.com/octocat",
+								},
+								MergedBy: changelog.User{
+									Name:     "The Octodog",
+									Username: "octodog",
+									URL:      "https://github.com/octodog",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	existingReleases = []changelog.Release{
+		{
+			TagName:    "v0.1.1",
+			TagURL:     "https://github.com/octocat/Hello-World/tree/v0.1.1",
+			TagTime:    tagTime1,
+			CompareURL: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.1.1",
+			IssueGroups: []changelog.IssueGroup{
+				{
+					Title: "Fixed Bugs",
+					Issues: []changelog.Issue{
+						{
+							Number: 1000,
+							Title:  "Fixed a bug",
+							URL:    "https://github.com/octocat/Hello-World/issues/1000",
+							OpenedBy: changelog.User{
+								Name:     "The Octocat",
+								Username: "octocat",
+								URL:      "https://github.com/octocat",
+							},
+							ClosedBy: changelog.User{
+								Name:     "The Octocat",
+								Username: "octocat",
+								URL:      "https://github.com/octocat",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			TagName: "v0.1.0",
+			TagURL:  "https://github.com/octocat/Hello-World/tree/v0.1.0",
+			TagTime: tagTime0,
+		},
+	}
+)
+
+const expectedNewContent = `version: 1
+title: Changelog
+releases:
+  - tag_name: v0.2.0
+    tag_url: https://github.com/octocat/Hello-World/tree/v0.2.0
+    tag_time: 2020-11-02T22:00:00-04:00
+    release_url: https://storage.artifactory.com/project/releases/v0.2.0
+    compare_url: https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0
+    issue_groups:
+      - title: Fixed Bugs
+        issues:
+          - number: 1001
+            title: Fixed a bug
+            url: https://github.com/octocat/Hello-World/issues/1001
+            opened_by:
+              name: The Octocat
+              username: octocat
+              url: https://github.com/octocat
+            closed_by:
+              name: The Octocat
+              username: octocat
+              url: https://github.com/octocat
+    merge_groups:
+      - title: Merged Changes
+        merges:
+          - number: 1002
+            title: Add a feature
+            url: https://github.com/octocat/Hello-World/pull/1002
+            opened_by:
+              name: The Octocat
+              username: octocat
+              url: https://github.com/octocat
+            merged_by:
+              name: The Octodog
+              username: octodog
+              url: https://github.com/octodog
+`
+
+func TestNewProcessor(t *testing.T) {
+	tests := []struct {
+		name          string
+		logger        log.Logger
+		baseFile      string
+		changelogFile string
+	}{
+		{
+			name:          "OK",
+			logger:        log.New(log.None),
+			baseFile:      "HISTORY.yaml",
+			changelogFile: "CHANGELOG.yaml",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewProcessor(tc.logger, tc.baseFile, tc.changelogFile)
+			assert.NotNil(t, p)
+
+			jp, ok := p.(*processor)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.logger, jp.logger)
+			assert.Equal(t, tc.baseFile, jp.baseFile)
+			assert.Equal(t, tc.changelogFile, jp.changelogFile)
+		})
+	}
+}
+
+func TestProcessor_createChangelog(t *testing.T) {
+	p := &processor{
+		logger: log.New(log.None),
+	}
+
+	chlog, err := p.createChangelog()
+
+	assert.NoError(t, err)
+	assert.Equal(t, &changelog.Changelog{Title: "Changelog"}, chlog)
+}
+
+func TestProcessor_Parse(t *testing.T) {
+	tests := []struct {
+		name              string
+		p                 *processor
+		opts              changelog.ParseOptions
+		expectedChangelog *changelog.Changelog
+		expectedError     string
+	}{
+		{
+			name: "FileNotExist",
+			p: &processor{
+				logger:        log.New(log.None),
+				changelogFile: "test/missing.yaml",
+			},
+			opts: changelog.ParseOptions{},
+			expectedChangelog: &changelog.Changelog{
+				Title: "Changelog",
+			},
+			expectedError: "",
+		},
+		{
+			name: "InvalidYAML",
+			p: &processor{
+				logger:        log.New(log.None),
+				changelogFile: "test/invalid.yaml",
+			},
+			opts:          changelog.ParseOptions{},
+			expectedError: "test/invalid.yaml: yaml: line 2: did not find expected node content",
+		},
+		{
+			name: "UnsupportedVersion",
+			p: &processor{
+				logger:        log.New(log.None),
+				changelogFile: "test/unsupported.yaml",
+			},
+			opts:          changelog.ParseOptions{},
+			expectedError: "test/unsupported.yaml: unsupported schema version 2",
+		},
+		{
+			name: "Success",
+			p: &processor{
+				logger:        log.New(log.None),
+				changelogFile: "test/CHANGELOG.yaml",
+			},
+			opts: changelog.ParseOptions{},
+			expectedChangelog: &changelog.Changelog{
+				Title:    "Changelog",
+				Existing: existingReleases,
+			},
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chlog, err := tc.p.Parse(tc.opts)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedChangelog, chlog)
+			} else {
+				assert.Nil(t, chlog)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestProcessor_Render(t *testing.T) {
+	tests := []struct {
+		name             string
+		p                *processor
+		chlog            *changelog.Changelog
+		expectedError    string
+		expectedReleases []changelog.Release
+	}{
+		{
+			name: "WithoutBaseFile",
+			p: &processor{
+				logger: log.New(log.None),
+			},
+			chlog:            chlog,
+			expectedReleases: chlog.New,
+		},
+		{
+			name: "WithBaseFile",
+			p: &processor{
+				logger:   log.New(log.None),
+				baseFile: "test/HISTORY.yaml",
+			},
+			chlog:            chlog,
+			expectedReleases: append(append([]changelog.Release{}, chlog.New...), existingReleases[1]),
+		},
+		{
+			name: "InvalidBaseFile",
+			p: &processor{
+				logger:   log.New(log.None),
+				baseFile: "test/invalid.yaml",
+			},
+			chlog:         chlog,
+			expectedError: "test/invalid.yaml: yaml: line 2: did not find expected node content",
+		},
+		{
+			name: "WithExistingReleases",
+			p: &processor{
+				logger:   log.New(log.None),
+				baseFile: "test/HISTORY.yaml",
+			},
+			chlog: &changelog.Changelog{
+				Title:    "Changelog",
+				New:      chlog.New,
+				Existing: existingReleases,
+			},
+			expectedReleases: append(append([]changelog.Release{}, chlog.New...), existingReleases...),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.p.changelogFile = filepath.Join(t.TempDir(), "CHANGELOG.yaml")
+
+			content, err := tc.p.Render(tc.chlog)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, content)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, expectedNewContent, content)
+
+				// The changelog file should be parsed back into the same releases
+				chlog, err := tc.p.Parse(changelog.ParseOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, tc.chlog.Title, chlog.Title)
+				assert.Equal(t, tc.expectedReleases, chlog.Existing)
+			}
+		})
+	}
+}
+
+func TestProcessor_Render_Stable(t *testing.T) {
+	p := &processor{
+		logger:        log.New(log.None),
+		changelogFile: filepath.Join(t.TempDir(), "CHANGELOG.yaml"),
+	}
+
+	b, err := ioutil.ReadFile("test/CHANGELOG.yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(p.changelogFile, b, 0644))
+
+	// Parsing and rendering a changelog without new releases should not change the file
+	chlog, err := p.Parse(changelog.ParseOptions{})
+	assert.NoError(t, err)
+
+	_, err = p.Render(chlog)
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(p.changelogFile)
+	assert.NoError(t, err)
+	assert.Equal(t, string(b), string(content))
+}
+
+func TestProcessor_Render_Regenerated(t *testing.T) {
+	p := &processor{
+		logger:        log.New(log.None),
+		changelogFile: filepath.Join(t.TempDir(), "CHANGELOG.yaml"),
+	}
+
+	b, err := ioutil.ReadFile("test/CHANGELOG.yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(p.changelogFile, b, 0644))
+
+	chlog, err := p.Parse(changelog.ParseOptions{})
+	assert.NoError(t, err)
+
+	// The labels of an issue are fixed after the release
+	regenerated := existingReleases[0]
+	regenerated.IssueGroups = []changelog.IssueGroup{
+		{
+			Title:  "Enhancements",
+			Issues: existingReleases[0].IssueGroups[0].Issues,
+		},
+	}
+	chlog.Regenerated = []changelog.Release{regenerated}
+
+	content, err := p.Render(chlog)
+	assert.NoError(t, err)
+	assert.Contains(t, content, `title: Enhancements`)
+
+	// The regenerated release should be replaced in place
+	chlog, err = p.Parse(changelog.ParseOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []changelog.Release{regenerated, existingReleases[1]}, chlog.Existing)
+}
+
+func TestProcessor_Render_Error(t *testing.T) {
+	p := &processor{
+		logger:        log.New(log.None),
+		changelogFile: filepath.Join(t.TempDir(), "missing", "CHANGELOG.yaml"),
+	}
+
+	content, err := p.Render(chlog)
+
+	assert.Empty(t, content)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestProcessor_Render_Lossless(t *testing.T) {
+	p := &processor{
+		logger:        log.New(log.None),
+		changelogFile: filepath.Join(t.TempDir(), "CHANGELOG.yaml"),
+	}
+
+	// Values that are not plain YAML strings should be quoted, so they are parsed back to the same values
+	chlog := &changelog.Changelog{
+		Title: "Release Notes: 2020",
+		New: []changelog.Release{
+			{
+				TagName: "1.10",
+				TagTime: time.Date(2020, time.November, 2, 22, 0, 0, 123456789, time.UTC),
+				IssueGroups: []changelog.IssueGroup{
+					{
+						Title: "yes",
+						Issues: []changelog.Issue{
+							{
+								Number:   1,
+								Title:    "Fix #1: handle 'quotes', \"double quotes\", and\nnew lines",
+								OpenedBy: changelog.User{Name: "null", Username: "~"},
+								ClosedBy: changelog.User{Name: "Jöhn Dœ", Username: "- dash"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	_, err := p.Render(chlog)
+	assert.NoError(t, err)
+
+	parsed, err := p.Parse(changelog.ParseOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, chlog.Title, parsed.Title)
+	assert.Equal(t, chlog.New, parsed.Existing)
+}
@@ -0,0 +1,177 @@
+// Package diff computes line-based differences between two texts.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines around every change in a unified diff.
+const context = 3
+
+type kind byte
+
+const (
+	same    kind = ' '
+	removed kind = '-'
+	added   kind = '+'
+)
+
+type edit struct {
+	kind kind
+	line string
+}
+
+// splitLines splits a text into lines, keeping the line endings.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// edits returns the shortest list of edits for changing a into b.
+// The common prefix and suffix are trimmed first, so only the changed region is compared line by line.
+func edits(a, b []string) []edit {
+	p := 0
+	for p < len(a) && p < len(b) && a[p] == b[p] {
+		p++
+	}
+
+	s := 0
+	for s < len(a)-p && s < len(b)-p && a[len(a)-1-s] == b[len(b)-1-s] {
+		s++
+	}
+
+	x, y := a[p:len(a)-s], b[p:len(b)-s]
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int32, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(y)+1)
+	}
+
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	es := make([]edit, 0, len(a)+len(b))
+	for _, line := range a[:p] {
+		es = append(es, edit{same, line})
+	}
+
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			es = append(es, edit{same, x[i]})
+			i++
+			j++
+		case j < len(y) && (i == len(x) || lcs[i][j+1] > lcs[i+1][j]):
+			es = append(es, edit{added, y[j]})
+			j++
+		default:
+			es = append(es, edit{removed, x[i]})
+			i++
+		}
+	}
+
+	for _, line := range a[len(a)-s:] {
+		es = append(es, edit{same, line})
+	}
+
+	return es
+}
+
+// Unified returns the unified diff for changing a text into another text.
+// If the two texts are the same, the diff is empty.
+func Unified(fromFile, toFile, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	es := edits(splitLines(from), splitLines(to))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromFile, toFile)
+
+	// aLine and bLine are the number of lines in each text before the current edit
+	aLine, bLine := 0, 0
+
+	for i := 0; i < len(es); {
+		if es[i].kind == same {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		// A hunk starts with the context lines before the first change
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// A hunk ends with the context lines after the last change, unless the next change is close enough to be merged
+		end := i
+		for j := i; j < len(es); j++ {
+			if es[j].kind != same {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+
+		if end += context; end > len(es) {
+			end = len(es)
+		}
+
+		aStart, bStart := aLine-(i-start), bLine-(i-start)
+		aLen, bLen := 0, 0
+
+		var hunk strings.Builder
+		for _, e := range es[start:end] {
+			switch e.kind {
+			case same:
+				aLen++
+				bLen++
+			case removed:
+				aLen++
+			case added:
+				bLen++
+			}
+
+			hunk.WriteByte(byte(e.kind))
+			hunk.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				hunk.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		b.WriteString(hunk.String())
+
+		aLine, bLine = aStart+aLen, bStart+bLen
+		i = end
+	}
+
+	return b.String()
+}
+
+// hunkRange formats the range of lines in a hunk header.
+// The start line of an empty range is the line before the range.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name         string
+		fromFile     string
+		toFile       string
+		from         string
+		to           string
+		expectedDiff string
+	}{
+		{
+			name:         "Same",
+			fromFile:     "CHANGELOG.md",
+			toFile:       "CHANGELOG.md",
+			from:         "# Changelog\n",
+			to:           "# Changelog\n",
+			expectedDiff: "",
+		},
+		{
+			name:     "NewFile",
+			fromFile: "a",
+			toFile:   "b",
+			from:     "",
+			to:       "a\nb\n",
+			expectedDiff: `--- a
++++ b
+@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+		{
+			name:     "NewRelease",
+			fromFile: "CHANGELOG.md",
+			toFile:   "CHANGELOG.md",
+			from:     "# Changelog\n\n## v0.1.0\n\n- Fixed a bug\n",
+			to:       "# Changelog\n\n## v0.2.0\n\n- Add a feature\n\n## v0.1.0\n\n- Fixed a bug\n",
+			expectedDiff: `--- CHANGELOG.md
++++ CHANGELOG.md
+@@ -1,5 +1,9 @@
+ # Changelog
+ 
++## v0.2.0
++
++- Add a feature
++
+ ## v0.1.0
+ 
+ - Fixed a bug
+`,
+		},
+		{
+			name:     "MultipleHunks",
+			fromFile: "a",
+			toFile:   "b",
+			from:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			to:       "1\nx\n3\n4\n5\n6\n7\n8\n9\n10\ny\n12",
+			expectedDiff: `--- a
++++ b
+@@ -1,5 +1,5 @@
+ 1
+-2
++x
+ 3
+ 4
+ 5
+@@ -8,5 +8,5 @@
+ 8
+ 9
+ 10
+-11
+-12
++y
++12
+\ No newline at end of file
+`,
+		},
+		{
+			name:     "MergedHunks",
+			fromFile: "a",
+			toFile:   "b",
+			from:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			to:       "1\nx\n3\n4\n5\n6\n7\ny\n9\n",
+			expectedDiff: `--- a
++++ b
+@@ -1,9 +1,9 @@
+ 1
+-2
++x
+ 3
+ 4
+ 5
+ 6
+ 7
+-8
++y
+ 9
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDiff, Unified(tc.fromFile, tc.toFile, tc.from, tc.to))
+		})
+	}
+}
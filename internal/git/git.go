@@ -11,12 +11,21 @@ import (
 
 var (
 	idPattern       = `[A-Za-z][0-9A-Za-z-]+[0-9A-Za-z]`
-	domainPattern   = fmt.Sprintf(`%s\.[A-Za-z]{2,63}`, idPattern)
+	domainPattern   = fmt.Sprintf(`(?:%s\.)+[A-Za-z]{2,63}`, idPattern)
 	repoPathPattern = fmt.Sprintf(`(%s/){1,20}(%s)`, idPattern, idPattern)
-	httpsPattern    = fmt.Sprintf(`^https://(%s)/(%s)(.git)?$`, domainPattern, repoPathPattern)
+	httpsPattern    = fmt.Sprintf(`^https://(?:[^@/]+@)?(%s)(?::[0-9]+)?/(%s)(.git)?$`, domainPattern, repoPathPattern)
 	sshPattern      = fmt.Sprintf(`^git@(%s):(%s)(.git)?$`, domainPattern, repoPathPattern)
+	sshURLPattern   = fmt.Sprintf(`^ssh://(?:[^@/]+@)?(%s)(?::[0-9]+)?/(%s)(.git)?$`, domainPattern, repoPathPattern)
 	httpsRE         = regexp.MustCompile(httpsPattern)
 	sshRE           = regexp.MustCompile(sshPattern)
+	sshURLRE        = regexp.MustCompile(sshURLPattern)
+
+	// Azure DevOps repository paths have a _git segment and allow names that are not valid in other platforms (i.e. spaces).
+	azurePathPattern = `(?:[^/@]+/){2,20}_git/[^/@]+`
+	azureURLPattern  = fmt.Sprintf(`^(?:https|ssh)://(?:[^@/]+@)?(%s)(?::[0-9]+)?/(%s)$`, domainPattern, azurePathPattern)
+	azureSSHPattern  = `^[^@/]+@ssh\.(dev\.azure\.com):v3/([^/]+/[^/]+)/([^/]+)$`
+	azureURLRE       = regexp.MustCompile(azureURLPattern)
+	azureSSHRE       = regexp.MustCompile(azureSSHPattern)
 )
 
 // Repo is a Git repository.
@@ -64,16 +73,34 @@ func (r *repo) GetRemote() (string, string, error) {
 		remoteURL = config.URLs[0]
 	}
 
-	// Parse the origin remote URL into a domain part a path part
-	if matches := httpsRE.FindStringSubmatch(remoteURL); len(matches) == 6 {
+	r.logger.Infof("Git remote URL: %s", remoteURL)
+
+	return parseRemoteURL(remoteURL)
+}
+
+// parseRemoteURL parses a Git remote repository URL into a domain part and a path part.
+func parseRemoteURL(remoteURL string) (string, string, error) {
+	if matches := azureURLRE.FindStringSubmatch(remoteURL); len(matches) == 3 {
+		// Git remote url is an Azure DevOps URL using HTTPS or SSH protocol
+		// Example: https://octo@dev.azure.com/octo/changelog/_git/changelog --> matches = []string{"https://octo@dev.azure.com/octo/changelog/_git/changelog", "dev.azure.com", "octo/changelog/_git/changelog"}
+		return matches[1], matches[2], nil
+	} else if matches := azureSSHRE.FindStringSubmatch(remoteURL); len(matches) == 4 {
+		// Git remote url is an Azure DevOps Services URL using SSH protocol
+		// The domain and path are normalized to the ones of the HTTPS URL
+		// Example: git@ssh.dev.azure.com:v3/octo/changelog/changelog --> matches = []string{"git@ssh.dev.azure.com:v3/octo/changelog/changelog", "dev.azure.com", "octo/changelog", "changelog"}
+		return matches[1], matches[2] + "/_git/" + matches[3], nil
+	} else if matches := httpsRE.FindStringSubmatch(remoteURL); len(matches) == 6 {
 		// Git remote url is using HTTPS protocol
 		// Example: https://github.com/moorara/changelog.git --> matches = []string{"https://github.com/moorara/changelog.git", "github.com", "moorara/changelog", "moorara/", "changelog", ".git"}
-		r.logger.Infof("Git remote URL: %s", remoteURL)
+		// Example: https://octocat@bitbucket.org/moorara/changelog.git --> matches = []string{"https://octocat@bitbucket.org/moorara/changelog.git", "bitbucket.org", "moorara/changelog", "moorara/", "changelog", ".git"}
 		return matches[1], matches[2], nil
 	} else if matches := sshRE.FindStringSubmatch(remoteURL); len(matches) == 6 {
 		// Git remote url is using SSH protocol
 		// Example: git@github.com:moorara/changelog.git --> matches = []string{"git@github.com:moorara/changelog.git", "github.com", "moorara/changelog, "moorara/", "changelog", ".git"}
-		r.logger.Infof("Git remote URL: %s", remoteURL)
+		return matches[1], matches[2], nil
+	} else if matches := sshURLRE.FindStringSubmatch(remoteURL); len(matches) == 6 {
+		// Git remote url is using SSH protocol with a URL (i.e. Bitbucket Server)
+		// Example: ssh://git@bitbucket.example.com:7999/octo/changelog.git --> matches = []string{"ssh://git@bitbucket.example.com:7999/octo/changelog.git", "bitbucket.example.com", "octo/changelog", "octo/", "changelog", ".git"}
 		return matches[1], matches[2], nil
 	}
 
@@ -75,3 +75,89 @@ func TestRepo_GetRemote(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		remoteURL      string
+		expectedDomain string
+		expectedPath   string
+		expectedError  string
+	}{
+		{
+			name:          "Invalid",
+			remoteURL:     "ftp://github.com/moorara/changelog",
+			expectedError: "invalid git remote url: ftp://github.com/moorara/changelog",
+		},
+		{
+			name:           "HTTPS",
+			remoteURL:      "https://github.com/moorara/changelog.git",
+			expectedDomain: "github.com",
+			expectedPath:   "moorara/changelog",
+		},
+		{
+			name:           "HTTPSWithUser",
+			remoteURL:      "https://octocat@bitbucket.org/moorara/changelog.git",
+			expectedDomain: "bitbucket.org",
+			expectedPath:   "moorara/changelog",
+		},
+		{
+			name:           "HTTPSWithPort",
+			remoteURL:      "https://bitbucket.example.com:8443/scm/octo/changelog.git",
+			expectedDomain: "bitbucket.example.com",
+			expectedPath:   "scm/octo/changelog",
+		},
+		{
+			name:           "SSH",
+			remoteURL:      "git@gitlab.com:moorara/changelog.git",
+			expectedDomain: "gitlab.com",
+			expectedPath:   "moorara/changelog",
+		},
+		{
+			name:           "SSHSubdomain",
+			remoteURL:      "git@github.example.com:octocat/Hello-World.git",
+			expectedDomain: "github.example.com",
+			expectedPath:   "octocat/Hello-World",
+		},
+		{
+			name:           "SSHURL",
+			remoteURL:      "ssh://git@bitbucket.example.com:7999/octo/changelog.git",
+			expectedDomain: "bitbucket.example.com",
+			expectedPath:   "octo/changelog",
+		},
+		{
+			name:           "AzureDevOpsHTTPS",
+			remoteURL:      "https://octo@dev.azure.com/octo/Octo%20Project/_git/changelog",
+			expectedDomain: "dev.azure.com",
+			expectedPath:   "octo/Octo%20Project/_git/changelog",
+		},
+		{
+			name:           "AzureDevOpsSSH",
+			remoteURL:      "git@ssh.dev.azure.com:v3/octo/Octo%20Project/changelog",
+			expectedDomain: "dev.azure.com",
+			expectedPath:   "octo/Octo%20Project/_git/changelog",
+		},
+		{
+			name:           "AzureDevOpsServerSSHURL",
+			remoteURL:      "ssh://tfs.example.com:22/tfs/DefaultCollection/octo/_git/changelog",
+			expectedDomain: "tfs.example.com",
+			expectedPath:   "tfs/DefaultCollection/octo/_git/changelog",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			domain, path, err := parseRemoteURL(tc.remoteURL)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedDomain, domain)
+				assert.Equal(t, tc.expectedPath, path)
+			} else {
+				assert.Empty(t, domain)
+				assert.Empty(t, path)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	userAgent = "moorara/changelog"
+	// 6.0 is the latest API version supported by both Azure DevOps Services and Azure DevOps Server 2020.
+	apiVersion = "6.0"
+	pageSize   = 100
+	// The work items batch endpoint accepts at most 200 ids per request.
+	workItemsBatchSize = 200
+	// continuationHeader is the response header carrying the token for the next page of refs.
+	continuationHeader = "x-ms-continuationtoken"
+)
+
+// Pull request statuses of interest.
+const (
+	statusCompleted = "completed"
+)
+
+type (
+	identityRef struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+		UniqueName  string `json:"uniqueName"`
+		URL         string `json:"url"`
+	}
+
+	project struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	repository struct {
+		ID            string  `json:"id"`
+		Name          string  `json:"name"`
+		DefaultBranch string  `json:"defaultBranch"`
+		Project       project `json:"project"`
+		WebURL        string  `json:"webUrl"`
+		RemoteURL     string  `json:"remoteUrl"`
+	}
+
+	gitUserDate struct {
+		Name  string    `json:"name"`
+		Email string    `json:"email"`
+		Date  time.Time `json:"date"`
+	}
+
+	commitRef struct {
+		CommitID string `json:"commitId"`
+	}
+
+	commit struct {
+		CommitID  string      `json:"commitId"`
+		Author    gitUserDate `json:"author"`
+		Committer gitUserDate `json:"committer"`
+		Comment   string      `json:"comment"`
+		Parents   []string    `json:"parents"`
+		URL       string      `json:"url"`
+		RemoteURL string      `json:"remoteUrl"`
+	}
+
+	item struct {
+		ObjectID      string `json:"objectId"`
+		GitObjectType string `json:"gitObjectType"`
+		Path          string `json:"path"`
+		IsFolder      bool   `json:"isFolder"`
+	}
+
+	// change is a file or folder changed by a commit.
+	// SourceServerItem is the original path of a renamed item.
+	change struct {
+		ChangeType       string `json:"changeType"`
+		Item             item   `json:"item"`
+		SourceServerItem string `json:"sourceServerItem"`
+	}
+
+	commitChanges struct {
+		ChangeCounts map[string]int `json:"changeCounts"`
+		Changes      []change       `json:"changes"`
+	}
+
+	ref struct {
+		Name string `json:"name"`
+		// ObjectID is the id of the tag object for annotated tags and the id of the commit otherwise.
+		ObjectID string `json:"objectId"`
+		// PeeledObjectID is the id of the commit for annotated tags.
+		PeeledObjectID string      `json:"peeledObjectId"`
+		Creator        identityRef `json:"creator"`
+		URL            string      `json:"url"`
+	}
+
+	label struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Active bool   `json:"active"`
+	}
+
+	pullRequest struct {
+		PullRequestID   int          `json:"pullRequestId"`
+		Title           string       `json:"title"`
+		Status          string       `json:"status"`
+		CreatedBy       identityRef  `json:"createdBy"`
+		CreationDate    time.Time    `json:"creationDate"`
+		ClosedDate      time.Time    `json:"closedDate"`
+		ClosedBy        *identityRef `json:"closedBy"`
+		LastMergeCommit *commitRef   `json:"lastMergeCommit"`
+		SourceRefName   string       `json:"sourceRefName"`
+		TargetRefName   string       `json:"targetRefName"`
+		Labels          []label      `json:"labels"`
+	}
+
+	resourceRef struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+
+	workItemFields struct {
+		Title         string       `json:"System.Title"`
+		State         string       `json:"System.State"`
+		WorkItemType  string       `json:"System.WorkItemType"`
+		Tags          string       `json:"System.Tags"`
+		IterationPath string       `json:"System.IterationPath"`
+		CreatedBy     identityRef  `json:"System.CreatedBy"`
+		CreatedDate   time.Time    `json:"System.CreatedDate"`
+		ClosedBy      *identityRef `json:"Microsoft.VSTS.Common.ClosedBy"`
+		ClosedDate    *time.Time   `json:"Microsoft.VSTS.Common.ClosedDate"`
+	}
+
+	workItem struct {
+		ID     int            `json:"id"`
+		Rev    int            `json:"rev"`
+		Fields workItemFields `json:"fields"`
+		URL    string         `json:"url"`
+	}
+
+	// list is the envelope of Azure DevOps API responses with a collection of values.
+	// Value should be set to a pointer to a slice, so the values are decoded into it.
+	list struct {
+		Count int         `json:"count"`
+		Value interface{} `json:"value"`
+	}
+)
+
+// responseError is returned when an Azure DevOps API call is not successful.
+type responseError struct {
+	method     string
+	url        string
+	statusCode int
+	message    string
+}
+
+func newResponseError(resp *http.Response) *responseError {
+	e := &responseError{
+		method:     resp.Request.Method,
+		url:        resp.Request.URL.String(),
+		statusCode: resp.StatusCode,
+	}
+
+	// Azure DevOps responds to unauthenticated requests with a sign-in page instead of an error
+	if resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		e.message = "access token is missing, invalid, or expired"
+		return e
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return e
+	}
+
+	body := struct {
+		Message string `json:"message"`
+	}{}
+
+	if err := json.Unmarshal(b, &body); err != nil {
+		e.message = string(b)
+		return e
+	}
+
+	e.message = body.Message
+
+	return e
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s %s %d: %s", e.method, e.url, e.statusCode, e.message)
+}
+
+// pageParams returns the parameters for paginating the pull requests.
+func pageParams(skip int) url.Values {
+	params := url.Values{}
+	params.Set("$top", strconv.Itoa(pageSize))
+	params.Set("$skip", strconv.Itoa(skip))
+
+	return params
+}
+
+// commitsParams returns the parameters for paginating the commits.
+func commitsParams(skip int) url.Values {
+	params := url.Values{}
+	params.Set("searchCriteria.$top", strconv.Itoa(pageSize))
+	params.Set("searchCriteria.$skip", strconv.Itoa(skip))
+
+	return params
+}
+
+// changesParams returns the parameters for paginating the changes of a commit.
+func changesParams(skip int) url.Values {
+	params := url.Values{}
+	params.Set("top", strconv.Itoa(pageSize))
+	params.Set("skip", strconv.Itoa(skip))
+
+	return params
+}
+
+// setAuth sets the credentials of a request.
+// Personal access tokens are sent using the basic authentication with an empty username.
+func setAuth(req *http.Request, accessToken string) {
+	if accessToken == "" {
+		return
+	}
+
+	req.SetBasicAuth("", accessToken)
+}
+
+// call makes a GET request to an Azure DevOps API endpoint and decodes the response body into out.
+// It returns the continuation token for the next page if there is any.
+func (r *repo) call(ctx context.Context, endpoint string, params url.Values, out interface{}) (string, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("api-version", apiVersion)
+
+	u := r.apiURL + endpoint + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	setAuth(req, r.accessToken)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newResponseError(resp)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", err
+	}
+
+	return resp.Header.Get(continuationHeader), nil
+}
+
+// fetchAllPages calls fetch for every page of a paginated Azure DevOps API endpoint.
+// fetch receives the number of values to skip and returns the number of values fetched.
+// Pages are fetched one after another until a page is not full.
+func fetchAllPages(fetch func(int) (int, error)) error {
+	for skip := 0; ; skip += pageSize {
+		n, err := fetch(skip)
+		if err != nil {
+			return err
+		}
+
+		if n < pageSize {
+			return nil
+		}
+	}
+}
+
+// joinIDs returns the comma-separated list of work item ids.
+func joinIDs(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+
+	return strings.Join(s, ",")
+}
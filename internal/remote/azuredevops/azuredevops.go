@@ -0,0 +1,488 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+// repo implements the remote.Repo interface for Azure DevOps Repos.
+type repo struct {
+	logger       log.Logger
+	client       *http.Client
+	apiURL       string
+	webURL       string
+	organization string
+	project      string
+	repo         string
+	accessToken  string
+	stores       struct {
+		commits *remote.Store
+	}
+}
+
+// NewRepo creates a new Azure DevOps repository.
+// apiURL and webURL are the base URLs of Azure DevOps Services (i.e. https://dev.azure.com) or an Azure DevOps Server instance.
+// For Azure DevOps Server, organization is the path to the project collection (i.e. tfs/DefaultCollection).
+func NewRepo(logger log.Logger, cache *remote.Cache, apiURL, webURL, organization, project, repoName, accessToken string) remote.Repo {
+	transport := remote.NewTransport(logger, cache)
+	client := &http.Client{
+		Transport: transport,
+	}
+
+	r := &repo{
+		logger:       logger,
+		client:       client,
+		apiURL:       strings.TrimSuffix(apiURL, "/"),
+		webURL:       strings.TrimSuffix(webURL, "/"),
+		organization: organization,
+		project:      project,
+		repo:         repoName,
+		accessToken:  accessToken,
+	}
+
+	r.stores.commits = remote.NewStore()
+
+	return r
+}
+
+// projectEndpoint returns an API endpoint scoped to the project.
+func (r *repo) projectEndpoint(format string, a ...interface{}) string {
+	return fmt.Sprintf("/%s/%s/_apis", r.organization, url.PathEscape(r.project)) + fmt.Sprintf(format, a...)
+}
+
+// endpoint returns an API endpoint scoped to the repository.
+func (r *repo) endpoint(format string, a ...interface{}) string {
+	return r.projectEndpoint("/git/repositories/%s", url.PathEscape(r.repo)) + fmt.Sprintf(format, a...)
+}
+
+// projectURL returns the web URL of the project.
+func (r *repo) projectURL() string {
+	return fmt.Sprintf("%s/%s/%s", r.webURL, r.organization, url.PathEscape(r.project))
+}
+
+// repoURL returns the web URL of the repository.
+func (r *repo) repoURL() string {
+	return fmt.Sprintf("%s/_git/%s", r.projectURL(), url.PathEscape(r.repo))
+}
+
+func (r *repo) getCommit(ctx context.Context, id string) (commit, error) {
+	// First, check the cache
+	if v, ok := r.stores.commits.Load(id); ok {
+		c := v.(commit)
+		return c, nil
+	}
+
+	c := commit{}
+	if _, err := r.call(ctx, r.endpoint("/commits/%s", id), nil, &c); err != nil {
+		return commit{}, err
+	}
+
+	// Update the cache
+	r.stores.commits.Save(c.CommitID, c)
+
+	return c, nil
+}
+
+func (r *repo) getRef(ctx context.Context, name string) (ref, bool, error) {
+	params := url.Values{}
+	params.Set("filter", strings.TrimPrefix(name, "refs/"))
+
+	// The filter matches the refs by prefix, so the exact match is searched for
+	refs := []ref{}
+	if _, err := r.call(ctx, r.endpoint("/refs"), params, &list{Value: &refs}); err != nil {
+		return ref{}, false, err
+	}
+
+	for _, rf := range refs {
+		if rf.Name == name {
+			return rf, true, nil
+		}
+	}
+
+	return ref{}, false, nil
+}
+
+// FutureTag returns a tag that does not exist yet for an Azure DevOps repository.
+func (r *repo) FutureTag(name string) remote.Tag {
+	return remote.Tag{
+		Name:   name,
+		Time:   time.Now(),
+		WebURL: fmt.Sprintf("%s?version=%s", r.repoURL(), toVersion(name)),
+	}
+}
+
+// CompareURL returns a URL for comparing two revisions for an Azure DevOps repository.
+func (r *repo) CompareURL(base, head string) string {
+	return fmt.Sprintf("%s/branchCompare?baseVersion=%s&targetVersion=%s", r.repoURL(), toVersion(base), toVersion(head))
+}
+
+// CommitURL returns the web URL of a commit for a Azure DevOps repository.
+func (r *repo) CommitURL(hash string) string {
+	return fmt.Sprintf("%s/commit/%s", r.repoURL(), hash)
+}
+
+// CheckPermissions ensures the client has all the required permissions for an Azure DevOps repository.
+func (r *repo) CheckPermissions(ctx context.Context) error {
+	// The repository is only visible to the clients with the read permission
+	rp := repository{}
+	if _, err := r.call(ctx, r.endpoint(""), nil, &rp); err != nil {
+		return err
+	}
+
+	r.logger.Debug("Azure DevOps repository permissions verified: read")
+
+	return nil
+}
+
+// FetchFirstCommit retrieves the firist/initial commit for an Azure DevOps repository.
+func (r *repo) FetchFirstCommit(ctx context.Context) (remote.Commit, error) {
+	r.logger.Debug("Fetching the first Azure DevOps commit ...")
+
+	var c commit
+
+	// The commits of the default branch are listed from the most recent to the least recent
+	err := fetchAllPages(func(skip int) (int, error) {
+		commits := []commit{}
+		if _, err := r.call(ctx, r.endpoint("/commits"), commitsParams(skip), &list{Value: &commits}); err != nil {
+			return 0, err
+		}
+
+		// Add commits to commit store
+		for _, c := range commits {
+			r.stores.commits.Save(c.CommitID, c)
+		}
+
+		if l := len(commits); l > 0 {
+			c = commits[l-1]
+		}
+
+		return len(commits), nil
+	})
+
+	if err != nil {
+		return remote.Commit{}, err
+	}
+
+	commit := toCommit(c)
+
+	r.logger.Debugf("Fetched the first Azure DevOps commit: %s", commit)
+
+	return commit, nil
+}
+
+// FetchBranch retrieves a branch by name for an Azure DevOps repository.
+func (r *repo) FetchBranch(ctx context.Context, name string) (remote.Branch, error) {
+	rf, ok, err := r.getRef(ctx, "refs/heads/"+name)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	if !ok {
+		return remote.Branch{}, fmt.Errorf("Azure DevOps branch not found: %s", name)
+	}
+
+	c, err := r.getCommit(ctx, rf.ObjectID)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(rf, c)
+
+	r.logger.Debugf("Fetched Azure DevOps branch: %s", name)
+
+	return branch, nil
+}
+
+// FetchDefaultBranch retrieves the default branch for an Azure DevOps repository.
+func (r *repo) FetchDefaultBranch(ctx context.Context) (remote.Branch, error) {
+	rp := repository{}
+	if _, err := r.call(ctx, r.endpoint(""), nil, &rp); err != nil {
+		return remote.Branch{}, err
+	}
+
+	// An empty repository does not have a default branch
+	if rp.DefaultBranch == "" {
+		return remote.Branch{}, fmt.Errorf("Azure DevOps repository has no default branch: %s", r.repo)
+	}
+
+	branch, err := r.FetchBranch(ctx, strings.TrimPrefix(rp.DefaultBranch, "refs/heads/"))
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	r.logger.Debugf("Fetched Azure DevOps default branch: %s", branch.Name)
+
+	return branch, nil
+}
+
+// FetchTags retrieves all tags for an Azure DevOps repository.
+func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
+	r.logger.Debug("Fetching Azure DevOps tags ...")
+
+	// ==============================> FETCH TAGS <==============================
+
+	tagStore := remote.NewStore()
+
+	// The refs are paginated using continuation tokens
+	for token, p := "", 1; p == 1 || token != ""; p++ {
+		params := url.Values{}
+		params.Set("filter", "tags/")
+		params.Set("peelTags", "true")
+		params.Set("$top", strconv.Itoa(pageSize))
+		if token != "" {
+			params.Set("continuationToken", token)
+		}
+
+		refs := []ref{}
+		next, err := r.call(ctx, r.endpoint("/refs"), params, &list{Value: &refs})
+		if err != nil {
+			return nil, err
+		}
+
+		r.logger.Debugf("Fetched Azure DevOps tags page %d ...", p)
+
+		for _, rf := range refs {
+			tagStore.Save(rf.Name, rf)
+		}
+
+		token = next
+	}
+
+	// ==============================> FETCH TAG COMMITS <==============================
+
+	r.logger.Debug("Fetching Azure DevOps commits for tags ...")
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	_ = tagStore.ForEach(func(_, v interface{}) error {
+		g.Go(func() error {
+			rf := v.(ref)
+			_, err := r.getCommit(ctx, tagCommitID(rf))
+			return err
+		})
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// ==============================> JOINING TAGS & COMMITS <==============================
+
+	tags := resolveTags(tagStore, r.stores.commits, r.repoURL())
+
+	r.logger.Debugf("Azure DevOps tags are fetched: %d", len(tags))
+
+	return tags, nil
+}
+
+// FetchIssuesAndMerges retrieves all closed work items and completed pull requests for an Azure DevOps repository.
+// Only the work items linked to the completed pull requests are retrieved.
+func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
+	if since.IsZero() {
+		r.logger.Info("Fetching Azure DevOps work items and pull requests since the beginning ...")
+	} else {
+		r.logger.Infof("Fetching Azure DevOps work items and pull requests since %s ...", since.Format(time.RFC3339))
+	}
+
+	// ==============================> FETCH PULL REQUESTS <==============================
+
+	pullStore := remote.NewStore()
+
+	// The pull requests cannot be filtered by the completion time,
+	// so all completed pull requests are fetched and filtered.
+	err := fetchAllPages(func(skip int) (int, error) {
+		params := pageParams(skip)
+		params.Set("searchCriteria.status", statusCompleted)
+
+		pulls := []pullRequest{}
+		if _, err := r.call(ctx, r.endpoint("/pullrequests"), params, &list{Value: &pulls}); err != nil {
+			return 0, err
+		}
+
+		r.logger.Debugf("Fetched Azure DevOps pull requests page %d ...", skip/pageSize+1)
+
+		for _, p := range pulls {
+			if since.IsZero() || !p.ClosedDate.Before(since) {
+				pullStore.Save(p.PullRequestID, p)
+			}
+		}
+
+		return len(pulls), nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.logger.Debugf("Fetched Azure DevOps pull requests (%d)", pullStore.Len())
+
+	// ==============================> FETCH COMMITS & WORK ITEM REFS <==============================
+
+	r.logger.Debug("Fetching Azure DevOps commits and work items for pull requests ...")
+
+	workItemRefStore := remote.NewStore()
+
+	g, ctx1 := errgroup.WithContext(ctx)
+
+	_ = pullStore.ForEach(func(k, v interface{}) error {
+		id := k.(int)
+		p := v.(pullRequest)
+
+		g.Go(func() error {
+			refs := []resourceRef{}
+			if _, err := r.call(ctx1, r.endpoint("/pullRequests/%d/workitems", id), nil, &list{Value: &refs}); err != nil {
+				return err
+			}
+
+			for _, rf := range refs {
+				if n, err := strconv.Atoi(rf.ID); err == nil {
+					workItemRefStore.Save(n, rf)
+				}
+			}
+
+			if p.LastMergeCommit == nil {
+				return nil
+			}
+
+			_, err := r.getCommit(ctx1, p.LastMergeCommit.CommitID)
+			return err
+		})
+
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	// ==============================> FETCH WORK ITEMS <==============================
+
+	workItemStore := remote.NewStore()
+
+	ids := []int{}
+	_ = workItemRefStore.ForEach(func(k, v interface{}) error {
+		ids = append(ids, k.(int))
+		return nil
+	})
+
+	sort.Ints(ids)
+
+	for i := 0; i < len(ids); i += workItemsBatchSize {
+		j := i + workItemsBatchSize
+		if j > len(ids) {
+			j = len(ids)
+		}
+
+		params := url.Values{}
+		params.Set("ids", joinIDs(ids[i:j]))
+		// The deleted or inaccessible work items are returned as null instead of failing the request
+		params.Set("errorPolicy", "omit")
+
+		workItems := []*workItem{}
+		if _, err := r.call(ctx, r.projectEndpoint("/wit/workitems"), params, &list{Value: &workItems}); err != nil {
+			return nil, nil, err
+		}
+
+		for _, w := range workItems {
+			// Only closed work items are of interest
+			if w != nil && w.Fields.ClosedDate != nil && (since.IsZero() || !w.Fields.ClosedDate.Before(since)) {
+				workItemStore.Save(w.ID, *w)
+			}
+		}
+	}
+
+	r.logger.Debugf("Fetched Azure DevOps work items (%d)", workItemStore.Len())
+
+	// ==============================> JOINING WORK ITEMS, PULLS, & COMMITS <==============================
+
+	issues, merges := resolveIssuesAndMerges(workItemStore, pullStore, r.stores.commits, r.projectURL(), r.repoURL())
+
+	r.logger.Debugf("Resolved and sorted Azure DevOps work items (%d) and pull requests (%d)", len(issues), len(merges))
+	r.logger.Infof("All Azure DevOps work items (%d) and pull requests (%d) are fetched", len(issues), len(merges))
+
+	return issues, merges, nil
+}
+
+// FetchParentCommits retrieves all parent commits of a given commit hash for an Azure DevOps repository.
+func (r *repo) FetchParentCommits(ctx context.Context, hash string) (remote.Commits, error) {
+	r.logger.Debugf("Fetching all Azure DevOps parent commits for %s ...", hash)
+
+	commitStore := remote.NewStore()
+
+	err := fetchAllPages(func(skip int) (int, error) {
+		params := commitsParams(skip)
+		params.Set("searchCriteria.itemVersion.version", hash)
+		params.Set("searchCriteria.itemVersion.versionType", "commit")
+
+		commits := []commit{}
+		if _, err := r.call(ctx, r.endpoint("/commits"), params, &list{Value: &commits}); err != nil {
+			return 0, err
+		}
+
+		for _, c := range commits {
+			commitStore.Save(c.CommitID, c)
+			r.stores.commits.Save(c.CommitID, c)
+		}
+
+		return len(commits), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	commits := resolveCommits(commitStore)
+
+	r.logger.Debugf("All Azure DevOps parent commits for %s are fetched", hash)
+
+	return commits, nil
+}
+
+// FetchCommitFiles retrieves the paths of files changed by a commit for an Azure DevOps repository.
+// Renamed files are changed in both their old and new paths.
+func (r *repo) FetchCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	r.logger.Debugf("Fetching Azure DevOps files changed by %s ...", hash)
+
+	files := []string{}
+
+	err := fetchAllPages(func(skip int) (int, error) {
+		cc := commitChanges{}
+		if _, err := r.call(ctx, r.endpoint("/commits/%s/changes", hash), changesParams(skip), &cc); err != nil {
+			return 0, err
+		}
+
+		for _, c := range cc.Changes {
+			if c.Item.IsFolder {
+				continue
+			}
+
+			// Azure DevOps paths are absolute to the root of the repository.
+			files = append(files, strings.TrimPrefix(c.Item.Path, "/"))
+			if c.SourceServerItem != "" && c.SourceServerItem != c.Item.Path {
+				files = append(files, strings.TrimPrefix(c.SourceServerItem, "/"))
+			}
+		}
+
+		return len(cc.Changes), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debugf("Azure DevOps files changed by %s are fetched: %d", hash, len(files))
+
+	return files, nil
+}
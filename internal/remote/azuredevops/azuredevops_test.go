@@ -0,0 +1,816 @@
+package azuredevops
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+const (
+	projectPath = "/octo-org/octo-project/_apis"
+	repoPath    = projectPath + "/git/repositories/hello-world"
+	notFound    = "404: Resource not found"
+)
+
+// fullPage returns a full page of commits ending with the given commit.
+func fullPage(last commit) []commit {
+	commits := make([]commit, pageSize)
+	for i := range commits {
+		commits[i] = azureCommit2
+	}
+	commits[pageSize-1] = last
+
+	return commits
+}
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name         string
+		logger       log.Logger
+		apiURL       string
+		webURL       string
+		organization string
+		project      string
+		repoName     string
+		accessToken  string
+		expectedAPI  string
+		expectedWeb  string
+	}{
+		{
+			name:         "OK",
+			logger:       log.New(log.None),
+			apiURL:       "https://dev.azure.com/",
+			webURL:       "https://dev.azure.com/",
+			organization: "moorara",
+			project:      "changelog",
+			repoName:     "changelog",
+			accessToken:  "azure-devops-access-token",
+			expectedAPI:  "https://dev.azure.com",
+			expectedWeb:  "https://dev.azure.com",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRepo(tc.logger, nil, tc.apiURL, tc.webURL, tc.organization, tc.project, tc.repoName, tc.accessToken)
+			assert.NotNil(t, r)
+
+			ar, ok := r.(*repo)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.logger, ar.logger)
+			assert.NotNil(t, ar.client)
+			assert.Equal(t, tc.expectedAPI, ar.apiURL)
+			assert.Equal(t, tc.expectedWeb, ar.webURL)
+			assert.Equal(t, tc.organization, ar.organization)
+			assert.Equal(t, tc.project, ar.project)
+			assert.Equal(t, tc.repoName, ar.repo)
+			assert.Equal(t, tc.accessToken, ar.accessToken)
+			assert.NotNil(t, ar.stores.commits)
+		})
+	}
+}
+
+func TestRepo_getCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		commitsStore   *remote.Store
+		routes         map[string]MockResponse
+		ctx            context.Context
+		id             string
+		expectedCommit commit
+		expectedError  string
+	}{
+		{
+			name: "CacheHit",
+			commitsStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": azureCommit1,
+			}),
+			routes:         map[string]MockResponse{},
+			ctx:            context.Background(),
+			id:             "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: azureCommit1,
+		},
+		{
+			name:          "Error",
+			commitsStore:  remote.NewStore(),
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			id:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedError: repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e?api-version=6.0 " + notFound,
+		},
+		{
+			name:         "Success",
+			commitsStore: remote.NewStore(),
+			routes: map[string]MockResponse{
+				repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: azureCommit1},
+			},
+			ctx:            context.Background(),
+			id:             "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: azureCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+			r.stores.commits = tc.commitsStore
+
+			commit, err := r.getCommit(tc.ctx, tc.id)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+
+				v, ok := r.stores.commits.Load(tc.id)
+				assert.True(t, ok)
+				assert.Equal(t, tc.expectedCommit, v)
+			}
+		})
+	}
+}
+
+func TestRepo_FutureTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		webURL      string
+		project     string
+		tagName     string
+		expectedTag remote.Tag
+	}{
+		{
+			name:    "OK",
+			webURL:  "https://dev.azure.com",
+			project: "octo-project",
+			tagName: "v0.1.0",
+			expectedTag: remote.Tag{
+				Name:   "v0.1.0",
+				WebURL: "https://dev.azure.com/octo-org/octo-project/_git/hello-world?version=GTv0.1.0",
+			},
+		},
+		{
+			name:    "ProjectWithSpace",
+			webURL:  "https://dev.azure.com",
+			project: "Octo Project",
+			tagName: "v0.1.0",
+			expectedTag: remote.Tag{
+				Name:   "v0.1.0",
+				WebURL: "https://dev.azure.com/octo-org/Octo%20Project/_git/hello-world?version=GTv0.1.0",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:       log.New(log.None),
+				webURL:       tc.webURL,
+				organization: "octo-org",
+				project:      tc.project,
+				repo:         "hello-world",
+			}
+
+			tag := r.FutureTag(tc.tagName)
+
+			assert.Equal(t, tc.expectedTag.Name, tag.Name)
+			assert.NotEmpty(t, tag.Time)
+			assert.Equal(t, tc.expectedTag.WebURL, tag.WebURL)
+		})
+	}
+}
+
+func TestRepo_CompareURL(t *testing.T) {
+	tests := []struct {
+		name               string
+		webURL             string
+		base               string
+		head               string
+		expectedCompareURL string
+	}{
+		{
+			name:               "Tags",
+			webURL:             "https://dev.azure.com",
+			base:               "v0.1.0",
+			head:               "v0.2.0",
+			expectedCompareURL: "https://dev.azure.com/octo-org/octo-project/_git/hello-world/branchCompare?baseVersion=GTv0.1.0&targetVersion=GTv0.2.0",
+		},
+		{
+			name:               "CommitAndTag",
+			webURL:             "https://dev.azure.com",
+			base:               "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			head:               "v0.1.0",
+			expectedCompareURL: "https://dev.azure.com/octo-org/octo-project/_git/hello-world/branchCompare?baseVersion=GC6dcb09b5b57875f334f61aebed695e2e4193db5e&targetVersion=GTv0.1.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:       log.New(log.None),
+				webURL:       tc.webURL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+
+			url := r.CompareURL(tc.base, tc.head)
+
+			assert.Equal(t, tc.expectedCompareURL, url)
+		})
+	}
+}
+
+func TestRepo_CommitURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		webURL      string
+		expectedURL string
+	}{
+		{
+			name:        "OK",
+			webURL:      "https://dev.azure.com",
+			expectedURL: "https://dev.azure.com/octo-org/octo-project/_git/hello-world/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:       log.New(log.None),
+				webURL:       tc.webURL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+
+			url := r.CommitURL("6dcb09b5b57875f334f61aebed695e2e4193db5e")
+
+			assert.Equal(t, tc.expectedURL, url)
+		})
+	}
+}
+
+func TestRepo_CheckPermissions(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "?api-version=6.0 " + notFound,
+		},
+		{
+			name: "Unauthorized",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusNonAuthoritativeInfo, Body: "<html></html>"},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + "?api-version=6.0 203: access token is missing, invalid, or expired",
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: azureRepository},
+			},
+			ctx:           context.Background(),
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+
+			err := r.CheckPermissions(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchFirstCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedCommit remote.Commit
+		expectedError  string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/commits?api-version=6.0&searchCriteria.%24skip=0&searchCriteria.%24top=100 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits":          {StatusCode: http.StatusOK, Values: fullPage(azureCommit2)},
+				repoPath + "/commits?skip=100": {StatusCode: http.StatusOK, Values: []commit{azureCommit1}},
+			},
+			ctx:            context.Background(),
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commit, err := r.FetchFirstCommit(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		branchName     string
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "RefsFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: repoPath + "/refs?api-version=6.0&filter=heads%2Fmain " + notFound,
+		},
+		{
+			name: "NotFound",
+			routes: map[string]MockResponse{
+				repoPath + "/refs": {StatusCode: http.StatusOK, Values: []ref{{Name: "refs/heads/main-old"}}},
+			},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: "Azure DevOps branch not found: main",
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/refs": {StatusCode: http.StatusOK, Values: []ref{azureBranch}},
+			},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c?api-version=6.0 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/refs": {StatusCode: http.StatusOK, Values: []ref{{Name: "refs/heads/main-old"}, azureBranch}},
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: azureCommit2},
+			},
+			ctx:            context.Background(),
+			branchName:     "main",
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			branch, err := r.FetchBranch(tc.ctx, tc.branchName)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "RepositoryFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "?api-version=6.0 " + notFound,
+		},
+		{
+			name: "NoDefaultBranch",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: repository{Name: "hello-world"}},
+			},
+			ctx:           context.Background(),
+			expectedError: "Azure DevOps repository has no default branch: hello-world",
+		},
+		{
+			name: "BranchFails",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: azureRepository},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/refs?api-version=6.0&filter=heads%2Fmain " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath:           {StatusCode: http.StatusOK, Body: azureRepository},
+				repoPath + "/refs": {StatusCode: http.StatusOK, Values: []ref{azureBranch}},
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: azureCommit2},
+			},
+			ctx:            context.Background(),
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			branch, err := r.FetchDefaultBranch(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchTags(t *testing.T) {
+	azureTag2 := ref{
+		Name:     "refs/tags/v0.0.1",
+		ObjectID: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+	}
+
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedTags  remote.Tags
+		expectedError string
+	}{
+		{
+			name:          "RefsFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/refs?%24top=100&api-version=6.0&filter=tags%2F&peelTags=true " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/refs": {StatusCode: http.StatusOK, Values: []ref{azureTag}},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c?api-version=6.0 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/refs": {StatusCode: http.StatusOK, ContinuationToken: "djAuMC4x", Values: []ref{azureTag}},
+				repoPath + "/refs?continuationToken=djAuMC4x":                  {StatusCode: http.StatusOK, Values: []ref{azureTag2}},
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: azureCommit2},
+				repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: azureCommit1},
+			},
+			ctx: context.Background(),
+			expectedTags: remote.Tags{
+				remoteTag,
+				{
+					Name:   "v0.0.1",
+					Time:   remoteCommit1.Time,
+					Commit: remoteCommit1,
+					WebURL: "https://dev.azure.com/octo-org/octo-project/_git/hello-world?version=GTv0.0.1",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				webURL:       "https://dev.azure.com",
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			tags, err := r.FetchTags(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, tags)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTags, tags.Sort())
+			}
+		})
+	}
+}
+
+func TestRepo_FetchIssuesAndMerges(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		since          time.Time
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+		expectedError  string
+	}{
+		{
+			name:          "PullsFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: repoPath + "/pullrequests?%24skip=0&%24top=100&api-version=6.0&searchCriteria.status=completed " + notFound,
+		},
+		{
+			name: "WorkItemRefsFails",
+			routes: map[string]MockResponse{
+				repoPath + "/pullrequests": {StatusCode: http.StatusOK, Values: []pullRequest{azurePull}},
+			},
+			ctx:           context.Background(),
+			since:         parseAzureTime("2020-10-01T00:00:00Z"),
+			expectedError: repoPath + "/pullRequests/1002/workitems?api-version=6.0 " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/pullrequests":                {StatusCode: http.StatusOK, Values: []pullRequest{azurePull}},
+				repoPath + "/pullRequests/1002/workitems": {StatusCode: http.StatusOK, Values: []resourceRef{}},
+			},
+			ctx:           context.Background(),
+			since:         parseAzureTime("2020-10-01T00:00:00Z"),
+			expectedError: repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e?api-version=6.0 " + notFound,
+		},
+		{
+			name: "WorkItemsFails",
+			routes: map[string]MockResponse{
+				repoPath + "/pullrequests":                                     {StatusCode: http.StatusOK, Values: []pullRequest{azurePull}},
+				repoPath + "/pullRequests/1002/workitems":                      {StatusCode: http.StatusOK, Values: []resourceRef{{ID: "1001"}}},
+				repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: azureCommit1},
+			},
+			ctx:           context.Background(),
+			since:         parseAzureTime("2020-10-01T00:00:00Z"),
+			expectedError: projectPath + "/wit/workitems?api-version=6.0&errorPolicy=omit&ids=1001 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/pullrequests": {StatusCode: http.StatusOK, Values: []pullRequest{
+					azurePull,
+					{PullRequestID: 1000, Status: "completed", ClosedDate: parseAzureTime("2020-09-01T00:00:00Z")},
+				}},
+				repoPath + "/pullRequests/1002/workitems":                      {StatusCode: http.StatusOK, Values: []resourceRef{{ID: "1001"}, {ID: "999"}}},
+				repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: azureCommit1},
+				projectPath + "/wit/workitems":                                 {StatusCode: http.StatusOK, Values: []*workItem{nil, &azureWorkItem}},
+			},
+			ctx:            context.Background(),
+			since:          parseAzureTime("2020-10-01T00:00:00Z"),
+			expectedIssues: remote.Issues{remoteIssue},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				webURL:       "https://dev.azure.com",
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			issues, merges, err := r.FetchIssuesAndMerges(tc.ctx, tc.since)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, issues)
+				assert.Nil(t, merges)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIssues, issues)
+				assert.Equal(t, tc.expectedMerges, merges)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchParentCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		routes          map[string]MockResponse
+		ctx             context.Context
+		hash            string
+		expectedCommits remote.Commits
+		expectedError   string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: repoPath + "/commits?api-version=6.0&searchCriteria.%24skip=0&searchCriteria.%24top=100&searchCriteria.itemVersion.version=c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c&searchCriteria.itemVersion.versionType=commit " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits":          {StatusCode: http.StatusOK, Values: fullPage(azureCommit2)},
+				repoPath + "/commits?skip=100": {StatusCode: http.StatusOK, Values: []commit{azureCommit1}},
+			},
+			ctx:             context.Background(),
+			hash:            "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commits, err := r.FetchParentCommits(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, commits)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommits, commits)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchCommitFiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		hash          string
+		expectedFiles []string
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c/changes?api-version=6.0&skip=0&top=100 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c/changes": {
+					StatusCode: http.StatusOK,
+					Body: commitChanges{
+						ChangeCounts: map[string]int{"Edit": 1, "Rename": 1},
+						Changes: []change{
+							{ChangeType: "edit", Item: item{GitObjectType: "tree", Path: "/api", IsFolder: true}},
+							{ChangeType: "edit", Item: item{GitObjectType: "blob", Path: "/api/server.go"}},
+							{ChangeType: "rename", Item: item{GitObjectType: "blob", Path: "/web/app.js"}, SourceServerItem: "/web/index.js"},
+						},
+					},
+				},
+			},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedFiles: []string{"api/server.go", "web/app.js", "web/index.js"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:       log.New(log.None),
+				client:       ts.Client(),
+				apiURL:       ts.URL,
+				organization: "octo-org",
+				project:      "octo-project",
+				repo:         "hello-world",
+			}
+
+			files, err := r.FetchCommitFiles(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, files)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFiles, files)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package azuredevops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+var (
+	azureUser1 = identityRef{
+		ID:          "6d3ed8f4-3f1c-4c8e-8a36-0b1f3e7e7a01",
+		DisplayName: "The Octocat",
+		UniqueName:  "octocat@example.com",
+		URL:         "https://vssps.dev.azure.com/octo-org/_apis/Identities/6d3ed8f4-3f1c-4c8e-8a36-0b1f3e7e7a01",
+	}
+
+	azureUser2 = identityRef{
+		ID:          "6d3ed8f4-3f1c-4c8e-8a36-0b1f3e7e7a02",
+		DisplayName: "The Octodog",
+		UniqueName:  "octodog@example.com",
+		URL:         "https://vssps.dev.azure.com/octo-org/_apis/Identities/6d3ed8f4-3f1c-4c8e-8a36-0b1f3e7e7a02",
+	}
+
+	azureUser3 = identityRef{
+		ID:          "6d3ed8f4-3f1c-4c8e-8a36-0b1f3e7e7a03",
+		DisplayName: "The Octofox",
+		UniqueName:  "octofox@example.com",
+		URL:         "https://vssps.dev.azure.com/octo-org/_apis/Identities/6d3ed8f4-3f1c-4c8e-8a36-0b1f3e7e7a03",
+	}
+
+	azureRepository = repository{
+		ID:            "5febef5a-833d-4e14-b9c0-14cb638f91e6",
+		Name:          "hello-world",
+		DefaultBranch: "refs/heads/main",
+		Project: project{
+			ID:   "6ce954b1-ce1f-45d1-b94d-e6bf2464ba2c",
+			Name: "octo-project",
+		},
+		WebURL:    "https://dev.azure.com/octo-org/octo-project/_git/hello-world",
+		RemoteURL: "https://octo-org@dev.azure.com/octo-org/octo-project/_git/hello-world",
+	}
+
+	azureCommit1 = commit{
+		CommitID: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Author: gitUserDate{
+			Name:  "The Octocat",
+			Email: "octocat@example.com",
+			Date:  parseAzureTime("2020-10-20T19:59:59Z"),
+		},
+		Committer: gitUserDate{
+			Name:  "The Octocat",
+			Email: "octocat@example.com",
+			Date:  parseAzureTime("2020-10-20T19:59:59Z"),
+		},
+		Comment: "Fix all the bugs",
+		Parents: []string{},
+	}
+
+	azureCommit2 = commit{
+		CommitID: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Author: gitUserDate{
+			Name:  "The Octocat",
+			Email: "octocat@example.com",
+			Date:  parseAzureTime("2020-10-27T23:59:59Z"),
+		},
+		Committer: gitUserDate{
+			Name:  "The Octocat",
+			Email: "octocat@example.com",
+			Date:  parseAzureTime("2020-10-27T23:59:59Z"),
+		},
+		Comment: "Release v0.1.0",
+		Parents: []string{
+			"6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+	}
+
+	azureBranch = ref{
+		Name:     "refs/heads/main",
+		ObjectID: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Creator:  azureUser1,
+	}
+
+	azureTag = ref{
+		Name:           "refs/tags/v0.1.0",
+		ObjectID:       "a5b5a3b5d2fd3b0c1b2a4e4f2b7d3e0c9f1e2d3c",
+		PeeledObjectID: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Creator:        azureUser1,
+	}
+
+	azurePull = pullRequest{
+		PullRequestID: 1002,
+		Title:         "Fixed a bug",
+		Status:        "completed",
+		CreatedBy:     azureUser2,
+		CreationDate:  parseAzureTime("2020-10-15T15:00:00Z"),
+		ClosedDate:    parseAzureTime("2020-10-20T20:00:00Z"),
+		ClosedBy:      &azureUser3,
+		LastMergeCommit: &commitRef{
+			CommitID: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+		SourceRefName: "refs/heads/fix",
+		TargetRefName: "refs/heads/main",
+		Labels: []label{
+			{ID: "1", Name: "bug", Active: true},
+			{ID: "2", Name: "stale", Active: false},
+		},
+	}
+
+	closedDate = parseAzureTime("2020-10-20T20:00:00Z")
+
+	azureWorkItem = workItem{
+		ID:  1001,
+		Rev: 3,
+		Fields: workItemFields{
+			Title:         "Found a bug",
+			State:         "Closed",
+			WorkItemType:  "Bug",
+			Tags:          "bug; help wanted",
+			IterationPath: `octo-project\Sprint 1`,
+			CreatedBy:     azureUser1,
+			CreatedDate:   parseAzureTime("2020-10-10T10:00:00Z"),
+			ClosedBy:      &azureUser1,
+			ClosedDate:    &closedDate,
+		},
+	}
+
+	remoteUser1 = remote.User{
+		Name:     "The Octocat",
+		Email:    "octocat@example.com",
+		Username: "octocat@example.com",
+	}
+
+	remoteUser2 = remote.User{
+		Name:     "The Octodog",
+		Email:    "octodog@example.com",
+		Username: "octodog@example.com",
+	}
+
+	remoteUser3 = remote.User{
+		Name:     "The Octofox",
+		Email:    "octofox@example.com",
+		Username: "octofox@example.com",
+	}
+
+	remoteCommit1 = remote.Commit{
+		Hash:    "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time:    parseAzureTime("2020-10-20T19:59:59Z"),
+		Message: "Fix all the bugs",
+	}
+
+	remoteCommit2 = remote.Commit{
+		Hash:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Time:    parseAzureTime("2020-10-27T23:59:59Z"),
+		Parents: []string{"6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		Message: "Release v0.1.0",
+	}
+
+	remoteBranch = remote.Branch{
+		Name:   "main",
+		Commit: remoteCommit2,
+	}
+
+	remoteTag = remote.Tag{
+		Name:   "v0.1.0",
+		Time:   parseAzureTime("2020-10-27T23:59:59Z"),
+		Commit: remoteCommit2,
+		WebURL: "https://dev.azure.com/octo-org/octo-project/_git/hello-world?version=GTv0.1.0",
+	}
+
+	remoteIssue = remote.Issue{
+		Change: remote.Change{
+			Number:    1001,
+			Title:     "Found a bug",
+			Labels:    []string{"bug", "help wanted"},
+			Milestone: "Sprint 1",
+			Time:      parseAzureTime("2020-10-20T20:00:00Z"),
+			Author:    remoteUser1,
+			WebURL:    "https://dev.azure.com/octo-org/octo-project/_workitems/edit/1001",
+		},
+		Closer: remoteUser1,
+	}
+
+	remoteMerge = remote.Merge{
+		Change: remote.Change{
+			Number: 1002,
+			Title:  "Fixed a bug",
+			Labels: []string{"bug"},
+			Time:   parseAzureTime("2020-10-20T19:59:59Z"),
+			Author: remoteUser2,
+			WebURL: "https://dev.azure.com/octo-org/octo-project/_git/hello-world/pullrequest/1002",
+		},
+		Merger: remoteUser3,
+		Commit: remoteCommit1,
+	}
+)
+
+func parseAzureTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// MockResponse is a canned response of the fake Azure DevOps API server.
+// If Values is set, the response is a list of values with a continuation token for the next page (if any).
+// Otherwise, Body is the response.
+type MockResponse struct {
+	StatusCode        int
+	ContinuationToken string
+	Values            interface{}
+	Body              interface{}
+}
+
+// newMockServer creates a fake Azure DevOps API server.
+// The routes are keyed by the request path and, for subsequent pages, the number of skipped values or the continuation token
+// (i.e. /octo-org/octo-project/_apis/git/repositories/hello-world/commits?skip=100).
+// Requests to unknown routes receive a 404 Not Found response.
+func newMockServer(routes map[string]MockResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		key := r.URL.Path
+		if skip := q.Get("$skip") + q.Get("searchCriteria.$skip"); skip != "" && skip != "0" {
+			key += "?skip=" + skip
+		} else if token := q.Get("continuationToken"); token != "" {
+			key += "?continuationToken=" + token
+		}
+
+		resp, ok := routes[key]
+		if !ok {
+			resp = MockResponse{
+				StatusCode: http.StatusNotFound,
+				Body: map[string]interface{}{
+					"message":   "Resource not found",
+					"typeName":  "Microsoft.TeamFoundation.Git.Server.GitRepositoryNotFoundException",
+					"errorCode": 0,
+				},
+			}
+		}
+
+		body := resp.Body
+		if resp.Values != nil {
+			body = map[string]interface{}{
+				"count": reflect.ValueOf(resp.Values).Len(),
+				"value": resp.Values,
+			}
+		}
+
+		if resp.ContinuationToken != "" {
+			w.Header().Set(continuationHeader, resp.ContinuationToken)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+}
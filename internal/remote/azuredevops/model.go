@@ -0,0 +1,232 @@
+package azuredevops
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+var commitHashRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+func toUser(i identityRef) remote.User {
+	var email string
+	if strings.Contains(i.UniqueName, "@") {
+		email = i.UniqueName
+	}
+
+	// Azure DevOps does not have public profile pages for users
+	return remote.User{
+		Name:     i.DisplayName,
+		Email:    email,
+		Username: i.UniqueName,
+	}
+}
+
+func toCommit(c commit) remote.Commit {
+	// Copy the parent hashes (nil for root commits)
+	parents := append([]string(nil), c.Parents...)
+
+	return remote.Commit{
+		Hash:    c.CommitID,
+		Time:    c.Committer.Date,
+		Parents: parents,
+		Message: c.Comment,
+	}
+}
+
+func toBranch(r ref, c commit) remote.Branch {
+	return remote.Branch{
+		Name:   strings.TrimPrefix(r.Name, "refs/heads/"),
+		Commit: toCommit(c),
+	}
+}
+
+// toVersion returns the version descriptor of a revision used in web URLs.
+// A revision is either a commit hash (GC) or a tag name (GT).
+func toVersion(rev string) string {
+	if commitHashRegex.MatchString(rev) {
+		return "GC" + rev
+	}
+
+	return "GT" + url.QueryEscape(rev)
+}
+
+func toTag(r ref, c commit, repoURL string) remote.Tag {
+	name := strings.TrimPrefix(r.Name, "refs/tags/")
+
+	return remote.Tag{
+		Name:   name,
+		Time:   c.Committer.Date,
+		Commit: toCommit(c),
+		WebURL: fmt.Sprintf("%s?version=%s", repoURL, toVersion(name)),
+	}
+}
+
+func toLabels(labels []label) []string {
+	names := []string{}
+	for _, l := range labels {
+		if l.Active {
+			names = append(names, l.Name)
+		}
+	}
+
+	return names
+}
+
+// toTags splits the semicolon-separated tags of a work item.
+func toTags(tags string) []string {
+	names := []string{}
+	for _, t := range strings.Split(tags, ";") {
+		if t = strings.TrimSpace(t); t != "" {
+			names = append(names, t)
+		}
+	}
+
+	return names
+}
+
+// toMilestone returns the name of the iteration of a work item.
+// Work items with the root iteration (the project itself) are not assigned to any iteration.
+func toMilestone(iterationPath string) string {
+	if i := strings.LastIndex(iterationPath, `\`); i >= 0 {
+		return iterationPath[i+1:]
+	}
+
+	return ""
+}
+
+func toIssue(w workItem, projectURL string) remote.Issue {
+	var time time.Time
+	if w.Fields.ClosedDate != nil {
+		time = *w.Fields.ClosedDate
+	}
+
+	var closer identityRef
+	if w.Fields.ClosedBy != nil {
+		closer = *w.Fields.ClosedBy
+	}
+
+	return remote.Issue{
+		Change: remote.Change{
+			Number: w.ID,
+			Title:  w.Fields.Title,
+			// The tags of work items are the equivalent of labels
+			Labels:    toTags(w.Fields.Tags),
+			Milestone: toMilestone(w.Fields.IterationPath),
+			Time:      time,
+			Author:    toUser(w.Fields.CreatedBy),
+			WebURL:    fmt.Sprintf("%s/_workitems/edit/%d", projectURL, w.ID),
+		},
+		Closer: toUser(closer),
+	}
+}
+
+func toMerge(p pullRequest, c commit, repoURL string) remote.Merge {
+	var merger identityRef
+	if p.ClosedBy != nil {
+		merger = *p.ClosedBy
+	}
+
+	// p.ClosedDate is the time the pull request was completed on Azure DevOps
+	// c.Committer.Date is the actual time of merge
+	time := c.Committer.Date
+
+	return remote.Merge{
+		Change: remote.Change{
+			Number: p.PullRequestID,
+			Title:  p.Title,
+			Labels: toLabels(p.Labels),
+			Time:   time,
+			Author: toUser(p.CreatedBy),
+			WebURL: fmt.Sprintf("%s/pullrequest/%d", repoURL, p.PullRequestID),
+		},
+		Merger: toUser(merger),
+		Commit: toCommit(c),
+	}
+}
+
+// tagCommitID returns the id of the commit a tag points to.
+func tagCommitID(r ref) string {
+	if r.PeeledObjectID != "" {
+		return r.PeeledObjectID
+	}
+
+	return r.ObjectID
+}
+
+func resolveTags(azureTags, azureCommits *remote.Store, repoURL string) remote.Tags {
+	tags := remote.Tags{}
+
+	_ = azureTags.ForEach(func(k, v interface{}) error {
+		r := v.(ref)
+
+		if v, ok := azureCommits.Load(tagCommitID(r)); ok {
+			c := v.(commit)
+			tags = append(tags, toTag(r, c, repoURL))
+		}
+
+		return nil
+	})
+
+	return tags
+}
+
+func resolveCommits(azureCommits *remote.Store) remote.Commits {
+	commits := remote.Commits{}
+
+	_ = azureCommits.ForEach(func(k, v interface{}) error {
+		c := v.(commit)
+		commits = append(commits, toCommit(c))
+		return nil
+	})
+
+	// The order of the commits should be from the most recent to the least recent
+	sort.Slice(commits, func(i, j int) bool {
+		if commits[i].Time.Equal(commits[j].Time) {
+			return commits[i].Hash < commits[j].Hash
+		}
+		return commits[i].Time.After(commits[j].Time)
+	})
+
+	return commits
+}
+
+func resolveIssuesAndMerges(azureWorkItems, azurePulls, azureCommits *remote.Store, projectURL, repoURL string) (remote.Issues, remote.Merges) {
+	issues := remote.Issues{}
+	merges := remote.Merges{}
+
+	_ = azureWorkItems.ForEach(func(k, v interface{}) error {
+		w := v.(workItem)
+
+		// If the closer is not known, the work item is skipped
+		if w.Fields.ClosedBy != nil && w.Fields.ClosedDate != nil {
+			issues = append(issues, toIssue(w, projectURL))
+		}
+
+		return nil
+	})
+
+	_ = azurePulls.ForEach(func(k, v interface{}) error {
+		p := v.(pullRequest)
+
+		// If the merger or the merge commit is not known, the pull request is skipped
+		if p.ClosedBy != nil && p.LastMergeCommit != nil {
+			if v, ok := azureCommits.Load(p.LastMergeCommit.CommitID); ok {
+				c := v.(commit)
+				merges = append(merges, toMerge(p, c, repoURL))
+			}
+		}
+
+		return nil
+	})
+
+	issues = issues.Sort()
+	merges = merges.Sort()
+
+	return issues, merges
+}
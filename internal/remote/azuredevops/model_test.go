@@ -0,0 +1,345 @@
+package azuredevops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func TestToUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		i            identityRef
+		expectedUser remote.User
+	}{
+		{
+			name:         "Email",
+			i:            azureUser1,
+			expectedUser: remoteUser1,
+		},
+		{
+			name: "DomainAccount",
+			i: identityRef{
+				DisplayName: "The Octocat",
+				UniqueName:  `OCTO\octocat`,
+			},
+			expectedUser: remote.User{
+				Name:     "The Octocat",
+				Username: `OCTO\octocat`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := toUser(tc.i)
+			assert.Equal(t, tc.expectedUser, user)
+		})
+	}
+}
+
+func TestToCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		c              commit
+		expectedCommit remote.Commit
+	}{
+		{
+			name:           "OK",
+			c:              azureCommit1,
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commit := toCommit(tc.c)
+			assert.Equal(t, tc.expectedCommit, commit)
+		})
+	}
+}
+
+func TestToBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		r              ref
+		c              commit
+		expectedBranch remote.Branch
+	}{
+		{
+			name:           "OK",
+			r:              azureBranch,
+			c:              azureCommit2,
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			branch := toBranch(tc.r, tc.c)
+			assert.Equal(t, tc.expectedBranch, branch)
+		})
+	}
+}
+
+func TestToVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		rev             string
+		expectedVersion string
+	}{
+		{
+			name:            "Tag",
+			rev:             "v0.1.0",
+			expectedVersion: "GTv0.1.0",
+		},
+		{
+			name:            "TagWithSlash",
+			rev:             "release/v0.1.0",
+			expectedVersion: "GTrelease%2Fv0.1.0",
+		},
+		{
+			name:            "Commit",
+			rev:             "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedVersion: "GC6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedVersion, toVersion(tc.rev))
+		})
+	}
+}
+
+func TestToTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		r           ref
+		c           commit
+		repoURL     string
+		expectedTag remote.Tag
+	}{
+		{
+			name:        "OK",
+			r:           azureTag,
+			c:           azureCommit2,
+			repoURL:     "https://dev.azure.com/octo-org/octo-project/_git/hello-world",
+			expectedTag: remoteTag,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tag := toTag(tc.r, tc.c, tc.repoURL)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestToTags(t *testing.T) {
+	tests := []struct {
+		name         string
+		tags         string
+		expectedTags []string
+	}{
+		{
+			name:         "Empty",
+			tags:         "",
+			expectedTags: []string{},
+		},
+		{
+			name:         "OK",
+			tags:         "bug; help wanted;ui",
+			expectedTags: []string{"bug", "help wanted", "ui"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedTags, toTags(tc.tags))
+		})
+	}
+}
+
+func TestToMilestone(t *testing.T) {
+	tests := []struct {
+		name              string
+		iterationPath     string
+		expectedMilestone string
+	}{
+		{
+			name:              "RootIteration",
+			iterationPath:     "octo-project",
+			expectedMilestone: "",
+		},
+		{
+			name:              "Iteration",
+			iterationPath:     `octo-project\Release 1\Sprint 1`,
+			expectedMilestone: "Sprint 1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedMilestone, toMilestone(tc.iterationPath))
+		})
+	}
+}
+
+func TestToIssue(t *testing.T) {
+	tests := []struct {
+		name          string
+		w             workItem
+		projectURL    string
+		expectedIssue remote.Issue
+	}{
+		{
+			name:          "OK",
+			w:             azureWorkItem,
+			projectURL:    "https://dev.azure.com/octo-org/octo-project",
+			expectedIssue: remoteIssue,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issue := toIssue(tc.w, tc.projectURL)
+			assert.Equal(t, tc.expectedIssue, issue)
+		})
+	}
+}
+
+func TestToMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		p             pullRequest
+		c             commit
+		repoURL       string
+		expectedMerge remote.Merge
+	}{
+		{
+			name:          "OK",
+			p:             azurePull,
+			c:             azureCommit1,
+			repoURL:       "https://dev.azure.com/octo-org/octo-project/_git/hello-world",
+			expectedMerge: remoteMerge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merge := toMerge(tc.p, tc.c, tc.repoURL)
+			assert.Equal(t, tc.expectedMerge, merge)
+		})
+	}
+}
+
+func TestResolveTags(t *testing.T) {
+	tests := []struct {
+		name         string
+		azureTags    *remote.Store
+		azureCommits *remote.Store
+		repoURL      string
+		expectedTags remote.Tags
+	}{
+		{
+			name: "OK",
+			azureTags: remote.NewStoreFrom(map[interface{}]interface{}{
+				"refs/tags/v0.1.0": azureTag,
+				"refs/tags/v0.0.1": ref{Name: "refs/tags/v0.0.1", ObjectID: "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378"},
+			}),
+			azureCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": azureCommit2,
+			}),
+			repoURL:      "https://dev.azure.com/octo-org/octo-project/_git/hello-world",
+			expectedTags: remote.Tags{remoteTag},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tags := resolveTags(tc.azureTags, tc.azureCommits, tc.repoURL)
+			assert.Equal(t, tc.expectedTags, tags)
+		})
+	}
+}
+
+func TestResolveCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		azureCommits    *remote.Store
+		expectedCommits remote.Commits
+	}{
+		{
+			name: "OK",
+			azureCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": azureCommit1,
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": azureCommit2,
+			}),
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commits := resolveCommits(tc.azureCommits)
+			assert.Equal(t, tc.expectedCommits, commits)
+		})
+	}
+}
+
+func TestResolveIssuesAndMerges(t *testing.T) {
+	tests := []struct {
+		name           string
+		azureWorkItems *remote.Store
+		azurePulls     *remote.Store
+		azureCommits   *remote.Store
+		projectURL     string
+		repoURL        string
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+	}{
+		{
+			name: "OK",
+			azureWorkItems: remote.NewStoreFrom(map[interface{}]interface{}{
+				1001: azureWorkItem,
+			}),
+			azurePulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: azurePull,
+			}),
+			azureCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": azureCommit1,
+			}),
+			projectURL:     "https://dev.azure.com/octo-org/octo-project",
+			repoURL:        "https://dev.azure.com/octo-org/octo-project/_git/hello-world",
+			expectedIssues: remote.Issues{remoteIssue},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+		{
+			name: "UnknownCloserAndMergeCommit",
+			azureWorkItems: remote.NewStoreFrom(map[interface{}]interface{}{
+				1003: workItem{ID: 1003},
+			}),
+			azurePulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: azurePull,
+				1004: pullRequest{PullRequestID: 1004},
+			}),
+			azureCommits:   remote.NewStore(),
+			projectURL:     "https://dev.azure.com/octo-org/octo-project",
+			repoURL:        "https://dev.azure.com/octo-org/octo-project/_git/hello-world",
+			expectedIssues: remote.Issues{},
+			expectedMerges: remote.Merges{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issues, merges := resolveIssuesAndMerges(tc.azureWorkItems, tc.azurePulls, tc.azureCommits, tc.projectURL, tc.repoURL)
+			assert.Equal(t, tc.expectedIssues, issues)
+			assert.Equal(t, tc.expectedMerges, merges)
+		})
+	}
+}
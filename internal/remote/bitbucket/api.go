@@ -0,0 +1,241 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	userAgent = "moorara/changelog"
+	// The maximum page length for pull requests is 50.
+	pageLen = 50
+)
+
+type (
+	link struct {
+		Href string `json:"href"`
+	}
+
+	links struct {
+		HTML link `json:"html"`
+	}
+
+	account struct {
+		UUID        string `json:"uuid"`
+		AccountID   string `json:"account_id"`
+		DisplayName string `json:"display_name"`
+		Nickname    string `json:"nickname"`
+		Links       links  `json:"links"`
+	}
+
+	author struct {
+		Raw  string   `json:"raw"`
+		User *account `json:"user"`
+	}
+
+	mainBranch struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+
+	repository struct {
+		UUID       string      `json:"uuid"`
+		Name       string      `json:"name"`
+		FullName   string      `json:"full_name"`
+		MainBranch *mainBranch `json:"mainbranch"`
+		Links      links       `json:"links"`
+	}
+
+	commitRef struct {
+		Hash string `json:"hash"`
+	}
+
+	commit struct {
+		Hash    string      `json:"hash"`
+		Date    time.Time   `json:"date"`
+		Message string      `json:"message"`
+		Author  author      `json:"author"`
+		Parents []commitRef `json:"parents"`
+		Links   links       `json:"links"`
+	}
+
+	// ref is either a branch or a tag.
+	ref struct {
+		Name   string `json:"name"`
+		Type   string `json:"type"`
+		Target commit `json:"target"`
+		Links  links  `json:"links"`
+	}
+
+	branchRef struct {
+		Name string `json:"name"`
+	}
+
+	endpointRef struct {
+		Branch branchRef `json:"branch"`
+		Commit commitRef `json:"commit"`
+	}
+
+	pullRequest struct {
+		ID          int         `json:"id"`
+		Title       string      `json:"title"`
+		State       string      `json:"state"`
+		Author      account     `json:"author"`
+		Source      endpointRef `json:"source"`
+		Destination endpointRef `json:"destination"`
+		MergeCommit *commitRef  `json:"merge_commit"`
+		ClosedBy    *account    `json:"closed_by"`
+		CreatedOn   time.Time   `json:"created_on"`
+		UpdatedOn   time.Time   `json:"updated_on"`
+		Links       links       `json:"links"`
+	}
+
+	commitFile struct {
+		Path string `json:"path"`
+	}
+
+	// diffStat is a file changed by a commit.
+	// Old is nil for an added file and New is nil for a removed file.
+	diffStat struct {
+		Status string      `json:"status"`
+		Old    *commitFile `json:"old"`
+		New    *commitFile `json:"new"`
+	}
+
+	// page is a paginated Bitbucket API response.
+	page struct {
+		Size    int             `json:"size"`
+		Page    int             `json:"page"`
+		PageLen int             `json:"pagelen"`
+		Next    string          `json:"next"`
+		Values  json.RawMessage `json:"values"`
+	}
+)
+
+// responseError is returned when a Bitbucket API call is not successful.
+type responseError struct {
+	method     string
+	url        string
+	statusCode int
+	message    string
+}
+
+func newResponseError(resp *http.Response) *responseError {
+	e := &responseError{
+		method:     resp.Request.Method,
+		url:        resp.Request.URL.String(),
+		statusCode: resp.StatusCode,
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return e
+	}
+
+	body := struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+
+	if err := json.Unmarshal(b, &body); err != nil {
+		e.message = string(b)
+		return e
+	}
+
+	e.message = body.Error.Message
+
+	return e
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s %s %d: %s", e.method, e.url, e.statusCode, e.message)
+}
+
+func pageParams() url.Values {
+	params := url.Values{}
+	params.Set("pagelen", strconv.Itoa(pageLen))
+
+	return params
+}
+
+// setAuth sets the credentials of a request.
+// An access token in the form of username:app-password is sent using the basic authentication.
+// Otherwise, the access token is sent as a bearer token (repository, project, and workspace access tokens).
+func setAuth(req *http.Request, accessToken string) {
+	if accessToken == "" {
+		return
+	}
+
+	if i := strings.Index(accessToken, ":"); i > 0 {
+		req.SetBasicAuth(accessToken[:i], accessToken[i+1:])
+	} else {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+}
+
+// get makes a GET request to a Bitbucket API URL and decodes the response body into out.
+func (r *repo) get(ctx context.Context, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	setAuth(req, r.accessToken)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// call makes a GET request to a Bitbucket API endpoint and decodes the response body into out.
+func (r *repo) call(ctx context.Context, endpoint string, params url.Values, out interface{}) error {
+	u := r.apiURL + endpoint
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	return r.get(ctx, u, out)
+}
+
+// fetchAllPages calls fetch for the values of every page of a paginated Bitbucket API endpoint.
+// Bitbucket pagination is based on opaque next links, so the pages are fetched one after another.
+func (r *repo) fetchAllPages(ctx context.Context, endpoint string, params url.Values, fetch func(json.RawMessage) error) error {
+	pg := page{}
+	if err := r.call(ctx, endpoint, params, &pg); err != nil {
+		return err
+	}
+
+	for {
+		if err := fetch(pg.Values); err != nil {
+			return err
+		}
+
+		if pg.Next == "" {
+			return nil
+		}
+
+		next := pg.Next
+		pg = page{}
+		if err := r.get(ctx, next, &pg); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+// repo implements the remote.Repo interface for Bitbucket Cloud.
+type repo struct {
+	logger      log.Logger
+	client      *http.Client
+	apiURL      string
+	webURL      string
+	workspace   string
+	repo        string
+	accessToken string
+	stores      struct {
+		commits *remote.Store
+	}
+}
+
+// NewRepo creates a new Bitbucket Cloud repository.
+// apiURL and webURL are the base URLs of Bitbucket Cloud (i.e. https://api.bitbucket.org/2.0 and https://bitbucket.org).
+// accessToken is either a repository, project, or workspace access token or a username:app-password pair.
+func NewRepo(logger log.Logger, cache *remote.Cache, apiURL, webURL, workspace, repoSlug, accessToken string) remote.Repo {
+	transport := remote.NewTransport(logger, cache)
+	client := &http.Client{
+		Transport: transport,
+	}
+
+	r := &repo{
+		logger:      logger,
+		client:      client,
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		webURL:      strings.TrimSuffix(webURL, "/"),
+		workspace:   workspace,
+		repo:        repoSlug,
+		accessToken: accessToken,
+	}
+
+	r.stores.commits = remote.NewStore()
+
+	return r
+}
+
+func (r *repo) endpoint(format string, a ...interface{}) string {
+	return fmt.Sprintf("/repositories/%s/%s", url.PathEscape(r.workspace), url.PathEscape(r.repo)) + fmt.Sprintf(format, a...)
+}
+
+func (r *repo) getCommit(ctx context.Context, hash string) (commit, error) {
+	// First, check the cache
+	if v, ok := r.stores.commits.Load(hash); ok {
+		c := v.(commit)
+		return c, nil
+	}
+
+	c := commit{}
+	if err := r.call(ctx, r.endpoint("/commit/%s", hash), nil, &c); err != nil {
+		return commit{}, err
+	}
+
+	// Update the cache
+	// Pull requests refer to their merge commits by abbreviated hashes, so the commit is cached by both hashes.
+	r.stores.commits.Save(c.Hash, c)
+	r.stores.commits.Save(hash, c)
+
+	return c, nil
+}
+
+// FutureTag returns a tag that does not exist yet for a Bitbucket repository.
+func (r *repo) FutureTag(name string) remote.Tag {
+	return remote.Tag{
+		Name:   name,
+		Time:   time.Now(),
+		WebURL: fmt.Sprintf("%s/%s/%s/src/%s", r.webURL, r.workspace, r.repo, name),
+	}
+}
+
+// CompareURL returns a URL for comparing two revisions for a Bitbucket repository.
+func (r *repo) CompareURL(base, head string) string {
+	return fmt.Sprintf("%s/%s/%s/branches/compare/%s%%0D%s#diff", r.webURL, r.workspace, r.repo, head, base)
+}
+
+// CommitURL returns the web URL of a commit for a Bitbucket repository.
+func (r *repo) CommitURL(hash string) string {
+	return fmt.Sprintf("%s/%s/%s/commits/%s", r.webURL, r.workspace, r.repo, hash)
+}
+
+// CheckPermissions ensures the client has all the required permissions for a Bitbucket repository.
+func (r *repo) CheckPermissions(ctx context.Context) error {
+	// Bitbucket access tokens are scoped, so being able to read the repository is all we need to verify.
+	rp := repository{}
+	if err := r.call(ctx, r.endpoint(""), nil, &rp); err != nil {
+		return err
+	}
+
+	r.logger.Debug("Bitbucket repository permissions verified: read")
+
+	return nil
+}
+
+// FetchFirstCommit retrieves the firist/initial commit for a Bitbucket repository.
+func (r *repo) FetchFirstCommit(ctx context.Context) (remote.Commit, error) {
+	r.logger.Debug("Fetching the first Bitbucket commit ...")
+
+	var c commit
+
+	// Bitbucket does not provide any way to jump to the last page, so all commits are walked through.
+	err := r.fetchAllPages(ctx, r.endpoint("/commits"), pageParams(), func(values json.RawMessage) error {
+		commits := []commit{}
+		if err := json.Unmarshal(values, &commits); err != nil {
+			return err
+		}
+
+		// Add commits to commit store
+		for _, c := range commits {
+			r.stores.commits.Save(c.Hash, c)
+		}
+
+		if l := len(commits); l > 0 {
+			c = commits[l-1]
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return remote.Commit{}, err
+	}
+
+	commit := toCommit(c)
+
+	r.logger.Debugf("Fetched the first Bitbucket commit: %s", commit)
+
+	return commit, nil
+}
+
+// FetchBranch retrieves a branch by name for a Bitbucket repository.
+func (r *repo) FetchBranch(ctx context.Context, name string) (remote.Branch, error) {
+	b := ref{}
+	if err := r.call(ctx, r.endpoint("/refs/branches/%s", url.PathEscape(name)), nil, &b); err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(b)
+
+	r.logger.Debugf("Fetched Bitbucket branch: %s", name)
+
+	return branch, nil
+}
+
+// FetchDefaultBranch retrieves the default branch for a Bitbucket repository.
+func (r *repo) FetchDefaultBranch(ctx context.Context) (remote.Branch, error) {
+	rp := repository{}
+	if err := r.call(ctx, r.endpoint(""), nil, &rp); err != nil {
+		return remote.Branch{}, err
+	}
+
+	if rp.MainBranch == nil {
+		return remote.Branch{}, fmt.Errorf("Bitbucket repository has no main branch: %s", rp.FullName)
+	}
+
+	b := ref{}
+	if err := r.call(ctx, r.endpoint("/refs/branches/%s", url.PathEscape(rp.MainBranch.Name)), nil, &b); err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(b)
+
+	r.logger.Debugf("Fetched Bitbucket default branch: %s", b.Name)
+
+	return branch, nil
+}
+
+// FetchTags retrieves all tags for a Bitbucket repository.
+func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
+	r.logger.Debug("Fetching Bitbucket tags ...")
+
+	tagStore := remote.NewStore()
+
+	err := r.fetchAllPages(ctx, r.endpoint("/refs/tags"), pageParams(), func(values json.RawMessage) error {
+		tags := []ref{}
+		if err := json.Unmarshal(values, &tags); err != nil {
+			return err
+		}
+		for _, t := range tags {
+			tagStore.Save(t.Name, t)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags := resolveTags(tagStore, r.webURL, r.workspace, r.repo)
+
+	r.logger.Debugf("Bitbucket tags are fetched: %d", len(tags))
+
+	return tags, nil
+}
+
+// FetchIssuesAndMerges retrieves all merged pull requests for a Bitbucket repository.
+// Bitbucket issues are usually tracked in Jira, so no issue is returned.
+func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
+	if since.IsZero() {
+		r.logger.Info("Fetching Bitbucket pull requests since the beginning ...")
+	} else {
+		r.logger.Infof("Fetching Bitbucket pull requests since %s ...", since.Format(time.RFC3339))
+	}
+
+	// ==============================> FETCH PULL REQUESTS <==============================
+
+	pullStore := remote.NewStore()
+
+	params := pageParams()
+	params.Set("state", "MERGED")
+	params.Set("sort", "-updated_on")
+	if !since.IsZero() {
+		params.Set("q", fmt.Sprintf("updated_on >= %s", since.UTC().Format(time.RFC3339)))
+	}
+
+	err := r.fetchAllPages(ctx, r.endpoint("/pullrequests"), params, func(values json.RawMessage) error {
+		pulls := []pullRequest{}
+		if err := json.Unmarshal(values, &pulls); err != nil {
+			return err
+		}
+		for _, p := range pulls {
+			pullStore.Save(p.ID, p)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.logger.Debugf("Fetched Bitbucket pull requests (%d)", pullStore.Len())
+
+	// ==============================> FETCH MERGE COMMITS <==============================
+
+	r.logger.Debug("Fetching Bitbucket commits for pull requests ...")
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Merge commits are fetched for the full hashes and the actual times of merge
+	_ = pullStore.ForEach(func(_, v interface{}) error {
+		p := v.(pullRequest)
+
+		if p.MergeCommit == nil {
+			return nil
+		}
+
+		g.Go(func() error {
+			_, err := r.getCommit(ctx, p.MergeCommit.Hash)
+			return err
+		})
+
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	// ==============================> JOINING PULL REQUESTS & COMMITS <==============================
+
+	merges := resolveMerges(pullStore, r.stores.commits)
+
+	r.logger.Debugf("Resolved and sorted Bitbucket pull requests (%d)", len(merges))
+	r.logger.Infof("All Bitbucket pull requests (%d) are fetched", len(merges))
+
+	return remote.Issues{}, merges, nil
+}
+
+// FetchParentCommits retrieves all parent commits of a given commit hash for a Bitbucket repository.
+func (r *repo) FetchParentCommits(ctx context.Context, hash string) (remote.Commits, error) {
+	r.logger.Debugf("Fetching all Bitbucket parent commits for %s ...", hash)
+
+	commitStore := remote.NewStore()
+
+	err := r.fetchAllPages(ctx, r.endpoint("/commits/%s", hash), pageParams(), func(values json.RawMessage) error {
+		commits := []commit{}
+		if err := json.Unmarshal(values, &commits); err != nil {
+			return err
+		}
+		for _, c := range commits {
+			commitStore.Save(c.Hash, c)
+			r.stores.commits.Save(c.Hash, c)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	commits := resolveCommits(commitStore)
+
+	r.logger.Debugf("All Bitbucket parent commits for %s are fetched", hash)
+
+	return commits, nil
+}
+
+// FetchCommitFiles retrieves the paths of files changed by a commit for a Bitbucket repository.
+// Renamed files are changed in both their old and new paths.
+func (r *repo) FetchCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	r.logger.Debugf("Fetching Bitbucket files changed by %s ...", hash)
+
+	files := []string{}
+
+	err := r.fetchAllPages(ctx, r.endpoint("/diffstat/%s", hash), pageParams(), func(values json.RawMessage) error {
+		stats := []diffStat{}
+		if err := json.Unmarshal(values, &stats); err != nil {
+			return err
+		}
+		for _, s := range stats {
+			if s.New != nil {
+				files = append(files, s.New.Path)
+			}
+			if s.Old != nil && (s.New == nil || s.Old.Path != s.New.Path) {
+				files = append(files, s.Old.Path)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debugf("Bitbucket files changed by %s are fetched: %d", hash, len(files))
+
+	return files, nil
+}
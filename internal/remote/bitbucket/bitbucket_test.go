@@ -0,0 +1,708 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+const (
+	repoPath = "/repositories/octocat/hello-world"
+	notFound = "404: Resource not found"
+)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name        string
+		logger      log.Logger
+		apiURL      string
+		webURL      string
+		workspace   string
+		repoSlug    string
+		accessToken string
+		expectedAPI string
+		expectedWeb string
+	}{
+		{
+			name:        "OK",
+			logger:      log.New(log.None),
+			apiURL:      "https://api.bitbucket.org/2.0/",
+			webURL:      "https://bitbucket.org/",
+			workspace:   "moorara",
+			repoSlug:    "changelog",
+			accessToken: "bitbucket-access-token",
+			expectedAPI: "https://api.bitbucket.org/2.0",
+			expectedWeb: "https://bitbucket.org",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRepo(tc.logger, nil, tc.apiURL, tc.webURL, tc.workspace, tc.repoSlug, tc.accessToken)
+			assert.NotNil(t, r)
+
+			br, ok := r.(*repo)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.logger, br.logger)
+			assert.NotNil(t, br.client)
+			assert.Equal(t, tc.expectedAPI, br.apiURL)
+			assert.Equal(t, tc.expectedWeb, br.webURL)
+			assert.Equal(t, tc.workspace, br.workspace)
+			assert.Equal(t, tc.repoSlug, br.repo)
+			assert.Equal(t, tc.accessToken, br.accessToken)
+			assert.NotNil(t, br.stores.commits)
+		})
+	}
+}
+
+func TestRepo_getCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		commitsStore   *remote.Store
+		routes         map[string]MockResponse
+		ctx            context.Context
+		hash           string
+		expectedCommit commit
+		expectedError  string
+	}{
+		{
+			name: "CacheHit",
+			commitsStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b578": bitbucketCommit1,
+			}),
+			ctx:            context.Background(),
+			hash:           "6dcb09b5b578",
+			expectedCommit: bitbucketCommit1,
+		},
+		{
+			name:          "Error",
+			commitsStore:  remote.NewStore(),
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "6dcb09b5b578",
+			expectedError: repoPath + "/commit/6dcb09b5b578 " + notFound,
+		},
+		{
+			name:         "Success",
+			commitsStore: remote.NewStore(),
+			routes: map[string]MockResponse{
+				repoPath + "/commit/6dcb09b5b578": {StatusCode: http.StatusOK, Body: bitbucketCommit1},
+			},
+			ctx:            context.Background(),
+			hash:           "6dcb09b5b578",
+			expectedCommit: bitbucketCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:    log.New(log.None),
+				client:    ts.Client(),
+				apiURL:    ts.URL,
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+			r.stores.commits = tc.commitsStore
+
+			commit, err := r.getCommit(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+				_, ok := r.stores.commits.Load(tc.hash)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
+func TestRepo_FutureTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tagName     string
+		expectedTag remote.Tag
+	}{
+		{
+			name:    "OK",
+			tagName: "v0.1.0",
+			expectedTag: remote.Tag{
+				Name:   "v0.1.0",
+				WebURL: "https://bitbucket.org/octocat/hello-world/src/v0.1.0",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:    log.New(log.None),
+				webURL:    "https://bitbucket.org",
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+
+			tag := r.FutureTag(tc.tagName)
+
+			assert.Equal(t, tc.expectedTag.Name, tag.Name)
+			assert.NotZero(t, tag.Time)
+			assert.Equal(t, tc.expectedTag.WebURL, tag.WebURL)
+		})
+	}
+}
+
+func TestRepo_CompareURL(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, head         string
+		expectedCompareURL string
+	}{
+		{
+			name:               "OK",
+			base:               "v0.1.0",
+			head:               "v0.2.0",
+			expectedCompareURL: "https://bitbucket.org/octocat/hello-world/branches/compare/v0.2.0%0Dv0.1.0#diff",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:    log.New(log.None),
+				webURL:    "https://bitbucket.org",
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+
+			url := r.CompareURL(tc.base, tc.head)
+
+			assert.Equal(t, tc.expectedCompareURL, url)
+		})
+	}
+}
+
+func TestRepo_CommitURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		webURL      string
+		expectedURL string
+	}{
+		{
+			name:        "OK",
+			webURL:      "https://bitbucket.org",
+			expectedURL: "https://bitbucket.org/octocat/hello-world/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:    log.New(log.None),
+				webURL:    tc.webURL,
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+
+			url := r.CommitURL("6dcb09b5b57875f334f61aebed695e2e4193db5e")
+
+			assert.Equal(t, tc.expectedURL, url)
+		})
+	}
+}
+
+func TestRepo_CheckPermissions(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + " " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: bitbucketRepository},
+			},
+			ctx:           context.Background(),
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:      log.New(log.None),
+				client:      ts.Client(),
+				apiURL:      ts.URL,
+				workspace:   "octocat",
+				repo:        "hello-world",
+				accessToken: "bitbucket-access-token",
+			}
+
+			err := r.CheckPermissions(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchFirstCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedCommit remote.Commit
+		expectedError  string
+	}{
+		{
+			name:          "FirstPageFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/commits?pagelen=50 " + notFound,
+		},
+		{
+			name: "NextPageFails",
+			routes: map[string]MockResponse{
+				repoPath + "/commits": {StatusCode: http.StatusOK, NextPage: 2, Values: []commit{bitbucketCommit2}},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/commits?page=2 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits":        {StatusCode: http.StatusOK, NextPage: 2, Values: []commit{bitbucketCommit2}},
+				repoPath + "/commits?page=2": {StatusCode: http.StatusOK, Values: []commit{bitbucketCommit1}},
+			},
+			ctx:            context.Background(),
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:    log.New(log.None),
+				client:    ts.Client(),
+				apiURL:    ts.URL,
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commit, err := r.FetchFirstCommit(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		branchName     string
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: repoPath + "/refs/branches/main " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/refs/branches/main": {StatusCode: http.StatusOK, Body: bitbucketBranch},
+			},
+			ctx:            context.Background(),
+			branchName:     "main",
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:    log.New(log.None),
+				client:    ts.Client(),
+				apiURL:    ts.URL,
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+
+			branch, err := r.FetchBranch(tc.ctx, tc.branchName)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "RepositoryFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + " " + notFound,
+		},
+		{
+			name: "NoMainBranch",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: repository{FullName: "octocat/hello-world"}},
+			},
+			ctx:           context.Background(),
+			expectedError: "Bitbucket repository has no main branch: octocat/hello-world",
+		},
+		{
+			name: "BranchFails",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: bitbucketRepository},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/refs/branches/main " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath:                         {StatusCode: http.StatusOK, Body: bitbucketRepository},
+				repoPath + "/refs/branches/main": {StatusCode: http.StatusOK, Body: bitbucketBranch},
+			},
+			ctx:            context.Background(),
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:    log.New(log.None),
+				client:    ts.Client(),
+				apiURL:    ts.URL,
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+
+			branch, err := r.FetchDefaultBranch(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchTags(t *testing.T) {
+	bitbucketTag2 := ref{
+		Name:   "v0.0.1",
+		Type:   "tag",
+		Target: bitbucketCommit1,
+	}
+
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedTags  remote.Tags
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/refs/tags?pagelen=50 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/refs/tags":        {StatusCode: http.StatusOK, NextPage: 2, Values: []ref{bitbucketTag}},
+				repoPath + "/refs/tags?page=2": {StatusCode: http.StatusOK, Values: []ref{bitbucketTag2}},
+			},
+			ctx: context.Background(),
+			expectedTags: remote.Tags{
+				remoteTag,
+				{
+					Name:   "v0.0.1",
+					Time:   remoteCommit1.Time,
+					Commit: remoteCommit1,
+					WebURL: "https://bitbucket.org/octocat/hello-world/src/v0.0.1",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:    log.New(log.None),
+				client:    ts.Client(),
+				apiURL:    ts.URL,
+				webURL:    "https://bitbucket.org",
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+
+			tags, err := r.FetchTags(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, tags)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTags, tags.Sort())
+			}
+		})
+	}
+}
+
+func TestRepo_FetchIssuesAndMerges(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		since          time.Time
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+		expectedError  string
+	}{
+		{
+			name:          "PullsFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: repoPath + "/pullrequests?pagelen=50&sort=-updated_on&state=MERGED " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/pullrequests": {StatusCode: http.StatusOK, Values: []pullRequest{bitbucketPull}},
+			},
+			ctx:           context.Background(),
+			since:         parseBitbucketTime("2020-10-01T00:00:00Z"),
+			expectedError: repoPath + "/commit/6dcb09b5b578 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/pullrequests":        {StatusCode: http.StatusOK, NextPage: 2, Values: []pullRequest{bitbucketPull}},
+				repoPath + "/pullrequests?page=2": {StatusCode: http.StatusOK, Values: []pullRequest{{ID: 1003, State: "MERGED"}}},
+				repoPath + "/commit/6dcb09b5b578": {StatusCode: http.StatusOK, Body: bitbucketCommit1},
+			},
+			ctx:            context.Background(),
+			since:          parseBitbucketTime("2020-10-01T00:00:00Z"),
+			expectedIssues: remote.Issues{},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:    log.New(log.None),
+				client:    ts.Client(),
+				apiURL:    ts.URL,
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			issues, merges, err := r.FetchIssuesAndMerges(tc.ctx, tc.since)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, issues)
+				assert.Nil(t, merges)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIssues, issues)
+				assert.Equal(t, tc.expectedMerges, merges)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchParentCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		routes          map[string]MockResponse
+		ctx             context.Context
+		hash            string
+		expectedCommits remote.Commits
+		expectedError   string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c?pagelen=50 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c":        {StatusCode: http.StatusOK, NextPage: 2, Values: []commit{bitbucketCommit2}},
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c?page=2": {StatusCode: http.StatusOK, Values: []commit{bitbucketCommit1}},
+			},
+			ctx:             context.Background(),
+			hash:            "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:    log.New(log.None),
+				client:    ts.Client(),
+				apiURL:    ts.URL,
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commits, err := r.FetchParentCommits(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, commits)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommits, commits)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchCommitFiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		hash          string
+		expectedFiles []string
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: repoPath + "/diffstat/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c?pagelen=50 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/diffstat/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {
+					StatusCode: http.StatusOK, NextPage: 2,
+					Values: []diffStat{
+						{Status: "modified", Old: &commitFile{Path: "api/server.go"}, New: &commitFile{Path: "api/server.go"}},
+						{Status: "added", New: &commitFile{Path: "api/handler.go"}},
+					},
+				},
+				repoPath + "/diffstat/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c?page=2": {
+					StatusCode: http.StatusOK,
+					Values: []diffStat{
+						{Status: "renamed", Old: &commitFile{Path: "web/index.js"}, New: &commitFile{Path: "web/app.js"}},
+						{Status: "removed", Old: &commitFile{Path: "web/legacy.js"}},
+					},
+				},
+			},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedFiles: []string{"api/server.go", "api/handler.go", "web/app.js", "web/index.js", "web/legacy.js"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:    log.New(log.None),
+				client:    ts.Client(),
+				apiURL:    ts.URL,
+				workspace: "octocat",
+				repo:      "hello-world",
+			}
+
+			files, err := r.FetchCommitFiles(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, files)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFiles, files)
+			}
+		})
+	}
+}
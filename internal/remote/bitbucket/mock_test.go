@@ -0,0 +1,222 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+var (
+	bitbucketUser1 = account{
+		UUID:        "{a1b2c3d4-0000-0000-0000-000000000001}",
+		AccountID:   "557058:00000000-0000-0000-0000-000000000001",
+		DisplayName: "The Octocat",
+		Nickname:    "octocat",
+		Links:       links{HTML: link{Href: "https://bitbucket.org/%7Ba1b2c3d4-0000-0000-0000-000000000001%7D/"}},
+	}
+
+	bitbucketUser2 = account{
+		UUID:        "{a1b2c3d4-0000-0000-0000-000000000002}",
+		AccountID:   "557058:00000000-0000-0000-0000-000000000002",
+		DisplayName: "The Octodog",
+		Nickname:    "octodog",
+		Links:       links{HTML: link{Href: "https://bitbucket.org/%7Ba1b2c3d4-0000-0000-0000-000000000002%7D/"}},
+	}
+
+	bitbucketUser3 = account{
+		UUID:        "{a1b2c3d4-0000-0000-0000-000000000003}",
+		AccountID:   "557058:00000000-0000-0000-0000-000000000003",
+		DisplayName: "The Octofox",
+		Nickname:    "octofox",
+		Links:       links{HTML: link{Href: "https://bitbucket.org/%7Ba1b2c3d4-0000-0000-0000-000000000003%7D/"}},
+	}
+
+	bitbucketRepository = repository{
+		UUID:     "{b1b2c3d4-0000-0000-0000-000000000000}",
+		Name:     "Hello-World",
+		FullName: "octocat/hello-world",
+		MainBranch: &mainBranch{
+			Name: "main",
+			Type: "branch",
+		},
+		Links: links{HTML: link{Href: "https://bitbucket.org/octocat/hello-world"}},
+	}
+
+	bitbucketCommit1 = commit{
+		Hash:    "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Date:    parseBitbucketTime("2020-10-20T19:59:59Z"),
+		Message: "Fix all the bugs",
+		Author: author{
+			Raw:  "The Octocat <octocat@example.com>",
+			User: &bitbucketUser1,
+		},
+		Parents: []commitRef{},
+		Links:   links{HTML: link{Href: "https://bitbucket.org/octocat/hello-world/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e"}},
+	}
+
+	bitbucketCommit2 = commit{
+		Hash:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Date:    parseBitbucketTime("2020-10-27T23:59:59Z"),
+		Message: "Release v0.1.0",
+		Author: author{
+			Raw:  "The Octocat <octocat@example.com>",
+			User: &bitbucketUser1,
+		},
+		Parents: []commitRef{
+			{Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		},
+		Links: links{HTML: link{Href: "https://bitbucket.org/octocat/hello-world/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c"}},
+	}
+
+	bitbucketBranch = ref{
+		Name:   "main",
+		Type:   "branch",
+		Target: bitbucketCommit2,
+		Links:  links{HTML: link{Href: "https://bitbucket.org/octocat/hello-world/branch/main"}},
+	}
+
+	bitbucketTag = ref{
+		Name:   "v0.1.0",
+		Type:   "tag",
+		Target: bitbucketCommit2,
+		Links:  links{HTML: link{Href: "https://bitbucket.org/octocat/hello-world/commits/tag/v0.1.0"}},
+	}
+
+	bitbucketPull = pullRequest{
+		ID:     1002,
+		Title:  "Fixed a bug",
+		State:  "MERGED",
+		Author: bitbucketUser2,
+		Source: endpointRef{
+			Branch: branchRef{Name: "bugfix"},
+			Commit: commitRef{Hash: "8e5d3a4bbf7a"},
+		},
+		Destination: endpointRef{
+			Branch: branchRef{Name: "main"},
+			Commit: commitRef{Hash: "25aa2bdbaf10"},
+		},
+		MergeCommit: &commitRef{Hash: "6dcb09b5b578"},
+		ClosedBy:    &bitbucketUser3,
+		CreatedOn:   parseBitbucketTime("2020-10-15T15:00:00Z"),
+		UpdatedOn:   parseBitbucketTime("2020-10-20T20:00:00Z"),
+		Links:       links{HTML: link{Href: "https://bitbucket.org/octocat/hello-world/pull-requests/1002"}},
+	}
+
+	remoteUser1 = remote.User{
+		Name:     "The Octocat",
+		Username: "octocat",
+		WebURL:   "https://bitbucket.org/%7Ba1b2c3d4-0000-0000-0000-000000000001%7D/",
+	}
+
+	remoteUser2 = remote.User{
+		Name:     "The Octodog",
+		Username: "octodog",
+		WebURL:   "https://bitbucket.org/%7Ba1b2c3d4-0000-0000-0000-000000000002%7D/",
+	}
+
+	remoteUser3 = remote.User{
+		Name:     "The Octofox",
+		Username: "octofox",
+		WebURL:   "https://bitbucket.org/%7Ba1b2c3d4-0000-0000-0000-000000000003%7D/",
+	}
+
+	remoteCommit1 = remote.Commit{
+		Hash:    "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time:    parseBitbucketTime("2020-10-20T19:59:59Z"),
+		Message: "Fix all the bugs",
+	}
+
+	remoteCommit2 = remote.Commit{
+		Hash:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Time:    parseBitbucketTime("2020-10-27T23:59:59Z"),
+		Parents: []string{"6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		Message: "Release v0.1.0",
+	}
+
+	remoteBranch = remote.Branch{
+		Name:   "main",
+		Commit: remoteCommit2,
+	}
+
+	remoteTag = remote.Tag{
+		Name:   "v0.1.0",
+		Time:   parseBitbucketTime("2020-10-27T23:59:59Z"),
+		Commit: remoteCommit2,
+		WebURL: "https://bitbucket.org/octocat/hello-world/src/v0.1.0",
+	}
+
+	remoteMerge = remote.Merge{
+		Change: remote.Change{
+			Number: 1002,
+			Title:  "Fixed a bug",
+			Labels: []string{},
+			Time:   parseBitbucketTime("2020-10-20T19:59:59Z"),
+			Author: remoteUser2,
+			WebURL: "https://bitbucket.org/octocat/hello-world/pull-requests/1002",
+		},
+		Merger: remoteUser3,
+		Commit: remoteCommit1,
+	}
+)
+
+func parseBitbucketTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// MockResponse is a canned response of the fake Bitbucket API server.
+// If Values is set, the response is a page of values with a next link to NextPage (if any).
+// Otherwise, Body is the response.
+type MockResponse struct {
+	StatusCode int
+	NextPage   int
+	Values     interface{}
+	Body       interface{}
+}
+
+// newMockServer creates a fake Bitbucket API server.
+// The routes are keyed by the request path and, for subsequent pages, the page number (i.e. /repositories/octocat/hello-world/refs/tags?page=2).
+// Requests to unknown routes receive a 404 Not Found response.
+func newMockServer(routes map[string]MockResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if page := r.URL.Query().Get("page"); page != "" {
+			key += "?page=" + page
+		}
+
+		resp, ok := routes[key]
+		if !ok {
+			resp = MockResponse{
+				StatusCode: http.StatusNotFound,
+				Body: map[string]interface{}{
+					"type":  "error",
+					"error": map[string]string{"message": "Resource not found"},
+				},
+			}
+		}
+
+		body := resp.Body
+		if resp.Values != nil {
+			pg := map[string]interface{}{
+				"pagelen": pageLen,
+				"values":  resp.Values,
+			}
+			if resp.NextPage > 0 {
+				pg["next"] = fmt.Sprintf("http://%s%s?page=%d", r.Host, r.URL.Path, resp.NextPage)
+			}
+			body = pg
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+}
@@ -0,0 +1,120 @@
+package bitbucket
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func toUser(a account) remote.User {
+	return remote.User{
+		Name:     a.DisplayName,
+		Username: a.Nickname,
+		WebURL:   a.Links.HTML.Href,
+	}
+}
+
+func toCommit(c commit) remote.Commit {
+	var parents []string
+	for _, p := range c.Parents {
+		parents = append(parents, p.Hash)
+	}
+
+	return remote.Commit{
+		Hash:    c.Hash,
+		Time:    c.Date,
+		Parents: parents,
+		Message: c.Message,
+	}
+}
+
+func toBranch(b ref) remote.Branch {
+	return remote.Branch{
+		Name:   b.Name,
+		Commit: toCommit(b.Target),
+	}
+}
+
+func toTag(t ref, webURL, workspace, repo string) remote.Tag {
+	return remote.Tag{
+		Name:   t.Name,
+		Time:   t.Target.Date,
+		Commit: toCommit(t.Target),
+		WebURL: fmt.Sprintf("%s/%s/%s/src/%s", webURL, workspace, repo, t.Name),
+	}
+}
+
+func toMerge(p pullRequest, c commit, merger account) remote.Merge {
+	// p.UpdatedOn is the last time the pull request was updated on Bitbucket
+	// c.Date is the actual time of merge
+	time := c.Date
+
+	return remote.Merge{
+		Change: remote.Change{
+			Number: p.ID,
+			Title:  p.Title,
+			// Bitbucket pull requests do not have labels
+			Labels: []string{},
+			Time:   time,
+			Author: toUser(p.Author),
+			WebURL: p.Links.HTML.Href,
+		},
+		Merger: toUser(merger),
+		Commit: toCommit(c),
+	}
+}
+
+func resolveTags(bitbucketTags *remote.Store, webURL, workspace, repo string) remote.Tags {
+	tags := remote.Tags{}
+
+	_ = bitbucketTags.ForEach(func(k, v interface{}) error {
+		t := v.(ref)
+		tags = append(tags, toTag(t, webURL, workspace, repo))
+		return nil
+	})
+
+	return tags
+}
+
+func resolveCommits(bitbucketCommits *remote.Store) remote.Commits {
+	commits := remote.Commits{}
+
+	_ = bitbucketCommits.ForEach(func(k, v interface{}) error {
+		c := v.(commit)
+		commits = append(commits, toCommit(c))
+		return nil
+	})
+
+	// The order of the commits should be from the most recent to the least recent
+	sort.Slice(commits, func(i, j int) bool {
+		if commits[i].Time.Equal(commits[j].Time) {
+			return commits[i].Hash < commits[j].Hash
+		}
+		return commits[i].Time.After(commits[j].Time)
+	})
+
+	return commits
+}
+
+func resolveMerges(bitbucketPulls, bitbucketCommits *remote.Store) remote.Merges {
+	merges := remote.Merges{}
+
+	_ = bitbucketPulls.ForEach(func(k, v interface{}) error {
+		p := v.(pullRequest)
+
+		// If the merger or the merge commit is not known, the pull request is skipped
+		if p.ClosedBy != nil && p.MergeCommit != nil {
+			if v, ok := bitbucketCommits.Load(p.MergeCommit.Hash); ok {
+				c := v.(commit)
+				merges = append(merges, toMerge(p, c, *p.ClosedBy))
+			}
+		}
+
+		return nil
+	})
+
+	merges = merges.Sort()
+
+	return merges
+}
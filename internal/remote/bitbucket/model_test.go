@@ -0,0 +1,212 @@
+package bitbucket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func TestToUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		a            account
+		expectedUser remote.User
+	}{
+		{
+			name:         "OK",
+			a:            bitbucketUser1,
+			expectedUser: remoteUser1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := toUser(tc.a)
+			assert.Equal(t, tc.expectedUser, user)
+		})
+	}
+}
+
+func TestToCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		c              commit
+		expectedCommit remote.Commit
+	}{
+		{
+			name:           "OK",
+			c:              bitbucketCommit1,
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commit := toCommit(tc.c)
+			assert.Equal(t, tc.expectedCommit, commit)
+		})
+	}
+}
+
+func TestToBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		b              ref
+		expectedBranch remote.Branch
+	}{
+		{
+			name:           "OK",
+			b:              bitbucketBranch,
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			branch := toBranch(tc.b)
+			assert.Equal(t, tc.expectedBranch, branch)
+		})
+	}
+}
+
+func TestToTag(t *testing.T) {
+	tests := []struct {
+		name            string
+		t               ref
+		webURL          string
+		workspace, repo string
+		expectedTag     remote.Tag
+	}{
+		{
+			name:        "OK",
+			t:           bitbucketTag,
+			webURL:      "https://bitbucket.org",
+			workspace:   "octocat",
+			repo:        "hello-world",
+			expectedTag: remoteTag,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tag := toTag(tc.t, tc.webURL, tc.workspace, tc.repo)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestToMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		p             pullRequest
+		c             commit
+		merger        account
+		expectedMerge remote.Merge
+	}{
+		{
+			name:          "OK",
+			p:             bitbucketPull,
+			c:             bitbucketCommit1,
+			merger:        bitbucketUser3,
+			expectedMerge: remoteMerge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merge := toMerge(tc.p, tc.c, tc.merger)
+			assert.Equal(t, tc.expectedMerge, merge)
+		})
+	}
+}
+
+func TestResolveTags(t *testing.T) {
+	tests := []struct {
+		name            string
+		bitbucketTags   *remote.Store
+		webURL          string
+		workspace, repo string
+		expectedTags    remote.Tags
+	}{
+		{
+			name: "OK",
+			bitbucketTags: remote.NewStoreFrom(map[interface{}]interface{}{
+				"v0.1.0": bitbucketTag,
+			}),
+			webURL:       "https://bitbucket.org",
+			workspace:    "octocat",
+			repo:         "hello-world",
+			expectedTags: remote.Tags{remoteTag},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tags := resolveTags(tc.bitbucketTags, tc.webURL, tc.workspace, tc.repo)
+			assert.Equal(t, tc.expectedTags, tags)
+		})
+	}
+}
+
+func TestResolveCommits(t *testing.T) {
+	tests := []struct {
+		name             string
+		bitbucketCommits *remote.Store
+		expectedCommits  remote.Commits
+	}{
+		{
+			name: "OK",
+			bitbucketCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": bitbucketCommit1,
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": bitbucketCommit2,
+			}),
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commits := resolveCommits(tc.bitbucketCommits)
+			assert.Equal(t, tc.expectedCommits, commits)
+		})
+	}
+}
+
+func TestResolveMerges(t *testing.T) {
+	tests := []struct {
+		name             string
+		bitbucketPulls   *remote.Store
+		bitbucketCommits *remote.Store
+		expectedMerges   remote.Merges
+	}{
+		{
+			name: "OK",
+			bitbucketPulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: bitbucketPull,
+			}),
+			bitbucketCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b578": bitbucketCommit1,
+			}),
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+		{
+			name: "UnknownMergerAndMergeCommit",
+			bitbucketPulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: bitbucketPull,
+				1003: pullRequest{ID: 1003, MergeCommit: &commitRef{Hash: "6dcb09b5b578"}},
+				1004: pullRequest{ID: 1004, ClosedBy: &bitbucketUser3},
+			}),
+			bitbucketCommits: remote.NewStore(),
+			expectedMerges:   remote.Merges{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merges := resolveMerges(tc.bitbucketPulls, tc.bitbucketCommits)
+			assert.Equal(t, tc.expectedMerges, merges)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package bitbucketserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	userAgent = "moorara/changelog"
+	pageLimit = 100
+)
+
+// Pull request activity actions of interest.
+const (
+	activityMerged = "MERGED"
+)
+
+type (
+	link struct {
+		Href string `json:"href"`
+	}
+
+	links struct {
+		Self []link `json:"self"`
+	}
+
+	user struct {
+		ID           int    `json:"id"`
+		Name         string `json:"name"`
+		EmailAddress string `json:"emailAddress"`
+		DisplayName  string `json:"displayName"`
+		Slug         string `json:"slug"`
+		Type         string `json:"type"`
+		Links        links  `json:"links"`
+	}
+
+	project struct {
+		ID   int    `json:"id"`
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	}
+
+	repository struct {
+		ID      int     `json:"id"`
+		Slug    string  `json:"slug"`
+		Name    string  `json:"name"`
+		Project project `json:"project"`
+		Links   links   `json:"links"`
+	}
+
+	commitRef struct {
+		ID        string `json:"id"`
+		DisplayID string `json:"displayId"`
+	}
+
+	commit struct {
+		ID                 string      `json:"id"`
+		DisplayID          string      `json:"displayId"`
+		Message            string      `json:"message"`
+		Author             user        `json:"author"`
+		AuthorTimestamp    int64       `json:"authorTimestamp"`
+		Committer          user        `json:"committer"`
+		CommitterTimestamp int64       `json:"committerTimestamp"`
+		Parents            []commitRef `json:"parents"`
+	}
+
+	branch struct {
+		ID           string `json:"id"`
+		DisplayID    string `json:"displayId"`
+		Type         string `json:"type"`
+		LatestCommit string `json:"latestCommit"`
+		IsDefault    bool   `json:"isDefault"`
+	}
+
+	tag struct {
+		ID           string `json:"id"`
+		DisplayID    string `json:"displayId"`
+		Type         string `json:"type"`
+		LatestCommit string `json:"latestCommit"`
+		Hash         string `json:"hash"`
+	}
+
+	participant struct {
+		User user   `json:"user"`
+		Role string `json:"role"`
+	}
+
+	pullRequest struct {
+		ID          int         `json:"id"`
+		Title       string      `json:"title"`
+		State       string      `json:"state"`
+		Author      participant `json:"author"`
+		CreatedDate int64       `json:"createdDate"`
+		UpdatedDate int64       `json:"updatedDate"`
+		ClosedDate  int64       `json:"closedDate"`
+		Links       links       `json:"links"`
+	}
+
+	activity struct {
+		ID          int        `json:"id"`
+		Action      string     `json:"action"`
+		User        user       `json:"user"`
+		CreatedDate int64      `json:"createdDate"`
+		Commit      *commitRef `json:"commit"`
+	}
+
+	path struct {
+		ToString string `json:"toString"`
+	}
+
+	// change is a file changed by a commit.
+	// SrcPath is only set for a moved or copied file.
+	change struct {
+		Type    string `json:"type"`
+		Path    path   `json:"path"`
+		SrcPath *path  `json:"srcPath"`
+	}
+
+	// page is a paginated Bitbucket Server API response.
+	page struct {
+		Size          int             `json:"size"`
+		Limit         int             `json:"limit"`
+		Start         int             `json:"start"`
+		IsLastPage    bool            `json:"isLastPage"`
+		NextPageStart int             `json:"nextPageStart"`
+		Values        json.RawMessage `json:"values"`
+	}
+)
+
+func (l links) href() string {
+	if len(l.Self) > 0 {
+		return l.Self[0].Href
+	}
+	return ""
+}
+
+// toTime converts a Bitbucket Server timestamp in milliseconds to a time.
+func toTime(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC()
+}
+
+// responseError is returned when a Bitbucket Server API call is not successful.
+type responseError struct {
+	method     string
+	url        string
+	statusCode int
+	message    string
+}
+
+func newResponseError(resp *http.Response) *responseError {
+	e := &responseError{
+		method:     resp.Request.Method,
+		url:        resp.Request.URL.String(),
+		statusCode: resp.StatusCode,
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return e
+	}
+
+	body := struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+
+	if err := json.Unmarshal(b, &body); err != nil {
+		e.message = string(b)
+		return e
+	}
+
+	messages := make([]string, len(body.Errors))
+	for i, err := range body.Errors {
+		messages[i] = err.Message
+	}
+
+	e.message = strings.Join(messages, ", ")
+
+	return e
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s %s %d: %s", e.method, e.url, e.statusCode, e.message)
+}
+
+func pageParams(start int) url.Values {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(pageLimit))
+	params.Set("start", strconv.Itoa(start))
+
+	return params
+}
+
+// setAuth sets the credentials of a request.
+// An access token in the form of username:password is sent using the basic authentication.
+// Otherwise, the access token is sent as a bearer token (HTTP access tokens and personal access tokens).
+func setAuth(req *http.Request, accessToken string) {
+	if accessToken == "" {
+		return
+	}
+
+	if i := strings.Index(accessToken, ":"); i > 0 {
+		req.SetBasicAuth(accessToken[:i], accessToken[i+1:])
+	} else {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+}
+
+// call makes a GET request to a Bitbucket Server API endpoint and decodes the response body into out.
+func (r *repo) call(ctx context.Context, endpoint string, params url.Values, out interface{}) error {
+	u := r.apiURL + endpoint
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	setAuth(req, r.accessToken)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetchAllPages calls fetch for the values of every page of a paginated Bitbucket Server API endpoint.
+// fetch returns false for stopping the pagination early.
+// Bitbucket Server pagination is based on the start of the next page, so the pages are fetched one after another.
+func (r *repo) fetchAllPages(ctx context.Context, endpoint string, params url.Values, fetch func(json.RawMessage) (bool, error)) error {
+	if params == nil {
+		params = url.Values{}
+	}
+
+	for start := 0; ; {
+		for k, v := range pageParams(start) {
+			params[k] = v
+		}
+
+		pg := page{}
+		if err := r.call(ctx, endpoint, params, &pg); err != nil {
+			return err
+		}
+
+		more, err := fetch(pg.Values)
+		if err != nil {
+			return err
+		}
+
+		if !more || pg.IsLastPage {
+			return nil
+		}
+
+		start = pg.NextPageStart
+	}
+}
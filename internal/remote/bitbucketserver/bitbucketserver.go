@@ -0,0 +1,431 @@
+package bitbucketserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+// repo implements the remote.Repo interface for Bitbucket Server and Bitbucket Data Center.
+type repo struct {
+	logger      log.Logger
+	client      *http.Client
+	apiURL      string
+	webURL      string
+	project     string
+	repo        string
+	accessToken string
+	stores      struct {
+		commits *remote.Store
+	}
+}
+
+// NewRepo creates a new Bitbucket Server repository.
+// apiURL and webURL are the base URLs of the Bitbucket Server instance (i.e. https://bitbucket.example.com/rest/api/1.0 and https://bitbucket.example.com).
+func NewRepo(logger log.Logger, cache *remote.Cache, apiURL, webURL, projectKey, repoSlug, accessToken string) remote.Repo {
+	transport := remote.NewTransport(logger, cache)
+	client := &http.Client{
+		Transport: transport,
+	}
+
+	r := &repo{
+		logger:      logger,
+		client:      client,
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		webURL:      strings.TrimSuffix(webURL, "/"),
+		project:     projectKey,
+		repo:        repoSlug,
+		accessToken: accessToken,
+	}
+
+	r.stores.commits = remote.NewStore()
+
+	return r
+}
+
+func (r *repo) endpoint(format string, a ...interface{}) string {
+	return fmt.Sprintf("/projects/%s/repos/%s", url.PathEscape(r.project), url.PathEscape(r.repo)) + fmt.Sprintf(format, a...)
+}
+
+func (r *repo) getCommit(ctx context.Context, id string) (commit, error) {
+	// First, check the cache
+	if v, ok := r.stores.commits.Load(id); ok {
+		c := v.(commit)
+		return c, nil
+	}
+
+	c := commit{}
+	if err := r.call(ctx, r.endpoint("/commits/%s", id), nil, &c); err != nil {
+		return commit{}, err
+	}
+
+	// Update the cache
+	r.stores.commits.Save(c.ID, c)
+
+	return c, nil
+}
+
+func (r *repo) findActivity(ctx context.Context, id int, action string) (activity, error) {
+	var a activity
+
+	err := r.fetchAllPages(ctx, r.endpoint("/pull-requests/%d/activities", id), nil, func(values json.RawMessage) (bool, error) {
+		activities := []activity{}
+		if err := json.Unmarshal(values, &activities); err != nil {
+			return false, err
+		}
+
+		for _, v := range activities {
+			if v.Action == action {
+				r.logger.Debugf("Found %s activity for pull request %d", action, id)
+				a = v
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+
+	if err != nil {
+		return activity{}, err
+	}
+
+	return a, nil
+}
+
+// FutureTag returns a tag that does not exist yet for a Bitbucket Server repository.
+func (r *repo) FutureTag(name string) remote.Tag {
+	return remote.Tag{
+		Name:   name,
+		Time:   time.Now(),
+		WebURL: fmt.Sprintf("%s/projects/%s/repos/%s/browse?at=refs/tags/%s", r.webURL, r.project, r.repo, name),
+	}
+}
+
+// CompareURL returns a URL for comparing two revisions for a Bitbucket Server repository.
+func (r *repo) CompareURL(base, head string) string {
+	return fmt.Sprintf("%s/projects/%s/repos/%s/compare/commits?sourceBranch=%s&targetBranch=%s", r.webURL, r.project, r.repo, head, base)
+}
+
+// CommitURL returns the web URL of a commit for a Bitbucket Server repository.
+func (r *repo) CommitURL(hash string) string {
+	return fmt.Sprintf("%s/projects/%s/repos/%s/commits/%s", r.webURL, r.project, r.repo, hash)
+}
+
+// CheckPermissions ensures the client has all the required permissions for a Bitbucket Server repository.
+func (r *repo) CheckPermissions(ctx context.Context) error {
+	// Being able to read the repository is all we need to verify.
+	rp := repository{}
+	if err := r.call(ctx, r.endpoint(""), nil, &rp); err != nil {
+		return err
+	}
+
+	r.logger.Debug("Bitbucket Server repository permissions verified: REPO_READ")
+
+	return nil
+}
+
+// FetchFirstCommit retrieves the firist/initial commit for a Bitbucket Server repository.
+func (r *repo) FetchFirstCommit(ctx context.Context) (remote.Commit, error) {
+	r.logger.Debug("Fetching the first Bitbucket Server commit ...")
+
+	var c commit
+
+	// Bitbucket Server does not provide any way to jump to the last page, so all commits are walked through.
+	err := r.fetchAllPages(ctx, r.endpoint("/commits"), nil, func(values json.RawMessage) (bool, error) {
+		commits := []commit{}
+		if err := json.Unmarshal(values, &commits); err != nil {
+			return false, err
+		}
+
+		// Add commits to commit store
+		for _, c := range commits {
+			r.stores.commits.Save(c.ID, c)
+		}
+
+		if l := len(commits); l > 0 {
+			c = commits[l-1]
+		}
+
+		return true, nil
+	})
+
+	if err != nil {
+		return remote.Commit{}, err
+	}
+
+	commit := toCommit(c)
+
+	r.logger.Debugf("Fetched the first Bitbucket Server commit: %s", commit)
+
+	return commit, nil
+}
+
+// FetchBranch retrieves a branch by name for a Bitbucket Server repository.
+func (r *repo) FetchBranch(ctx context.Context, name string) (remote.Branch, error) {
+	var b branch
+
+	params := url.Values{}
+	params.Set("filterText", name)
+
+	err := r.fetchAllPages(ctx, r.endpoint("/branches"), params, func(values json.RawMessage) (bool, error) {
+		branches := []branch{}
+		if err := json.Unmarshal(values, &branches); err != nil {
+			return false, err
+		}
+
+		// The filter text matches branch names partially
+		for _, v := range branches {
+			if v.DisplayID == name {
+				b = v
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	if b.ID == "" {
+		return remote.Branch{}, fmt.Errorf("Bitbucket Server branch not found: %s", name)
+	}
+
+	// The branch only has the latest commit id, so the commit is fetched for the committer time
+	c, err := r.getCommit(ctx, b.LatestCommit)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(b, c)
+
+	r.logger.Debugf("Fetched Bitbucket Server branch: %s", name)
+
+	return branch, nil
+}
+
+// FetchDefaultBranch retrieves the default branch for a Bitbucket Server repository.
+func (r *repo) FetchDefaultBranch(ctx context.Context) (remote.Branch, error) {
+	b := branch{}
+	if err := r.call(ctx, r.endpoint("/branches/default"), nil, &b); err != nil {
+		return remote.Branch{}, err
+	}
+
+	c, err := r.getCommit(ctx, b.LatestCommit)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(b, c)
+
+	r.logger.Debugf("Fetched Bitbucket Server default branch: %s", b.DisplayID)
+
+	return branch, nil
+}
+
+// FetchTags retrieves all tags for a Bitbucket Server repository.
+func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
+	r.logger.Debug("Fetching Bitbucket Server tags ...")
+
+	// ==============================> FETCH TAGS <==============================
+
+	tagStore := remote.NewStore()
+
+	err := r.fetchAllPages(ctx, r.endpoint("/tags"), nil, func(values json.RawMessage) (bool, error) {
+		tags := []tag{}
+		if err := json.Unmarshal(values, &tags); err != nil {
+			return false, err
+		}
+		for _, t := range tags {
+			tagStore.Save(t.DisplayID, t)
+		}
+		return true, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// ==============================> FETCH TAG COMMITS <==============================
+
+	r.logger.Debug("Fetching Bitbucket Server commits for tags ...")
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Tags only have the latest commit ids, so the commits are fetched for the committer times
+	_ = tagStore.ForEach(func(_, v interface{}) error {
+		g.Go(func() error {
+			t := v.(tag)
+			_, err := r.getCommit(ctx, t.LatestCommit)
+			return err
+		})
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// ==============================> JOINING TAGS & COMMITS <==============================
+
+	tags := resolveTags(tagStore, r.stores.commits, r.webURL, r.project, r.repo)
+
+	r.logger.Debugf("Bitbucket Server tags are fetched: %d", len(tags))
+
+	return tags, nil
+}
+
+// FetchIssuesAndMerges retrieves all merged pull requests for a Bitbucket Server repository.
+// Bitbucket Server does not have an issue tracker, so no issue is returned.
+func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
+	if since.IsZero() {
+		r.logger.Info("Fetching Bitbucket Server pull requests since the beginning ...")
+	} else {
+		r.logger.Infof("Fetching Bitbucket Server pull requests since %s ...", since.Format(time.RFC3339))
+	}
+
+	// ==============================> FETCH PULL REQUESTS <==============================
+
+	pullStore := remote.NewStore()
+
+	params := url.Values{}
+	params.Set("state", "MERGED")
+	params.Set("order", "NEWEST")
+
+	// The pull-requests endpoint does not support filtering by time, so pull requests are filtered by their closed time.
+	err := r.fetchAllPages(ctx, r.endpoint("/pull-requests"), params, func(values json.RawMessage) (bool, error) {
+		pulls := []pullRequest{}
+		if err := json.Unmarshal(values, &pulls); err != nil {
+			return false, err
+		}
+		for _, p := range pulls {
+			if since.IsZero() || !toTime(p.ClosedDate).Before(since) {
+				pullStore.Save(p.ID, p)
+			}
+		}
+		return true, nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.logger.Debugf("Fetched Bitbucket Server pull requests (%d)", pullStore.Len())
+
+	// ==============================> FETCH ACTIVITIES & COMMITS <==============================
+
+	r.logger.Debug("Fetching Bitbucket Server activities and commits for pull requests ...")
+
+	activityStore := remote.NewStore()
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Search the activities of every pull request for its merger and merge commit
+	_ = pullStore.ForEach(func(k, v interface{}) error {
+		id := k.(int)
+
+		g.Go(func() error {
+			a, err := r.findActivity(ctx, id, activityMerged)
+			if err != nil {
+				return err
+			}
+
+			// If the activity is empty/zero, the desired activity has not been found
+			if a.ID == 0 || a.Commit == nil {
+				return nil
+			}
+
+			activityStore.Save(id, a)
+
+			_, err = r.getCommit(ctx, a.Commit.ID)
+			return err
+		})
+
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	// ==============================> JOINING PULL REQUESTS, ACTIVITIES, & COMMITS <==============================
+
+	merges := resolveMerges(pullStore, activityStore, r.stores.commits)
+
+	r.logger.Debugf("Resolved and sorted Bitbucket Server pull requests (%d)", len(merges))
+	r.logger.Infof("All Bitbucket Server pull requests (%d) are fetched", len(merges))
+
+	return remote.Issues{}, merges, nil
+}
+
+// FetchParentCommits retrieves all parent commits of a given commit hash for a Bitbucket Server repository.
+func (r *repo) FetchParentCommits(ctx context.Context, hash string) (remote.Commits, error) {
+	r.logger.Debugf("Fetching all Bitbucket Server parent commits for %s ...", hash)
+
+	commitStore := remote.NewStore()
+
+	params := url.Values{}
+	params.Set("until", hash)
+
+	err := r.fetchAllPages(ctx, r.endpoint("/commits"), params, func(values json.RawMessage) (bool, error) {
+		commits := []commit{}
+		if err := json.Unmarshal(values, &commits); err != nil {
+			return false, err
+		}
+		for _, c := range commits {
+			commitStore.Save(c.ID, c)
+			r.stores.commits.Save(c.ID, c)
+		}
+		return true, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	commits := resolveCommits(commitStore)
+
+	r.logger.Debugf("All Bitbucket Server parent commits for %s are fetched", hash)
+
+	return commits, nil
+}
+
+// FetchCommitFiles retrieves the paths of files changed by a commit for a Bitbucket Server repository.
+// Moved files are changed in both their old and new paths.
+func (r *repo) FetchCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	r.logger.Debugf("Fetching Bitbucket Server files changed by %s ...", hash)
+
+	files := []string{}
+
+	err := r.fetchAllPages(ctx, r.endpoint("/commits/%s/changes", hash), nil, func(values json.RawMessage) (bool, error) {
+		changes := []change{}
+		if err := json.Unmarshal(values, &changes); err != nil {
+			return false, err
+		}
+		for _, c := range changes {
+			files = append(files, c.Path.ToString)
+			if c.SrcPath != nil && c.SrcPath.ToString != c.Path.ToString {
+				files = append(files, c.SrcPath.ToString)
+			}
+		}
+		return true, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debugf("Bitbucket Server files changed by %s are fetched: %d", hash, len(files))
+
+	return files, nil
+}
@@ -0,0 +1,810 @@
+package bitbucketserver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+const (
+	repoPath = "/projects/OCTO/repos/hello-world"
+	notFound = "404: Resource not found"
+)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name        string
+		logger      log.Logger
+		apiURL      string
+		webURL      string
+		projectKey  string
+		repoSlug    string
+		accessToken string
+		expectedAPI string
+		expectedWeb string
+	}{
+		{
+			name:        "OK",
+			logger:      log.New(log.None),
+			apiURL:      "https://bitbucket.example.com/rest/api/1.0/",
+			webURL:      "https://bitbucket.example.com/",
+			projectKey:  "OCTO",
+			repoSlug:    "hello-world",
+			accessToken: "bitbucket-access-token",
+			expectedAPI: "https://bitbucket.example.com/rest/api/1.0",
+			expectedWeb: "https://bitbucket.example.com",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRepo(tc.logger, nil, tc.apiURL, tc.webURL, tc.projectKey, tc.repoSlug, tc.accessToken)
+			assert.NotNil(t, r)
+
+			br, ok := r.(*repo)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.logger, br.logger)
+			assert.NotNil(t, br.client)
+			assert.Equal(t, tc.expectedAPI, br.apiURL)
+			assert.Equal(t, tc.expectedWeb, br.webURL)
+			assert.Equal(t, tc.projectKey, br.project)
+			assert.Equal(t, tc.repoSlug, br.repo)
+			assert.Equal(t, tc.accessToken, br.accessToken)
+			assert.NotNil(t, br.stores.commits)
+		})
+	}
+}
+
+func TestRepo_getCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		commitsStore   *remote.Store
+		routes         map[string]MockResponse
+		ctx            context.Context
+		id             string
+		expectedCommit commit
+		expectedError  string
+	}{
+		{
+			name: "CacheHit",
+			commitsStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": bitbucketCommit1,
+			}),
+			ctx:            context.Background(),
+			id:             "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: bitbucketCommit1,
+		},
+		{
+			name:          "Error",
+			commitsStore:  remote.NewStore(),
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			id:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedError: repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e " + notFound,
+		},
+		{
+			name:         "Success",
+			commitsStore: remote.NewStore(),
+			routes: map[string]MockResponse{
+				repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: bitbucketCommit1},
+			},
+			ctx:            context.Background(),
+			id:             "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: bitbucketCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+			r.stores.commits = tc.commitsStore
+
+			commit, err := r.getCommit(tc.ctx, tc.id)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+			}
+		})
+	}
+}
+
+func TestRepo_findActivity(t *testing.T) {
+	tests := []struct {
+		name             string
+		routes           map[string]MockResponse
+		ctx              context.Context
+		id               int
+		action           string
+		expectedActivity activity
+		expectedError    string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			id:            1002,
+			action:        activityMerged,
+			expectedError: repoPath + "/pull-requests/1002/activities?limit=100&start=0 " + notFound,
+		},
+		{
+			name: "NotFound",
+			routes: map[string]MockResponse{
+				repoPath + "/pull-requests/1002/activities?start=0": {StatusCode: http.StatusOK, Values: []activity{{ID: 2, Action: "APPROVED"}}},
+			},
+			ctx:              context.Background(),
+			id:               1002,
+			action:           activityMerged,
+			expectedActivity: activity{},
+		},
+		{
+			name: "Found",
+			routes: map[string]MockResponse{
+				repoPath + "/pull-requests/1002/activities?start=0": {StatusCode: http.StatusOK, NextPageStart: 1, Values: []activity{{ID: 2, Action: "APPROVED"}}},
+				repoPath + "/pull-requests/1002/activities?start=1": {StatusCode: http.StatusOK, Values: []activity{bitbucketMergeActivity}},
+			},
+			ctx:              context.Background(),
+			id:               1002,
+			action:           activityMerged,
+			expectedActivity: bitbucketMergeActivity,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+
+			activity, err := r.findActivity(tc.ctx, tc.id, tc.action)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, activity)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedActivity, activity)
+			}
+		})
+	}
+}
+
+func TestRepo_FutureTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tagName     string
+		expectedTag remote.Tag
+	}{
+		{
+			name:    "OK",
+			tagName: "v0.1.0",
+			expectedTag: remote.Tag{
+				Name:   "v0.1.0",
+				WebURL: "https://bitbucket.example.com/projects/OCTO/repos/hello-world/browse?at=refs/tags/v0.1.0",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:  log.New(log.None),
+				webURL:  "https://bitbucket.example.com",
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+
+			tag := r.FutureTag(tc.tagName)
+
+			assert.Equal(t, tc.expectedTag.Name, tag.Name)
+			assert.NotZero(t, tag.Time)
+			assert.Equal(t, tc.expectedTag.WebURL, tag.WebURL)
+		})
+	}
+}
+
+func TestRepo_CompareURL(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, head         string
+		expectedCompareURL string
+	}{
+		{
+			name:               "OK",
+			base:               "v0.1.0",
+			head:               "v0.2.0",
+			expectedCompareURL: "https://bitbucket.example.com/projects/OCTO/repos/hello-world/compare/commits?sourceBranch=v0.2.0&targetBranch=v0.1.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:  log.New(log.None),
+				webURL:  "https://bitbucket.example.com",
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+
+			url := r.CompareURL(tc.base, tc.head)
+
+			assert.Equal(t, tc.expectedCompareURL, url)
+		})
+	}
+}
+
+func TestRepo_CommitURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		webURL      string
+		expectedURL string
+	}{
+		{
+			name:        "OK",
+			webURL:      "https://bitbucket.example.com",
+			expectedURL: "https://bitbucket.example.com/projects/OCTO/repos/hello-world/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:  log.New(log.None),
+				webURL:  tc.webURL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+
+			url := r.CommitURL("6dcb09b5b57875f334f61aebed695e2e4193db5e")
+
+			assert.Equal(t, tc.expectedURL, url)
+		})
+	}
+}
+
+func TestRepo_CheckPermissions(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + " " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: bitbucketRepository},
+			},
+			ctx:           context.Background(),
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:      log.New(log.None),
+				client:      ts.Client(),
+				apiURL:      ts.URL,
+				project:     "OCTO",
+				repo:        "hello-world",
+				accessToken: "bitbucket-access-token",
+			}
+
+			err := r.CheckPermissions(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchFirstCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedCommit remote.Commit
+		expectedError  string
+	}{
+		{
+			name:          "FirstPageFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/commits?limit=100&start=0 " + notFound,
+		},
+		{
+			name: "NextPageFails",
+			routes: map[string]MockResponse{
+				repoPath + "/commits?start=0": {StatusCode: http.StatusOK, NextPageStart: 100, Values: []commit{bitbucketCommit2}},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/commits?limit=100&start=100 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits?start=0":   {StatusCode: http.StatusOK, NextPageStart: 100, Values: []commit{bitbucketCommit2}},
+				repoPath + "/commits?start=100": {StatusCode: http.StatusOK, Values: []commit{bitbucketCommit1}},
+			},
+			ctx:            context.Background(),
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commit, err := r.FetchFirstCommit(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		branchName     string
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "BranchesFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: repoPath + "/branches?filterText=main&limit=100&start=0 " + notFound,
+		},
+		{
+			name: "BranchNotFound",
+			routes: map[string]MockResponse{
+				repoPath + "/branches?start=0": {StatusCode: http.StatusOK, Values: []branch{{ID: "refs/heads/maintenance", DisplayID: "maintenance"}}},
+			},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: "Bitbucket Server branch not found: main",
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/branches?start=0": {StatusCode: http.StatusOK, Values: []branch{bitbucketBranch}},
+			},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/branches?start=0":                                 {StatusCode: http.StatusOK, Values: []branch{{ID: "refs/heads/maintenance", DisplayID: "maintenance"}, bitbucketBranch}},
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: bitbucketCommit2},
+			},
+			ctx:            context.Background(),
+			branchName:     "main",
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			branch, err := r.FetchBranch(tc.ctx, tc.branchName)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "BranchFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/branches/default " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/branches/default": {StatusCode: http.StatusOK, Body: bitbucketBranch},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/branches/default":                                 {StatusCode: http.StatusOK, Body: bitbucketBranch},
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: bitbucketCommit2},
+			},
+			ctx:            context.Background(),
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			branch, err := r.FetchDefaultBranch(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchTags(t *testing.T) {
+	bitbucketTag2 := tag{
+		ID:           "refs/tags/v0.0.1",
+		DisplayID:    "v0.0.1",
+		Type:         "TAG",
+		LatestCommit: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+	}
+
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedTags  remote.Tags
+		expectedError string
+	}{
+		{
+			name:          "TagsFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/tags?limit=100&start=0 " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/tags?start=0": {StatusCode: http.StatusOK, Values: []tag{bitbucketTag}},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/tags?start=0":                                     {StatusCode: http.StatusOK, NextPageStart: 100, Values: []tag{bitbucketTag}},
+				repoPath + "/tags?start=100":                                   {StatusCode: http.StatusOK, Values: []tag{bitbucketTag2}},
+				repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: bitbucketCommit1},
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: bitbucketCommit2},
+			},
+			ctx: context.Background(),
+			expectedTags: remote.Tags{
+				remoteTag,
+				{
+					Name:   "v0.0.1",
+					Time:   remoteCommit1.Time,
+					Commit: remoteCommit1,
+					WebURL: "https://bitbucket.example.com/projects/OCTO/repos/hello-world/browse?at=refs/tags/v0.0.1",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				webURL:  "https://bitbucket.example.com",
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			tags, err := r.FetchTags(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, tags)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTags, tags.Sort())
+			}
+		})
+	}
+}
+
+func TestRepo_FetchIssuesAndMerges(t *testing.T) {
+	// An old pull request that should be filtered out
+	bitbucketPull2 := pullRequest{ID: 1001, State: "MERGED", ClosedDate: 1598918400000}
+
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		since          time.Time
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+		expectedError  string
+	}{
+		{
+			name:          "PullsFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: repoPath + "/pull-requests?limit=100&order=NEWEST&start=0&state=MERGED " + notFound,
+		},
+		{
+			name: "ActivitiesFails",
+			routes: map[string]MockResponse{
+				repoPath + "/pull-requests?start=0": {StatusCode: http.StatusOK, Values: []pullRequest{bitbucketPull}},
+			},
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: repoPath + "/pull-requests/1002/activities?limit=100&start=0 " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/pull-requests?start=0":                 {StatusCode: http.StatusOK, Values: []pullRequest{bitbucketPull}},
+				repoPath + "/pull-requests/1002/activities?start=0": {StatusCode: http.StatusOK, Values: []activity{bitbucketMergeActivity}},
+			},
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/pull-requests?start=0":                            {StatusCode: http.StatusOK, NextPageStart: 100, Values: []pullRequest{bitbucketPull}},
+				repoPath + "/pull-requests?start=100":                          {StatusCode: http.StatusOK, Values: []pullRequest{bitbucketPull2}},
+				repoPath + "/pull-requests/1002/activities?start=0":            {StatusCode: http.StatusOK, Values: []activity{bitbucketMergeActivity}},
+				repoPath + "/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: bitbucketCommit1},
+			},
+			ctx:            context.Background(),
+			since:          parseBitbucketTime("2020-10-01T00:00:00Z"),
+			expectedIssues: remote.Issues{},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			issues, merges, err := r.FetchIssuesAndMerges(tc.ctx, tc.since)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, issues)
+				assert.Nil(t, merges)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIssues, issues)
+				assert.Equal(t, tc.expectedMerges, merges)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchParentCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		routes          map[string]MockResponse
+		ctx             context.Context
+		hash            string
+		expectedCommits remote.Commits
+		expectedError   string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: repoPath + "/commits?limit=100&start=0&until=c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits?start=0":   {StatusCode: http.StatusOK, NextPageStart: 100, Values: []commit{bitbucketCommit2}},
+				repoPath + "/commits?start=100": {StatusCode: http.StatusOK, Values: []commit{bitbucketCommit1}},
+			},
+			ctx:             context.Background(),
+			hash:            "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commits, err := r.FetchParentCommits(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, commits)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommits, commits)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchCommitFiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		hash          string
+		expectedFiles []string
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c/changes?limit=100&start=0 " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c/changes?start=0": {
+					StatusCode: http.StatusOK, NextPageStart: 100,
+					Values: []change{
+						{Type: "MODIFY", Path: path{ToString: "api/server.go"}},
+					},
+				},
+				repoPath + "/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c/changes?start=100": {
+					StatusCode: http.StatusOK,
+					Values: []change{
+						{Type: "MOVE", Path: path{ToString: "web/app.js"}, SrcPath: &path{ToString: "web/index.js"}},
+					},
+				},
+			},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedFiles: []string{"api/server.go", "web/app.js", "web/index.js"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:  log.New(log.None),
+				client:  ts.Client(),
+				apiURL:  ts.URL,
+				project: "OCTO",
+				repo:    "hello-world",
+			}
+
+			files, err := r.FetchCommitFiles(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, files)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFiles, files)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package bitbucketserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+var (
+	bitbucketUser1 = user{
+		ID:           1,
+		Name:         "octocat",
+		EmailAddress: "octocat@example.com",
+		DisplayName:  "The Octocat",
+		Slug:         "octocat",
+		Type:         "NORMAL",
+		Links:        links{Self: []link{{Href: "https://bitbucket.example.com/users/octocat"}}},
+	}
+
+	bitbucketUser2 = user{
+		ID:           2,
+		Name:         "octodog",
+		EmailAddress: "octodog@example.com",
+		DisplayName:  "The Octodog",
+		Slug:         "octodog",
+		Type:         "NORMAL",
+		Links:        links{Self: []link{{Href: "https://bitbucket.example.com/users/octodog"}}},
+	}
+
+	bitbucketUser3 = user{
+		ID:           3,
+		Name:         "octofox",
+		EmailAddress: "octofox@example.com",
+		DisplayName:  "The Octofox",
+		Slug:         "octofox",
+		Type:         "NORMAL",
+		Links:        links{Self: []link{{Href: "https://bitbucket.example.com/users/octofox"}}},
+	}
+
+	bitbucketRepository = repository{
+		ID:   1,
+		Slug: "hello-world",
+		Name: "Hello-World",
+		Project: project{
+			ID:   1,
+			Key:  "OCTO",
+			Name: "Octo",
+		},
+		Links: links{Self: []link{{Href: "https://bitbucket.example.com/projects/OCTO/repos/hello-world/browse"}}},
+	}
+
+	bitbucketCommit1 = commit{
+		ID:                 "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		DisplayID:          "6dcb09b5b57",
+		Message:            "Fix all the bugs",
+		Author:             bitbucketUser1,
+		AuthorTimestamp:    1603223999000,
+		Committer:          bitbucketUser1,
+		CommitterTimestamp: 1603223999000,
+		Parents:            []commitRef{},
+	}
+
+	bitbucketCommit2 = commit{
+		ID:                 "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		DisplayID:          "c3d0be41ecb",
+		Message:            "Release v0.1.0",
+		Author:             bitbucketUser1,
+		AuthorTimestamp:    1603843199000,
+		Committer:          bitbucketUser1,
+		CommitterTimestamp: 1603843199000,
+		Parents: []commitRef{
+			{ID: "6dcb09b5b57875f334f61aebed695e2e4193db5e", DisplayID: "6dcb09b5b57"},
+		},
+	}
+
+	bitbucketBranch = branch{
+		ID:           "refs/heads/main",
+		DisplayID:    "main",
+		Type:         "BRANCH",
+		LatestCommit: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		IsDefault:    true,
+	}
+
+	bitbucketTag = tag{
+		ID:           "refs/tags/v0.1.0",
+		DisplayID:    "v0.1.0",
+		Type:         "TAG",
+		LatestCommit: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Hash:         "a5b5a3b5d2fd3b0c1b2a4e4f2b7d3e0c9f1e2d3c",
+	}
+
+	bitbucketPull = pullRequest{
+		ID:    1002,
+		Title: "Fixed a bug",
+		State: "MERGED",
+		Author: participant{
+			User: bitbucketUser2,
+			Role: "AUTHOR",
+		},
+		CreatedDate: 1602774000000,
+		UpdatedDate: 1603224000000,
+		ClosedDate:  1603224000000,
+		Links:       links{Self: []link{{Href: "https://bitbucket.example.com/projects/OCTO/repos/hello-world/pull-requests/1002"}}},
+	}
+
+	bitbucketMergeActivity = activity{
+		ID:          1,
+		Action:      "MERGED",
+		User:        bitbucketUser3,
+		CreatedDate: 1603224000000,
+		Commit: &commitRef{
+			ID:        "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			DisplayID: "6dcb09b5b57",
+		},
+	}
+
+	remoteUser1 = remote.User{
+		Name:     "The Octocat",
+		Email:    "octocat@example.com",
+		Username: "octocat",
+		WebURL:   "https://bitbucket.example.com/users/octocat",
+	}
+
+	remoteUser2 = remote.User{
+		Name:     "The Octodog",
+		Email:    "octodog@example.com",
+		Username: "octodog",
+		WebURL:   "https://bitbucket.example.com/users/octodog",
+	}
+
+	remoteUser3 = remote.User{
+		Name:     "The Octofox",
+		Email:    "octofox@example.com",
+		Username: "octofox",
+		WebURL:   "https://bitbucket.example.com/users/octofox",
+	}
+
+	remoteCommit1 = remote.Commit{
+		Hash:    "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time:    parseBitbucketTime("2020-10-20T19:59:59Z"),
+		Message: "Fix all the bugs",
+	}
+
+	remoteCommit2 = remote.Commit{
+		Hash:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Time:    parseBitbucketTime("2020-10-27T23:59:59Z"),
+		Parents: []string{"6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		Message: "Release v0.1.0",
+	}
+
+	remoteBranch = remote.Branch{
+		Name:   "main",
+		Commit: remoteCommit2,
+	}
+
+	remoteTag = remote.Tag{
+		Name:   "v0.1.0",
+		Time:   parseBitbucketTime("2020-10-27T23:59:59Z"),
+		Commit: remoteCommit2,
+		WebURL: "https://bitbucket.example.com/projects/OCTO/repos/hello-world/browse?at=refs/tags/v0.1.0",
+	}
+
+	remoteMerge = remote.Merge{
+		Change: remote.Change{
+			Number: 1002,
+			Title:  "Fixed a bug",
+			Labels: []string{},
+			Time:   parseBitbucketTime("2020-10-20T19:59:59Z"),
+			Author: remoteUser2,
+			WebURL: "https://bitbucket.example.com/projects/OCTO/repos/hello-world/pull-requests/1002",
+		},
+		Merger: remoteUser3,
+		Commit: remoteCommit1,
+	}
+)
+
+func parseBitbucketTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// MockResponse is a canned response of the fake Bitbucket Server API server.
+// If Values is set, the response is a page of values which is the last page unless NextPageStart is set.
+// Otherwise, Body is the response.
+type MockResponse struct {
+	StatusCode    int
+	NextPageStart int
+	Values        interface{}
+	Body          interface{}
+}
+
+// newMockServer creates a fake Bitbucket Server API server.
+// The routes are keyed by the request path and, for paginated endpoints, the start of the page (i.e. /projects/OCTO/repos/hello-world/tags?start=100).
+// Requests to unknown routes receive a 404 Not Found response.
+func newMockServer(routes map[string]MockResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if start := r.URL.Query().Get("start"); start != "" {
+			key += "?start=" + start
+		}
+
+		resp, ok := routes[key]
+		if !ok {
+			resp = MockResponse{
+				StatusCode: http.StatusNotFound,
+				Body: map[string]interface{}{
+					"errors": []map[string]string{
+						{"message": "Resource not found"},
+					},
+				},
+			}
+		}
+
+		body := resp.Body
+		if resp.Values != nil {
+			body = map[string]interface{}{
+				"limit":         pageLimit,
+				"isLastPage":    resp.NextPageStart == 0,
+				"nextPageStart": resp.NextPageStart,
+				"values":        resp.Values,
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+}
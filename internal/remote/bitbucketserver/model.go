@@ -0,0 +1,128 @@
+package bitbucketserver
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func toUser(u user) remote.User {
+	return remote.User{
+		Name:     u.DisplayName,
+		Email:    u.EmailAddress,
+		Username: u.Name,
+		WebURL:   u.Links.href(),
+	}
+}
+
+func toCommit(c commit) remote.Commit {
+	var parents []string
+	for _, p := range c.Parents {
+		parents = append(parents, p.ID)
+	}
+
+	return remote.Commit{
+		Hash:    c.ID,
+		Time:    toTime(c.CommitterTimestamp),
+		Parents: parents,
+		Message: c.Message,
+	}
+}
+
+func toBranch(b branch, c commit) remote.Branch {
+	return remote.Branch{
+		Name:   b.DisplayID,
+		Commit: toCommit(c),
+	}
+}
+
+func toTag(t tag, c commit, webURL, project, repo string) remote.Tag {
+	return remote.Tag{
+		Name:   t.DisplayID,
+		Time:   toTime(c.CommitterTimestamp),
+		Commit: toCommit(c),
+		WebURL: fmt.Sprintf("%s/projects/%s/repos/%s/browse?at=refs/tags/%s", webURL, project, repo, t.DisplayID),
+	}
+}
+
+func toMerge(p pullRequest, c commit, merger user) remote.Merge {
+	// p.ClosedDate is the time the pull request was merged on Bitbucket Server
+	// c.CommitterTimestamp is the actual time of merge
+	time := toTime(c.CommitterTimestamp)
+
+	return remote.Merge{
+		Change: remote.Change{
+			Number: p.ID,
+			Title:  p.Title,
+			// Bitbucket Server pull requests do not have labels
+			Labels: []string{},
+			Time:   time,
+			Author: toUser(p.Author.User),
+			WebURL: p.Links.href(),
+		},
+		Merger: toUser(merger),
+		Commit: toCommit(c),
+	}
+}
+
+func resolveTags(bitbucketTags, bitbucketCommits *remote.Store, webURL, project, repo string) remote.Tags {
+	tags := remote.Tags{}
+
+	_ = bitbucketTags.ForEach(func(k, v interface{}) error {
+		t := v.(tag)
+
+		if v, ok := bitbucketCommits.Load(t.LatestCommit); ok {
+			c := v.(commit)
+			tags = append(tags, toTag(t, c, webURL, project, repo))
+		}
+
+		return nil
+	})
+
+	return tags
+}
+
+func resolveCommits(bitbucketCommits *remote.Store) remote.Commits {
+	commits := remote.Commits{}
+
+	_ = bitbucketCommits.ForEach(func(k, v interface{}) error {
+		c := v.(commit)
+		commits = append(commits, toCommit(c))
+		return nil
+	})
+
+	// The order of the commits should be from the most recent to the least recent
+	sort.Slice(commits, func(i, j int) bool {
+		if commits[i].Time.Equal(commits[j].Time) {
+			return commits[i].Hash < commits[j].Hash
+		}
+		return commits[i].Time.After(commits[j].Time)
+	})
+
+	return commits
+}
+
+func resolveMerges(bitbucketPulls, bitbucketActivities, bitbucketCommits *remote.Store) remote.Merges {
+	merges := remote.Merges{}
+
+	_ = bitbucketPulls.ForEach(func(k, v interface{}) error {
+		id := k.(int)
+		p := v.(pullRequest)
+
+		// If the merge activity or the merge commit is not known, the pull request is skipped
+		if v, ok := bitbucketActivities.Load(id); ok {
+			a := v.(activity)
+			if v, ok := bitbucketCommits.Load(a.Commit.ID); ok {
+				c := v.(commit)
+				merges = append(merges, toMerge(p, c, a.User))
+			}
+		}
+
+		return nil
+	})
+
+	merges = merges.Sort()
+
+	return merges
+}
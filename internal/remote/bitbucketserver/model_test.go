@@ -0,0 +1,227 @@
+package bitbucketserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func TestToUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		u            user
+		expectedUser remote.User
+	}{
+		{
+			name:         "OK",
+			u:            bitbucketUser1,
+			expectedUser: remoteUser1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := toUser(tc.u)
+			assert.Equal(t, tc.expectedUser, user)
+		})
+	}
+}
+
+func TestToCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		c              commit
+		expectedCommit remote.Commit
+	}{
+		{
+			name:           "OK",
+			c:              bitbucketCommit1,
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commit := toCommit(tc.c)
+			assert.Equal(t, tc.expectedCommit, commit)
+		})
+	}
+}
+
+func TestToBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		b              branch
+		c              commit
+		expectedBranch remote.Branch
+	}{
+		{
+			name:           "OK",
+			b:              bitbucketBranch,
+			c:              bitbucketCommit2,
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			branch := toBranch(tc.b, tc.c)
+			assert.Equal(t, tc.expectedBranch, branch)
+		})
+	}
+}
+
+func TestToTag(t *testing.T) {
+	tests := []struct {
+		name          string
+		t             tag
+		c             commit
+		webURL        string
+		project, repo string
+		expectedTag   remote.Tag
+	}{
+		{
+			name:        "OK",
+			t:           bitbucketTag,
+			c:           bitbucketCommit2,
+			webURL:      "https://bitbucket.example.com",
+			project:     "OCTO",
+			repo:        "hello-world",
+			expectedTag: remoteTag,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tag := toTag(tc.t, tc.c, tc.webURL, tc.project, tc.repo)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestToMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		p             pullRequest
+		c             commit
+		merger        user
+		expectedMerge remote.Merge
+	}{
+		{
+			name:          "OK",
+			p:             bitbucketPull,
+			c:             bitbucketCommit1,
+			merger:        bitbucketUser3,
+			expectedMerge: remoteMerge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merge := toMerge(tc.p, tc.c, tc.merger)
+			assert.Equal(t, tc.expectedMerge, merge)
+		})
+	}
+}
+
+func TestResolveTags(t *testing.T) {
+	tests := []struct {
+		name             string
+		bitbucketTags    *remote.Store
+		bitbucketCommits *remote.Store
+		webURL           string
+		project, repo    string
+		expectedTags     remote.Tags
+	}{
+		{
+			name: "OK",
+			bitbucketTags: remote.NewStoreFrom(map[interface{}]interface{}{
+				"v0.1.0": bitbucketTag,
+				"v0.0.1": tag{DisplayID: "v0.0.1", LatestCommit: "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378"},
+			}),
+			bitbucketCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": bitbucketCommit2,
+			}),
+			webURL:       "https://bitbucket.example.com",
+			project:      "OCTO",
+			repo:         "hello-world",
+			expectedTags: remote.Tags{remoteTag},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tags := resolveTags(tc.bitbucketTags, tc.bitbucketCommits, tc.webURL, tc.project, tc.repo)
+			assert.Equal(t, tc.expectedTags, tags)
+		})
+	}
+}
+
+func TestResolveCommits(t *testing.T) {
+	tests := []struct {
+		name             string
+		bitbucketCommits *remote.Store
+		expectedCommits  remote.Commits
+	}{
+		{
+			name: "OK",
+			bitbucketCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": bitbucketCommit1,
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": bitbucketCommit2,
+			}),
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commits := resolveCommits(tc.bitbucketCommits)
+			assert.Equal(t, tc.expectedCommits, commits)
+		})
+	}
+}
+
+func TestResolveMerges(t *testing.T) {
+	tests := []struct {
+		name                string
+		bitbucketPulls      *remote.Store
+		bitbucketActivities *remote.Store
+		bitbucketCommits    *remote.Store
+		expectedMerges      remote.Merges
+	}{
+		{
+			name: "OK",
+			bitbucketPulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: bitbucketPull,
+			}),
+			bitbucketActivities: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: bitbucketMergeActivity,
+			}),
+			bitbucketCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": bitbucketCommit1,
+			}),
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+		{
+			name: "UnknownActivityAndMergeCommit",
+			bitbucketPulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: bitbucketPull,
+				1003: pullRequest{ID: 1003},
+			}),
+			bitbucketActivities: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: bitbucketMergeActivity,
+			}),
+			bitbucketCommits: remote.NewStore(),
+			expectedMerges:   remote.Merges{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merges := resolveMerges(tc.bitbucketPulls, tc.bitbucketActivities, tc.bitbucketCommits)
+			assert.Equal(t, tc.expectedMerges, merges)
+		})
+	}
+}
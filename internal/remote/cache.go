@@ -0,0 +1,109 @@
+package remote
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultCacheDir returns the default directory for caching remote API data between runs.
+// On Unix systems, it is $XDG_CACHE_HOME/changelog or $HOME/.cache/changelog if $XDG_CACHE_HOME is not set.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "changelog"), nil
+}
+
+// Cache is an on-disk cache for persisting remote API data of a repository between runs.
+// Each entry is stored as a JSON file and the keys are slash-separated paths (i.e. commits/<sha>).
+// A nil cache is valid and does not cache anything, so it can be used for disabling the cache.
+type Cache struct {
+	dir string
+}
+
+// NewCache creates a new on-disk cache for a repository.
+// The cache entries are stored under <dir>/<host>/<path> (i.e. ~/.cache/changelog/github.com/moorara/changelog).
+func NewCache(dir, host, path string) *Cache {
+	// Port numbers are not allowed in directory names on all platforms
+	host = strings.ReplaceAll(host, ":", "_")
+
+	return &Cache{
+		dir: filepath.Join(dir, host, filepath.FromSlash(path)),
+	}
+}
+
+// Dir returns the directory of the cache.
+func (c *Cache) Dir() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.dir
+}
+
+func (c *Cache) filename(key string) string {
+	return filepath.Join(c.dir, filepath.FromSlash(key)+".json")
+}
+
+// Load reads an entry from the cache and decodes it into v.
+// The return value is false if the entry is not cached or it cannot be decoded.
+func (c *Cache) Load(key string, v interface{}) bool {
+	if c == nil {
+		return false
+	}
+
+	b, err := ioutil.ReadFile(c.filename(key))
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(b, v) == nil
+}
+
+// Save encodes v and writes it to the cache.
+// The entry is written to a temporary file first and then renamed, so concurrent readers never see a partial entry.
+func (c *Cache) Save(key string, v interface{}) error {
+	if c == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	filename := c.filename(key)
+	dir := filepath.Dir(filename)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := os.Rename(f.Name(), filename); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package remote
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultCacheDir(t *testing.T) {
+	os.Setenv("XDG_CACHE_HOME", "/tmp/cache")
+	defer os.Unsetenv("XDG_CACHE_HOME")
+
+	dir, err := DefaultCacheDir()
+
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/cache", "changelog"), dir)
+}
+
+func TestNewCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		dir         string
+		host        string
+		path        string
+		expectedDir string
+	}{
+		{
+			name:        "OK",
+			dir:         "/tmp/cache/changelog",
+			host:        "github.com",
+			path:        "moorara/changelog",
+			expectedDir: filepath.Join("/tmp/cache/changelog", "github.com", "moorara", "changelog"),
+		},
+		{
+			name:        "WithPort",
+			dir:         "/tmp/cache/changelog",
+			host:        "git.example.com:8443",
+			path:        "octocat/Hello-World",
+			expectedDir: filepath.Join("/tmp/cache/changelog", "git.example.com_8443", "octocat", "Hello-World"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewCache(tc.dir, tc.host, tc.path)
+
+			assert.NotNil(t, c)
+			assert.Equal(t, tc.expectedDir, c.Dir())
+		})
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *Cache
+
+	assert.Equal(t, "", c.Dir())
+	assert.NoError(t, c.Save("commits/6dcb09b5b57875f334f61aebed695e2e4193db5e", "value"))
+	assert.False(t, c.Load("commits/6dcb09b5b57875f334f61aebed695e2e4193db5e", new(string)))
+}
+
+func TestCache_SaveLoad(t *testing.T) {
+	type entry struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	c := NewCache(t.TempDir(), "github.com", "octocat/Hello-World")
+
+	e := entry{}
+	assert.False(t, c.Load("entries/1", &e))
+
+	assert.NoError(t, c.Save("entries/1", entry{Name: "first", Count: 1}))
+	assert.True(t, c.Load("entries/1", &e))
+	assert.Equal(t, entry{Name: "first", Count: 1}, e)
+
+	// Overwrite the entry
+	assert.NoError(t, c.Save("entries/1", entry{Name: "second", Count: 2}))
+	assert.True(t, c.Load("entries/1", &e))
+	assert.Equal(t, entry{Name: "second", Count: 2}, e)
+
+	// No temporary file should be left behind
+	files, err := ioutil.ReadDir(filepath.Join(c.Dir(), "entries"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "1.json", files[0].Name())
+}
+
+func TestCache_LoadCorrupted(t *testing.T) {
+	c := NewCache(t.TempDir(), "github.com", "octocat/Hello-World")
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(c.Dir(), "entries"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(c.Dir(), "entries", "1.json"), []byte("{"), 0644))
+
+	v := map[string]string{}
+	assert.False(t, c.Load("entries/1", &v))
+}
+
+func TestCache_SaveError(t *testing.T) {
+	c := NewCache(t.TempDir(), "github.com", "octocat/Hello-World")
+
+	assert.Error(t, c.Save("entries/1", make(chan int)))
+}
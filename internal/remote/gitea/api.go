@@ -0,0 +1,314 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	userAgent = "moorara/changelog"
+	// Gitea caps the page size to MAX_RESPONSE_ITEMS which is 50 by default.
+	pageSize = 50
+)
+
+var linkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="([a-z]+)"`)
+
+// Timeline comment types of interest.
+const (
+	timelineClose     = "close"
+	timelineMergePull = "merge_pull"
+)
+
+type (
+	user struct {
+		ID       int    `json:"id"`
+		Login    string `json:"login"`
+		FullName string `json:"full_name"`
+		Email    string `json:"email"`
+		HTMLURL  string `json:"html_url"`
+	}
+
+	permission struct {
+		Admin bool `json:"admin"`
+		Push  bool `json:"push"`
+		Pull  bool `json:"pull"`
+	}
+
+	repository struct {
+		ID            int         `json:"id"`
+		Name          string      `json:"name"`
+		FullName      string      `json:"full_name"`
+		DefaultBranch string      `json:"default_branch"`
+		HTMLURL       string      `json:"html_url"`
+		Permissions   *permission `json:"permissions"`
+	}
+
+	commitUser struct {
+		Name  string    `json:"name"`
+		Email string    `json:"email"`
+		Date  time.Time `json:"date"`
+	}
+
+	repoCommit struct {
+		Message   string     `json:"message"`
+		Author    commitUser `json:"author"`
+		Committer commitUser `json:"committer"`
+	}
+
+	commitMeta struct {
+		URL     string    `json:"url"`
+		SHA     string    `json:"sha"`
+		Created time.Time `json:"created"`
+	}
+
+	commit struct {
+		URL        string       `json:"url"`
+		SHA        string       `json:"sha"`
+		HTMLURL    string       `json:"html_url"`
+		Created    time.Time    `json:"created"`
+		RepoCommit repoCommit   `json:"commit"`
+		Author     *user        `json:"author"`
+		Committer  *user        `json:"committer"`
+		Parents    []commitMeta `json:"parents"`
+		Files      []file       `json:"files,omitempty"`
+	}
+
+	file struct {
+		Filename string `json:"filename"`
+		Status   string `json:"status"`
+	}
+
+	payloadCommit struct {
+		ID        string    `json:"id"`
+		Message   string    `json:"message"`
+		URL       string    `json:"url"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+
+	branch struct {
+		Name      string        `json:"name"`
+		Protected bool          `json:"protected"`
+		Commit    payloadCommit `json:"commit"`
+	}
+
+	tag struct {
+		Name    string     `json:"name"`
+		Message string     `json:"message"`
+		ID      string     `json:"id"`
+		Commit  commitMeta `json:"commit"`
+	}
+
+	label struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+
+	milestone struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+		State string `json:"state"`
+	}
+
+	issue struct {
+		ID        int        `json:"id"`
+		Number    int        `json:"number"`
+		Title     string     `json:"title"`
+		State     string     `json:"state"`
+		Labels    []label    `json:"labels"`
+		Milestone *milestone `json:"milestone"`
+		User      user       `json:"user"`
+		HTMLURL   string     `json:"html_url"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		ClosedAt  *time.Time `json:"closed_at"`
+	}
+
+	pullRequest struct {
+		ID             int        `json:"id"`
+		Number         int        `json:"number"`
+		Title          string     `json:"title"`
+		State          string     `json:"state"`
+		Labels         []label    `json:"labels"`
+		Milestone      *milestone `json:"milestone"`
+		User           user       `json:"user"`
+		HTMLURL        string     `json:"html_url"`
+		Merged         bool       `json:"merged"`
+		MergedAt       *time.Time `json:"merged_at"`
+		MergeCommitSHA string     `json:"merge_commit_sha"`
+		MergedBy       *user      `json:"merged_by"`
+		CreatedAt      time.Time  `json:"created_at"`
+		UpdatedAt      time.Time  `json:"updated_at"`
+		ClosedAt       *time.Time `json:"closed_at"`
+	}
+
+	timelineComment struct {
+		ID        int       `json:"id"`
+		Type      string    `json:"type"`
+		User      user      `json:"user"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
+
+// pages has the pagination information of a Gitea API response.
+// A zero page number means the corresponding link is not available.
+type pages struct {
+	next int
+	last int
+}
+
+// parsePages parses the Link header of a Gitea API response.
+func parsePages(resp *http.Response) pages {
+	p := pages{}
+
+	for _, m := range linkRegex.FindAllStringSubmatch(resp.Header.Get("Link"), -1) {
+		u, err := url.Parse(m[1])
+		if err != nil {
+			continue
+		}
+
+		page, _ := strconv.Atoi(u.Query().Get("page"))
+
+		switch m[2] {
+		case "next":
+			p.next = page
+		case "last":
+			p.last = page
+		}
+	}
+
+	return p
+}
+
+// responseError is returned when a Gitea API call is not successful.
+type responseError struct {
+	method     string
+	url        string
+	statusCode int
+	message    string
+}
+
+func newResponseError(resp *http.Response) *responseError {
+	e := &responseError{
+		method:     resp.Request.Method,
+		url:        resp.Request.URL.String(),
+		statusCode: resp.StatusCode,
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return e
+	}
+
+	body := struct {
+		Message string `json:"message"`
+	}{}
+
+	if err := json.Unmarshal(b, &body); err != nil {
+		e.message = string(b)
+		return e
+	}
+
+	e.message = body.Message
+
+	return e
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s %s %d: %s", e.method, e.url, e.statusCode, e.message)
+}
+
+func pageParams(page int) url.Values {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(pageSize))
+	params.Set("page", strconv.Itoa(page))
+
+	return params
+}
+
+// commitsParams returns the parameters for listing commits without the expensive diff stats, verification, and files.
+func commitsParams(page int) url.Values {
+	params := pageParams(page)
+	params.Set("stat", "false")
+	params.Set("verification", "false")
+	params.Set("files", "false")
+
+	return params
+}
+
+// call makes a GET request to a Gitea API endpoint and decodes the response body into out.
+func (r *repo) call(ctx context.Context, endpoint string, params url.Values, out interface{}) (pages, error) {
+	u := r.apiURL + endpoint
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return pages{}, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if r.accessToken != "" {
+		req.Header.Set("Authorization", "token "+r.accessToken)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return pages{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return pages{}, newResponseError(resp)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return pages{}, err
+	}
+
+	return parsePages(resp), nil
+}
+
+// fetchAllPages calls fetch for every page of a paginated Gitea API endpoint.
+// If the last page is known after fetching the first page, the remaining pages are fetched concurrently.
+// Otherwise, the remaining pages are fetched one after another by following the next page.
+func fetchAllPages(ctx context.Context, fetch func(context.Context, int) (pages, error)) error {
+	pg, err := fetch(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	if pg.last > 0 {
+		g, ctx := errgroup.WithContext(ctx)
+
+		for p := 2; p <= pg.last; p++ {
+			p := p // https://golang.org/doc/faq#closures_and_goroutines
+			g.Go(func() error {
+				_, err := fetch(ctx, p)
+				return err
+			})
+		}
+
+		return g.Wait()
+	}
+
+	// pg.next == 0 is not a valid page number and causes the loop to exit
+	for p := pg.next; p > 0; p = pg.next {
+		if pg, err = fetch(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
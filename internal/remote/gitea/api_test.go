@@ -0,0 +1,183 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePages(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        http.Header
+		expectedPages pages
+	}{
+		{
+			name:          "NoHeader",
+			header:        http.Header{},
+			expectedPages: pages{},
+		},
+		{
+			name: "NextPageOnly",
+			header: http.Header{
+				"Link": []string{`<https://gitea.com/api/v1/repos/octocat/Hello-World/tags?limit=50&page=2>; rel="next"`},
+			},
+			expectedPages: pages{next: 2},
+		},
+		{
+			name: "NextAndLastPages",
+			header: http.Header{
+				"Link": []string{`<https://gitea.com/api/v1/repos/octocat/Hello-World/tags?limit=50&page=2>; rel="next",<https://gitea.com/api/v1/repos/octocat/Hello-World/tags?limit=50&page=4>; rel="last"`},
+			},
+			expectedPages: pages{next: 2, last: 4},
+		},
+		{
+			name: "FirstAndPrevPages",
+			header: http.Header{
+				"Link": []string{`<https://gitea.com/api/v1/repos/octocat/Hello-World/tags?limit=50&page=1>; rel="first",<https://gitea.com/api/v1/repos/octocat/Hello-World/tags?limit=50&page=3>; rel="prev"`},
+			},
+			expectedPages: pages{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{Header: tc.header}
+			assert.Equal(t, tc.expectedPages, parsePages(resp))
+		})
+	}
+}
+
+func TestNewResponseError(t *testing.T) {
+	req := &http.Request{
+		Method: "GET",
+		URL:    &url.URL{Scheme: "https", Host: "gitea.com", Path: "/api/v1/repos/octocat/Hello-World"},
+	}
+
+	tests := []struct {
+		name          string
+		resp          *http.Response
+		expectedError string
+	}{
+		{
+			name: "Message",
+			resp: &http.Response{
+				Request:    req,
+				StatusCode: 404,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"message": "The target couldn't be found.", "url": "https://gitea.com/api/swagger"}`)),
+			},
+			expectedError: "GET https://gitea.com/api/v1/repos/octocat/Hello-World 404: The target couldn't be found.",
+		},
+		{
+			name: "NotJSON",
+			resp: &http.Response{
+				Request:    req,
+				StatusCode: 502,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`Bad Gateway`)),
+			},
+			expectedError: "GET https://gitea.com/api/v1/repos/octocat/Hello-World 502: Bad Gateway",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newResponseError(tc.resp)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestCommitsParams(t *testing.T) {
+	params := commitsParams(2)
+
+	assert.Equal(t, "files=false&limit=50&page=2&stat=false&verification=false", params.Encode())
+}
+
+func TestFetchAllPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		pages         map[int]pages
+		errors        map[int]error
+		expectedPages []int
+		expectedError string
+	}{
+		{
+			name:          "FirstPageFails",
+			pages:         map[int]pages{},
+			errors:        map[int]error{1: errors.New("error on fetching page 1")},
+			expectedError: "error on fetching page 1",
+		},
+		{
+			name: "Concurrent",
+			pages: map[int]pages{
+				1: {next: 2, last: 3},
+				2: {next: 3, last: 3},
+				3: {next: 0, last: 3},
+			},
+			errors:        map[int]error{},
+			expectedPages: []int{1, 2, 3},
+		},
+		{
+			name: "ConcurrentFails",
+			pages: map[int]pages{
+				1: {next: 2, last: 3},
+				2: {next: 3, last: 3},
+			},
+			errors:        map[int]error{3: errors.New("error on fetching page 3")},
+			expectedError: "error on fetching page 3",
+		},
+		{
+			name: "Sequential",
+			pages: map[int]pages{
+				1: {next: 2},
+				2: {next: 3},
+				3: {next: 0},
+			},
+			errors:        map[int]error{},
+			expectedPages: []int{1, 2, 3},
+		},
+		{
+			name: "SequentialFails",
+			pages: map[int]pages{
+				1: {next: 2},
+			},
+			errors:        map[int]error{2: errors.New("error on fetching page 2")},
+			expectedError: "error on fetching page 2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			fetched := map[int]bool{}
+
+			err := fetchAllPages(context.Background(), func(ctx context.Context, p int) (pages, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err, ok := tc.errors[p]; ok {
+					return pages{}, err
+				}
+				fetched[p] = true
+				return tc.pages[p], nil
+			})
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, fetched, len(tc.expectedPages))
+				for _, p := range tc.expectedPages {
+					assert.True(t, fetched[p])
+				}
+			}
+		})
+	}
+}
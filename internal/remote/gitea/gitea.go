@@ -0,0 +1,470 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+// repo implements the remote.Repo interface for Gitea and Forgejo.
+type repo struct {
+	logger      log.Logger
+	client      *http.Client
+	apiURL      string
+	webURL      string
+	owner       string
+	repo        string
+	accessToken string
+	stores      struct {
+		commits *remote.Store
+	}
+}
+
+// NewRepo creates a new Gitea repository.
+// apiURL and webURL are the base URLs of the Gitea instance (i.e. https://gitea.com/api/v1 and https://gitea.com).
+// Forgejo is a fork of Gitea with the same API, so it is supported too.
+func NewRepo(logger log.Logger, cache *remote.Cache, apiURL, webURL, ownerName, repoName, accessToken string) remote.Repo {
+	transport := remote.NewTransport(logger, cache)
+	client := &http.Client{
+		Transport: transport,
+	}
+
+	r := &repo{
+		logger:      logger,
+		client:      client,
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		webURL:      strings.TrimSuffix(webURL, "/"),
+		owner:       ownerName,
+		repo:        repoName,
+		accessToken: accessToken,
+	}
+
+	r.stores.commits = remote.NewStore()
+
+	return r
+}
+
+func (r *repo) endpoint(format string, a ...interface{}) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(r.owner), url.PathEscape(r.repo)) + fmt.Sprintf(format, a...)
+}
+
+func (r *repo) getCommit(ctx context.Context, sha string) (commit, error) {
+	// First, check the cache
+	if v, ok := r.stores.commits.Load(sha); ok {
+		c := v.(commit)
+		return c, nil
+	}
+
+	c := commit{}
+	if _, err := r.call(ctx, r.endpoint("/git/commits/%s", sha), nil, &c); err != nil {
+		return commit{}, err
+	}
+
+	// Update the cache
+	r.stores.commits.Save(c.SHA, c)
+
+	return c, nil
+}
+
+func (r *repo) findEvent(ctx context.Context, num int, eventType string) (timelineComment, error) {
+	endpoint := r.endpoint("/issues/%d/timeline", num)
+
+	for p := 1; p > 0; {
+		events := []timelineComment{}
+		pg, err := r.call(ctx, endpoint, pageParams(p), &events)
+		if err != nil {
+			return timelineComment{}, err
+		}
+
+		for _, e := range events {
+			if e.Type == eventType {
+				r.logger.Debugf("Found %s event for issue %d", eventType, num)
+				return e, nil
+			}
+		}
+
+		// pg.next == 0 is not a valid page number and causes the loop to exit
+		p = pg.next
+	}
+
+	return timelineComment{}, nil
+}
+
+// FutureTag returns a tag that does not exist yet for a Gitea repository.
+func (r *repo) FutureTag(name string) remote.Tag {
+	return remote.Tag{
+		Name:   name,
+		Time:   time.Now(),
+		WebURL: fmt.Sprintf("%s/%s/%s/src/tag/%s", r.webURL, r.owner, r.repo, name),
+	}
+}
+
+// CompareURL returns a URL for comparing two revisions for a Gitea repository.
+func (r *repo) CompareURL(base, head string) string {
+	return fmt.Sprintf("%s/%s/%s/compare/%s...%s", r.webURL, r.owner, r.repo, base, head)
+}
+
+// CommitURL returns the web URL of a commit for a Gitea repository.
+func (r *repo) CommitURL(hash string) string {
+	return fmt.Sprintf("%s/%s/%s/commit/%s", r.webURL, r.owner, r.repo, hash)
+}
+
+// CheckPermissions ensures the client has all the required permissions for a Gitea repository.
+func (r *repo) CheckPermissions(ctx context.Context) error {
+	rp := repository{}
+	if _, err := r.call(ctx, r.endpoint(""), nil, &rp); err != nil {
+		return err
+	}
+
+	if rp.Permissions == nil || !rp.Permissions.Pull {
+		return errors.New("Gitea access token does not have read access to the repository")
+	}
+
+	r.logger.Debug("Gitea repository permissions verified: pull")
+
+	return nil
+}
+
+// FetchFirstCommit retrieves the firist/initial commit for a Gitea repository.
+func (r *repo) FetchFirstCommit(ctx context.Context) (remote.Commit, error) {
+	r.logger.Debug("Fetching the first Gitea commit ...")
+
+	var c commit
+
+	for p := 1; p > 0; {
+		commits := []commit{}
+		pg, err := r.call(ctx, r.endpoint("/commits"), commitsParams(p), &commits)
+		if err != nil {
+			return remote.Commit{}, err
+		}
+
+		// Add commits to commit store
+		for _, c := range commits {
+			r.stores.commits.Save(c.SHA, c)
+		}
+
+		if l := len(commits); l > 0 {
+			c = commits[l-1]
+		}
+
+		// Jump to the last page if it is known, otherwise follow the next page
+		// pg.next == 0 is not a valid page number and causes the loop to exit
+		if pg.last > p {
+			p = pg.last
+		} else {
+			p = pg.next
+		}
+	}
+
+	commit := toCommit(c)
+
+	r.logger.Debugf("Fetched the first Gitea commit: %s", commit)
+
+	return commit, nil
+}
+
+// FetchBranch retrieves a branch by name for a Gitea repository.
+func (r *repo) FetchBranch(ctx context.Context, name string) (remote.Branch, error) {
+	b := branch{}
+	if _, err := r.call(ctx, r.endpoint("/branches/%s", url.PathEscape(name)), nil, &b); err != nil {
+		return remote.Branch{}, err
+	}
+
+	// The branch payload only has the author time, so the commit is fetched for the committer time
+	c, err := r.getCommit(ctx, b.Commit.ID)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(b, c)
+
+	r.logger.Debugf("Fetched Gitea branch: %s", name)
+
+	return branch, nil
+}
+
+// FetchDefaultBranch retrieves the default branch for a Gitea repository.
+func (r *repo) FetchDefaultBranch(ctx context.Context) (remote.Branch, error) {
+	rp := repository{}
+	if _, err := r.call(ctx, r.endpoint(""), nil, &rp); err != nil {
+		return remote.Branch{}, err
+	}
+
+	b := branch{}
+	if _, err := r.call(ctx, r.endpoint("/branches/%s", url.PathEscape(rp.DefaultBranch)), nil, &b); err != nil {
+		return remote.Branch{}, err
+	}
+
+	c, err := r.getCommit(ctx, b.Commit.ID)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(b, c)
+
+	r.logger.Debugf("Fetched Gitea default branch: %s", b.Name)
+
+	return branch, nil
+}
+
+// FetchTags retrieves all tags for a Gitea repository.
+func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
+	r.logger.Debug("Fetching Gitea tags ...")
+
+	// ==============================> FETCH TAGS <==============================
+
+	tagStore := remote.NewStore()
+
+	err := fetchAllPages(ctx, func(ctx context.Context, p int) (pages, error) {
+		r.logger.Debugf("Fetched Gitea tags page %d ...", p)
+		tags := []tag{}
+		pg, err := r.call(ctx, r.endpoint("/tags"), pageParams(p), &tags)
+		if err != nil {
+			return pages{}, err
+		}
+		for _, t := range tags {
+			tagStore.Save(t.Name, t)
+		}
+		return pg, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// ==============================> FETCH TAG COMMITS <==============================
+
+	r.logger.Debug("Fetching Gitea commits for tags ...")
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// The commit time of a tag is the tagger time for annotated tags, so the commits are fetched for the committer times
+	_ = tagStore.ForEach(func(_, v interface{}) error {
+		g.Go(func() error {
+			t := v.(tag)
+			_, err := r.getCommit(ctx, t.Commit.SHA)
+			return err
+		})
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// ==============================> JOINING TAGS & COMMITS <==============================
+
+	tags := resolveTags(tagStore, r.stores.commits, r.webURL, r.owner, r.repo)
+
+	r.logger.Debugf("Gitea tags are fetched: %d", len(tags))
+
+	return tags, nil
+}
+
+// FetchIssuesAndMerges retrieves all closed issues and merged pull requests for a Gitea repository.
+func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
+	if since.IsZero() {
+		r.logger.Info("Fetching Gitea issues and pull requests since the beginning ...")
+	} else {
+		r.logger.Infof("Fetching Gitea issues and pull requests since %s ...", since.Format(time.RFC3339))
+	}
+
+	// ==============================> FETCH ISSUES & PULL REQUESTS <==============================
+
+	issueStore := remote.NewStore()
+	pullStore := remote.NewStore()
+
+	g1, ctx1 := errgroup.WithContext(ctx)
+
+	// Fetch closed issues
+	g1.Go(func() error {
+		return fetchAllPages(ctx1, func(ctx context.Context, p int) (pages, error) {
+			r.logger.Debugf("Fetched Gitea issues page %d ...", p)
+			params := pageParams(p)
+			params.Set("state", "closed")
+			params.Set("type", "issues")
+			if !since.IsZero() {
+				params.Set("since", since.Format(time.RFC3339))
+			}
+
+			issues := []issue{}
+			pg, err := r.call(ctx, r.endpoint("/issues"), params, &issues)
+			if err != nil {
+				return pages{}, err
+			}
+			for _, i := range issues {
+				issueStore.Save(i.Number, i)
+			}
+			return pg, nil
+		})
+	})
+
+	// Fetch merged pull requests
+	// The pulls endpoint does not support the since parameter,
+	// so pull requests are fetched from the most recently updated ones until reaching the since time.
+	g1.Go(func() error {
+		for p := 1; p > 0; {
+			r.logger.Debugf("Fetched Gitea pull requests page %d ...", p)
+			params := pageParams(p)
+			params.Set("state", "closed")
+			params.Set("sort", "recentupdate")
+
+			pulls := []pullRequest{}
+			pg, err := r.call(ctx1, r.endpoint("/pulls"), params, &pulls)
+			if err != nil {
+				return err
+			}
+
+			// pg.next == 0 is not a valid page number and causes the loop to exit
+			p = pg.next
+
+			for _, pr := range pulls {
+				if !since.IsZero() && pr.UpdatedAt.Before(since) {
+					p = 0
+					break
+				}
+				if pr.Merged {
+					pullStore.Save(pr.Number, pr)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err := g1.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	r.logger.Debugf("Fetched Gitea issues (%d) and pull requests (%d)", issueStore.Len(), pullStore.Len())
+
+	// ==============================> FETCH EVENTS & COMMITS <==============================
+
+	r.logger.Debug("Fetching Gitea events and commits for issues and pull requests ...")
+
+	eventStore := remote.NewStore()
+
+	g2, ctx2 := errgroup.WithContext(ctx)
+
+	// Search the timeline of every issue for its closer
+	_ = issueStore.ForEach(func(k, v interface{}) error {
+		num := k.(int)
+
+		g2.Go(func() error {
+			e, err := r.findEvent(ctx2, num, timelineClose)
+			if err != nil {
+				return err
+			}
+			// If the event is empty/zero, the desired event has not been found
+			if e.ID != 0 {
+				eventStore.Save(num, e)
+			}
+			return nil
+		})
+
+		return nil
+	})
+
+	// Fetch merge commits and search the timeline only for pull requests without a known merger
+	_ = pullStore.ForEach(func(k, v interface{}) error {
+		num := k.(int)
+		pr := v.(pullRequest)
+
+		g2.Go(func() error {
+			if pr.MergedBy == nil {
+				e, err := r.findEvent(ctx2, num, timelineMergePull)
+				if err != nil {
+					return err
+				}
+				// If the event is empty/zero, the desired event has not been found
+				if e.ID != 0 {
+					eventStore.Save(num, e)
+				}
+			}
+
+			if pr.MergeCommitSHA == "" {
+				return nil
+			}
+
+			_, err := r.getCommit(ctx2, pr.MergeCommitSHA)
+			return err
+		})
+
+		return nil
+	})
+
+	if err := g2.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	// ==============================> JOINING ISSUES, PULLS, EVENTS, & COMMITS <==============================
+
+	issues, merges := resolveIssuesAndMerges(issueStore, pullStore, eventStore, r.stores.commits)
+
+	r.logger.Debugf("Resolved and sorted Gitea issues (%d) and pull requests (%d)", len(issues), len(merges))
+	r.logger.Infof("All Gitea issues (%d) and pull requests (%d) are fetched", len(issues), len(merges))
+
+	return issues, merges, nil
+}
+
+// FetchParentCommits retrieves all parent commits of a given commit hash for a Gitea repository.
+func (r *repo) FetchParentCommits(ctx context.Context, hash string) (remote.Commits, error) {
+	r.logger.Debugf("Fetching all Gitea parent commits for %s ...", hash)
+
+	commitStore := remote.NewStore()
+
+	err := fetchAllPages(ctx, func(ctx context.Context, p int) (pages, error) {
+		params := commitsParams(p)
+		params.Set("sha", hash)
+
+		commits := []commit{}
+		pg, err := r.call(ctx, r.endpoint("/commits"), params, &commits)
+		if err != nil {
+			return pages{}, err
+		}
+		for _, c := range commits {
+			commitStore.Save(c.SHA, c)
+			r.stores.commits.Save(c.SHA, c)
+		}
+		return pg, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	commits := resolveCommits(commitStore)
+
+	r.logger.Debugf("All Gitea parent commits for %s are fetched", hash)
+
+	return commits, nil
+}
+
+// FetchCommitFiles retrieves the paths of files changed by a commit for a Gitea repository.
+func (r *repo) FetchCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	r.logger.Debugf("Fetching Gitea files changed by %s ...", hash)
+
+	// The commits in the store may not have the changed files, so the commit is always fetched.
+	c := commit{}
+	if _, err := r.call(ctx, r.endpoint("/git/commits/%s", hash), nil, &c); err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, f := range c.Files {
+		files = append(files, f.Filename)
+	}
+
+	r.logger.Debugf("Gitea files changed by %s are fetched: %d", hash, len(files))
+
+	return files, nil
+}
@@ -0,0 +1,877 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+const (
+	repoPath = "/repos/octocat/Hello-World"
+	notFound = "404: The target couldn't be found."
+)
+
+func TestNewRepo(t *testing.T) {
+	tests := []struct {
+		name        string
+		logger      log.Logger
+		apiURL      string
+		webURL      string
+		ownerName   string
+		repoName    string
+		accessToken string
+		expectedAPI string
+		expectedWeb string
+	}{
+		{
+			name:        "Gitea",
+			logger:      log.New(log.None),
+			apiURL:      "https://gitea.com/api/v1",
+			webURL:      "https://gitea.com",
+			ownerName:   "moorara",
+			repoName:    "changelog",
+			accessToken: "gitea-access-token",
+			expectedAPI: "https://gitea.com/api/v1",
+			expectedWeb: "https://gitea.com",
+		},
+		{
+			name:        "Forgejo",
+			logger:      log.New(log.None),
+			apiURL:      "https://codeberg.org/api/v1/",
+			webURL:      "https://codeberg.org/",
+			ownerName:   "moorara",
+			repoName:    "changelog",
+			accessToken: "forgejo-access-token",
+			expectedAPI: "https://codeberg.org/api/v1",
+			expectedWeb: "https://codeberg.org",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRepo(tc.logger, nil, tc.apiURL, tc.webURL, tc.ownerName, tc.repoName, tc.accessToken)
+			assert.NotNil(t, r)
+
+			gr, ok := r.(*repo)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.logger, gr.logger)
+			assert.NotNil(t, gr.client)
+			assert.Equal(t, tc.expectedAPI, gr.apiURL)
+			assert.Equal(t, tc.expectedWeb, gr.webURL)
+			assert.Equal(t, tc.ownerName, gr.owner)
+			assert.Equal(t, tc.repoName, gr.repo)
+			assert.Equal(t, tc.accessToken, gr.accessToken)
+			assert.NotNil(t, gr.stores.commits)
+		})
+	}
+}
+
+func TestRepo_getCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		commitsStore   *remote.Store
+		routes         map[string]MockResponse
+		ctx            context.Context
+		sha            string
+		expectedCommit commit
+		expectedError  string
+	}{
+		{
+			name: "CacheHit",
+			commitsStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": giteaCommit1,
+			}),
+			ctx:            context.Background(),
+			sha:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: giteaCommit1,
+		},
+		{
+			name:          "Error",
+			commitsStore:  remote.NewStore(),
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			sha:           "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedError: "/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e " + notFound,
+		},
+		{
+			name:         "Success",
+			commitsStore: remote.NewStore(),
+			routes: map[string]MockResponse{
+				repoPath + "/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: giteaCommit1},
+			},
+			ctx:            context.Background(),
+			sha:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: giteaCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+			r.stores.commits = tc.commitsStore
+
+			commit, err := r.getCommit(tc.ctx, tc.sha)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+			}
+		})
+	}
+}
+
+func TestRepo_findEvent(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		num           int
+		eventType     string
+		expectedEvent timelineComment
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			num:           1001,
+			eventType:     timelineClose,
+			expectedError: "/issues/1001/timeline?limit=50&page=1 " + notFound,
+		},
+		{
+			name: "NotFound",
+			routes: map[string]MockResponse{
+				repoPath + "/issues/1001/timeline?page=1": {StatusCode: http.StatusOK, Body: []timelineComment{{ID: 3, Type: "comment"}}},
+			},
+			ctx:           context.Background(),
+			num:           1001,
+			eventType:     timelineClose,
+			expectedEvent: timelineComment{},
+		},
+		{
+			name: "Found",
+			routes: map[string]MockResponse{
+				repoPath + "/issues/1001/timeline?page=1": {StatusCode: http.StatusOK, NextPage: 2, LastPage: 2, Body: []timelineComment{{ID: 3, Type: "comment"}}},
+				repoPath + "/issues/1001/timeline?page=2": {StatusCode: http.StatusOK, Body: []timelineComment{giteaCloseEvent}},
+			},
+			ctx:           context.Background(),
+			num:           1001,
+			eventType:     timelineClose,
+			expectedEvent: giteaCloseEvent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+
+			event, err := r.findEvent(tc.ctx, tc.num, tc.eventType)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, event)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedEvent, event)
+			}
+		})
+	}
+}
+
+func TestRepo_FutureTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		webURL      string
+		tagName     string
+		expectedTag remote.Tag
+	}{
+		{
+			name:    "Gitea",
+			webURL:  "https://gitea.com",
+			tagName: "v0.1.0",
+			expectedTag: remote.Tag{
+				Name:   "v0.1.0",
+				WebURL: "https://gitea.com/octocat/Hello-World/src/tag/v0.1.0",
+			},
+		},
+		{
+			name:    "Forgejo",
+			webURL:  "https://codeberg.org",
+			tagName: "v0.1.0",
+			expectedTag: remote.Tag{
+				Name:   "v0.1.0",
+				WebURL: "https://codeberg.org/octocat/Hello-World/src/tag/v0.1.0",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger: log.New(log.None),
+				webURL: tc.webURL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+
+			tag := r.FutureTag(tc.tagName)
+
+			assert.Equal(t, tc.expectedTag.Name, tag.Name)
+			assert.NotZero(t, tag.Time)
+			assert.Equal(t, tc.expectedTag.WebURL, tag.WebURL)
+		})
+	}
+}
+
+func TestRepo_CompareURL(t *testing.T) {
+	tests := []struct {
+		name               string
+		webURL             string
+		base, head         string
+		expectedCompareURL string
+	}{
+		{
+			name:               "OK",
+			webURL:             "https://gitea.com",
+			base:               "v0.1.0",
+			head:               "v0.2.0",
+			expectedCompareURL: "https://gitea.com/octocat/Hello-World/compare/v0.1.0...v0.2.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger: log.New(log.None),
+				webURL: tc.webURL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+
+			url := r.CompareURL(tc.base, tc.head)
+
+			assert.Equal(t, tc.expectedCompareURL, url)
+		})
+	}
+}
+
+func TestRepo_CommitURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		webURL      string
+		expectedURL string
+	}{
+		{
+			name:        "OK",
+			webURL:      "https://gitea.com",
+			expectedURL: "https://gitea.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger: log.New(log.None),
+				webURL: tc.webURL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+
+			url := r.CommitURL("6dcb09b5b57875f334f61aebed695e2e4193db5e")
+
+			assert.Equal(t, tc.expectedURL, url)
+		})
+	}
+}
+
+func TestRepo_CheckPermissions(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedError string
+	}{
+		{
+			name: "Unauthorized",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusUnauthorized, Body: map[string]string{"message": "token is required"}},
+			},
+			ctx:           context.Background(),
+			expectedError: repoPath + " 401: token is required",
+		},
+		{
+			name: "NoPermissions",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: repository{ID: 1296269, Name: "Hello-World"}},
+			},
+			ctx:           context.Background(),
+			expectedError: "Gitea access token does not have read access to the repository",
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: giteaRepository},
+			},
+			ctx:           context.Background(),
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:      log.New(log.None),
+				client:      ts.Client(),
+				apiURL:      ts.URL,
+				owner:       "octocat",
+				repo:        "Hello-World",
+				accessToken: "gitea-access-token",
+			}
+
+			err := r.CheckPermissions(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchFirstCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedCommit remote.Commit
+		expectedError  string
+	}{
+		{
+			name:          "FirstPageFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: "/commits?files=false&limit=50&page=1&stat=false&verification=false " + notFound,
+		},
+		{
+			name: "LastPageFails",
+			routes: map[string]MockResponse{
+				repoPath + "/commits?page=1": {StatusCode: http.StatusOK, NextPage: 2, LastPage: 2, Body: []commit{giteaCommit2}},
+			},
+			ctx:           context.Background(),
+			expectedError: "/commits?files=false&limit=50&page=2&stat=false&verification=false " + notFound,
+		},
+		{
+			name: "JumpToLastPage",
+			routes: map[string]MockResponse{
+				repoPath + "/commits?page=1": {StatusCode: http.StatusOK, NextPage: 2, LastPage: 3, Body: []commit{giteaCommit2}},
+				repoPath + "/commits?page=3": {StatusCode: http.StatusOK, Body: []commit{giteaCommit1}},
+			},
+			ctx:            context.Background(),
+			expectedCommit: remoteCommit1,
+		},
+		{
+			name: "FollowNextPage",
+			routes: map[string]MockResponse{
+				repoPath + "/commits?page=1": {StatusCode: http.StatusOK, NextPage: 2, Body: []commit{giteaCommit2}},
+				repoPath + "/commits?page=2": {StatusCode: http.StatusOK, Body: []commit{giteaCommit1}},
+			},
+			ctx:            context.Background(),
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commit, err := r.FetchFirstCommit(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		branchName     string
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "BranchFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: "/branches/main " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/branches/main": {StatusCode: http.StatusOK, Body: giteaBranch},
+			},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: "/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/branches/main":                                        {StatusCode: http.StatusOK, Body: giteaBranch},
+				repoPath + "/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: giteaCommit2},
+			},
+			ctx:            context.Background(),
+			branchName:     "main",
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			branch, err := r.FetchBranch(tc.ctx, tc.branchName)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "RepositoryFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: repoPath + " " + notFound,
+		},
+		{
+			name: "BranchFails",
+			routes: map[string]MockResponse{
+				repoPath: {StatusCode: http.StatusOK, Body: giteaRepository},
+			},
+			ctx:           context.Background(),
+			expectedError: "/branches/main " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath:                    {StatusCode: http.StatusOK, Body: giteaRepository},
+				repoPath + "/branches/main": {StatusCode: http.StatusOK, Body: giteaBranch},
+			},
+			ctx:           context.Background(),
+			expectedError: "/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath:                    {StatusCode: http.StatusOK, Body: giteaRepository},
+				repoPath + "/branches/main": {StatusCode: http.StatusOK, Body: giteaBranch},
+				repoPath + "/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: giteaCommit2},
+			},
+			ctx:            context.Background(),
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			branch, err := r.FetchDefaultBranch(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchTags(t *testing.T) {
+	giteaTag2 := tag{
+		Name: "v0.0.1",
+		Commit: commitMeta{
+			SHA: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedTags  remote.Tags
+		expectedError string
+	}{
+		{
+			name:          "TagsFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: "/tags?limit=50&page=1 " + notFound,
+		},
+		{
+			name: "CommitFails",
+			routes: map[string]MockResponse{
+				repoPath + "/tags?page=1": {StatusCode: http.StatusOK, Body: []tag{giteaTag}},
+			},
+			ctx:           context.Background(),
+			expectedError: "/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/tags?page=1": {StatusCode: http.StatusOK, NextPage: 2, LastPage: 2, Body: []tag{giteaTag}},
+				repoPath + "/tags?page=2": {StatusCode: http.StatusOK, Body: []tag{giteaTag2}},
+				repoPath + "/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: giteaCommit1},
+				repoPath + "/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: giteaCommit2},
+			},
+			ctx: context.Background(),
+			expectedTags: remote.Tags{
+				remoteTag,
+				{
+					Name:   "v0.0.1",
+					Time:   remoteCommit1.Time,
+					Commit: remoteCommit1,
+					WebURL: "https://gitea.com/octocat/Hello-World/src/tag/v0.0.1",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				webURL: "https://gitea.com",
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			tags, err := r.FetchTags(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, tags)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTags, tags.Sort())
+			}
+		})
+	}
+}
+
+func TestRepo_FetchIssuesAndMerges(t *testing.T) {
+	// A merged pull request without merged_by and an old pull request that should stop the pagination
+	giteaPull2 := pullRequest{Number: 1003, Merged: true, MergeCommitSHA: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c", User: giteaUser1, UpdatedAt: parseGiteaTime("2020-10-21T21:00:00Z")}
+	giteaPull3 := pullRequest{Number: 1004, Merged: true, User: giteaUser1, UpdatedAt: parseGiteaTime("2020-09-01T00:00:00Z")}
+
+	routes := func(exclude ...string) map[string]MockResponse {
+		routes := map[string]MockResponse{
+			repoPath + "/issues?page=1":                                        {StatusCode: http.StatusOK, Body: []issue{giteaIssue}},
+			repoPath + "/pulls?page=1":                                         {StatusCode: http.StatusOK, NextPage: 2, Body: []pullRequest{giteaPull, giteaPull2}},
+			repoPath + "/pulls?page=2":                                         {StatusCode: http.StatusOK, NextPage: 3, Body: []pullRequest{giteaPull3}},
+			repoPath + "/issues/1001/timeline?page=1":                          {StatusCode: http.StatusOK, Body: []timelineComment{giteaCloseEvent}},
+			repoPath + "/issues/1003/timeline?page=1":                          {StatusCode: http.StatusOK, Body: []timelineComment{giteaMergeEvent}},
+			repoPath + "/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: giteaCommit1},
+			repoPath + "/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": {StatusCode: http.StatusOK, Body: giteaCommit2},
+		}
+
+		for _, key := range exclude {
+			delete(routes, key)
+		}
+
+		return routes
+	}
+
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		since          time.Time
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+		expectedError  string
+	}{
+		{
+			name:          "IssuesFails",
+			routes:        routes(repoPath + "/issues?page=1"),
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: "/issues?limit=50&page=1&state=closed&type=issues " + notFound,
+		},
+		{
+			name:          "PullsFails",
+			routes:        routes(repoPath + "/pulls?page=1"),
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: "/pulls?limit=50&page=1&sort=recentupdate&state=closed " + notFound,
+		},
+		{
+			name:          "TimelineFails",
+			routes:        routes(repoPath + "/issues/1003/timeline?page=1"),
+			ctx:           context.Background(),
+			since:         parseGiteaTime("2020-10-01T00:00:00Z"),
+			expectedError: "/issues/1003/timeline?limit=50&page=1 " + notFound,
+		},
+		{
+			name:          "CommitFails",
+			routes:        routes(repoPath + "/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e"),
+			ctx:           context.Background(),
+			since:         parseGiteaTime("2020-10-01T00:00:00Z"),
+			expectedError: "/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e " + notFound,
+		},
+		{
+			name:           "Success",
+			routes:         routes(),
+			ctx:            context.Background(),
+			since:          parseGiteaTime("2020-10-01T00:00:00Z"),
+			expectedIssues: remote.Issues{remoteIssue},
+			expectedMerges: remote.Merges{
+				{
+					Change: remote.Change{
+						Number: 1003,
+						Labels: []string{},
+						Time:   remoteCommit2.Time,
+						Author: remoteUser1,
+					},
+					Merger: remoteUser3,
+					Commit: remoteCommit2,
+				},
+				remoteMerge,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			issues, merges, err := r.FetchIssuesAndMerges(tc.ctx, tc.since)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, issues)
+				assert.Nil(t, merges)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIssues, issues)
+				assert.Equal(t, tc.expectedMerges, merges)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchParentCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		routes          map[string]MockResponse
+		ctx             context.Context
+		hash            string
+		expectedCommits remote.Commits
+		expectedError   string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: "/commits?files=false&limit=50&page=1&sha=c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c&stat=false&verification=false " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/commits?page=1": {StatusCode: http.StatusOK, NextPage: 2, LastPage: 2, Body: []commit{giteaCommit2}},
+				repoPath + "/commits?page=2": {StatusCode: http.StatusOK, Body: []commit{giteaCommit1}},
+			},
+			ctx:             context.Background(),
+			hash:            "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commits, err := r.FetchParentCommits(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, commits)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommits, commits)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchCommitFiles(t *testing.T) {
+	c := giteaCommit1
+	c.Files = []file{
+		{Filename: "api/server.go", Status: "modified"},
+		{Filename: "web/app.js", Status: "added"},
+	}
+
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		hash          string
+		expectedFiles []string
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedError: "/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e " + notFound,
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				repoPath + "/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: c},
+			},
+			ctx:           context.Background(),
+			hash:          "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedFiles: []string{"api/server.go", "web/app.js"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+
+			files, err := r.FetchCommitFiles(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, files)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFiles, files)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+var (
+	giteaUser1 = user{
+		ID:       1,
+		Login:    "octocat",
+		FullName: "The Octocat",
+		Email:    "octocat@gitea.com",
+		HTMLURL:  "https://gitea.com/octocat",
+	}
+
+	giteaUser2 = user{
+		ID:       2,
+		Login:    "octodog",
+		FullName: "The Octodog",
+		Email:    "octodog@gitea.com",
+		HTMLURL:  "https://gitea.com/octodog",
+	}
+
+	giteaUser3 = user{
+		ID:       3,
+		Login:    "octofox",
+		FullName: "The Octofox",
+		Email:    "octofox@gitea.com",
+		HTMLURL:  "https://gitea.com/octofox",
+	}
+
+	giteaRepository = repository{
+		ID:            1296269,
+		Name:          "Hello-World",
+		FullName:      "octocat/Hello-World",
+		DefaultBranch: "main",
+		HTMLURL:       "https://gitea.com/octocat/Hello-World",
+		Permissions: &permission{
+			Admin: false,
+			Push:  false,
+			Pull:  true,
+		},
+	}
+
+	giteaCommit1 = commit{
+		URL:     "https://gitea.com/api/v1/repos/octocat/Hello-World/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		SHA:     "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		HTMLURL: "https://gitea.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Created: parseGiteaTime("2020-10-20T19:59:59Z"),
+		RepoCommit: repoCommit{
+			Message: "Fix all the bugs",
+			Author: commitUser{
+				Name:  "The Octocat",
+				Email: "octocat@gitea.com",
+				Date:  parseGiteaTime("2020-10-20T19:59:59Z"),
+			},
+			Committer: commitUser{
+				Name:  "The Octocat",
+				Email: "octocat@gitea.com",
+				Date:  parseGiteaTime("2020-10-20T19:59:59Z"),
+			},
+		},
+		Author:    &giteaUser1,
+		Committer: &giteaUser1,
+		Parents:   []commitMeta{},
+	}
+
+	giteaCommit2 = commit{
+		URL:     "https://gitea.com/api/v1/repos/octocat/Hello-World/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		SHA:     "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		HTMLURL: "https://gitea.com/octocat/Hello-World/commit/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Created: parseGiteaTime("2020-10-27T23:59:59Z"),
+		RepoCommit: repoCommit{
+			Message: "Release v0.1.0",
+			Author: commitUser{
+				Name:  "The Octocat",
+				Email: "octocat@gitea.com",
+				Date:  parseGiteaTime("2020-10-27T23:59:59Z"),
+			},
+			Committer: commitUser{
+				Name:  "The Octocat",
+				Email: "octocat@gitea.com",
+				Date:  parseGiteaTime("2020-10-27T23:59:59Z"),
+			},
+		},
+		Author:    &giteaUser1,
+		Committer: &giteaUser1,
+		Parents: []commitMeta{
+			{
+				URL: "https://gitea.com/api/v1/repos/octocat/Hello-World/git/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+				SHA: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			},
+		},
+	}
+
+	giteaBranch = branch{
+		Name:      "main",
+		Protected: true,
+		Commit: payloadCommit{
+			ID:        "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			Message:   "Release v0.1.0",
+			URL:       "https://gitea.com/octocat/Hello-World/commit/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			Timestamp: parseGiteaTime("2020-10-27T23:59:59Z"),
+		},
+	}
+
+	giteaTag = tag{
+		Name:    "v0.1.0",
+		Message: "Release v0.1.0",
+		ID:      "a5b5a3b5d2fd3b0c1b2a4e4f2b7d3e0c9f1e2d3c",
+		Commit: commitMeta{
+			URL:     "https://gitea.com/api/v1/repos/octocat/Hello-World/git/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			SHA:     "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			Created: parseGiteaTime("2020-10-28T10:00:00Z"),
+		},
+	}
+
+	giteaIssue = issue{
+		ID:     1,
+		Number: 1001,
+		Title:  "Found a bug",
+		State:  "closed",
+		Labels: []label{
+			{ID: 2000, Name: "bug", Color: "ee0701"},
+		},
+		Milestone: &milestone{
+			ID:    3000,
+			Title: "v1.0",
+			State: "open",
+		},
+		User:      giteaUser1,
+		HTMLURL:   "https://gitea.com/octocat/Hello-World/issues/1001",
+		CreatedAt: parseGiteaTime("2020-10-10T10:00:00Z"),
+		UpdatedAt: parseGiteaTime("2020-10-20T20:00:00Z"),
+		ClosedAt:  parseGiteaTimePtr("2020-10-20T20:00:00Z"),
+	}
+
+	giteaPull = pullRequest{
+		ID:     2,
+		Number: 1002,
+		Title:  "Fixed a bug",
+		State:  "closed",
+		Labels: []label{
+			{ID: 2000, Name: "bug", Color: "ee0701"},
+		},
+		Milestone: &milestone{
+			ID:    3000,
+			Title: "v1.0",
+			State: "open",
+		},
+		User:           giteaUser2,
+		HTMLURL:        "https://gitea.com/octocat/Hello-World/pulls/1002",
+		Merged:         true,
+		MergedAt:       parseGiteaTimePtr("2020-10-20T20:00:00Z"),
+		MergeCommitSHA: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		MergedBy:       &giteaUser3,
+		CreatedAt:      parseGiteaTime("2020-10-15T15:00:00Z"),
+		UpdatedAt:      parseGiteaTime("2020-10-22T22:00:00Z"),
+		ClosedAt:       parseGiteaTimePtr("2020-10-20T20:00:00Z"),
+	}
+
+	giteaCloseEvent = timelineComment{
+		ID:        1,
+		Type:      "close",
+		User:      giteaUser1,
+		CreatedAt: parseGiteaTime("2020-10-20T20:00:00Z"),
+	}
+
+	giteaMergeEvent = timelineComment{
+		ID:        2,
+		Type:      "merge_pull",
+		User:      giteaUser3,
+		CreatedAt: parseGiteaTime("2020-10-20T20:00:00Z"),
+	}
+
+	remoteUser1 = remote.User{
+		Name:     "The Octocat",
+		Email:    "octocat@gitea.com",
+		Username: "octocat",
+		WebURL:   "https://gitea.com/octocat",
+	}
+
+	remoteUser2 = remote.User{
+		Name:     "The Octodog",
+		Email:    "octodog@gitea.com",
+		Username: "octodog",
+		WebURL:   "https://gitea.com/octodog",
+	}
+
+	remoteUser3 = remote.User{
+		Name:     "The Octofox",
+		Email:    "octofox@gitea.com",
+		Username: "octofox",
+		WebURL:   "https://gitea.com/octofox",
+	}
+
+	remoteCommit1 = remote.Commit{
+		Hash:    "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time:    parseGiteaTime("2020-10-20T19:59:59Z"),
+		Message: "Fix all the bugs",
+	}
+
+	remoteCommit2 = remote.Commit{
+		Hash:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Time:    parseGiteaTime("2020-10-27T23:59:59Z"),
+		Parents: []string{"6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		Message: "Release v0.1.0",
+	}
+
+	remoteBranch = remote.Branch{
+		Name:   "main",
+		Commit: remoteCommit2,
+	}
+
+	remoteTag = remote.Tag{
+		Name:   "v0.1.0",
+		Time:   parseGiteaTime("2020-10-27T23:59:59Z"),
+		Commit: remoteCommit2,
+		WebURL: "https://gitea.com/octocat/Hello-World/src/tag/v0.1.0",
+	}
+
+	remoteIssue = remote.Issue{
+		Change: remote.Change{
+			Number:    1001,
+			Title:     "Found a bug",
+			Labels:    []string{"bug"},
+			Milestone: "v1.0",
+			Time:      parseGiteaTime("2020-10-20T20:00:00Z"),
+			Author:    remoteUser1,
+			WebURL:    "https://gitea.com/octocat/Hello-World/issues/1001",
+		},
+		Closer: remoteUser1,
+	}
+
+	remoteMerge = remote.Merge{
+		Change: remote.Change{
+			Number:    1002,
+			Title:     "Fixed a bug",
+			Labels:    []string{"bug"},
+			Milestone: "v1.0",
+			Time:      parseGiteaTime("2020-10-20T19:59:59Z"),
+			Author:    remoteUser2,
+			WebURL:    "https://gitea.com/octocat/Hello-World/pulls/1002",
+		},
+		Merger: remoteUser3,
+		Commit: remoteCommit1,
+	}
+)
+
+func parseGiteaTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func parseGiteaTimePtr(s string) *time.Time {
+	t := parseGiteaTime(s)
+	return &t
+}
+
+// MockResponse is a canned response of the fake Gitea API server.
+type MockResponse struct {
+	StatusCode int
+	NextPage   int
+	LastPage   int
+	Body       interface{}
+}
+
+// newMockServer creates a fake Gitea API server.
+// The routes are keyed by the request path and, for paginated endpoints, the page number (i.e. /repos/octocat/Hello-World/tags?page=2).
+// Requests to unknown routes receive a 404 Not Found response.
+func newMockServer(routes map[string]MockResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if page := r.URL.Query().Get("page"); page != "" {
+			key += "?page=" + page
+		}
+
+		resp, ok := routes[key]
+		if !ok {
+			resp = MockResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       map[string]string{"message": "The target couldn't be found."},
+			}
+		}
+
+		links := []string{}
+		if resp.NextPage > 0 {
+			links = append(links, fmt.Sprintf(`<http://%s%s?page=%d>; rel="next"`, r.Host, r.URL.Path, resp.NextPage))
+		}
+		if resp.LastPage > 0 {
+			links = append(links, fmt.Sprintf(`<http://%s%s?page=%d>; rel="last"`, r.Host, r.URL.Path, resp.LastPage))
+		}
+		if len(links) > 0 {
+			w.Header().Set("Link", strings.Join(links, ", "))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_ = json.NewEncoder(w).Encode(resp.Body)
+	}))
+}
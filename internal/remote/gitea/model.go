@@ -0,0 +1,195 @@
+package gitea
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func toUser(u user) remote.User {
+	return remote.User{
+		Name:     u.FullName,
+		Email:    u.Email,
+		Username: u.Login,
+		WebURL:   u.HTMLURL,
+	}
+}
+
+func toCommit(c commit) remote.Commit {
+	var parents []string
+	for _, p := range c.Parents {
+		parents = append(parents, p.SHA)
+	}
+
+	return remote.Commit{
+		Hash:    c.SHA,
+		Time:    c.RepoCommit.Committer.Date,
+		Parents: parents,
+		Message: c.RepoCommit.Message,
+	}
+}
+
+func toBranch(b branch, c commit) remote.Branch {
+	return remote.Branch{
+		Name:   b.Name,
+		Commit: toCommit(c),
+	}
+}
+
+func toTag(t tag, c commit, webURL, owner, repo string) remote.Tag {
+	return remote.Tag{
+		Name:   t.Name,
+		Time:   c.RepoCommit.Committer.Date,
+		Commit: toCommit(c),
+		WebURL: fmt.Sprintf("%s/%s/%s/src/tag/%s", webURL, owner, repo, t.Name),
+	}
+}
+
+func toLabels(labels []label) []string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+	}
+
+	return names
+}
+
+func toIssue(i issue, closer user) remote.Issue {
+	var milestone string
+	if i.Milestone != nil {
+		milestone = i.Milestone.Title
+	}
+
+	var time time.Time
+	if i.ClosedAt != nil {
+		time = *i.ClosedAt
+	}
+
+	return remote.Issue{
+		Change: remote.Change{
+			Number:    i.Number,
+			Title:     i.Title,
+			Labels:    toLabels(i.Labels),
+			Milestone: milestone,
+			Time:      time,
+			Author:    toUser(i.User),
+			WebURL:    i.HTMLURL,
+		},
+		Closer: toUser(closer),
+	}
+}
+
+func toMerge(p pullRequest, c commit, merger user) remote.Merge {
+	var milestone string
+	if p.Milestone != nil {
+		milestone = p.Milestone.Title
+	}
+
+	// p.MergedAt is the time the pull request was merged on Gitea
+	// c.RepoCommit.Committer.Date is the actual time of merge
+	time := c.RepoCommit.Committer.Date
+
+	return remote.Merge{
+		Change: remote.Change{
+			Number:    p.Number,
+			Title:     p.Title,
+			Labels:    toLabels(p.Labels),
+			Milestone: milestone,
+			Time:      time,
+			Author:    toUser(p.User),
+			WebURL:    p.HTMLURL,
+		},
+		Merger: toUser(merger),
+		Commit: toCommit(c),
+	}
+}
+
+func resolveTags(giteaTags, giteaCommits *remote.Store, webURL, owner, repo string) remote.Tags {
+	tags := remote.Tags{}
+
+	_ = giteaTags.ForEach(func(k, v interface{}) error {
+		t := v.(tag)
+
+		if v, ok := giteaCommits.Load(t.Commit.SHA); ok {
+			c := v.(commit)
+			tags = append(tags, toTag(t, c, webURL, owner, repo))
+		}
+
+		return nil
+	})
+
+	return tags
+}
+
+func resolveCommits(giteaCommits *remote.Store) remote.Commits {
+	commits := remote.Commits{}
+
+	_ = giteaCommits.ForEach(func(k, v interface{}) error {
+		c := v.(commit)
+		commits = append(commits, toCommit(c))
+		return nil
+	})
+
+	// The order of the commits should be from the most recent to the least recent
+	sort.Slice(commits, func(i, j int) bool {
+		if commits[i].Time.Equal(commits[j].Time) {
+			return commits[i].Hash < commits[j].Hash
+		}
+		return commits[i].Time.After(commits[j].Time)
+	})
+
+	return commits
+}
+
+func resolveIssuesAndMerges(giteaIssues, giteaPulls, giteaEvents, giteaCommits *remote.Store) (remote.Issues, remote.Merges) {
+	issues := remote.Issues{}
+	merges := remote.Merges{}
+
+	_ = giteaIssues.ForEach(func(k, v interface{}) error {
+		num := k.(int)
+		i := v.(issue)
+
+		// If the closer is not known, the issue is skipped
+		if v, ok := giteaEvents.Load(num); ok {
+			e := v.(timelineComment)
+			issues = append(issues, toIssue(i, e.User))
+		}
+
+		return nil
+	})
+
+	_ = giteaPulls.ForEach(func(k, v interface{}) error {
+		num := k.(int)
+		p := v.(pullRequest)
+
+		// If the merger or the merge commit is not known, the pull request is skipped
+		if merger, ok := mergerOf(num, p, giteaEvents); ok {
+			if v, ok := giteaCommits.Load(p.MergeCommitSHA); ok {
+				c := v.(commit)
+				merges = append(merges, toMerge(p, c, merger))
+			}
+		}
+
+		return nil
+	})
+
+	issues = issues.Sort()
+	merges = merges.Sort()
+
+	return issues, merges
+}
+
+func mergerOf(num int, p pullRequest, giteaEvents *remote.Store) (user, bool) {
+	if p.MergedBy != nil {
+		return *p.MergedBy, true
+	}
+
+	if v, ok := giteaEvents.Load(num); ok {
+		e := v.(timelineComment)
+		return e.User, true
+	}
+
+	return user{}, false
+}
@@ -0,0 +1,290 @@
+package gitea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func TestToUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		u            user
+		expectedUser remote.User
+	}{
+		{
+			name:         "OK",
+			u:            giteaUser1,
+			expectedUser: remoteUser1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := toUser(tc.u)
+			assert.Equal(t, tc.expectedUser, user)
+		})
+	}
+}
+
+func TestToCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		c              commit
+		expectedCommit remote.Commit
+	}{
+		{
+			name:           "OK",
+			c:              giteaCommit1,
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commit := toCommit(tc.c)
+			assert.Equal(t, tc.expectedCommit, commit)
+		})
+	}
+}
+
+func TestToBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		b              branch
+		c              commit
+		expectedBranch remote.Branch
+	}{
+		{
+			name:           "OK",
+			b:              giteaBranch,
+			c:              giteaCommit2,
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			branch := toBranch(tc.b, tc.c)
+			assert.Equal(t, tc.expectedBranch, branch)
+		})
+	}
+}
+
+func TestToTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		t           tag
+		c           commit
+		webURL      string
+		owner, repo string
+		expectedTag remote.Tag
+	}{
+		{
+			name:        "OK",
+			t:           giteaTag,
+			c:           giteaCommit2,
+			webURL:      "https://gitea.com",
+			owner:       "octocat",
+			repo:        "Hello-World",
+			expectedTag: remoteTag,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tag := toTag(tc.t, tc.c, tc.webURL, tc.owner, tc.repo)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestToIssue(t *testing.T) {
+	tests := []struct {
+		name          string
+		i             issue
+		closer        user
+		expectedIssue remote.Issue
+	}{
+		{
+			name:          "OK",
+			i:             giteaIssue,
+			closer:        giteaUser1,
+			expectedIssue: remoteIssue,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issue := toIssue(tc.i, tc.closer)
+			assert.Equal(t, tc.expectedIssue, issue)
+		})
+	}
+}
+
+func TestToMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		p             pullRequest
+		c             commit
+		merger        user
+		expectedMerge remote.Merge
+	}{
+		{
+			name:          "OK",
+			p:             giteaPull,
+			c:             giteaCommit1,
+			merger:        giteaUser3,
+			expectedMerge: remoteMerge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merge := toMerge(tc.p, tc.c, tc.merger)
+			assert.Equal(t, tc.expectedMerge, merge)
+		})
+	}
+}
+
+func TestResolveTags(t *testing.T) {
+	tests := []struct {
+		name         string
+		giteaTags    *remote.Store
+		giteaCommits *remote.Store
+		webURL       string
+		owner, repo  string
+		expectedTags remote.Tags
+	}{
+		{
+			name: "OK",
+			giteaTags: remote.NewStoreFrom(map[interface{}]interface{}{
+				"v0.1.0": giteaTag,
+				"v0.0.1": tag{Name: "v0.0.1", Commit: commitMeta{SHA: "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378"}},
+			}),
+			giteaCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": giteaCommit2,
+			}),
+			webURL:       "https://gitea.com",
+			owner:        "octocat",
+			repo:         "Hello-World",
+			expectedTags: remote.Tags{remoteTag},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tags := resolveTags(tc.giteaTags, tc.giteaCommits, tc.webURL, tc.owner, tc.repo)
+			assert.Equal(t, tc.expectedTags, tags)
+		})
+	}
+}
+
+func TestResolveCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		giteaCommits    *remote.Store
+		expectedCommits remote.Commits
+	}{
+		{
+			name: "OK",
+			giteaCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": giteaCommit1,
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": giteaCommit2,
+			}),
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commits := resolveCommits(tc.giteaCommits)
+			assert.Equal(t, tc.expectedCommits, commits)
+		})
+	}
+}
+
+func TestResolveIssuesAndMerges(t *testing.T) {
+	giteaPullWithoutMerger := giteaPull
+	giteaPullWithoutMerger.MergedBy = nil
+
+	tests := []struct {
+		name           string
+		giteaIssues    *remote.Store
+		giteaPulls     *remote.Store
+		giteaEvents    *remote.Store
+		giteaCommits   *remote.Store
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+	}{
+		{
+			name: "OK",
+			giteaIssues: remote.NewStoreFrom(map[interface{}]interface{}{
+				1001: giteaIssue,
+			}),
+			giteaPulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: giteaPull,
+			}),
+			giteaEvents: remote.NewStoreFrom(map[interface{}]interface{}{
+				1001: giteaCloseEvent,
+			}),
+			giteaCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": giteaCommit1,
+			}),
+			expectedIssues: remote.Issues{remoteIssue},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+		{
+			name:        "MergerFromTimeline",
+			giteaIssues: remote.NewStore(),
+			giteaPulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: giteaPullWithoutMerger,
+			}),
+			giteaEvents: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: giteaMergeEvent,
+			}),
+			giteaCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": giteaCommit1,
+			}),
+			expectedIssues: remote.Issues{},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+		{
+			name: "UnknownCloserAndMerger",
+			giteaIssues: remote.NewStoreFrom(map[interface{}]interface{}{
+				1001: giteaIssue,
+			}),
+			giteaPulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: giteaPullWithoutMerger,
+			}),
+			giteaEvents: remote.NewStore(),
+			giteaCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": giteaCommit1,
+			}),
+			expectedIssues: remote.Issues{},
+			expectedMerges: remote.Merges{},
+		},
+		{
+			name:        "UnknownMergeCommit",
+			giteaIssues: remote.NewStore(),
+			giteaPulls: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: giteaPull,
+			}),
+			giteaEvents:    remote.NewStore(),
+			giteaCommits:   remote.NewStore(),
+			expectedIssues: remote.Issues{},
+			expectedMerges: remote.Merges{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issues, merges := resolveIssuesAndMerges(tc.giteaIssues, tc.giteaPulls, tc.giteaEvents, tc.giteaCommits)
+
+			assert.Equal(t, tc.expectedIssues, issues)
+			assert.Equal(t, tc.expectedMerges, merges)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -12,7 +14,12 @@ import (
 	"github.com/moorara/go-github"
 )
 
-const pageSize = 100
+const (
+	pageSize = 100
+
+	// maxConcurrency is the maximum number of goroutines fetching pages or resources concurrently for a single call.
+	maxConcurrency = 8
+)
 
 type (
 	githubService interface {
@@ -32,41 +39,100 @@ type (
 		Issues(context.Context, int, int, github.IssuesParams) ([]github.Issue, *github.Response, error)
 		Events(context.Context, int, int, int) ([]github.Event, *github.Response, error)
 	}
+
+	commitsService interface {
+		Commits(context.Context, string, int, int) ([]github.Commit, *github.Response, error)
+		Files(context.Context, string, int, int) ([]file, *github.Response, error)
+	}
+
+	graphqlService interface {
+		Issues(context.Context, int, string, time.Time) (*issueConnection, error)
+		PullRequests(context.Context, int, string) (*pullRequestConnection, error)
+	}
 )
 
+// group is an errgroup.Group running at most maxConcurrency goroutines at a time.
+type group struct {
+	*errgroup.Group
+	sem chan struct{}
+}
+
+func newGroup(ctx context.Context) (*group, context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	return &group{
+		Group: g,
+		sem:   make(chan struct{}, maxConcurrency),
+	}, ctx
+}
+
+// Go blocks until fewer than maxConcurrency goroutines are running and then calls the given function in a new goroutine.
+func (g *group) Go(f func() error) {
+	g.sem <- struct{}{}
+	g.Group.Go(func() error {
+		defer func() { <-g.sem }()
+		return f()
+	})
+}
+
 // repo implements the remote.Repo interface for GitHub.
 type repo struct {
-	logger log.Logger
-	owner  string
-	repo   string
-	stores struct {
-		users   *store
-		commits *store
+	logger    log.Logger
+	cache     *remote.Cache
+	transport http.RoundTripper
+	webURL    string
+	owner     string
+	repo      string
+	stores    struct {
+		users   *remote.Store
+		commits *remote.Store
 	}
 	services struct {
-		github githubService
-		users  usersService
-		repo   repoService
+		github  githubService
+		users   usersService
+		repo    repoService
+		commits commitsService
 	}
 }
 
 // NewRepo creates a new GitHub repository.
-func NewRepo(logger log.Logger, ownerName, repoName, accessToken string) remote.Repo {
-	client := github.NewClient(accessToken)
+// apiURL and webURL are the base URLs of the GitHub instance (i.e. https://api.github.com and https://github.com).
+// For GitHub Enterprise Server, they are http(s)://<hostname>/api/v3 and http(s)://<hostname> respectively.
+// cache is an optional on-disk cache for persisting commits, events, changed files, and API responses (revalidated using ETags) between runs.
+func NewRepo(logger log.Logger, cache *remote.Cache, apiURL, webURL, ownerName, repoName, accessToken string) (remote.Repo, error) {
+	r, err := newRepo(logger, cache, apiURL, webURL, ownerName, repoName, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func newRepo(logger log.Logger, cache *remote.Cache, apiURL, webURL, ownerName, repoName, accessToken string) (*repo, error) {
+	// The transport is shared by the REST and GraphQL clients, so they throttle requests and wait for rate limits together
+	transport := remote.NewTransport(logger, cache)
+
+	client, err := newRESTClient(transport, apiURL, accessToken, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
 
 	r := &repo{
-		logger: logger,
-		owner:  ownerName,
-		repo:   repoName,
+		logger:    logger,
+		cache:     cache,
+		transport: transport,
+		webURL:    strings.TrimSuffix(webURL, "/"),
+		owner:     ownerName,
+		repo:      repoName,
 	}
 
-	r.stores.users = newStore()
-	r.stores.commits = newStore()
+	r.stores.users = remote.NewStore()
+	r.stores.commits = remote.NewStore()
 	r.services.github = client
-	r.services.users = client.Users
-	r.services.repo = client.Repo(ownerName, repoName)
+	r.services.users = &usersClient{client}
+	r.services.repo = &repoClient{client}
+	r.services.commits = &commitsClient{client}
 
-	return r
+	return r, nil
 }
 
 func (r *repo) getUser(ctx context.Context, username string) (github.User, error) {
@@ -94,6 +160,13 @@ func (r *repo) getCommit(ctx context.Context, ref string) (github.Commit, error)
 		return c, nil
 	}
 
+	// Next, check the on-disk cache
+	// Commits are immutable, so they never need to be revalidated.
+	if c := (github.Commit{}); r.cache.Load("commits/"+ref, &c) {
+		r.stores.commits.Save(c.SHA, c)
+		return c, nil
+	}
+
 	c, _, err := r.services.repo.Commit(ctx, ref)
 	if err != nil {
 		return github.Commit{}, err
@@ -101,27 +174,115 @@ func (r *repo) getCommit(ctx context.Context, ref string) (github.Commit, error)
 
 	// Update the cache
 	r.stores.commits.Save(c.SHA, *c)
+	r.saveCache("commits/"+c.SHA, *c)
 
 	return *c, nil
 }
 
-func (r *repo) getParentCommits(ctx context.Context, ref string) (remote.Commits, error) {
-	commits := remote.Commits{}
+// cachedEvent is the event of an issue or pull request cached on disk.
+// UpdatedAt is the update time of the issue or pull request when the event was cached.
+type cachedEvent struct {
+	Name      string       `json:"name"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Event     github.Event `json:"event"`
+}
+
+// getEvent finds the first event with a given name for an issue or pull request.
+// The event found is cached on disk along with the update time of the issue or pull request.
+// The cached event is invalidated once the issue or pull request is updated.
+func (r *repo) getEvent(ctx context.Context, i github.Issue, name string) (github.Event, error) {
+	key := fmt.Sprintf("events/%d", i.Number)
 
-	c, err := r.getCommit(ctx, ref)
+	if ce := (cachedEvent{}); r.cache.Load(key, &ce) && ce.Name == name && ce.UpdatedAt.Equal(i.UpdatedAt) {
+		return ce.Event, nil
+	}
+
+	e, err := r.findEvent(ctx, i.Number, name)
 	if err != nil {
-		return nil, err
+		return github.Event{}, err
+	}
+
+	r.saveCache(key, cachedEvent{
+		Name:      name,
+		UpdatedAt: i.UpdatedAt,
+		Event:     e,
+	})
+
+	return e, nil
+}
+
+// saveCache writes an entry to the on-disk cache.
+// Failing to write to the cache is not fatal.
+func (r *repo) saveCache(key string, v interface{}) {
+	if err := r.cache.Save(key, v); err != nil {
+		r.logger.Warnf("Failed to cache %s: %s", key, err)
+	}
+}
+
+// loadCommit returns a commit from the cache or fetches it along with its ancestors.
+// Listing the commits of a revision returns the commit itself and its most recent ancestors,
+// so a walk over the commit graph makes one API call per page of commits instead of one API call per commit.
+func (r *repo) loadCommit(ctx context.Context, sha string) (github.Commit, error) {
+	// First, check the cache
+	if v, ok := r.stores.commits.Load(sha); ok {
+		c := v.(github.Commit)
+		return c, nil
+	}
+
+	// Next, check the on-disk cache
+	if c := (github.Commit{}); r.cache.Load("commits/"+sha, &c) {
+		r.stores.commits.Save(c.SHA, c)
+		return c, nil
+	}
+
+	commits, _, err := r.services.commits.Commits(ctx, sha, pageSize, 1)
+	if err != nil {
+		return github.Commit{}, err
+	}
+
+	if len(commits) == 0 {
+		return github.Commit{}, fmt.Errorf("GitHub commit not found: %s", sha)
 	}
 
-	commits = append(commits, toCommit(c))
+	// Update the cache
+	for _, c := range commits {
+		r.stores.commits.Save(c.SHA, c)
+		r.saveCache("commits/"+c.SHA, c)
+	}
 
-	for _, parent := range c.Parents {
-		parentCommits, err := r.getParentCommits(ctx, parent.SHA)
+	// The first commit is the revision itself
+	return commits[0], nil
+}
+
+// getParentCommits walks the commit graph from a given commit to the first commit iteratively.
+// Every commit is visited only once, so the ancestors shared by the parents of merge commits are not revisited.
+func (r *repo) getParentCommits(ctx context.Context, ref string) (remote.Commits, error) {
+	commits := remote.Commits{}
+	visited := map[string]bool{}
+
+	for stack := []string{ref}; len(stack) > 0; {
+		sha := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if visited[sha] {
+			continue
+		}
+
+		c, err := r.loadCommit(ctx, sha)
 		if err != nil {
 			return nil, err
 		}
 
-		commits = append(commits, parentCommits...)
+		visited[sha] = true
+		visited[c.SHA] = true
+		commits = append(commits, toCommit(c))
+
+		// Push the parents in reverse order, so the first parent is visited first
+		for i := len(c.Parents) - 1; i >= 0; i-- {
+			if p := c.Parents[i].SHA; !visited[p] {
+				stack = append(stack, p)
+			}
+		}
 	}
 
 	return commits, nil
@@ -153,13 +314,18 @@ func (r *repo) FutureTag(name string) remote.Tag {
 	return remote.Tag{
 		Name:   name,
 		Time:   time.Now(),
-		WebURL: fmt.Sprintf("https://github.com/%s/%s/tree/%s", r.owner, r.repo, name),
+		WebURL: fmt.Sprintf("%s/%s/%s/tree/%s", r.webURL, r.owner, r.repo, name),
 	}
 }
 
 // CompareURL returns a URL for comparing two revisions for a GitHub repository.
 func (r *repo) CompareURL(base, head string) string {
-	return fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s", r.owner, r.repo, base, head)
+	return fmt.Sprintf("%s/%s/%s/compare/%s...%s", r.webURL, r.owner, r.repo, base, head)
+}
+
+// CommitURL returns the web URL of a commit for a GitHub repository.
+func (r *repo) CommitURL(hash string) string {
+	return fmt.Sprintf("%s/%s/%s/commit/%s", r.webURL, r.owner, r.repo, hash)
 }
 
 // CheckPermissions ensures the client has all the required permissions for a GitHub repository.
@@ -246,7 +412,7 @@ func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
 
 	// ==============================> FETCH TAGS <==============================
 
-	tagStore := newStore()
+	tagStore := remote.NewStore()
 
 	// Fetch tags
 	r.logger.Debug("Fetched GitHub tags page 1 ...")
@@ -258,7 +424,7 @@ func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
 		tagStore.Save(t.Name, t)
 	}
 
-	g1, ctx1 := errgroup.WithContext(ctx)
+	g1, ctx1 := newGroup(ctx)
 
 	// Fetch more tags if any
 	for p := 2; p <= resp.Pages.Last; p++ {
@@ -284,7 +450,7 @@ func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
 
 	r.logger.Debug("Fetching GitHub commits for tags ...")
 
-	g2, ctx2 := errgroup.WithContext(ctx)
+	g2, ctx2 := newGroup(ctx)
 
 	// Fetch commits for tags
 	_ = tagStore.ForEach(func(_, v interface{}) error {
@@ -302,7 +468,7 @@ func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
 
 	// ==============================> JOINING TAGS & COMMITS <==============================
 
-	tags := resolveTags(tagStore, r.stores.commits, r.owner, r.repo)
+	tags := resolveTags(tagStore, r.stores.commits, r.webURL, r.owner, r.repo)
 
 	r.logger.Debugf("GitHub tags are fetched: %d", len(tags))
 
@@ -319,7 +485,7 @@ func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remot
 
 	// ==============================> FETCH ISSUES <==============================
 
-	issueStore := newStore()
+	issueStore := remote.NewStore()
 	opts := github.IssuesParams{
 		State: "closed",
 		Since: since,
@@ -335,7 +501,7 @@ func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remot
 		issueStore.Save(i.Number, i)
 	}
 
-	g1, ctx1 := errgroup.WithContext(ctx)
+	g1, ctx1 := newGroup(ctx)
 
 	// Fetch more closed issues if any
 	for p := 2; p <= resp.Pages.Last; p++ {
@@ -363,9 +529,9 @@ func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remot
 
 	r.logger.Debug("Fetching GitHub events and commits for issues and pull requests ...")
 
-	eventStore := newStore()
+	eventStore := remote.NewStore()
 
-	g2, ctx2 := errgroup.WithContext(ctx)
+	g2, ctx2 := newGroup(ctx)
 
 	// Fetch and search events
 	_ = issueStore.ForEach(func(k, v interface{}) error {
@@ -375,7 +541,7 @@ func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remot
 		g2.Go(func() error {
 			// Issue
 			if issue.PullURLs == nil {
-				e, err := r.findEvent(ctx2, num, "closed")
+				e, err := r.getEvent(ctx2, issue, "closed")
 				if err != nil {
 					return err
 				}
@@ -384,7 +550,7 @@ func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remot
 			}
 
 			// Pull Request
-			e, err := r.findEvent(ctx2, num, "merged")
+			e, err := r.getEvent(ctx2, issue, "merged")
 			if err != nil {
 				return err
 			}
@@ -464,3 +630,136 @@ func (r *repo) FetchParentCommits(ctx context.Context, ref string) (remote.Commi
 
 	return commits, nil
 }
+
+// FetchCommitFiles retrieves the paths of files changed by a commit for a GitHub repository.
+// Renamed files are changed in both their previous and current paths.
+func (r *repo) FetchCommitFiles(ctx context.Context, ref string) ([]string, error) {
+	// Commits are immutable, so the files changed by them never need to be revalidated.
+	key := "files/" + ref
+	if files := []string{}; r.cache.Load(key, &files) {
+		return files, nil
+	}
+
+	r.logger.Debugf("Fetching GitHub files changed by %s ...", ref)
+
+	files := []string{}
+	for pageNo := 1; pageNo > 0; {
+		page, resp, err := r.services.commits.Files(ctx, ref, pageSize, pageNo)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range page {
+			files = append(files, f.Filename)
+			if f.PreviousFilename != "" {
+				files = append(files, f.PreviousFilename)
+			}
+		}
+
+		pageNo = resp.Pages.Next
+	}
+
+	r.saveCache(key, files)
+
+	r.logger.Debugf("GitHub files changed by %s are fetched: %d", ref, len(files))
+
+	return files, nil
+}
+
+// graphqlRepo implements the remote.Repo interface for GitHub using the GraphQL API v4 for issues and pull requests.
+// Closed issues and merged pull requests are fetched with their labels, milestones, authors, closers/mergers, and merge commits
+// in paged queries instead of making one REST API call per issue, pull request, and user.
+type graphqlRepo struct {
+	*repo
+	graphql graphqlService
+}
+
+// NewGraphQLRepo creates a new GitHub repository fetching issues and pull requests using the GraphQL API v4.
+// apiURL is the base URL of the REST API, and the GraphQL API endpoint is derived from it
+// (i.e. https://api.github.com/graphql for github.com and http(s)://<hostname>/api/graphql for GitHub Enterprise Server).
+func NewGraphQLRepo(logger log.Logger, cache *remote.Cache, apiURL, webURL, ownerName, repoName, accessToken string) (remote.Repo, error) {
+	r, err := newRepo(logger, cache, apiURL, webURL, ownerName, repoName, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphqlRepo{
+		repo:    r,
+		graphql: newGraphQLClient(r.transport, graphqlURL(apiURL), accessToken, ownerName, repoName),
+	}, nil
+}
+
+// FetchIssuesAndMerges retrieves all closed issues and merged pull requests for a GitHub repository using the GraphQL API.
+func (r *graphqlRepo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
+	if since.IsZero() {
+		r.logger.Info("Fetching GitHub issues and pull requests since the beginning ...")
+	} else {
+		r.logger.Infof("Fetching GitHub issues and pull requests since %s ...", since.Format(time.RFC3339))
+	}
+
+	issues := remote.Issues{}
+	merges := remote.Merges{}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// ==============================> FETCH ISSUES <==============================
+
+	g.Go(func() error {
+		for p, after := 1, ""; ; p++ {
+			r.logger.Debugf("Fetching GitHub issues page %d ...", p)
+			conn, err := r.graphql.Issues(ctx, pageSize, after, since)
+			if err != nil {
+				return err
+			}
+
+			for _, i := range conn.Nodes {
+				issues = append(issues, toIssueFromNode(i))
+			}
+
+			if !conn.PageInfo.HasNextPage {
+				return nil
+			}
+			after = conn.PageInfo.EndCursor
+		}
+	})
+
+	// ==============================> FETCH PULL REQUESTS <==============================
+
+	g.Go(func() error {
+		for p, after := 1, ""; ; p++ {
+			r.logger.Debugf("Fetching GitHub pull requests page %d ...", p)
+			conn, err := r.graphql.PullRequests(ctx, pageSize, after)
+			if err != nil {
+				return err
+			}
+
+			for _, pr := range conn.Nodes {
+				// Pull requests are sorted by update time, so the remaining ones are not updated since the given time
+				if !since.IsZero() && pr.UpdatedAt.Before(since) {
+					return nil
+				}
+
+				// The merge commit is not available if the base branch is deleted
+				if pr.MergeCommit != nil {
+					merges = append(merges, toMergeFromNode(pr))
+				}
+			}
+
+			if !conn.PageInfo.HasNextPage {
+				return nil
+			}
+			after = conn.PageInfo.EndCursor
+		}
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	issues = issues.Sort()
+	merges = merges.Sort()
+
+	r.logger.Infof("All GitHub issues (%d) and pull requests (%d) are fetched", len(issues), len(merges))
+
+	return issues, merges, nil
+}
@@ -2,7 +2,12 @@ package github
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,45 +20,112 @@ import (
 
 func TestNewRepo(t *testing.T) {
 	tests := []struct {
-		name        string
-		logger      log.Logger
-		ownerName   string
-		repoName    string
-		accessToken string
+		name          string
+		logger        log.Logger
+		apiURL        string
+		webURL        string
+		ownerName     string
+		repoName      string
+		accessToken   string
+		expectedWeb   string
+		expectedError string
 	}{
+		{
+			name:          "InvalidAPIURL",
+			logger:        log.New(log.None),
+			apiURL:        ":invalid",
+			webURL:        "https://github.com",
+			ownerName:     "moorara",
+			repoName:      "changelog",
+			accessToken:   "github-access-token",
+			expectedError: `parse ":invalid": missing protocol scheme`,
+		},
 		{
 			name:        "OK",
 			logger:      log.New(log.None),
+			apiURL:      "https://api.github.com",
+			webURL:      "https://github.com",
+			ownerName:   "moorara",
+			repoName:    "changelog",
+			accessToken: "github-access-token",
+			expectedWeb: "https://github.com",
+		},
+		{
+			name:        "Enterprise",
+			logger:      log.New(log.None),
+			apiURL:      "https://github.example.com/api/v3/",
+			webURL:      "https://github.example.com/",
 			ownerName:   "moorara",
 			repoName:    "changelog",
 			accessToken: "github-access-token",
+			expectedWeb: "https://github.example.com",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			r := NewRepo(tc.logger, tc.ownerName, tc.repoName, tc.accessToken)
+			r, err := NewRepo(tc.logger, nil, tc.apiURL, tc.webURL, tc.ownerName, tc.repoName, tc.accessToken)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, r)
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
 			assert.NotNil(t, r)
 
 			gr, ok := r.(*repo)
 			assert.True(t, ok)
 
 			assert.Equal(t, tc.logger, gr.logger)
+			assert.Equal(t, tc.expectedWeb, gr.webURL)
 			assert.Equal(t, tc.ownerName, gr.owner)
 			assert.Equal(t, tc.repoName, gr.repo)
+			assert.NotNil(t, gr.transport)
 			assert.NotNil(t, gr.stores.users)
 			assert.NotNil(t, gr.stores.commits)
 			assert.NotNil(t, gr.services.github)
 			assert.NotNil(t, gr.services.users)
 			assert.NotNil(t, gr.services.repo)
+			assert.NotNil(t, gr.services.commits)
 		})
 	}
 }
 
+func TestGroup(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+
+	g, _ := newGroup(context.Background())
+
+	for i := 0; i < 4*maxConcurrency; i++ {
+		g.Go(func() error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	assert.NoError(t, g.Wait())
+	assert.LessOrEqual(t, maxRunning, maxConcurrency)
+}
+
 func TestRepo_getUser(t *testing.T) {
 	tests := []struct {
 		name          string
-		usersStore    *store
+		usersStore    *remote.Store
 		usersService  *MockUsersService
 		ctx           context.Context
 		username      string
@@ -62,21 +134,17 @@ func TestRepo_getUser(t *testing.T) {
 	}{
 		{
 			name: "CacheHit",
-			usersStore: &store{
-				m: map[interface{}]interface{}{
-					"octocat": gitHubUser1,
-				},
-			},
+			usersStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"octocat": gitHubUser1,
+			}),
 			usersService: nil,
 			ctx:          context.Background(),
 			username:     "octocat",
 			expectedUser: gitHubUser1,
 		},
 		{
-			name: "Error",
-			usersStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:       "Error",
+			usersStore: remote.NewStore(),
 			usersService: &MockUsersService{
 				GetMocks: []GetUserMock{
 					{OutError: errors.New("error on getting github user")},
@@ -87,10 +155,8 @@ func TestRepo_getUser(t *testing.T) {
 			expectedError: "error on getting github user",
 		},
 		{
-			name: "Success",
-			usersStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:       "Success",
+			usersStore: remote.NewStore(),
 			usersService: &MockUsersService{
 				GetMocks: []GetUserMock{
 					{OutUser: &gitHubUser1, OutResponse: &github.Response{}},
@@ -121,10 +187,46 @@ func TestRepo_getUser(t *testing.T) {
 	}
 }
 
+func TestRepo_getUser_Cache(t *testing.T) {
+	notModified := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/users/octocat", r.URL.Path)
+
+		if r.Header.Get("If-None-Match") == `"644b5b0155e6404a9cc4bd9d8b1ae730"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"644b5b0155e6404a9cc4bd9d8b1ae730"`)
+		_ = json.NewEncoder(w).Encode(gitHubUser1)
+	}))
+	defer ts.Close()
+
+	cache := remote.NewCache(t.TempDir(), "github.example.com", "octocat/Hello-World")
+
+	// Every run has its own repository and shares the on-disk cache
+	for i := 0; i < 2; i++ {
+		r, err := newRepo(log.New(log.None), cache, ts.URL+"/api/v3", "https://github.example.com", "octocat", "Hello-World", "github-access-token")
+		assert.NoError(t, err)
+
+		user, err := r.getUser(context.Background(), "octocat")
+		assert.NoError(t, err)
+		assert.Equal(t, gitHubUser1, user)
+	}
+
+	assert.Equal(t, 1, notModified)
+}
+
 func TestRepo_getCommit(t *testing.T) {
+	cache := remote.NewCache(t.TempDir(), "github.com", "octocat/Hello-World")
+	assert.NoError(t, cache.Save("commits/6dcb09b5b57875f334f61aebed695e2e4193db5e", gitHubCommit1))
+
 	tests := []struct {
 		name           string
-		commitsStore   *store
+		cache          *remote.Cache
+		commitsStore   *remote.Store
 		repoService    *MockRepoService
 		ctx            context.Context
 		ref            string
@@ -133,21 +235,26 @@ func TestRepo_getCommit(t *testing.T) {
 	}{
 		{
 			name: "CacheHit",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{
-					"6dcb09b5b57875f334f61aebed695e2e4193db5e": gitHubCommit1,
-				},
-			},
+			commitsStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": gitHubCommit1,
+			}),
 			repoService:    nil,
 			ctx:            context.Background(),
 			ref:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
 			expectedCommit: gitHubCommit1,
 		},
 		{
-			name: "Error",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:           "DiskCacheHit",
+			cache:          cache,
+			commitsStore:   remote.NewStore(),
+			repoService:    nil,
+			ctx:            context.Background(),
+			ref:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: gitHubCommit1,
+		},
+		{
+			name:         "Error",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				CommitMocks: []CommitMock{
 					{OutError: errors.New("error on getting github commit")},
@@ -158,10 +265,8 @@ func TestRepo_getCommit(t *testing.T) {
 			expectedError: "error on getting github commit",
 		},
 		{
-			name: "Success",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "Success",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				CommitMocks: []CommitMock{
 					{OutCommit: &gitHubCommit1, OutResponse: &github.Response{}},
@@ -175,7 +280,10 @@ func TestRepo_getCommit(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			r := &repo{logger: log.New(log.None)}
+			r := &repo{
+				logger: log.New(log.None),
+				cache:  tc.cache,
+			}
 			r.stores.commits = tc.commitsStore
 			r.services.repo = tc.repoService
 
@@ -192,67 +300,190 @@ func TestRepo_getCommit(t *testing.T) {
 	}
 }
 
+func TestRepo_loadCommit(t *testing.T) {
+	cache := remote.NewCache(t.TempDir(), "github.com", "octocat/Hello-World")
+	assert.NoError(t, cache.Save("commits/6dcb09b5b57875f334f61aebed695e2e4193db5e", gitHubCommit1))
+
+	tests := []struct {
+		name           string
+		cache          *remote.Cache
+		commitsStore   *remote.Store
+		commitsService *MockCommitsService
+		ctx            context.Context
+		sha            string
+		expectedCommit github.Commit
+		expectedError  string
+	}{
+		{
+			name: "CacheHit",
+			commitsStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": gitHubCommit2,
+			}),
+			commitsService: nil,
+			ctx:            context.Background(),
+			sha:            "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedCommit: gitHubCommit2,
+		},
+		{
+			name:           "DiskCacheHit",
+			cache:          cache,
+			commitsStore:   remote.NewStore(),
+			commitsService: nil,
+			ctx:            context.Background(),
+			sha:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: gitHubCommit1,
+		},
+		{
+			name:         "CommitsFails",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					{OutError: errors.New("error on listing github commits")},
+				},
+			},
+			ctx:           context.Background(),
+			sha:           "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: "error on listing github commits",
+		},
+		{
+			name:         "NotFound",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					{OutCommits: []github.Commit{}, OutResponse: &github.Response{}},
+				},
+			},
+			ctx:           context.Background(),
+			sha:           "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: "GitHub commit not found: c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		},
+		{
+			name:         "Success",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					{OutCommits: []github.Commit{gitHubCommit2, gitHubCommit1}, OutResponse: &github.Response{}},
+				},
+			},
+			ctx:            context.Background(),
+			sha:            "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedCommit: gitHubCommit2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger: log.New(log.None),
+				cache:  tc.cache,
+			}
+			r.stores.commits = tc.commitsStore
+			r.services.commits = tc.commitsService
+
+			c, err := r.loadCommit(tc.ctx, tc.sha)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, c)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, c)
+				v, ok := tc.commitsStore.Load(tc.sha)
+				assert.True(t, ok)
+				assert.Equal(t, tc.expectedCommit, v)
+			}
+
+			if tc.commitsService != nil {
+				assert.Equal(t, tc.sha, tc.commitsService.CommitsMocks[0].InSHA)
+			}
+		})
+	}
+}
+
 func TestRepo_getParentCommits(t *testing.T) {
+	// A merge-heavy commit graph where c4 merges c3 into c2 and both of them have c1 as their parent:
+	//
+	//   c1 --- c2 --- c4
+	//     \          /
+	//      `-- c3 --'
+	//
+	parent := func(sha string) github.Hash {
+		return github.Hash{SHA: sha}
+	}
+
+	c1 := github.Commit{SHA: "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378", Commit: gitHubCommit1.Commit}
+	c2 := github.Commit{SHA: "0251a422d2038967eeaaaa5c8aa76c7067fdef05", Commit: gitHubCommit1.Commit, Parents: []github.Hash{parent(c1.SHA)}}
+	c3 := github.Commit{SHA: "c414d1004154c6c324bd78c69d10ee101e676059", Commit: gitHubCommit1.Commit, Parents: []github.Hash{parent(c1.SHA)}}
+	c4 := github.Commit{SHA: "20c5414eccaa147f2d6644de4ca36f35293fa43e", Commit: gitHubCommit2.Commit, Parents: []github.Hash{parent(c2.SHA), parent(c3.SHA)}}
+
 	tests := []struct {
 		name            string
-		commitsStore    *store
-		repoService     *MockRepoService
+		commitsStore    *remote.Store
+		commitsService  *MockCommitsService
 		ctx             context.Context
 		ref             string
 		expectedCommits remote.Commits
 		expectedError   string
 	}{
 		{
-			name: "CommitFails_1",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			repoService: &MockRepoService{
-				CommitMocks: []CommitMock{
-					{OutError: errors.New("error on getting github commit")},
+			name:         "CommitsFails_First",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					{OutError: errors.New("error on listing github commits")},
 				},
 			},
 			ctx:           context.Background(),
 			ref:           "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
-			expectedError: "error on getting github commit",
+			expectedError: "error on listing github commits",
 		},
 		{
-			name: "CommitFails_2",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			repoService: &MockRepoService{
-				CommitMocks: []CommitMock{
-					{OutCommit: &gitHubCommit2, OutResponse: &github.Response{}},
-					{OutError: errors.New("error on getting github commit")},
+			name:         "CommitsFails_Second",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					{OutCommits: []github.Commit{gitHubCommit2}, OutResponse: &github.Response{}},
+					{OutError: errors.New("error on listing github commits")},
 				},
 			},
 			ctx:           context.Background(),
 			ref:           "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
-			expectedError: "error on getting github commit",
+			expectedError: "error on listing github commits",
 		},
 		{
-			name: "Success",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			repoService: &MockRepoService{
-				CommitMocks: []CommitMock{
-					{OutCommit: &gitHubCommit2, OutResponse: &github.Response{}},
-					{OutCommit: &gitHubCommit1, OutResponse: &github.Response{}},
+			name:         "Success",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					{OutCommits: []github.Commit{gitHubCommit2, gitHubCommit1}, OutResponse: &github.Response{}},
 				},
 			},
 			ctx:             context.Background(),
 			ref:             "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
 			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
 		},
+		{
+			name:         "SharedAncestors",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					// The first page does not have all ancestors
+					{OutCommits: []github.Commit{c4, c2}, OutResponse: &github.Response{}},
+					{OutCommits: []github.Commit{c1}, OutResponse: &github.Response{}},
+					{OutCommits: []github.Commit{c3, c1}, OutResponse: &github.Response{}},
+				},
+			},
+			ctx:             context.Background(),
+			ref:             c4.SHA,
+			expectedCommits: remote.Commits{toCommit(c4), toCommit(c2), toCommit(c1), toCommit(c3)},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			r := &repo{logger: log.New(log.None)}
 			r.stores.commits = tc.commitsStore
-			r.services.repo = tc.repoService
+			r.services.commits = tc.commitsService
 
 			commits, err := r.getParentCommits(tc.ctx, tc.ref)
 
@@ -262,6 +493,8 @@ func TestRepo_getParentCommits(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tc.expectedCommits, commits)
+				// Every page of commits is fetched only once
+				assert.Equal(t, len(tc.commitsService.CommitsMocks), tc.commitsService.CommitsIndex)
 			}
 		})
 	}
@@ -372,9 +605,101 @@ func TestRepo_findEvent(t *testing.T) {
 	}
 }
 
+func TestRepo_getEvent(t *testing.T) {
+	cached := cachedEvent{
+		Name:      "closed",
+		UpdatedAt: gitHubIssue1.UpdatedAt,
+		Event:     gitHubEvent1,
+	}
+
+	updatedIssue := gitHubIssue1
+	updatedIssue.UpdatedAt = updatedIssue.UpdatedAt.Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		cachedEvent   *cachedEvent
+		repoService   *MockRepoService
+		ctx           context.Context
+		issue         github.Issue
+		eventName     string
+		expectedEvent github.Event
+		expectedError string
+	}{
+		{
+			name:          "CacheHit",
+			cachedEvent:   &cached,
+			repoService:   nil,
+			ctx:           context.Background(),
+			issue:         gitHubIssue1,
+			eventName:     "closed",
+			expectedEvent: gitHubEvent1,
+		},
+		{
+			name:        "CacheInvalidated",
+			cachedEvent: &cached,
+			repoService: &MockRepoService{
+				EventsMocks: []EventsMock{
+					{OutError: errors.New("error on getting github events")},
+				},
+			},
+			ctx:           context.Background(),
+			issue:         updatedIssue,
+			eventName:     "closed",
+			expectedError: "error on getting github events",
+		},
+		{
+			name: "CacheMiss",
+			repoService: &MockRepoService{
+				EventsMocks: []EventsMock{
+					{
+						OutEvents:   []github.Event{gitHubEvent1},
+						OutResponse: &github.Response{},
+					},
+				},
+			},
+			ctx:           context.Background(),
+			issue:         gitHubIssue1,
+			eventName:     "closed",
+			expectedEvent: gitHubEvent1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := remote.NewCache(t.TempDir(), "github.com", "octocat/Hello-World")
+			if tc.cachedEvent != nil {
+				assert.NoError(t, cache.Save("events/1001", tc.cachedEvent))
+			}
+
+			r := &repo{
+				logger: log.New(log.None),
+				cache:  cache,
+			}
+			r.services.repo = tc.repoService
+
+			event, err := r.getEvent(tc.ctx, tc.issue, tc.eventName)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, event)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedEvent, event)
+
+				// The event should be cached for the next run
+				ce := cachedEvent{}
+				assert.True(t, cache.Load("events/1001", &ce))
+				assert.Equal(t, tc.issue.UpdatedAt, ce.UpdatedAt)
+				assert.Equal(t, tc.expectedEvent, ce.Event)
+			}
+		})
+	}
+}
+
 func TestRepo_FutureTag(t *testing.T) {
 	tests := []struct {
 		name            string
+		webURL          string
 		owner           string
 		repo            string
 		tagName         string
@@ -383,18 +708,29 @@ func TestRepo_FutureTag(t *testing.T) {
 	}{
 		{
 			name:            "OK",
+			webURL:          "https://github.com",
 			owner:           "octocat",
 			repo:            "Hello-World",
 			tagName:         "v0.1.1",
 			expectedTagName: "v0.1.1",
 			expectedTagURL:  "https://github.com/octocat/Hello-World/tree/v0.1.1",
 		},
+		{
+			name:            "Enterprise",
+			webURL:          "https://github.example.com",
+			owner:           "octocat",
+			repo:            "Hello-World",
+			tagName:         "v0.1.1",
+			expectedTagName: "v0.1.1",
+			expectedTagURL:  "https://github.example.com/octocat/Hello-World/tree/v0.1.1",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			r := &repo{
 				logger: log.New(log.None),
+				webURL: tc.webURL,
 				owner:  tc.owner,
 				repo:   tc.repo,
 			}
@@ -412,6 +748,7 @@ func TestRepo_FutureTag(t *testing.T) {
 func TestRepo_CompareURL(t *testing.T) {
 	tests := []struct {
 		name        string
+		webURL      string
 		owner       string
 		repo        string
 		base        string
@@ -420,18 +757,29 @@ func TestRepo_CompareURL(t *testing.T) {
 	}{
 		{
 			name:        "OK",
+			webURL:      "https://github.com",
 			owner:       "octocat",
 			repo:        "Hello-World",
 			base:        "v0.1.1",
 			head:        "v0.1.2",
 			expectedURL: "https://github.com/octocat/Hello-World/compare/v0.1.1...v0.1.2",
 		},
+		{
+			name:        "Enterprise",
+			webURL:      "https://github.example.com",
+			owner:       "octocat",
+			repo:        "Hello-World",
+			base:        "v0.1.1",
+			head:        "v0.1.2",
+			expectedURL: "https://github.example.com/octocat/Hello-World/compare/v0.1.1...v0.1.2",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			r := &repo{
 				logger: log.New(log.None),
+				webURL: tc.webURL,
 				owner:  tc.owner,
 				repo:   tc.repo,
 			}
@@ -443,6 +791,40 @@ func TestRepo_CompareURL(t *testing.T) {
 	}
 }
 
+func TestRepo_CommitURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		webURL      string
+		expectedURL string
+	}{
+		{
+			name:        "OK",
+			webURL:      "https://github.com",
+			expectedURL: "https://github.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+		{
+			name:        "Enterprise",
+			webURL:      "https://github.example.com",
+			expectedURL: "https://github.example.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger: log.New(log.None),
+				webURL: tc.webURL,
+				owner:  "octocat",
+				repo:   "Hello-World",
+			}
+
+			url := r.CommitURL("6dcb09b5b57875f334f61aebed695e2e4193db5e")
+
+			assert.Equal(t, tc.expectedURL, url)
+		})
+	}
+}
+
 func TestRepo_CheckPermissions(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -491,17 +873,15 @@ func TestRepo_CheckPermissions(t *testing.T) {
 func TestRepo_FetchFirstCommit(t *testing.T) {
 	tests := []struct {
 		name           string
-		commitsStore   *store
+		commitsStore   *remote.Store
 		repoService    *MockRepoService
 		ctx            context.Context
 		expectedCommit remote.Commit
 		expectedError  string
 	}{
 		{
-			name: "Error",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "Error",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				CommitsMocks: []CommitsMock{
 					{OutError: errors.New("error on getting github commits")},
@@ -511,10 +891,8 @@ func TestRepo_FetchFirstCommit(t *testing.T) {
 			expectedError: "error on getting github commits",
 		},
 		{
-			name: "Success_OnePage",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "Success_OnePage",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				CommitsMocks: []CommitsMock{
 					{
@@ -527,10 +905,8 @@ func TestRepo_FetchFirstCommit(t *testing.T) {
 			expectedCommit: remoteCommit1,
 		},
 		{
-			name: "Success_TwoPages",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "Success_TwoPages",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				CommitsMocks: []CommitsMock{
 					{
@@ -691,19 +1067,17 @@ func TestRepo_FetchTags(t *testing.T) {
 		name          string
 		owner         string
 		repo          string
-		commitsStore  *store
+		commitsStore  *remote.Store
 		repoService   *MockRepoService
 		ctx           context.Context
 		expectedTags  remote.Tags
 		expectedError string
 	}{
 		{
-			name:  "TagsFails_FirstPage",
-			owner: "octocat",
-			repo:  "Hello-World",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "TagsFails_FirstPage",
+			owner:        "octocat",
+			repo:         "Hello-World",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				TagsMocks: []TagsMock{
 					{OutError: errors.New("error on getting github tags")},
@@ -713,12 +1087,10 @@ func TestRepo_FetchTags(t *testing.T) {
 			expectedError: "error on getting github tags",
 		},
 		{
-			name:  "TagsFails_SecondPage",
-			owner: "octocat",
-			repo:  "Hello-World",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "TagsFails_SecondPage",
+			owner:        "octocat",
+			repo:         "Hello-World",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				TagsMocks: []TagsMock{
 					{
@@ -734,12 +1106,10 @@ func TestRepo_FetchTags(t *testing.T) {
 			expectedError: "error on getting github tags",
 		},
 		{
-			name:  "CommitFails",
-			owner: "octocat",
-			repo:  "Hello-World",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "CommitFails",
+			owner:        "octocat",
+			repo:         "Hello-World",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				TagsMocks: []TagsMock{
 					{
@@ -763,12 +1133,10 @@ func TestRepo_FetchTags(t *testing.T) {
 			expectedError: "error on getting github commits",
 		},
 		{
-			name:  "Success",
-			owner: "octocat",
-			repo:  "Hello-World",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "Success",
+			owner:        "octocat",
+			repo:         "Hello-World",
+			commitsStore: remote.NewStore(),
 			repoService: &MockRepoService{
 				TagsMocks: []TagsMock{
 					{
@@ -797,6 +1165,7 @@ func TestRepo_FetchTags(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			r := &repo{
 				logger: log.New(log.None),
+				webURL: "https://github.com",
 				owner:  tc.owner,
 				repo:   tc.repo,
 			}
@@ -817,13 +1186,96 @@ func TestRepo_FetchTags(t *testing.T) {
 	}
 }
 
+func TestRepo_FetchTags_RateLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		responses     []func(http.ResponseWriter)
+		expectedCalls int
+		expectedTags  remote.Tags
+		expectedError string
+	}{
+		{
+			name: "RetryAfterReset",
+			responses: []func(http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Limit", "5000")
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+				},
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+					_, _ = w.Write([]byte(`{"message": "Too Many Requests"}`))
+				},
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Limit", "5000")
+					w.Header().Set("X-RateLimit-Remaining", "4999")
+					_ = json.NewEncoder(w).Encode([]github.Tag{gitHubTag})
+				},
+			},
+			expectedCalls: 3,
+			expectedTags:  remote.Tags{remoteTag},
+		},
+		{
+			name: "ResetTooFar",
+			responses: []func(http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Limit", "5000")
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+				},
+			},
+			expectedCalls: 1,
+			expectedError: "API rate limit exceeded: the rate limit resets in",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v3/repos/octocat/Hello-World/tags":
+					tc.responses[calls](w)
+					calls++
+				case "/api/v3/repos/octocat/Hello-World/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c":
+					_ = json.NewEncoder(w).Encode(gitHubCommit2)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer ts.Close()
+
+			r, err := newRepo(log.New(log.None), nil, ts.URL+"/api/v3", "https://github.com", "octocat", "Hello-World", "github-access-token")
+			assert.NoError(t, err)
+
+			tags, err := r.FetchTags(context.Background())
+
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedError != "" {
+				assert.Empty(t, tags)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTags, tags)
+			}
+		})
+	}
+}
+
 func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 	since, _ := time.Parse(time.RFC3339, "2020-10-20T22:30:00-04:00")
 
 	tests := []struct {
 		name           string
-		usersStore     *store
-		commitsStore   *store
+		usersStore     *remote.Store
+		commitsStore   *remote.Store
 		usersService   *MockUsersService
 		repoService    *MockRepoService
 		ctx            context.Context
@@ -833,13 +1285,9 @@ func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 		expectedError  string
 	}{
 		{
-			name: "IssuesFails_FirstPage",
-			usersStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "IssuesFails_FirstPage",
+			usersStore:   remote.NewStore(),
+			commitsStore: remote.NewStore(),
 			usersService: &MockUsersService{},
 			repoService: &MockRepoService{
 				IssuesMocks: []IssuesMock{
@@ -851,13 +1299,9 @@ func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 			expectedError: "error on getting github issues",
 		},
 		{
-			name: "IssuesFails_Second",
-			usersStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "IssuesFails_Second",
+			usersStore:   remote.NewStore(),
+			commitsStore: remote.NewStore(),
 			usersService: &MockUsersService{},
 			repoService: &MockRepoService{
 				IssuesMocks: []IssuesMock{
@@ -875,13 +1319,9 @@ func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 			expectedError: "error on getting github issues",
 		},
 		{
-			name: "EventsFail",
-			usersStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "EventsFail",
+			usersStore:   remote.NewStore(),
+			commitsStore: remote.NewStore(),
 			usersService: &MockUsersService{},
 			repoService: &MockRepoService{
 				IssuesMocks: []IssuesMock{
@@ -908,13 +1348,9 @@ func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 			expectedError: "error on getting github events",
 		},
 		{
-			name: "CommitFails",
-			usersStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "CommitFails",
+			usersStore:   remote.NewStore(),
+			commitsStore: remote.NewStore(),
 			usersService: &MockUsersService{},
 			repoService: &MockRepoService{
 				IssuesMocks: []IssuesMock{
@@ -945,13 +1381,9 @@ func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 			expectedError: "error on getting github commit",
 		},
 		{
-			name: "UserFails_Author",
-			usersStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			name:         "UserFails_Author",
+			usersStore:   remote.NewStore(),
+			commitsStore: remote.NewStore(),
 			usersService: &MockUsersService{
 				GetMocks: []GetUserMock{
 					{OutError: errors.New("error on getting github user")},
@@ -987,15 +1419,11 @@ func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 		},
 		{
 			name: "UserFails_Merger",
-			usersStore: &store{
-				m: map[interface{}]interface{}{
-					"octocat": gitHubUser1,
-					"octodog": gitHubUser2,
-				},
-			},
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			usersStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"octocat": gitHubUser1,
+				"octodog": gitHubUser2,
+			}),
+			commitsStore: remote.NewStore(),
 			usersService: &MockUsersService{
 				GetMocks: []GetUserMock{
 					{OutError: errors.New("error on getting github user")},
@@ -1031,16 +1459,12 @@ func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 		},
 		{
 			name: "Success",
-			usersStore: &store{
-				m: map[interface{}]interface{}{
-					"octocat": gitHubUser1,
-					"octodog": gitHubUser2,
-					"octofox": gitHubUser3,
-				},
-			},
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
+			usersStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"octocat": gitHubUser1,
+				"octodog": gitHubUser2,
+				"octofox": gitHubUser3,
+			}),
+			commitsStore: remote.NewStore(),
 			usersService: &MockUsersService{},
 			repoService: &MockRepoService{
 				IssuesMocks: []IssuesMock{
@@ -1099,36 +1523,31 @@ func TestRepo_FetchIssuesAndMerges(t *testing.T) {
 func TestRepo_FetchParentCommits(t *testing.T) {
 	tests := []struct {
 		name            string
-		commitsStore    *store
-		repoService     *MockRepoService
+		commitsStore    *remote.Store
+		commitsService  *MockCommitsService
 		ctx             context.Context
 		ref             string
 		expectedCommits remote.Commits
 		expectedError   string
 	}{
 		{
-			name: "CommitFails",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			repoService: &MockRepoService{
-				CommitMocks: []CommitMock{
-					{OutError: errors.New("error on getting github commit")},
+			name:         "CommitsFails",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					{OutError: errors.New("error on listing github commits")},
 				},
 			},
 			ctx:           context.Background(),
 			ref:           "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
-			expectedError: "error on getting github commit",
+			expectedError: "error on listing github commits",
 		},
 		{
-			name: "Success",
-			commitsStore: &store{
-				m: map[interface{}]interface{}{},
-			},
-			repoService: &MockRepoService{
-				CommitMocks: []CommitMock{
-					{OutCommit: &gitHubCommit2, OutResponse: &github.Response{}},
-					{OutCommit: &gitHubCommit1, OutResponse: &github.Response{}},
+			name:         "Success",
+			commitsStore: remote.NewStore(),
+			commitsService: &MockCommitsService{
+				CommitsMocks: []RevisionCommitsMock{
+					{OutCommits: []github.Commit{gitHubCommit2, gitHubCommit1}, OutResponse: &github.Response{}},
 				},
 			},
 			ctx:             context.Background(),
@@ -1141,7 +1560,7 @@ func TestRepo_FetchParentCommits(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			r := &repo{logger: log.New(log.None)}
 			r.stores.commits = tc.commitsStore
-			r.services.repo = tc.repoService
+			r.services.commits = tc.commitsService
 
 			commits, err := r.FetchParentCommits(tc.ctx, tc.ref)
 
@@ -1155,3 +1574,203 @@ func TestRepo_FetchParentCommits(t *testing.T) {
 		})
 	}
 }
+
+func TestRepo_FetchCommitFiles(t *testing.T) {
+	tests := []struct {
+		name           string
+		commitsService *MockCommitsService
+		ctx            context.Context
+		ref            string
+		expectedFiles  []string
+		expectedError  string
+	}{
+		{
+			name: "CommitFilesFails",
+			commitsService: &MockCommitsService{
+				FilesMocks: []FilesMock{
+					{OutError: errors.New("error on getting github commit files")},
+				},
+			},
+			ctx:           context.Background(),
+			ref:           "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: "error on getting github commit files",
+		},
+		{
+			name: "Success",
+			commitsService: &MockCommitsService{
+				FilesMocks: []FilesMock{
+					{
+						OutFiles:    []file{{Filename: "api/server.go", Status: "modified"}},
+						OutResponse: &github.Response{Pages: github.Pages{Next: 2, Last: 2}},
+					},
+					{
+						OutFiles:    []file{{Filename: "web/app.js", PreviousFilename: "web/index.js", Status: "renamed"}},
+						OutResponse: &github.Response{},
+					},
+				},
+			},
+			ctx:           context.Background(),
+			ref:           "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedFiles: []string{"api/server.go", "web/app.js", "web/index.js"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{logger: log.New(log.None)}
+			r.services.commits = tc.commitsService
+
+			files, err := r.FetchCommitFiles(tc.ctx, tc.ref)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, files)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFiles, files)
+			}
+		})
+	}
+}
+
+func TestNewGraphQLRepo(t *testing.T) {
+	logger := log.New(log.None)
+	r, err := NewGraphQLRepo(logger, nil, "https://github.example.com/api/v3", "https://github.example.com", "octocat", "Hello-World", "github-access-token")
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+
+	gr, ok := r.(*graphqlRepo)
+	assert.True(t, ok)
+
+	assert.Equal(t, logger, gr.logger)
+	assert.Equal(t, "https://github.example.com", gr.webURL)
+	assert.Equal(t, "octocat", gr.owner)
+	assert.Equal(t, "Hello-World", gr.repo.repo)
+	assert.NotNil(t, gr.services.repo)
+
+	gc, ok := gr.graphql.(*graphqlClient)
+	assert.True(t, ok)
+	assert.Equal(t, "https://github.example.com/api/graphql", gc.url)
+	assert.Equal(t, gr.transport, gc.httpClient.Transport)
+}
+
+func TestGraphQLRepo_FetchIssuesAndMerges(t *testing.T) {
+	since := parseGitHubTime("2020-10-21T00:00:00Z")
+
+	oldPullRequest := graphqlPullRequest
+	oldPullRequest.Number = 1000
+	oldPullRequest.UpdatedAt = parseGitHubTime("2020-10-01T00:00:00Z")
+
+	unmergedPullRequest := graphqlPullRequest
+	unmergedPullRequest.Number = 1003
+	unmergedPullRequest.MergeCommit = nil
+
+	tests := []struct {
+		name           string
+		graphql        *MockGraphQLService
+		ctx            context.Context
+		since          time.Time
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+		expectedError  string
+	}{
+		{
+			name: "IssuesFails",
+			graphql: &MockGraphQLService{
+				IssuesMocks: []GraphQLIssuesMock{
+					{OutError: errors.New("error on querying issues")},
+				},
+				PullRequestsMocks: []GraphQLPullRequestsMock{
+					{OutConnection: &pullRequestConnection{Nodes: []pullRequestNode{}}},
+				},
+			},
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: "error on querying issues",
+		},
+		{
+			name: "PullRequestsFails",
+			graphql: &MockGraphQLService{
+				IssuesMocks: []GraphQLIssuesMock{
+					{OutConnection: &issueConnection{Nodes: []issueNode{}}},
+				},
+				PullRequestsMocks: []GraphQLPullRequestsMock{
+					{OutError: errors.New("error on querying pull requests")},
+				},
+			},
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: "error on querying pull requests",
+		},
+		{
+			name: "Success",
+			graphql: &MockGraphQLService{
+				IssuesMocks: []GraphQLIssuesMock{
+					{
+						OutConnection: &issueConnection{
+							PageInfo: pageInfo{HasNextPage: true, EndCursor: "Y3Vyc29yOjE="},
+							Nodes:    []issueNode{graphqlIssue},
+						},
+					},
+					{
+						OutConnection: &issueConnection{
+							PageInfo: pageInfo{HasNextPage: false},
+							Nodes:    []issueNode{},
+						},
+					},
+				},
+				PullRequestsMocks: []GraphQLPullRequestsMock{
+					{
+						OutConnection: &pullRequestConnection{
+							PageInfo: pageInfo{HasNextPage: true, EndCursor: "Y3Vyc29yOjE="},
+							Nodes:    []pullRequestNode{unmergedPullRequest, graphqlPullRequest},
+						},
+					},
+					{
+						// Paging stops at the first pull request updated before since
+						OutConnection: &pullRequestConnection{
+							PageInfo: pageInfo{HasNextPage: true, EndCursor: "Y3Vyc29yOjI="},
+							Nodes:    []pullRequestNode{oldPullRequest},
+						},
+					},
+				},
+			},
+			ctx:            context.Background(),
+			since:          since,
+			expectedIssues: remote.Issues{remoteIssue},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &graphqlRepo{
+				repo: &repo{
+					logger: log.New(log.None),
+					webURL: "https://github.com",
+					owner:  "octocat",
+					repo:   "Hello-World",
+				},
+				graphql: tc.graphql,
+			}
+
+			issues, merges, err := r.FetchIssuesAndMerges(tc.ctx, tc.since)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, issues)
+				assert.Nil(t, merges)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIssues, issues)
+				assert.Equal(t, tc.expectedMerges, merges)
+
+				assert.Equal(t, "", tc.graphql.IssuesMocks[0].InAfter)
+				assert.Equal(t, "Y3Vyc29yOjE=", tc.graphql.IssuesMocks[1].InAfter)
+				assert.Equal(t, tc.since, tc.graphql.IssuesMocks[0].InSince)
+				assert.Equal(t, "Y3Vyc29yOjE=", tc.graphql.PullRequestsMocks[1].InAfter)
+				assert.Equal(t, 2, tc.graphql.PullRequestsIndex)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const userAgent = "moorara/changelog"
+
+// issuesQuery retrieves a page of closed issues updated since a given time.
+// The last closed event of each issue determines who closed the issue.
+const issuesQuery = `query ($owner: String!, $name: String!, $first: Int!, $after: String, $since: DateTime) {
+  repository(owner: $owner, name: $name) {
+    issues(first: $first, after: $after, states: CLOSED, filterBy: {since: $since}, orderBy: {field: UPDATED_AT, direction: ASC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number
+        title
+        url
+        closedAt
+        author { login url ... on User { name email } }
+        labels(first: 100) { nodes { name } }
+        milestone { title }
+        timelineItems(itemTypes: [CLOSED_EVENT], last: 1) {
+          nodes { ... on ClosedEvent { createdAt actor { login url ... on User { name email } } } }
+        }
+      }
+    }
+  }
+}`
+
+// pullRequestsQuery retrieves a page of merged pull requests from the most recently updated to the least recently updated.
+// Pull requests cannot be filtered by time, so paging should stop once a pull request is updated before the desired time.
+const pullRequestsQuery = `query ($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(first: $first, after: $after, states: MERGED, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number
+        title
+        url
+        mergedAt
+        updatedAt
+        author { login url ... on User { name email } }
+        mergedBy { login url ... on User { name email } }
+        labels(first: 100) { nodes { name } }
+        milestone { title }
+        mergeCommit { oid committedDate message }
+      }
+    }
+  }
+}`
+
+type (
+	// actor is a GitHub user, bot, or app.
+	// Name and Email are only available for users.
+	actor struct {
+		Login string `json:"login"`
+		URL   string `json:"url"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	pageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	}
+
+	labelConnection struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	}
+
+	milestoneNode struct {
+		Title string `json:"title"`
+	}
+
+	closedEventNode struct {
+		Actor     *actor    `json:"actor"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	commitNode struct {
+		OID           string    `json:"oid"`
+		CommittedDate time.Time `json:"committedDate"`
+		Message       string    `json:"message"`
+	}
+
+	issueNode struct {
+		Number        int             `json:"number"`
+		Title         string          `json:"title"`
+		URL           string          `json:"url"`
+		ClosedAt      *time.Time      `json:"closedAt"`
+		Author        *actor          `json:"author"`
+		Labels        labelConnection `json:"labels"`
+		Milestone     *milestoneNode  `json:"milestone"`
+		TimelineItems struct {
+			Nodes []closedEventNode `json:"nodes"`
+		} `json:"timelineItems"`
+	}
+
+	pullRequestNode struct {
+		Number      int             `json:"number"`
+		Title       string          `json:"title"`
+		URL         string          `json:"url"`
+		MergedAt    *time.Time      `json:"mergedAt"`
+		UpdatedAt   time.Time       `json:"updatedAt"`
+		Author      *actor          `json:"author"`
+		MergedBy    *actor          `json:"mergedBy"`
+		Labels      labelConnection `json:"labels"`
+		Milestone   *milestoneNode  `json:"milestone"`
+		MergeCommit *commitNode     `json:"mergeCommit"`
+	}
+
+	issueConnection struct {
+		PageInfo pageInfo    `json:"pageInfo"`
+		Nodes    []issueNode `json:"nodes"`
+	}
+
+	pullRequestConnection struct {
+		PageInfo pageInfo          `json:"pageInfo"`
+		Nodes    []pullRequestNode `json:"nodes"`
+	}
+)
+
+// responseError is returned when a GitHub API call is not successful.
+type responseError struct {
+	method     string
+	url        string
+	statusCode int
+	message    string
+}
+
+func newResponseError(resp *http.Response) *responseError {
+	e := &responseError{
+		method:     resp.Request.Method,
+		url:        resp.Request.URL.String(),
+		statusCode: resp.StatusCode,
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return e
+	}
+
+	body := struct {
+		Message string `json:"message"`
+	}{}
+
+	if err := json.Unmarshal(b, &body); err != nil {
+		e.message = string(b)
+		return e
+	}
+
+	e.message = body.Message
+
+	return e
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s %s %d: %s", e.method, e.url, e.statusCode, e.message)
+}
+
+// graphqlError is returned when a GitHub GraphQL query is not successful.
+type graphqlError struct {
+	messages []string
+}
+
+func (e *graphqlError) Error() string {
+	return fmt.Sprintf("GitHub GraphQL query failed: %s", strings.Join(e.messages, "; "))
+}
+
+// graphqlURL returns the GraphQL API endpoint for a REST API URL.
+// The GraphQL endpoint is https://api.github.com/graphql for github.com and http(s)://<hostname>/api/graphql for GitHub Enterprise Server.
+func graphqlURL(apiURL string) string {
+	apiURL = strings.TrimSuffix(apiURL, "/")
+
+	if strings.HasSuffix(apiURL, "/api/v3") {
+		return strings.TrimSuffix(apiURL, "/v3") + "/graphql"
+	}
+
+	return apiURL + "/graphql"
+}
+
+// graphqlClient is a minimal client for the GitHub GraphQL API v4.
+type graphqlClient struct {
+	httpClient  *http.Client
+	url         string
+	accessToken string
+	owner       string
+	repo        string
+}
+
+func newGraphQLClient(transport http.RoundTripper, url, accessToken, owner, repo string) *graphqlClient {
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+
+	return &graphqlClient{
+		httpClient:  httpClient,
+		url:         url,
+		accessToken: accessToken,
+		owner:       owner,
+		repo:        repo,
+	}
+}
+
+// query makes a GraphQL query and decodes the data of the response into out.
+func (c *graphqlClient) query(ctx context.Context, query string, vars map[string]interface{}, out interface{}) error {
+	b, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": vars,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	// Queries are idempotent and can be retried (the header is not sent)
+	req.Header["X-Idempotency-Key"] = nil
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "bearer "+c.accessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp)
+	}
+
+	body := struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+
+	// A GraphQL response can have both data and errors, but partial data is not useful for generating a changelog
+	if len(body.Errors) > 0 {
+		e := &graphqlError{}
+		for _, err := range body.Errors {
+			e.messages = append(e.messages, err.Message)
+		}
+		return e
+	}
+
+	return json.Unmarshal(body.Data, out)
+}
+
+// Issues retrieves a page of closed issues updated since a given time.
+// after is the cursor of the previous page and it is empty for the first page.
+func (c *graphqlClient) Issues(ctx context.Context, pageSize int, after string, since time.Time) (*issueConnection, error) {
+	vars := map[string]interface{}{
+		"owner": c.owner,
+		"name":  c.repo,
+		"first": pageSize,
+		"after": nil,
+		"since": nil,
+	}
+
+	if after != "" {
+		vars["after"] = after
+	}
+
+	if !since.IsZero() {
+		vars["since"] = since.Format(time.RFC3339)
+	}
+
+	data := struct {
+		Repository struct {
+			Issues issueConnection `json:"issues"`
+		} `json:"repository"`
+	}{}
+
+	if err := c.query(ctx, issuesQuery, vars, &data); err != nil {
+		return nil, err
+	}
+
+	return &data.Repository.Issues, nil
+}
+
+// PullRequests retrieves a page of merged pull requests from the most recently updated to the least recently updated.
+// after is the cursor of the previous page and it is empty for the first page.
+func (c *graphqlClient) PullRequests(ctx context.Context, pageSize int, after string) (*pullRequestConnection, error) {
+	vars := map[string]interface{}{
+		"owner": c.owner,
+		"name":  c.repo,
+		"first": pageSize,
+		"after": nil,
+	}
+
+	if after != "" {
+		vars["after"] = after
+	}
+
+	data := struct {
+		Repository struct {
+			PullRequests pullRequestConnection `json:"pullRequests"`
+		} `json:"repository"`
+	}{}
+
+	if err := c.query(ctx, pullRequestsQuery, vars, &data); err != nil {
+		return nil, err
+	}
+
+	return &data.Repository.PullRequests, nil
+}
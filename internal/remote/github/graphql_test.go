@@ -0,0 +1,247 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphqlURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		apiURL      string
+		expectedURL string
+	}{
+		{
+			name:        "GitHub",
+			apiURL:      "https://api.github.com",
+			expectedURL: "https://api.github.com/graphql",
+		},
+		{
+			name:        "Enterprise",
+			apiURL:      "https://github.example.com/api/v3/",
+			expectedURL: "https://github.example.com/api/graphql",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedURL, graphqlURL(tc.apiURL))
+		})
+	}
+}
+
+func TestGraphQLError(t *testing.T) {
+	err := &graphqlError{
+		messages: []string{"Field 'foo' doesn't exist on type 'Repository'", "Variable $bar is declared but not used"},
+	}
+
+	assert.EqualError(t, err, "GitHub GraphQL query failed: Field 'foo' doesn't exist on type 'Repository'; Variable $bar is declared but not used")
+}
+
+func TestNewGraphQLClient(t *testing.T) {
+	c := newGraphQLClient(&http.Transport{}, "https://api.github.com/graphql", "github-access-token", "octocat", "Hello-World")
+
+	assert.NotNil(t, c.httpClient)
+	assert.Equal(t, "https://api.github.com/graphql", c.url)
+	assert.Equal(t, "github-access-token", c.accessToken)
+	assert.Equal(t, "octocat", c.owner)
+	assert.Equal(t, "Hello-World", c.repo)
+}
+
+func TestGraphQLClient_query(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/graphql", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, userAgent, r.Header.Get("User-Agent"))
+		assert.Equal(t, "bearer github-access-token", r.Header.Get("Authorization"))
+
+		req := struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}{}
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "query { viewer { login } }", req.Query)
+		assert.Equal(t, map[string]interface{}{"owner": "octocat"}, req.Variables)
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"viewer": map[string]string{"login": "octocat"},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	c := newGraphQLClient(&http.Transport{}, ts.URL+"/api/graphql", "github-access-token", "octocat", "Hello-World")
+
+	data := struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+	}{}
+
+	err := c.query(context.Background(), "query { viewer { login } }", map[string]interface{}{"owner": "octocat"}, &data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "octocat", data.Viewer.Login)
+}
+
+func TestGraphQLClient_Issues(t *testing.T) {
+	since := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		routes             map[string]MockResponse
+		after              string
+		since              time.Time
+		expectedConnection *issueConnection
+		expectedError      string
+	}{
+		{
+			name: "Unauthorized",
+			routes: map[string]MockResponse{
+				"issues": {StatusCode: http.StatusUnauthorized, Body: map[string]string{"message": "Bad credentials"}},
+			},
+			expectedError: "401: Bad credentials",
+		},
+		{
+			name:          "NotFound",
+			routes:        map[string]MockResponse{},
+			expectedError: "GitHub GraphQL query failed: Could not resolve to a Repository with the name 'octocat/Hello-World'.",
+		},
+		{
+			name: "FirstPage",
+			routes: map[string]MockResponse{
+				"issues": {
+					StatusCode: http.StatusOK,
+					Body: issueConnection{
+						PageInfo: pageInfo{HasNextPage: true, EndCursor: "Y3Vyc29yOjE="},
+						Nodes:    []issueNode{graphqlIssue},
+					},
+				},
+			},
+			since: since,
+			expectedConnection: &issueConnection{
+				PageInfo: pageInfo{HasNextPage: true, EndCursor: "Y3Vyc29yOjE="},
+				Nodes:    []issueNode{graphqlIssue},
+			},
+		},
+		{
+			name: "NextPage",
+			routes: map[string]MockResponse{
+				"issues?after=Y3Vyc29yOjE=": {
+					StatusCode: http.StatusOK,
+					Body: issueConnection{
+						PageInfo: pageInfo{HasNextPage: false},
+						Nodes:    []issueNode{},
+					},
+				},
+			},
+			after: "Y3Vyc29yOjE=",
+			since: since,
+			expectedConnection: &issueConnection{
+				PageInfo: pageInfo{HasNextPage: false},
+				Nodes:    []issueNode{},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockGraphQLServer(tc.routes)
+			defer ts.Close()
+
+			c := newGraphQLClient(&http.Transport{}, ts.URL, "github-access-token", "octocat", "Hello-World")
+			conn, err := c.Issues(context.Background(), pageSize, tc.after, tc.since)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, conn)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedConnection, conn)
+			}
+		})
+	}
+}
+
+func TestGraphQLClient_PullRequests(t *testing.T) {
+	tests := []struct {
+		name               string
+		routes             map[string]MockResponse
+		after              string
+		expectedConnection *pullRequestConnection
+		expectedError      string
+	}{
+		{
+			name: "Unauthorized",
+			routes: map[string]MockResponse{
+				"pullRequests": {StatusCode: http.StatusUnauthorized, Body: map[string]string{"message": "Bad credentials"}},
+			},
+			expectedError: "401: Bad credentials",
+		},
+		{
+			name:          "NotFound",
+			routes:        map[string]MockResponse{},
+			expectedError: "GitHub GraphQL query failed: Could not resolve to a Repository with the name 'octocat/Hello-World'.",
+		},
+		{
+			name: "FirstPage",
+			routes: map[string]MockResponse{
+				"pullRequests": {
+					StatusCode: http.StatusOK,
+					Body: pullRequestConnection{
+						PageInfo: pageInfo{HasNextPage: true, EndCursor: "Y3Vyc29yOjE="},
+						Nodes:    []pullRequestNode{graphqlPullRequest},
+					},
+				},
+			},
+			expectedConnection: &pullRequestConnection{
+				PageInfo: pageInfo{HasNextPage: true, EndCursor: "Y3Vyc29yOjE="},
+				Nodes:    []pullRequestNode{graphqlPullRequest},
+			},
+		},
+		{
+			name: "NextPage",
+			routes: map[string]MockResponse{
+				"pullRequests?after=Y3Vyc29yOjE=": {
+					StatusCode: http.StatusOK,
+					Body: pullRequestConnection{
+						PageInfo: pageInfo{HasNextPage: false},
+						Nodes:    []pullRequestNode{},
+					},
+				},
+			},
+			after: "Y3Vyc29yOjE=",
+			expectedConnection: &pullRequestConnection{
+				PageInfo: pageInfo{HasNextPage: false},
+				Nodes:    []pullRequestNode{},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockGraphQLServer(tc.routes)
+			defer ts.Close()
+
+			c := newGraphQLClient(&http.Transport{}, ts.URL, "github-access-token", "octocat", "Hello-World")
+			conn, err := c.PullRequests(context.Background(), pageSize, tc.after)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, conn)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedConnection, conn)
+			}
+		})
+	}
+}
@@ -2,6 +2,10 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"time"
 
@@ -234,6 +238,69 @@ var (
 		CreatedAt: parseGitHubTime("2020-10-20T20:00:00Z"),
 	}
 
+	graphqlActor1 = actor{
+		Login: "octocat",
+		URL:   "https://github.com/octocat",
+		Name:  "The Octocat",
+		Email: "octocat@github.com",
+	}
+
+	graphqlActor2 = actor{
+		Login: "octodog",
+		URL:   "https://github.com/octodog",
+		Name:  "The Octodog",
+		Email: "octodog@github.com",
+	}
+
+	graphqlActor3 = actor{
+		Login: "octofox",
+		URL:   "https://github.com/octofox",
+		Name:  "The Octofox",
+		Email: "octofox@github.com",
+	}
+
+	graphqlLabels = labelConnection{
+		Nodes: []struct {
+			Name string `json:"name"`
+		}{
+			{Name: "bug"},
+		},
+	}
+
+	graphqlIssue = issueNode{
+		Number:    1001,
+		Title:     "Found a bug",
+		URL:       "https://github.com/octocat/Hello-World/issues/1001",
+		ClosedAt:  nil,
+		Author:    &graphqlActor1,
+		Labels:    graphqlLabels,
+		Milestone: &milestoneNode{Title: "v1.0"},
+		TimelineItems: struct {
+			Nodes []closedEventNode `json:"nodes"`
+		}{
+			Nodes: []closedEventNode{
+				{Actor: &graphqlActor1, CreatedAt: parseGitHubTime("2020-10-20T20:00:00Z")},
+			},
+		},
+	}
+
+	graphqlPullRequest = pullRequestNode{
+		Number:    1002,
+		Title:     "Fixed a bug",
+		URL:       "https://github.com/octocat/Hello-World/pull/1002",
+		MergedAt:  parseGitHubTimePtr("2020-10-20T20:00:00Z"),
+		UpdatedAt: parseGitHubTime("2020-10-22T22:00:00Z"),
+		Author:    &graphqlActor2,
+		MergedBy:  &graphqlActor3,
+		Labels:    graphqlLabels,
+		Milestone: &milestoneNode{Title: "v1.0"},
+		MergeCommit: &commitNode{
+			OID:           "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			CommittedDate: parseGitHubTime("2020-10-20T19:59:59Z"),
+			Message:       "Fix all the bugs",
+		},
+	}
+
 	remoteUser1 = remote.User{
 		Name:     "The Octocat",
 		Email:    "octocat@github.com",
@@ -256,13 +323,16 @@ var (
 	}
 
 	remoteCommit1 = remote.Commit{
-		Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
-		Time: parseGitHubTime("2020-10-20T19:59:59Z"),
+		Hash:    "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time:    parseGitHubTime("2020-10-20T19:59:59Z"),
+		Message: "Fix all the bugs",
 	}
 
 	remoteCommit2 = remote.Commit{
-		Hash: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
-		Time: parseGitHubTime("2020-10-27T23:59:59Z"),
+		Hash:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Time:    parseGitHubTime("2020-10-27T23:59:59Z"),
+		Parents: []string{"6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		Message: "Release v0.1.0",
 	}
 
 	remoteBranch = remote.Branch{
@@ -517,3 +587,168 @@ func (m *MockRepoService) Events(ctx context.Context, number, pageSize, pageNo i
 	m.EventsMocks[i].InPageNo = pageNo
 	return m.EventsMocks[i].OutEvents, m.EventsMocks[i].OutResponse, m.EventsMocks[i].OutError
 }
+
+type (
+	RevisionCommitsMock struct {
+		InContext   context.Context
+		InSHA       string
+		InPageSize  int
+		InPageNo    int
+		OutCommits  []github.Commit
+		OutResponse *github.Response
+		OutError    error
+	}
+
+	FilesMock struct {
+		InContext   context.Context
+		InRef       string
+		InPageSize  int
+		InPageNo    int
+		OutFiles    []file
+		OutResponse *github.Response
+		OutError    error
+	}
+
+	MockCommitsService struct {
+		CommitsIndex int
+		CommitsMocks []RevisionCommitsMock
+
+		FilesIndex int
+		FilesMocks []FilesMock
+	}
+)
+
+func (m *MockCommitsService) Commits(ctx context.Context, sha string, pageSize, pageNo int) ([]github.Commit, *github.Response, error) {
+	i := m.CommitsIndex
+	m.CommitsIndex++
+	m.CommitsMocks[i].InContext = ctx
+	m.CommitsMocks[i].InSHA = sha
+	m.CommitsMocks[i].InPageSize = pageSize
+	m.CommitsMocks[i].InPageNo = pageNo
+	return m.CommitsMocks[i].OutCommits, m.CommitsMocks[i].OutResponse, m.CommitsMocks[i].OutError
+}
+
+func (m *MockCommitsService) Files(ctx context.Context, ref string, pageSize, pageNo int) ([]file, *github.Response, error) {
+	i := m.FilesIndex
+	m.FilesIndex++
+	m.FilesMocks[i].InContext = ctx
+	m.FilesMocks[i].InRef = ref
+	m.FilesMocks[i].InPageSize = pageSize
+	m.FilesMocks[i].InPageNo = pageNo
+	return m.FilesMocks[i].OutFiles, m.FilesMocks[i].OutResponse, m.FilesMocks[i].OutError
+}
+
+type (
+	GraphQLIssuesMock struct {
+		InContext     context.Context
+		InPageSize    int
+		InAfter       string
+		InSince       time.Time
+		OutConnection *issueConnection
+		OutError      error
+	}
+
+	GraphQLPullRequestsMock struct {
+		InContext     context.Context
+		InPageSize    int
+		InAfter       string
+		OutConnection *pullRequestConnection
+		OutError      error
+	}
+
+	MockGraphQLService struct {
+		IssuesIndex int
+		IssuesMocks []GraphQLIssuesMock
+
+		PullRequestsIndex int
+		PullRequestsMocks []GraphQLPullRequestsMock
+	}
+)
+
+func (m *MockGraphQLService) Issues(ctx context.Context, pageSize int, after string, since time.Time) (*issueConnection, error) {
+	i := m.IssuesIndex
+	m.IssuesIndex++
+	m.IssuesMocks[i].InContext = ctx
+	m.IssuesMocks[i].InPageSize = pageSize
+	m.IssuesMocks[i].InAfter = after
+	m.IssuesMocks[i].InSince = since
+	return m.IssuesMocks[i].OutConnection, m.IssuesMocks[i].OutError
+}
+
+func (m *MockGraphQLService) PullRequests(ctx context.Context, pageSize int, after string) (*pullRequestConnection, error) {
+	i := m.PullRequestsIndex
+	m.PullRequestsIndex++
+	m.PullRequestsMocks[i].InContext = ctx
+	m.PullRequestsMocks[i].InPageSize = pageSize
+	m.PullRequestsMocks[i].InAfter = after
+	return m.PullRequestsMocks[i].OutConnection, m.PullRequestsMocks[i].OutError
+}
+
+// MockResponse is a canned response of the fake GitHub API server.
+type MockResponse struct {
+	StatusCode int
+	Header     map[string]string
+	Body       interface{}
+}
+
+// newMockGraphQLServer creates a fake GitHub GraphQL API server.
+// The routes are keyed by the root field of the repository query and, for subsequent pages, the cursor (i.e. pullRequests?after=Y3Vyc29yOjI=).
+// The body of a route is the value of the root field and it is wrapped in a GraphQL response.
+// If the status code of a route is not 200 OK, the body is returned as is.
+// Queries for unknown routes receive a GraphQL NOT_FOUND error.
+func newMockGraphQLServer(routes map[string]MockResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}{}
+
+		if r.Method != "POST" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "Problems parsing JSON"})
+			return
+		}
+
+		var field string
+		switch {
+		case strings.Contains(req.Query, "pullRequests("):
+			field = "pullRequests"
+		case strings.Contains(req.Query, "issues("):
+			field = "issues"
+		}
+
+		key := field
+		if after, ok := req.Variables["after"].(string); ok {
+			key += "?after=" + after
+		}
+
+		var body interface{}
+		resp, ok := routes[key]
+		switch {
+		case !ok:
+			resp.StatusCode = http.StatusOK
+			body = map[string]interface{}{
+				"data": map[string]interface{}{"repository": nil},
+				"errors": []map[string]string{
+					{"type": "NOT_FOUND", "message": "Could not resolve to a Repository with the name 'octocat/Hello-World'."},
+				},
+			}
+		case resp.StatusCode != http.StatusOK:
+			body = resp.Body
+		default:
+			body = map[string]interface{}{
+				"data": map[string]interface{}{
+					"repository": map[string]interface{}{field: resp.Body},
+				},
+			}
+		}
+
+		for k, v := range resp.Header {
+			w.Header().Set(k, v)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+}
@@ -4,9 +4,8 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/moorara/go-github"
-
 	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/go-github"
 )
 
 func toUser(u github.User) remote.User {
@@ -19,9 +18,16 @@ func toUser(u github.User) remote.User {
 }
 
 func toCommit(c github.Commit) remote.Commit {
+	var parents []string
+	for _, p := range c.Parents {
+		parents = append(parents, p.SHA)
+	}
+
 	return remote.Commit{
-		Hash: c.SHA,
-		Time: c.Commit.Committer.Time,
+		Hash:    c.SHA,
+		Time:    c.Commit.Committer.Time,
+		Parents: parents,
+		Message: c.Commit.Message,
 	}
 }
 
@@ -32,12 +38,12 @@ func toBranch(b github.Branch) remote.Branch {
 	}
 }
 
-func toTag(t github.Tag, c github.Commit, owner, repo string) remote.Tag {
+func toTag(t github.Tag, c github.Commit, webURL, owner, repo string) remote.Tag {
 	return remote.Tag{
 		Name:   t.Name,
 		Time:   c.Commit.Committer.Time,
 		Commit: toCommit(c),
-		WebURL: fmt.Sprintf("https://github.com/%s/%s/tree/%s", owner, repo, t.Name),
+		WebURL: fmt.Sprintf("%s/%s/%s/tree/%s", webURL, owner, repo, t.Name),
 	}
 }
 
@@ -102,7 +108,88 @@ func toMerge(i github.Issue, e github.Event, c github.Commit, author, merger git
 	}
 }
 
-func resolveTags(gitHubTags, gitHubCommits *store, owner, repo string) remote.Tags {
+func toActorUser(a *actor) remote.User {
+	// The author of an issue or a pull request is null if the account is deleted
+	if a == nil {
+		return remote.User{}
+	}
+
+	return remote.User{
+		Name:     a.Name,
+		Email:    a.Email,
+		Username: a.Login,
+		WebURL:   a.URL,
+	}
+}
+
+func toLabelNames(c labelConnection) []string {
+	labels := make([]string, len(c.Nodes))
+	for i, l := range c.Nodes {
+		labels[i] = l.Name
+	}
+
+	return labels
+}
+
+func toIssueFromNode(i issueNode) remote.Issue {
+	var milestone string
+	if i.Milestone != nil {
+		milestone = i.Milestone.Title
+	}
+
+	var time time.Time
+	if i.ClosedAt != nil {
+		time = *i.ClosedAt
+	}
+
+	var closer remote.User
+	if n := len(i.TimelineItems.Nodes); n > 0 {
+		closer = toActorUser(i.TimelineItems.Nodes[n-1].Actor)
+	}
+
+	return remote.Issue{
+		Change: remote.Change{
+			Number:    i.Number,
+			Title:     i.Title,
+			Labels:    toLabelNames(i.Labels),
+			Milestone: milestone,
+			Time:      time,
+			Author:    toActorUser(i.Author),
+			WebURL:    i.URL,
+		},
+		Closer: closer,
+	}
+}
+
+func toMergeFromNode(p pullRequestNode) remote.Merge {
+	var milestone string
+	if p.Milestone != nil {
+		milestone = p.Milestone.Title
+	}
+
+	// p.MergeCommit.CommittedDate is the actual time of merge
+	commit := remote.Commit{
+		Hash:    p.MergeCommit.OID,
+		Time:    p.MergeCommit.CommittedDate,
+		Message: p.MergeCommit.Message,
+	}
+
+	return remote.Merge{
+		Change: remote.Change{
+			Number:    p.Number,
+			Title:     p.Title,
+			Labels:    toLabelNames(p.Labels),
+			Milestone: milestone,
+			Time:      commit.Time,
+			Author:    toActorUser(p.Author),
+			WebURL:    p.URL,
+		},
+		Merger: toActorUser(p.MergedBy),
+		Commit: commit,
+	}
+}
+
+func resolveTags(gitHubTags, gitHubCommits *remote.Store, webURL, owner, repo string) remote.Tags {
 	tags := remote.Tags{}
 
 	_ = gitHubTags.ForEach(func(k, v interface{}) error {
@@ -110,7 +197,7 @@ func resolveTags(gitHubTags, gitHubCommits *store, owner, repo string) remote.Ta
 
 		if v, ok := gitHubCommits.Load(t.Commit.SHA); ok {
 			c := v.(github.Commit)
-			tags = append(tags, toTag(t, c, owner, repo))
+			tags = append(tags, toTag(t, c, webURL, owner, repo))
 		}
 
 		return nil
@@ -119,7 +206,7 @@ func resolveTags(gitHubTags, gitHubCommits *store, owner, repo string) remote.Ta
 	return tags
 }
 
-func resolveIssuesAndMerges(gitHubIssues, gitHubEvents, gitHubCommits, gitHubUsers *store) (remote.Issues, remote.Merges) {
+func resolveIssuesAndMerges(gitHubIssues, gitHubEvents, gitHubCommits, gitHubUsers *remote.Store) (remote.Issues, remote.Merges) {
 	issues := remote.Issues{}
 	merges := remote.Merges{}
 
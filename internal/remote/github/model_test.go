@@ -77,6 +77,7 @@ func TestToTag(t *testing.T) {
 		name        string
 		t           github.Tag
 		c           github.Commit
+		webURL      string
 		owner, repo string
 		expectedTag remote.Tag
 	}{
@@ -84,6 +85,7 @@ func TestToTag(t *testing.T) {
 			name:        "OK",
 			t:           gitHubTag,
 			c:           gitHubCommit2,
+			webURL:      "https://github.com",
 			owner:       "octocat",
 			repo:        "Hello-World",
 			expectedTag: remoteTag,
@@ -92,7 +94,7 @@ func TestToTag(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			tag := toTag(tc.t, tc.c, tc.owner, tc.repo)
+			tag := toTag(tc.t, tc.c, tc.webURL, tc.owner, tc.repo)
 			assert.Equal(t, tc.expectedTag, tag)
 		})
 	}
@@ -155,23 +157,21 @@ func TestToMerge(t *testing.T) {
 func TestResolveTags(t *testing.T) {
 	tests := []struct {
 		name          string
-		gitHubTags    *store
-		gitHubCommits *store
+		gitHubTags    *remote.Store
+		gitHubCommits *remote.Store
+		webURL        string
 		owner, repo   string
 		expectedTags  remote.Tags
 	}{
 		{
 			name: "OK",
-			gitHubTags: &store{
-				m: map[interface{}]interface{}{
-					"v0.1.0": gitHubTag,
-				},
-			},
-			gitHubCommits: &store{
-				m: map[interface{}]interface{}{
-					"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": gitHubCommit2,
-				},
-			},
+			gitHubTags: remote.NewStoreFrom(map[interface{}]interface{}{
+				"v0.1.0": gitHubTag,
+			}),
+			gitHubCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": gitHubCommit2,
+			}),
+			webURL:       "https://github.com",
 			owner:        "octocat",
 			repo:         "Hello-World",
 			expectedTags: remote.Tags{remoteTag},
@@ -180,7 +180,7 @@ func TestResolveTags(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			tags := resolveTags(tc.gitHubTags, tc.gitHubCommits, tc.owner, tc.repo)
+			tags := resolveTags(tc.gitHubTags, tc.gitHubCommits, tc.webURL, tc.owner, tc.repo)
 			assert.Equal(t, tc.expectedTags, tags)
 		})
 	}
@@ -189,39 +189,31 @@ func TestResolveTags(t *testing.T) {
 func TestResolveIssuesAndMerges(t *testing.T) {
 	tests := []struct {
 		name           string
-		gitHubIssues   *store
-		gitHubEvents   *store
-		gitHubCommits  *store
-		gitHubUsers    *store
+		gitHubIssues   *remote.Store
+		gitHubEvents   *remote.Store
+		gitHubCommits  *remote.Store
+		gitHubUsers    *remote.Store
 		expectedIssues remote.Issues
 		expectedMerges remote.Merges
 	}{
 		{
 			name: "OK",
-			gitHubIssues: &store{
-				m: map[interface{}]interface{}{
-					1001: gitHubIssue1,
-					1002: gitHubIssue2,
-				},
-			},
-			gitHubEvents: &store{
-				m: map[interface{}]interface{}{
-					1001: gitHubEvent1,
-					1002: gitHubEvent2,
-				},
-			},
-			gitHubCommits: &store{
-				m: map[interface{}]interface{}{
-					"6dcb09b5b57875f334f61aebed695e2e4193db5e": gitHubCommit1,
-				},
-			},
-			gitHubUsers: &store{
-				m: map[interface{}]interface{}{
-					"octocat": gitHubUser1,
-					"octodog": gitHubUser2,
-					"octofox": gitHubUser3,
-				},
-			},
+			gitHubIssues: remote.NewStoreFrom(map[interface{}]interface{}{
+				1001: gitHubIssue1,
+				1002: gitHubIssue2,
+			}),
+			gitHubEvents: remote.NewStoreFrom(map[interface{}]interface{}{
+				1001: gitHubEvent1,
+				1002: gitHubEvent2,
+			}),
+			gitHubCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": gitHubCommit1,
+			}),
+			gitHubUsers: remote.NewStoreFrom(map[interface{}]interface{}{
+				"octocat": gitHubUser1,
+				"octodog": gitHubUser2,
+				"octofox": gitHubUser3,
+			}),
 			expectedIssues: remote.Issues{remoteIssue},
 			expectedMerges: remote.Merges{remoteMerge},
 		},
@@ -236,3 +228,38 @@ func TestResolveIssuesAndMerges(t *testing.T) {
 		})
 	}
 }
+
+func TestToActorUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		a            *actor
+		expectedUser remote.User
+	}{
+		{
+			name:         "Deleted",
+			a:            nil,
+			expectedUser: remote.User{},
+		},
+		{
+			name:         "OK",
+			a:            &graphqlActor1,
+			expectedUser: remoteUser1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedUser, toActorUser(tc.a))
+		})
+	}
+}
+
+func TestToIssueFromNode(t *testing.T) {
+	issue := toIssueFromNode(graphqlIssue)
+	assert.Equal(t, remoteIssue, issue)
+}
+
+func TestToMergeFromNode(t *testing.T) {
+	merge := toMergeFromNode(graphqlPullRequest)
+	assert.Equal(t, remoteMerge, merge)
+}
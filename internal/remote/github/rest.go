@@ -0,0 +1,322 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moorara/go-github"
+)
+
+// file is a file changed by a GitHub commit.
+type file struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"`
+}
+
+// restClient is a minimal client for the GitHub REST API v3 using the go-github data types.
+// The request paths are relative to the API URL, so the path of a GitHub Enterprise Server API URL (/api/v3) is kept.
+// It implements the githubService interface.
+type restClient struct {
+	httpClient  *http.Client
+	apiURL      string
+	accessToken string
+	owner       string
+	repo        string
+}
+
+func newRESTClient(transport http.RoundTripper, apiURL, accessToken, owner, repo string) (*restClient, error) {
+	if _, err := url.Parse(apiURL); err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+
+	return &restClient{
+		httpClient:  httpClient,
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		accessToken: accessToken,
+		owner:       owner,
+		repo:        repo,
+	}, nil
+}
+
+// parsePages parses the pagination information of a response from its Link header.
+func parsePages(h http.Header) github.Pages {
+	pages := github.Pages{}
+
+	for _, link := range strings.Split(h.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+		if err != nil {
+			continue
+		}
+
+		page, err := strconv.Atoi(u.Query().Get("page"))
+		if err != nil {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			switch strings.TrimSpace(param) {
+			case `rel="first"`:
+				pages.First = page
+			case `rel="prev"`:
+				pages.Prev = page
+			case `rel="next"`:
+				pages.Next = page
+			case `rel="last"`:
+				pages.Last = page
+			}
+		}
+	}
+
+	return pages
+}
+
+// pageParams returns the query parameters for a page of a paginated endpoint.
+func pageParams(pageSize, pageNo int) url.Values {
+	params := url.Values{}
+	params.Set("per_page", strconv.Itoa(pageSize))
+	params.Set("page", strconv.Itoa(pageNo))
+
+	return params
+}
+
+// path returns the path of an endpoint of the repository.
+func (c *restClient) path(format string, a ...interface{}) string {
+	return fmt.Sprintf("/repos/%s/%s", c.owner, c.repo) + fmt.Sprintf(format, a...)
+}
+
+// call makes a request to a path relative to the API URL and decodes the response body into out if it is not nil.
+func (c *restClient) call(ctx context.Context, method, path string, params url.Values, out interface{}) (*github.Response, error) {
+	u := c.apiURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", userAgent)
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "token "+c.accessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(resp)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, err
+		}
+	}
+
+	return &github.Response{
+		Response: resp,
+		Pages:    parsePages(resp.Header),
+	}, nil
+}
+
+// EnsureScopes makes sure the access token has the given scopes.
+// See https://docs.github.com/developers/apps/scopes-for-oauth-apps
+func (c *restClient) EnsureScopes(ctx context.Context, scopes ...github.Scope) error {
+	// Call an endpoint to get the OAuth scopes of the access token from the headers
+	resp, err := c.call(ctx, "HEAD", "/user", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	oauthScopes := resp.Header.Get("X-OAuth-Scopes")
+	for _, scope := range scopes {
+		if !strings.Contains(oauthScopes, string(scope)) {
+			return fmt.Errorf("access token does not have the scope: %s", scope)
+		}
+	}
+
+	return nil
+}
+
+// usersClient implements the usersService interface.
+type usersClient struct {
+	*restClient
+}
+
+// Get retrieves a user by its username (login).
+// See https://docs.github.com/rest/reference/users#get-a-user
+func (c *usersClient) Get(ctx context.Context, username string) (*github.User, *github.Response, error) {
+	user := new(github.User)
+
+	resp, err := c.call(ctx, "GET", "/users/"+username, nil, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, resp, nil
+}
+
+// repoClient implements the repoService interface.
+type repoClient struct {
+	*restClient
+}
+
+// Get retrieves the repository.
+// See https://docs.github.com/rest/reference/repos#get-a-repository
+func (c *repoClient) Get(ctx context.Context) (*github.Repository, *github.Response, error) {
+	repository := new(github.Repository)
+
+	resp, err := c.call(ctx, "GET", c.path(""), nil, repository)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return repository, resp, nil
+}
+
+// Commit retrieves a commit by its reference.
+// See https://docs.github.com/rest/reference/repos#get-a-commit
+func (c *repoClient) Commit(ctx context.Context, ref string) (*github.Commit, *github.Response, error) {
+	commit := new(github.Commit)
+
+	resp, err := c.call(ctx, "GET", c.path("/commits/%s", ref), nil, commit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return commit, resp, nil
+}
+
+// Commits retrieves all commits of the default branch page by page.
+// See https://docs.github.com/rest/reference/repos#list-commits
+func (c *repoClient) Commits(ctx context.Context, pageSize, pageNo int) ([]github.Commit, *github.Response, error) {
+	commits := []github.Commit{}
+
+	resp, err := c.call(ctx, "GET", c.path("/commits"), pageParams(pageSize, pageNo), &commits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return commits, resp, nil
+}
+
+// Branch retrieves a branch by its name.
+// See https://docs.github.com/rest/reference/repos#get-a-branch
+func (c *repoClient) Branch(ctx context.Context, name string) (*github.Branch, *github.Response, error) {
+	branch := new(github.Branch)
+
+	resp, err := c.call(ctx, "GET", c.path("/branches/%s", name), nil, branch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return branch, resp, nil
+}
+
+// Tags retrieves all tags page by page.
+// See https://docs.github.com/rest/reference/repos#list-repository-tags
+func (c *repoClient) Tags(ctx context.Context, pageSize, pageNo int) ([]github.Tag, *github.Response, error) {
+	tags := []github.Tag{}
+
+	resp, err := c.call(ctx, "GET", c.path("/tags"), pageParams(pageSize, pageNo), &tags)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tags, resp, nil
+}
+
+// Issues retrieves all issues and pull requests page by page.
+// See https://docs.github.com/rest/reference/issues#list-repository-issues
+func (c *repoClient) Issues(ctx context.Context, pageSize, pageNo int, params github.IssuesParams) ([]github.Issue, *github.Response, error) {
+	q := pageParams(pageSize, pageNo)
+
+	if params.State != "" {
+		q.Set("state", params.State)
+	}
+
+	if !params.Since.IsZero() {
+		q.Set("since", params.Since.Format(time.RFC3339))
+	}
+
+	issues := []github.Issue{}
+
+	resp, err := c.call(ctx, "GET", c.path("/issues"), q, &issues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return issues, resp, nil
+}
+
+// Events retrieves all events of an issue or pull request page by page.
+// See https://docs.github.com/rest/reference/issues#list-issue-events
+func (c *repoClient) Events(ctx context.Context, number, pageSize, pageNo int) ([]github.Event, *github.Response, error) {
+	events := []github.Event{}
+
+	resp, err := c.call(ctx, "GET", c.path("/issues/%d/events", number), pageParams(pageSize, pageNo), &events)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, resp, nil
+}
+
+// commitsClient implements the commitsService interface.
+// It provides the commit APIs for a given revision.
+type commitsClient struct {
+	*restClient
+}
+
+// Commits retrieves the commits of a given revision and its ancestors page by page.
+// See https://docs.github.com/rest/reference/repos#list-commits
+func (c *commitsClient) Commits(ctx context.Context, sha string, pageSize, pageNo int) ([]github.Commit, *github.Response, error) {
+	q := pageParams(pageSize, pageNo)
+	q.Set("sha", sha)
+
+	commits := []github.Commit{}
+
+	resp, err := c.call(ctx, "GET", c.path("/commits"), q, &commits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return commits, resp, nil
+}
+
+// Files retrieves the files changed by a commit page by page.
+// See https://docs.github.com/rest/reference/repos#get-a-commit
+func (c *commitsClient) Files(ctx context.Context, ref string, pageSize, pageNo int) ([]file, *github.Response, error) {
+	commit := struct {
+		Files []file `json:"files"`
+	}{}
+
+	resp, err := c.call(ctx, "GET", c.path("/commits/%s", ref), pageParams(pageSize, pageNo), &commit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return commit.Files, resp, nil
+}
@@ -0,0 +1,233 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/go-github"
+)
+
+func TestNewRESTClient(t *testing.T) {
+	tests := []struct {
+		name          string
+		apiURL        string
+		expectedURL   string
+		expectedError string
+	}{
+		{
+			name:          "InvalidAPIURL",
+			apiURL:        ":invalid",
+			expectedError: `parse ":invalid": missing protocol scheme`,
+		},
+		{
+			name:        "GitHub",
+			apiURL:      "https://api.github.com",
+			expectedURL: "https://api.github.com",
+		},
+		{
+			name:        "Enterprise",
+			apiURL:      "https://github.example.com/api/v3/",
+			expectedURL: "https://github.example.com/api/v3",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newRESTClient(&http.Transport{}, tc.apiURL, "github-access-token", "octocat", "Hello-World")
+
+			if tc.expectedError != "" {
+				assert.Nil(t, c)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, c.httpClient)
+				assert.Equal(t, tc.expectedURL, c.apiURL)
+				assert.Equal(t, "github-access-token", c.accessToken)
+				assert.Equal(t, "octocat", c.owner)
+				assert.Equal(t, "Hello-World", c.repo)
+			}
+		})
+	}
+}
+
+func TestParsePages(t *testing.T) {
+	tests := []struct {
+		name          string
+		link          string
+		expectedPages github.Pages
+	}{
+		{
+			name:          "NoLink",
+			link:          "",
+			expectedPages: github.Pages{},
+		},
+		{
+			name:          "FirstPage",
+			link:          `<https://github.example.com/api/v3/repositories/1296269/tags?per_page=100&page=2>; rel="next", <https://github.example.com/api/v3/repositories/1296269/tags?per_page=100&page=5>; rel="last"`,
+			expectedPages: github.Pages{Next: 2, Last: 5},
+		},
+		{
+			name:          "MiddlePage",
+			link:          `<https://api.github.com/repositories/1296269/tags?page=1&per_page=100>; rel="first", <https://api.github.com/repositories/1296269/tags?page=2&per_page=100>; rel="prev", <https://api.github.com/repositories/1296269/tags?page=4&per_page=100>; rel="next", <https://api.github.com/repositories/1296269/tags?page=5&per_page=100>; rel="last"`,
+			expectedPages: github.Pages{First: 1, Prev: 2, Next: 4, Last: 5},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set("Link", tc.link)
+
+			assert.Equal(t, tc.expectedPages, parsePages(h))
+		})
+	}
+}
+
+func TestRESTClient(t *testing.T) {
+	since := time.Date(2020, 10, 20, 19, 59, 59, 0, time.UTC)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github.v3+json", r.Header.Get("Accept"))
+		assert.Equal(t, "token github-access-token", r.Header.Get("Authorization"))
+
+		q := r.URL.Query()
+		var body interface{}
+
+		switch r.Method + " " + r.URL.Path {
+		case "HEAD /api/v3/user":
+			w.Header().Set("X-OAuth-Scopes", "read:org, repo")
+		case "GET /api/v3/users/octocat":
+			body = gitHubUser1
+		case "GET /api/v3/repos/octocat/Hello-World":
+			body = gitHubRepository
+		case "GET /api/v3/repos/octocat/Hello-World/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e":
+			body = gitHubCommit1
+		case "GET /api/v3/repos/octocat/Hello-World/commits":
+			assert.Equal(t, "100", q.Get("per_page"))
+			if q.Get("sha") != "" {
+				assert.Equal(t, "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c", q.Get("sha"))
+				assert.Equal(t, "1", q.Get("page"))
+			} else {
+				assert.Equal(t, "3", q.Get("page"))
+				w.Header().Set("Link", `<https://github.example.com/api/v3/repositories/1296269/commits?per_page=100&page=1>; rel="first", <https://github.example.com/api/v3/repositories/1296269/commits?per_page=100&page=2>; rel="prev"`)
+			}
+			body = []github.Commit{gitHubCommit2, gitHubCommit1}
+		case "GET /api/v3/repos/octocat/Hello-World/commits/20c5414eccaa147f2d6644de4ca36f35293fa43e":
+			assert.Equal(t, "100", q.Get("per_page"))
+			assert.Equal(t, "2", q.Get("page"))
+			body = map[string]interface{}{
+				"sha":   "20c5414eccaa147f2d6644de4ca36f35293fa43e",
+				"files": []file{{Filename: "README.md", Status: "modified"}},
+			}
+		case "GET /api/v3/repos/octocat/Hello-World/branches/main":
+			body = gitHubBranch
+		case "GET /api/v3/repos/octocat/Hello-World/tags":
+			assert.Equal(t, "100", q.Get("per_page"))
+			assert.Equal(t, "1", q.Get("page"))
+			w.Header().Set("Link", `<https://github.example.com/api/v3/repositories/1296269/tags?per_page=100&page=2>; rel="next", <https://github.example.com/api/v3/repositories/1296269/tags?per_page=100&page=2>; rel="last"`)
+			body = []github.Tag{gitHubTag}
+		case "GET /api/v3/repos/octocat/Hello-World/issues":
+			assert.Equal(t, "closed", q.Get("state"))
+			assert.Equal(t, "2020-10-20T19:59:59Z", q.Get("since"))
+			body = []github.Issue{gitHubIssue1, gitHubIssue2}
+		case "GET /api/v3/repos/octocat/Hello-World/issues/1001/events":
+			body = []github.Event{gitHubEvent1}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			body = map[string]string{"message": "Not Found"}
+		}
+
+		if body != nil {
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	}))
+	defer ts.Close()
+
+	// The base URL of a GitHub Enterprise Server REST API has a path
+	client, err := newRESTClient(&http.Transport{}, ts.URL+"/api/v3/", "github-access-token", "octocat", "Hello-World")
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	users := &usersClient{client}
+	repo := &repoClient{client}
+	commits := &commitsClient{client}
+
+	t.Run("EnsureScopes", func(t *testing.T) {
+		assert.NoError(t, client.EnsureScopes(ctx, github.ScopeRepo))
+		assert.EqualError(t, client.EnsureScopes(ctx, github.ScopeAdminOrg), "access token does not have the scope: admin:org")
+	})
+
+	t.Run("GetUser", func(t *testing.T) {
+		user, _, err := users.Get(ctx, "octocat")
+		assert.NoError(t, err)
+		assert.Equal(t, &gitHubUser1, user)
+	})
+
+	t.Run("GetRepository", func(t *testing.T) {
+		repository, _, err := repo.Get(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &gitHubRepository, repository)
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		commit, _, err := repo.Commit(ctx, "6dcb09b5b57875f334f61aebed695e2e4193db5e")
+		assert.NoError(t, err)
+		assert.Equal(t, &gitHubCommit1, commit)
+	})
+
+	t.Run("DefaultBranchCommits", func(t *testing.T) {
+		commits, resp, err := repo.Commits(ctx, 100, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []github.Commit{gitHubCommit2, gitHubCommit1}, commits)
+		assert.Equal(t, github.Pages{First: 1, Prev: 2}, resp.Pages)
+	})
+
+	t.Run("Branch", func(t *testing.T) {
+		branch, _, err := repo.Branch(ctx, "main")
+		assert.NoError(t, err)
+		assert.Equal(t, &gitHubBranch, branch)
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		tags, resp, err := repo.Tags(ctx, 100, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []github.Tag{gitHubTag}, tags)
+		assert.Equal(t, github.Pages{Next: 2, Last: 2}, resp.Pages)
+	})
+
+	t.Run("Issues", func(t *testing.T) {
+		issues, _, err := repo.Issues(ctx, 100, 1, github.IssuesParams{State: "closed", Since: since})
+		assert.NoError(t, err)
+		assert.Equal(t, []github.Issue{gitHubIssue1, gitHubIssue2}, issues)
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		events, _, err := repo.Events(ctx, 1001, 100, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []github.Event{gitHubEvent1}, events)
+	})
+
+	t.Run("Commits", func(t *testing.T) {
+		c, _, err := commits.Commits(ctx, "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c", 100, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []github.Commit{gitHubCommit2, gitHubCommit1}, c)
+	})
+
+	t.Run("Files", func(t *testing.T) {
+		files, _, err := commits.Files(ctx, "20c5414eccaa147f2d6644de4ca36f35293fa43e", 100, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []file{{Filename: "README.md", Status: "modified"}}, files)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		files, _, err := commits.Files(ctx, "0000000000000000000000000000000000000000", 100, 1)
+		assert.Nil(t, files)
+		assert.EqualError(t, err, "GET "+ts.URL+"/api/v3/repos/octocat/Hello-World/commits/0000000000000000000000000000000000000000?page=1&per_page=100 404: Not Found")
+	})
+}
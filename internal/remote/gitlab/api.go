@@ -0,0 +1,313 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	userAgent = "moorara/changelog"
+	pageSize  = 100
+)
+
+// scope is a GitLab personal access token scope.
+type scope string
+
+const (
+	scopeAPI     scope = "api"
+	scopeReadAPI scope = "read_api"
+)
+
+type (
+	user struct {
+		ID          int    `json:"id"`
+		Username    string `json:"username"`
+		Name        string `json:"name"`
+		State       string `json:"state"`
+		PublicEmail string `json:"public_email"`
+		WebURL      string `json:"web_url"`
+	}
+
+	project struct {
+		ID                int    `json:"id"`
+		Name              string `json:"name"`
+		PathWithNamespace string `json:"path_with_namespace"`
+		DefaultBranch     string `json:"default_branch"`
+		WebURL            string `json:"web_url"`
+	}
+
+	commit struct {
+		ID             string    `json:"id"`
+		ShortID        string    `json:"short_id"`
+		Title          string    `json:"title"`
+		Message        string    `json:"message"`
+		AuthorName     string    `json:"author_name"`
+		AuthorEmail    string    `json:"author_email"`
+		AuthoredDate   time.Time `json:"authored_date"`
+		CommitterName  string    `json:"committer_name"`
+		CommitterEmail string    `json:"committer_email"`
+		CommittedDate  time.Time `json:"committed_date"`
+		ParentIDs      []string  `json:"parent_ids"`
+		WebURL         string    `json:"web_url"`
+	}
+
+	branch struct {
+		Name      string `json:"name"`
+		Protected bool   `json:"protected"`
+		Default   bool   `json:"default"`
+		Commit    commit `json:"commit"`
+	}
+
+	tag struct {
+		Name      string     `json:"name"`
+		Message   string     `json:"message"`
+		Target    string     `json:"target"`
+		Protected bool       `json:"protected"`
+		CreatedAt *time.Time `json:"created_at"`
+		Commit    commit     `json:"commit"`
+	}
+
+	diff struct {
+		OldPath     string `json:"old_path"`
+		NewPath     string `json:"new_path"`
+		NewFile     bool   `json:"new_file"`
+		RenamedFile bool   `json:"renamed_file"`
+		DeletedFile bool   `json:"deleted_file"`
+	}
+
+	milestone struct {
+		ID    int    `json:"id"`
+		IID   int    `json:"iid"`
+		Title string `json:"title"`
+		State string `json:"state"`
+	}
+
+	issue struct {
+		ID        int        `json:"id"`
+		IID       int        `json:"iid"`
+		Title     string     `json:"title"`
+		State     string     `json:"state"`
+		Labels    []string   `json:"labels"`
+		Milestone *milestone `json:"milestone"`
+		Author    user       `json:"author"`
+		ClosedBy  *user      `json:"closed_by"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		ClosedAt  *time.Time `json:"closed_at"`
+		WebURL    string     `json:"web_url"`
+	}
+
+	mergeRequest struct {
+		ID              int        `json:"id"`
+		IID             int        `json:"iid"`
+		Title           string     `json:"title"`
+		State           string     `json:"state"`
+		Labels          []string   `json:"labels"`
+		Milestone       *milestone `json:"milestone"`
+		Author          user       `json:"author"`
+		MergedBy        *user      `json:"merged_by"`
+		MergeUser       *user      `json:"merge_user"`
+		TargetBranch    string     `json:"target_branch"`
+		SHA             string     `json:"sha"`
+		MergeCommitSHA  string     `json:"merge_commit_sha"`
+		SquashCommitSHA string     `json:"squash_commit_sha"`
+		CreatedAt       time.Time  `json:"created_at"`
+		UpdatedAt       time.Time  `json:"updated_at"`
+		MergedAt        *time.Time `json:"merged_at"`
+		WebURL          string     `json:"web_url"`
+	}
+
+	stateEvent struct {
+		ID           int       `json:"id"`
+		User         user      `json:"user"`
+		State        string    `json:"state"`
+		ResourceType string    `json:"resource_type"`
+		ResourceID   int       `json:"resource_id"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+
+	personalAccessToken struct {
+		ID      int      `json:"id"`
+		Name    string   `json:"name"`
+		Revoked bool     `json:"revoked"`
+		Active  bool     `json:"active"`
+		Scopes  []string `json:"scopes"`
+	}
+)
+
+// mergeCommitSHA returns the hash of the commit that landed a merge request on the target branch.
+// Merge requests merged with fast-forward have no merge commit and squashed ones may only have a squash commit.
+func (m mergeRequest) mergeCommitSHA() string {
+	switch {
+	case m.MergeCommitSHA != "":
+		return m.MergeCommitSHA
+	case m.SquashCommitSHA != "":
+		return m.SquashCommitSHA
+	default:
+		return m.SHA
+	}
+}
+
+// merger returns the user who merged a merge request if known.
+// merged_by is deprecated in favor of merge_user, but older GitLab instances only return the former.
+func (m mergeRequest) merger() *user {
+	if m.MergeUser != nil {
+		return m.MergeUser
+	}
+	return m.MergedBy
+}
+
+// pages has the pagination information of a GitLab API response.
+// GitLab does not return the total number of pages for collections with more than 10,000 items,
+// in which case last is zero and the pages can only be followed using next.
+type pages struct {
+	next int
+	last int
+}
+
+func parsePages(resp *http.Response) pages {
+	next, _ := strconv.Atoi(resp.Header.Get("X-Next-Page"))
+	last, _ := strconv.Atoi(resp.Header.Get("X-Total-Pages"))
+
+	return pages{
+		next: next,
+		last: last,
+	}
+}
+
+// responseError is returned when a GitLab API call is not successful.
+type responseError struct {
+	method     string
+	url        string
+	statusCode int
+	message    string
+}
+
+func newResponseError(resp *http.Response) *responseError {
+	e := &responseError{
+		method:     resp.Request.Method,
+		url:        resp.Request.URL.String(),
+		statusCode: resp.StatusCode,
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return e
+	}
+
+	// GitLab error responses are either {"message": ...} or {"error": ..., "error_description": ...}
+	body := struct {
+		Message          interface{} `json:"message"`
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}{}
+
+	if err := json.Unmarshal(b, &body); err != nil {
+		e.message = string(b)
+		return e
+	}
+
+	switch {
+	case body.Message != nil:
+		e.message = fmt.Sprintf("%v", body.Message)
+	case body.ErrorDescription != "":
+		e.message = body.ErrorDescription
+	default:
+		e.message = body.Error
+	}
+
+	return e
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s %s %d: %s", e.method, e.url, e.statusCode, e.message)
+}
+
+// projectID returns the URL-encoded path of a project that can be used as the project id in GitLab API calls.
+func projectID(path string) string {
+	return url.PathEscape(path)
+}
+
+func pageParams(page int) url.Values {
+	params := url.Values{}
+	params.Set("per_page", strconv.Itoa(pageSize))
+	params.Set("page", strconv.Itoa(page))
+
+	return params
+}
+
+// call makes a GET request to a GitLab API endpoint and decodes the response body into out.
+func (r *repo) call(ctx context.Context, endpoint string, params url.Values, out interface{}) (pages, error) {
+	u := r.apiURL + endpoint
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return pages{}, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if r.accessToken != "" {
+		req.Header.Set("PRIVATE-TOKEN", r.accessToken)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return pages{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return pages{}, newResponseError(resp)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return pages{}, err
+	}
+
+	return parsePages(resp), nil
+}
+
+// fetchAllPages calls fetch for every page of a paginated GitLab API endpoint.
+// If the total number of pages is known after fetching the first page, the remaining pages are fetched concurrently.
+// Otherwise, the remaining pages are fetched one after another by following the next page.
+func fetchAllPages(ctx context.Context, fetch func(context.Context, int) (pages, error)) error {
+	pg, err := fetch(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	if pg.last > 0 {
+		g, ctx := errgroup.WithContext(ctx)
+
+		for p := 2; p <= pg.last; p++ {
+			p := p // https://golang.org/doc/faq#closures_and_goroutines
+			g.Go(func() error {
+				_, err := fetch(ctx, p)
+				return err
+			})
+		}
+
+		return g.Wait()
+	}
+
+	// pg.next == 0 is not a valid page number and causes the loop to exit
+	for p := pg.next; p > 0; p = pg.next {
+		if pg, err = fetch(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeRequest_mergeCommitSHA(t *testing.T) {
+	tests := []struct {
+		name        string
+		m           mergeRequest
+		expectedSHA string
+	}{
+		{
+			name: "MergeCommit",
+			m: mergeRequest{
+				SHA:             "8e5d3a4bbf7a4a2a4e3b36f3d2a4e30d4f4b5c6d",
+				MergeCommitSHA:  "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+				SquashCommitSHA: "",
+			},
+			expectedSHA: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+		{
+			name: "SquashCommit",
+			m: mergeRequest{
+				SHA:             "8e5d3a4bbf7a4a2a4e3b36f3d2a4e30d4f4b5c6d",
+				MergeCommitSHA:  "",
+				SquashCommitSHA: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			},
+			expectedSHA: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		},
+		{
+			name: "FastForward",
+			m: mergeRequest{
+				SHA:             "8e5d3a4bbf7a4a2a4e3b36f3d2a4e30d4f4b5c6d",
+				MergeCommitSHA:  "",
+				SquashCommitSHA: "",
+			},
+			expectedSHA: "8e5d3a4bbf7a4a2a4e3b36f3d2a4e30d4f4b5c6d",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedSHA, tc.m.mergeCommitSHA())
+		})
+	}
+}
+
+func TestMergeRequest_merger(t *testing.T) {
+	tests := []struct {
+		name           string
+		m              mergeRequest
+		expectedMerger *user
+	}{
+		{
+			name:           "MergeUser",
+			m:              mergeRequest{MergedBy: &gitLabUser2, MergeUser: &gitLabUser3},
+			expectedMerger: &gitLabUser3,
+		},
+		{
+			name:           "MergedBy",
+			m:              mergeRequest{MergedBy: &gitLabUser2},
+			expectedMerger: &gitLabUser2,
+		},
+		{
+			name:           "Unknown",
+			m:              mergeRequest{},
+			expectedMerger: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedMerger, tc.m.merger())
+		})
+	}
+}
+
+func TestParsePages(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        http.Header
+		expectedPages pages
+	}{
+		{
+			name:          "NoHeader",
+			header:        http.Header{},
+			expectedPages: pages{},
+		},
+		{
+			name: "NextPageOnly",
+			header: http.Header{
+				"X-Next-Page": []string{"2"},
+			},
+			expectedPages: pages{next: 2},
+		},
+		{
+			name: "NextAndLastPages",
+			header: http.Header{
+				"X-Next-Page":   []string{"2"},
+				"X-Total-Pages": []string{"4"},
+			},
+			expectedPages: pages{next: 2, last: 4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{Header: tc.header}
+			assert.Equal(t, tc.expectedPages, parsePages(resp))
+		})
+	}
+}
+
+func TestNewResponseError(t *testing.T) {
+	req := &http.Request{
+		Method: "GET",
+		URL:    &url.URL{Scheme: "https", Host: "gitlab.com", Path: "/api/v4/projects"},
+	}
+
+	tests := []struct {
+		name          string
+		resp          *http.Response
+		expectedError string
+	}{
+		{
+			name: "Message",
+			resp: &http.Response{
+				Request:    req,
+				StatusCode: 404,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"message": "404 Project Not Found"}`)),
+			},
+			expectedError: "GET https://gitlab.com/api/v4/projects 404: 404 Project Not Found",
+		},
+		{
+			name: "ErrorDescription",
+			resp: &http.Response{
+				Request:    req,
+				StatusCode: 401,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error": "invalid_token", "error_description": "Token was revoked"}`)),
+			},
+			expectedError: "GET https://gitlab.com/api/v4/projects 401: Token was revoked",
+		},
+		{
+			name: "NotJSON",
+			resp: &http.Response{
+				Request:    req,
+				StatusCode: 502,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`Bad Gateway`)),
+			},
+			expectedError: "GET https://gitlab.com/api/v4/projects 502: Bad Gateway",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newResponseError(tc.resp)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestFetchAllPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		pages         map[int]pages
+		errors        map[int]error
+		expectedPages []int
+		expectedError string
+	}{
+		{
+			name:          "FirstPageFails",
+			pages:         map[int]pages{},
+			errors:        map[int]error{1: errors.New("error on fetching page 1")},
+			expectedError: "error on fetching page 1",
+		},
+		{
+			name: "Concurrent",
+			pages: map[int]pages{
+				1: {next: 2, last: 3},
+				2: {next: 3, last: 3},
+				3: {next: 0, last: 3},
+			},
+			errors:        map[int]error{},
+			expectedPages: []int{1, 2, 3},
+		},
+		{
+			name: "ConcurrentFails",
+			pages: map[int]pages{
+				1: {next: 2, last: 3},
+				2: {next: 3, last: 3},
+			},
+			errors:        map[int]error{3: errors.New("error on fetching page 3")},
+			expectedError: "error on fetching page 3",
+		},
+		{
+			name: "Sequential",
+			pages: map[int]pages{
+				1: {next: 2},
+				2: {next: 3},
+				3: {next: 0},
+			},
+			errors:        map[int]error{},
+			expectedPages: []int{1, 2, 3},
+		},
+		{
+			name: "SequentialFails",
+			pages: map[int]pages{
+				1: {next: 2},
+			},
+			errors:        map[int]error{2: errors.New("error on fetching page 2")},
+			expectedError: "error on fetching page 2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			fetched := map[int]bool{}
+
+			err := fetchAllPages(context.Background(), func(ctx context.Context, p int) (pages, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err, ok := tc.errors[p]; ok {
+					return pages{}, err
+				}
+				fetched[p] = true
+				return tc.pages[p], nil
+			})
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, fetched, len(tc.expectedPages))
+				for _, p := range tc.expectedPages {
+					assert.True(t, fetched[p])
+				}
+			}
+		})
+	}
+}
@@ -2,77 +2,507 @@ package gitlab
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/moorara/changelog/internal/remote"
 	"github.com/moorara/changelog/log"
 )
 
+const (
+	resourceIssues        = "issues"
+	resourceMergeRequests = "merge_requests"
+)
+
 // repo implements the remote.Repo interface for GitLab.
 type repo struct {
 	logger      log.Logger
 	client      *http.Client
+	apiURL      string
+	webURL      string
 	path        string
 	accessToken string
+	stores      struct {
+		users   *remote.Store
+		commits *remote.Store
+	}
 }
 
 // NewRepo creates a new GitLab repository.
-func NewRepo(logger log.Logger, path, accessToken string) remote.Repo {
-	transport := &http.Transport{}
+// apiURL and webURL are the base URLs of the GitLab instance (i.e. https://gitlab.com/api/v4 and https://gitlab.com).
+// For self-managed GitLab, they are http(s)://<hostname>/api/v4 and http(s)://<hostname> respectively.
+func NewRepo(logger log.Logger, cache *remote.Cache, apiURL, webURL, path, accessToken string) remote.Repo {
+	transport := remote.NewTransport(logger, cache)
 	client := &http.Client{
 		Transport: transport,
 	}
 
-	return &repo{
+	r := &repo{
 		logger:      logger,
 		client:      client,
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		webURL:      strings.TrimSuffix(webURL, "/"),
 		path:        path,
 		accessToken: accessToken,
 	}
+
+	r.stores.users = remote.NewStore()
+	r.stores.commits = remote.NewStore()
+
+	return r
+}
+
+func (r *repo) getUser(ctx context.Context, id int) (user, error) {
+	// First, check the cache
+	if v, ok := r.stores.users.Load(id); ok {
+		u := v.(user)
+		return u, nil
+	}
+
+	u := user{}
+	if _, err := r.call(ctx, fmt.Sprintf("/users/%d", id), nil, &u); err != nil {
+		return user{}, err
+	}
+
+	// Update the cache
+	r.stores.users.Save(u.ID, u)
+
+	return u, nil
+}
+
+func (r *repo) getCommit(ctx context.Context, sha string) (commit, error) {
+	// First, check the cache
+	if v, ok := r.stores.commits.Load(sha); ok {
+		c := v.(commit)
+		return c, nil
+	}
+
+	c := commit{}
+	endpoint := fmt.Sprintf("/projects/%s/repository/commits/%s", projectID(r.path), sha)
+	if _, err := r.call(ctx, endpoint, nil, &c); err != nil {
+		return commit{}, err
+	}
+
+	// Update the cache
+	r.stores.commits.Save(c.ID, c)
+
+	return c, nil
+}
+
+func (r *repo) findEvent(ctx context.Context, resource string, iid int, state string) (stateEvent, error) {
+	endpoint := fmt.Sprintf("/projects/%s/%s/%d/resource_state_events", projectID(r.path), resource, iid)
+
+	for p := 1; p > 0; {
+		events := []stateEvent{}
+		pg, err := r.call(ctx, endpoint, pageParams(p), &events)
+		if err != nil {
+			return stateEvent{}, err
+		}
+
+		for _, e := range events {
+			if e.State == state {
+				r.logger.Debugf("Found %s event for %s %d", state, resource, iid)
+				return e, nil
+			}
+		}
+
+		// pg.next == 0 is not a valid page number and causes the loop to exit
+		p = pg.next
+	}
+
+	return stateEvent{}, nil
 }
 
 // FutureTag returns a tag that does not exist yet for a GitLab repository.
 func (r *repo) FutureTag(name string) remote.Tag {
-	return remote.Tag{}
+	return remote.Tag{
+		Name:   name,
+		Time:   time.Now(),
+		WebURL: fmt.Sprintf("%s/%s/-/tree/%s", r.webURL, r.path, name),
+	}
 }
 
 // CompareURL returns a URL for comparing two revisions for a GitLab repository.
 func (r *repo) CompareURL(base, head string) string {
-	return ""
+	return fmt.Sprintf("%s/%s/-/compare/%s...%s", r.webURL, r.path, base, head)
+}
+
+// CommitURL returns the web URL of a commit for a GitLab repository.
+func (r *repo) CommitURL(hash string) string {
+	return fmt.Sprintf("%s/%s/-/commit/%s", r.webURL, r.path, hash)
 }
 
 // CheckPermissions ensures the client has all the required permissions for a GitLab repository.
-func (r *repo) CheckPermissions(context.Context) error {
-	return nil
+func (r *repo) CheckPermissions(ctx context.Context) error {
+	t := personalAccessToken{}
+	if _, err := r.call(ctx, "/personal_access_tokens/self", nil, &t); err != nil {
+		return err
+	}
+
+	for _, s := range t.Scopes {
+		if s == string(scopeAPI) || s == string(scopeReadAPI) {
+			r.logger.Debugf("GitLab token scopes verified: %s", s)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("GitLab access token does not have any of the required scopes: %s, %s", scopeReadAPI, scopeAPI)
 }
 
 // FetchFirstCommit retrieves the firist/initial commit for a GitLab repository.
 func (r *repo) FetchFirstCommit(ctx context.Context) (remote.Commit, error) {
-	return remote.Commit{}, nil
+	r.logger.Debug("Fetching the first GitLab commit ...")
+
+	var c commit
+	endpoint := fmt.Sprintf("/projects/%s/repository/commits", projectID(r.path))
+
+	for p := 1; p > 0; {
+		commits := []commit{}
+		pg, err := r.call(ctx, endpoint, pageParams(p), &commits)
+		if err != nil {
+			return remote.Commit{}, err
+		}
+
+		// Add commits to commit store
+		for _, c := range commits {
+			r.stores.commits.Save(c.ID, c)
+		}
+
+		if l := len(commits); l > 0 {
+			c = commits[l-1]
+		}
+
+		// Jump to the last page if it is known, otherwise follow the next page
+		// pg.next == 0 is not a valid page number and causes the loop to exit
+		if pg.last > p {
+			p = pg.last
+		} else {
+			p = pg.next
+		}
+	}
+
+	commit := toCommit(c)
+
+	r.logger.Debugf("Fetched the first GitLab commit: %s", commit)
+
+	return commit, nil
 }
 
 // FetchBranch retrieves a branch by name for a GitLab repository.
 func (r *repo) FetchBranch(ctx context.Context, name string) (remote.Branch, error) {
-	return remote.Branch{}, nil
+	b := branch{}
+	endpoint := fmt.Sprintf("/projects/%s/repository/branches/%s", projectID(r.path), url.PathEscape(name))
+	if _, err := r.call(ctx, endpoint, nil, &b); err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(b)
+
+	r.logger.Debugf("Fetched GitLab branch: %s", name)
+
+	return branch, nil
 }
 
 // FetchDefaultBranch retrieves the default branch for a GitLab repository.
 func (r *repo) FetchDefaultBranch(ctx context.Context) (remote.Branch, error) {
-	return remote.Branch{}, nil
+	p := project{}
+	endpoint := fmt.Sprintf("/projects/%s", projectID(r.path))
+	if _, err := r.call(ctx, endpoint, nil, &p); err != nil {
+		return remote.Branch{}, err
+	}
+
+	b := branch{}
+	endpoint = fmt.Sprintf("/projects/%s/repository/branches/%s", projectID(r.path), url.PathEscape(p.DefaultBranch))
+	if _, err := r.call(ctx, endpoint, nil, &b); err != nil {
+		return remote.Branch{}, err
+	}
+
+	branch := toBranch(b)
+
+	r.logger.Debugf("Fetched GitLab default branch: %s", b.Name)
+
+	return branch, nil
 }
 
 // FetchTags retrieves all tags for a GitLab repository.
 func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
-	return remote.Tags{}, nil
+	r.logger.Debug("Fetching GitLab tags ...")
+
+	// ==============================> FETCH TAGS <==============================
+
+	tagStore := remote.NewStore()
+	endpoint := fmt.Sprintf("/projects/%s/repository/tags", projectID(r.path))
+
+	// GitLab tags come with the commits they point to
+	err := fetchAllPages(ctx, func(ctx context.Context, p int) (pages, error) {
+		r.logger.Debugf("Fetched GitLab tags page %d ...", p)
+		tags := []tag{}
+		pg, err := r.call(ctx, endpoint, pageParams(p), &tags)
+		if err != nil {
+			return pages{}, err
+		}
+		for _, t := range tags {
+			tagStore.Save(t.Name, t)
+			r.stores.commits.Save(t.Commit.ID, t.Commit)
+		}
+		return pg, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// ==============================> RESOLVING TAGS <==============================
+
+	tags := resolveTags(tagStore, r.webURL, r.path)
+
+	r.logger.Debugf("GitLab tags are fetched: %d", len(tags))
+
+	return tags, nil
 }
 
 // FetchIssuesAndMerges retrieves all closed issues and merged merge requests for a GitLab repository.
 func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
-	return remote.Issues{}, remote.Merges{}, nil
+	if since.IsZero() {
+		r.logger.Info("Fetching GitLab issues and merge requests since the beginning ...")
+	} else {
+		r.logger.Infof("Fetching GitLab issues and merge requests since %s ...", since.Format(time.RFC3339))
+	}
+
+	params := func(p int, state string) url.Values {
+		params := pageParams(p)
+		params.Set("state", state)
+		params.Set("scope", "all")
+		if !since.IsZero() {
+			params.Set("updated_after", since.Format(time.RFC3339))
+		}
+		return params
+	}
+
+	// ==============================> FETCH ISSUES & MERGE REQUESTS <==============================
+
+	issueStore := remote.NewStore()
+	mergeStore := remote.NewStore()
+
+	g1, ctx1 := errgroup.WithContext(ctx)
+
+	// Fetch closed issues
+	g1.Go(func() error {
+		endpoint := fmt.Sprintf("/projects/%s/issues", projectID(r.path))
+		return fetchAllPages(ctx1, func(ctx context.Context, p int) (pages, error) {
+			r.logger.Debugf("Fetched GitLab issues page %d ...", p)
+			issues := []issue{}
+			pg, err := r.call(ctx, endpoint, params(p, "closed"), &issues)
+			if err != nil {
+				return pages{}, err
+			}
+			for _, i := range issues {
+				issueStore.Save(i.IID, i)
+			}
+			return pg, nil
+		})
+	})
+
+	// Fetch merged merge requests
+	g1.Go(func() error {
+		endpoint := fmt.Sprintf("/projects/%s/merge_requests", projectID(r.path))
+		return fetchAllPages(ctx1, func(ctx context.Context, p int) (pages, error) {
+			r.logger.Debugf("Fetched GitLab merge requests page %d ...", p)
+			merges := []mergeRequest{}
+			pg, err := r.call(ctx, endpoint, params(p, "merged"), &merges)
+			if err != nil {
+				return pages{}, err
+			}
+			for _, m := range merges {
+				mergeStore.Save(m.IID, m)
+			}
+			return pg, nil
+		})
+	})
+
+	if err := g1.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	r.logger.Debugf("Fetched GitLab issues (%d) and merge requests (%d)", issueStore.Len(), mergeStore.Len())
+
+	// ==============================> FETCH EVENTS & COMMITS <==============================
+
+	r.logger.Debug("Fetching GitLab events and commits for issues and merge requests ...")
+
+	eventStore := remote.NewStore()
+
+	g2, ctx2 := errgroup.WithContext(ctx)
+
+	// Search events only for issues without a known closer
+	_ = issueStore.ForEach(func(k, v interface{}) error {
+		i := v.(issue)
+
+		if i.ClosedBy == nil {
+			g2.Go(func() error {
+				e, err := r.findEvent(ctx2, resourceIssues, i.IID, "closed")
+				if err != nil {
+					return err
+				}
+				// If the event is empty/zero, the desired event has not been found
+				if e.ID != 0 {
+					eventStore.Save(eventKey{resourceIssues, i.IID}, e)
+				}
+				return nil
+			})
+		}
+
+		return nil
+	})
+
+	// Fetch merge commits and search events only for merge requests without a known merger
+	_ = mergeStore.ForEach(func(k, v interface{}) error {
+		m := v.(mergeRequest)
+
+		g2.Go(func() error {
+			if m.merger() == nil {
+				e, err := r.findEvent(ctx2, resourceMergeRequests, m.IID, "merged")
+				if err != nil {
+					return err
+				}
+				// If the event is empty/zero, the desired event has not been found
+				if e.ID != 0 {
+					eventStore.Save(eventKey{resourceMergeRequests, m.IID}, e)
+				}
+			}
+
+			_, err := r.getCommit(ctx2, m.mergeCommitSHA())
+			return err
+		})
+
+		return nil
+	})
+
+	if err := g2.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	// ==============================> FETCH USERS <==============================
+
+	r.logger.Debug("Fetching GitLab users for issues and merge requests ...")
+
+	// Fetch author and closer users for issues
+	err := issueStore.ForEach(func(k, v interface{}) error {
+		i := v.(issue)
+
+		// Only fetch the users if the closer of the issue is known
+		if id, ok := closerID(i, eventStore); ok {
+			if _, err := r.getUser(ctx, i.Author.ID); err != nil {
+				return err
+			}
+			_, err := r.getUser(ctx, id)
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Fetch author and merger users for merge requests
+	err = mergeStore.ForEach(func(k, v interface{}) error {
+		m := v.(mergeRequest)
+
+		// Only fetch the users if the merger of the merge request is known
+		if id, ok := mergerID(m, eventStore); ok {
+			if _, err := r.getUser(ctx, m.Author.ID); err != nil {
+				return err
+			}
+			_, err := r.getUser(ctx, id)
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// ==============================> JOINING ISSUES, MERGES, EVENTS, COMMITS, & USERS <==============================
+
+	issues, merges := resolveIssuesAndMerges(issueStore, mergeStore, eventStore, r.stores.commits, r.stores.users)
+
+	r.logger.Debugf("Resolved and sorted GitLab issues (%d) and merge requests (%d)", len(issues), len(merges))
+	r.logger.Infof("All GitLab issues (%d) and merge requests (%d) are fetched", len(issues), len(merges))
+
+	return issues, merges, nil
 }
 
 // FetchParentCommits retrieves all parent commits of a given commit hash for a GitLab repository.
 func (r *repo) FetchParentCommits(ctx context.Context, hash string) (remote.Commits, error) {
-	return remote.Commits{}, nil
+	r.logger.Debugf("Fetching all GitLab parent commits for %s ...", hash)
+
+	commitStore := remote.NewStore()
+	endpoint := fmt.Sprintf("/projects/%s/repository/commits", projectID(r.path))
+
+	// Listing the commits of a revision returns the commit itself and all of its ancestors
+	err := fetchAllPages(ctx, func(ctx context.Context, p int) (pages, error) {
+		params := pageParams(p)
+		params.Set("ref_name", hash)
+
+		commits := []commit{}
+		pg, err := r.call(ctx, endpoint, params, &commits)
+		if err != nil {
+			return pages{}, err
+		}
+		for _, c := range commits {
+			commitStore.Save(c.ID, c)
+			r.stores.commits.Save(c.ID, c)
+		}
+		return pg, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	commits := resolveCommits(commitStore)
+
+	r.logger.Debugf("All GitLab parent commits for %s are fetched", hash)
+
+	return commits, nil
+}
+
+// FetchCommitFiles retrieves the paths of files changed by a commit for a GitLab repository.
+// Renamed files are changed in both their old and new paths.
+func (r *repo) FetchCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	r.logger.Debugf("Fetching GitLab files changed by %s ...", hash)
+
+	files := []string{}
+	endpoint := fmt.Sprintf("/projects/%s/repository/commits/%s/diff", projectID(r.path), hash)
+
+	for p := 1; p > 0; {
+		diffs := []diff{}
+		pg, err := r.call(ctx, endpoint, pageParams(p), &diffs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range diffs {
+			files = append(files, d.NewPath)
+			if d.OldPath != d.NewPath {
+				files = append(files, d.OldPath)
+			}
+		}
+
+		// pg.next == 0 is not a valid page number and causes the loop to exit
+		p = pg.next
+	}
+
+	r.logger.Debugf("GitLab files changed by %s are fetched: %d", hash, len(files))
+
+	return files, nil
 }
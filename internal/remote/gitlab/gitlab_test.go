@@ -2,32 +2,54 @@ package gitlab
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/moorara/changelog/internal/remote"
 	"github.com/moorara/changelog/log"
 )
 
+const projectPath = "/projects/octocat%2FHello-World"
+
 func TestNewRepo(t *testing.T) {
 	tests := []struct {
 		name        string
 		logger      log.Logger
+		apiURL      string
+		webURL      string
 		path        string
 		accessToken string
+		expectedAPI string
+		expectedWeb string
 	}{
 		{
 			name:        "OK",
 			logger:      log.New(log.None),
+			apiURL:      "https://gitlab.com/api/v4",
+			webURL:      "https://gitlab.com",
 			path:        "moorara/changelog",
 			accessToken: "gitlab-access-token",
+			expectedAPI: "https://gitlab.com/api/v4",
+			expectedWeb: "https://gitlab.com",
+		},
+		{
+			name:        "SelfManaged",
+			logger:      log.New(log.None),
+			apiURL:      "https://gitlab.example.com/api/v4/",
+			webURL:      "https://gitlab.example.com/",
+			path:        "moorara/changelog",
+			accessToken: "gitlab-access-token",
+			expectedAPI: "https://gitlab.example.com/api/v4",
+			expectedWeb: "https://gitlab.example.com",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			r := NewRepo(tc.logger, tc.path, tc.accessToken)
+			r := NewRepo(tc.logger, nil, tc.apiURL, tc.webURL, tc.path, tc.accessToken)
 			assert.NotNil(t, r)
 
 			gr, ok := r.(*repo)
@@ -35,105 +57,853 @@ func TestNewRepo(t *testing.T) {
 
 			assert.Equal(t, tc.logger, gr.logger)
 			assert.NotNil(t, gr.client)
+			assert.Equal(t, tc.expectedAPI, gr.apiURL)
+			assert.Equal(t, tc.expectedWeb, gr.webURL)
 			assert.Equal(t, tc.path, gr.path)
 			assert.Equal(t, tc.accessToken, gr.accessToken)
+			assert.NotNil(t, gr.stores.users)
+			assert.NotNil(t, gr.stores.commits)
+		})
+	}
+}
+
+func TestRepo_getUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		usersStore    *remote.Store
+		routes        map[string]MockResponse
+		ctx           context.Context
+		id            int
+		expectedUser  user
+		expectedError string
+	}{
+		{
+			name: "CacheHit",
+			usersStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				1: gitLabUser1,
+			}),
+			ctx:          context.Background(),
+			id:           1,
+			expectedUser: gitLabUser1,
+		},
+		{
+			name:          "Error",
+			usersStore:    remote.NewStore(),
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			id:            1,
+			expectedError: "/users/1 404: 404 Not Found",
+		},
+		{
+			name:       "Success",
+			usersStore: remote.NewStore(),
+			routes: map[string]MockResponse{
+				"/users/1": {StatusCode: http.StatusOK, Body: gitLabUser1},
+			},
+			ctx:          context.Background(),
+			id:           1,
+			expectedUser: gitLabUser1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+			r.stores.users = tc.usersStore
+
+			user, err := r.getUser(tc.ctx, tc.id)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, user)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedUser, user)
+			}
+		})
+	}
+}
+
+func TestRepo_getCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		commitsStore   *remote.Store
+		routes         map[string]MockResponse
+		ctx            context.Context
+		sha            string
+		expectedCommit commit
+		expectedError  string
+	}{
+		{
+			name: "CacheHit",
+			commitsStore: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": gitLabCommit1,
+			}),
+			ctx:            context.Background(),
+			sha:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: gitLabCommit1,
+		},
+		{
+			name:          "Error",
+			commitsStore:  remote.NewStore(),
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			sha:           "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedError: "/repository/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e 404: 404 Not Found",
+		},
+		{
+			name:         "Success",
+			commitsStore: remote.NewStore(),
+			routes: map[string]MockResponse{
+				projectPath + "/repository/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e": {StatusCode: http.StatusOK, Body: gitLabCommit1},
+			},
+			ctx:            context.Background(),
+			sha:            "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+			expectedCommit: gitLabCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+			r.stores.commits = tc.commitsStore
+
+			commit, err := r.getCommit(tc.ctx, tc.sha)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+			}
+		})
+	}
+}
+
+func TestRepo_findEvent(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		resource      string
+		iid           int
+		state         string
+		expectedEvent stateEvent
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			resource:      resourceIssues,
+			iid:           1002,
+			state:         "closed",
+			expectedError: "/issues/1002/resource_state_events?page=1&per_page=100 404: 404 Not Found",
+		},
+		{
+			name: "NotFound",
+			routes: map[string]MockResponse{
+				projectPath + "/issues/1002/resource_state_events?page=1": {StatusCode: http.StatusOK, Body: []stateEvent{}},
+			},
+			ctx:           context.Background(),
+			resource:      resourceIssues,
+			iid:           1002,
+			state:         "closed",
+			expectedEvent: stateEvent{},
+		},
+		{
+			name: "Found",
+			routes: map[string]MockResponse{
+				projectPath + "/issues/1002/resource_state_events?page=1": {StatusCode: http.StatusOK, NextPage: 2, TotalPages: 2, Body: []stateEvent{{ID: 2, State: "reopened"}}},
+				projectPath + "/issues/1002/resource_state_events?page=2": {StatusCode: http.StatusOK, TotalPages: 2, Body: []stateEvent{gitLabEvent}},
+			},
+			ctx:           context.Background(),
+			resource:      resourceIssues,
+			iid:           1002,
+			state:         "closed",
+			expectedEvent: gitLabEvent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+
+			event, err := r.findEvent(tc.ctx, tc.resource, tc.iid, tc.state)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, event)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedEvent, event)
+			}
 		})
 	}
 }
 
 func TestRepo_FutureTag(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
+	tests := []struct {
+		name        string
+		webURL      string
+		path        string
+		tagName     string
+		expectedTag remote.Tag
+	}{
+		{
+			name:    "OK",
+			webURL:  "https://gitlab.com",
+			path:    "octocat/Hello-World",
+			tagName: "v0.1.0",
+			expectedTag: remote.Tag{
+				Name:   "v0.1.0",
+				WebURL: "https://gitlab.com/octocat/Hello-World/-/tree/v0.1.0",
+			},
+		},
+		{
+			name:    "SelfManaged",
+			webURL:  "https://gitlab.example.com",
+			path:    "octocat/Hello-World",
+			tagName: "v0.1.0",
+			expectedTag: remote.Tag{
+				Name:   "v0.1.0",
+				WebURL: "https://gitlab.example.com/octocat/Hello-World/-/tree/v0.1.0",
+			},
+		},
 	}
 
-	tag := r.FutureTag("v0.1.0")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger: log.New(log.None),
+				webURL: tc.webURL,
+				path:   tc.path,
+			}
+
+			tag := r.FutureTag(tc.tagName)
 
-	assert.Empty(t, tag)
+			assert.Equal(t, tc.expectedTag.Name, tag.Name)
+			assert.NotZero(t, tag.Time)
+			assert.Equal(t, tc.expectedTag.WebURL, tag.WebURL)
+		})
+	}
 }
 
 func TestRepo_CompareURL(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
+	tests := []struct {
+		name               string
+		webURL             string
+		path               string
+		base, head         string
+		expectedCompareURL string
+	}{
+		{
+			name:               "OK",
+			webURL:             "https://gitlab.com",
+			path:               "octocat/Hello-World",
+			base:               "v0.1.0",
+			head:               "v0.2.0",
+			expectedCompareURL: "https://gitlab.com/octocat/Hello-World/-/compare/v0.1.0...v0.2.0",
+		},
+		{
+			name:               "SelfManaged",
+			webURL:             "https://gitlab.example.com",
+			path:               "octocat/Hello-World",
+			base:               "v0.1.0",
+			head:               "v0.2.0",
+			expectedCompareURL: "https://gitlab.example.com/octocat/Hello-World/-/compare/v0.1.0...v0.2.0",
+		},
 	}
 
-	url := r.CompareURL("v0.1.0", "v0.2.0")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger: log.New(log.None),
+				webURL: tc.webURL,
+				path:   tc.path,
+			}
+
+			url := r.CompareURL(tc.base, tc.head)
 
-	assert.Empty(t, url)
+			assert.Equal(t, tc.expectedCompareURL, url)
+		})
+	}
 }
 
-func TestRepo_CheckPermissions(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
+func TestRepo_CommitURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		webURL      string
+		expectedURL string
+	}{
+		{
+			name:        "OK",
+			webURL:      "https://gitlab.com",
+			expectedURL: "https://gitlab.com/octocat/Hello-World/-/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
+		{
+			name:        "SelfManaged",
+			webURL:      "https://gitlab.example.com",
+			expectedURL: "https://gitlab.example.com/octocat/Hello-World/-/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		},
 	}
 
-	err := r.CheckPermissions(context.Background())
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger: log.New(log.None),
+				webURL: tc.webURL,
+				path:   "octocat/Hello-World",
+			}
+
+			url := r.CommitURL("6dcb09b5b57875f334f61aebed695e2e4193db5e")
 
-	assert.NoError(t, err)
+			assert.Equal(t, tc.expectedURL, url)
+		})
+	}
 }
 
-func TestRepo_FetchFirstCommit(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
+func TestRepo_CheckPermissions(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedError string
+	}{
+		{
+			name: "Unauthorized",
+			routes: map[string]MockResponse{
+				"/personal_access_tokens/self": {StatusCode: http.StatusUnauthorized, Body: map[string]string{"message": "401 Unauthorized"}},
+			},
+			ctx:           context.Background(),
+			expectedError: "/personal_access_tokens/self 401: 401 Unauthorized",
+		},
+		{
+			name: "MissingScopes",
+			routes: map[string]MockResponse{
+				"/personal_access_tokens/self": {StatusCode: http.StatusOK, Body: personalAccessToken{ID: 1, Active: true, Scopes: []string{"read_user"}}},
+			},
+			ctx:           context.Background(),
+			expectedError: "GitLab access token does not have any of the required scopes: read_api, api",
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				"/personal_access_tokens/self": {StatusCode: http.StatusOK, Body: personalAccessToken{ID: 1, Active: true, Scopes: []string{"read_user", "read_api"}}},
+			},
+			ctx:           context.Background(),
+			expectedError: "",
+		},
 	}
 
-	commit, err := r.FetchFirstCommit(context.Background())
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger:      log.New(log.None),
+				client:      ts.Client(),
+				apiURL:      ts.URL,
+				path:        "octocat/Hello-World",
+				accessToken: "gitlab-access-token",
+			}
+
+			err := r.CheckPermissions(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchFirstCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedCommit remote.Commit
+		expectedError  string
+	}{
+		{
+			name:          "FirstPageFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: "/repository/commits?page=1&per_page=100 404: 404 Not Found",
+		},
+		{
+			name: "LastPageFails",
+			routes: map[string]MockResponse{
+				projectPath + "/repository/commits?page=1": {StatusCode: http.StatusOK, NextPage: 2, TotalPages: 2, Body: []commit{gitLabCommit2}},
+			},
+			ctx:           context.Background(),
+			expectedError: "/repository/commits?page=2&per_page=100 404: 404 Not Found",
+		},
+		{
+			name: "JumpToLastPage",
+			routes: map[string]MockResponse{
+				projectPath + "/repository/commits?page=1": {StatusCode: http.StatusOK, NextPage: 2, TotalPages: 3, Body: []commit{gitLabCommit2}},
+				projectPath + "/repository/commits?page=3": {StatusCode: http.StatusOK, TotalPages: 3, Body: []commit{gitLabCommit1}},
+			},
+			ctx:            context.Background(),
+			expectedCommit: remoteCommit1,
+		},
+		{
+			name: "FollowNextPage",
+			routes: map[string]MockResponse{
+				projectPath + "/repository/commits?page=1": {StatusCode: http.StatusOK, NextPage: 2, Body: []commit{gitLabCommit2}},
+				projectPath + "/repository/commits?page=2": {StatusCode: http.StatusOK, Body: []commit{gitLabCommit1}},
+			},
+			ctx:            context.Background(),
+			expectedCommit: remoteCommit1,
+		},
+	}
 
-	assert.NoError(t, err)
-	assert.Empty(t, commit)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commit, err := r.FetchFirstCommit(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, commit)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommit, commit)
+			}
+		})
+	}
 }
 
 func TestRepo_FetchBranch(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		branchName     string
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			branchName:    "main",
+			expectedError: "/repository/branches/main 404: 404 Not Found",
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				projectPath + "/repository/branches/main": {StatusCode: http.StatusOK, Body: gitLabBranch},
+			},
+			ctx:            context.Background(),
+			branchName:     "main",
+			expectedBranch: remoteBranch,
+		},
 	}
 
-	branch, err := r.FetchBranch(context.Background(), "main")
-
-	assert.NoError(t, err)
-	assert.Empty(t, branch)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+
+			branch, err := r.FetchBranch(tc.ctx, tc.branchName)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
 }
 
 func TestRepo_FetchDefaultBranch(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name:          "ProjectError",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: "/projects/octocat%2FHello-World 404: 404 Not Found",
+		},
+		{
+			name: "BranchError",
+			routes: map[string]MockResponse{
+				projectPath: {StatusCode: http.StatusOK, Body: gitLabProject},
+			},
+			ctx:           context.Background(),
+			expectedError: "/repository/branches/main 404: 404 Not Found",
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				projectPath: {StatusCode: http.StatusOK, Body: gitLabProject},
+				projectPath + "/repository/branches/main": {StatusCode: http.StatusOK, Body: gitLabBranch},
+			},
+			ctx:            context.Background(),
+			expectedBranch: remoteBranch,
+		},
 	}
 
-	branch, err := r.FetchDefaultBranch(context.Background())
-
-	assert.NoError(t, err)
-	assert.Empty(t, branch)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+
+			branch, err := r.FetchDefaultBranch(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Empty(t, branch)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			}
+		})
+	}
 }
 
 func TestRepo_FetchTags(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
-	}
+	tag2 := tag{Name: "v0.0.1", Commit: gitLabCommit1}
 
-	tags, err := r.FetchTags(context.Background())
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		expectedTags  remote.Tags
+		expectedError string
+	}{
+		{
+			name:          "FirstPageFails",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			expectedError: "/repository/tags?page=1&per_page=100 404: 404 Not Found",
+		},
+		{
+			name: "SecondPageFails",
+			routes: map[string]MockResponse{
+				projectPath + "/repository/tags?page=1": {StatusCode: http.StatusOK, NextPage: 2, TotalPages: 2, Body: []tag{gitLabTag}},
+			},
+			ctx:           context.Background(),
+			expectedError: "/repository/tags?page=2&per_page=100 404: 404 Not Found",
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				projectPath + "/repository/tags?page=1": {StatusCode: http.StatusOK, NextPage: 2, TotalPages: 2, Body: []tag{gitLabTag}},
+				projectPath + "/repository/tags?page=2": {StatusCode: http.StatusOK, TotalPages: 2, Body: []tag{tag2}},
+			},
+			ctx: context.Background(),
+			expectedTags: remote.Tags{
+				remoteTag,
+				{
+					Name:   "v0.0.1",
+					Time:   remoteCommit1.Time,
+					Commit: remoteCommit1,
+					WebURL: "https://gitlab.com/octocat/Hello-World/-/tags/v0.0.1",
+				},
+			},
+		},
+	}
 
-	assert.NoError(t, err)
-	assert.NotNil(t, tags)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				webURL: "https://gitlab.com",
+				path:   "octocat/Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			tags, err := r.FetchTags(tc.ctx)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, tags)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTags, tags.Sort())
+			}
+		})
+	}
 }
 
 func TestRepo_FetchIssuesAndMerges(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
+	routes := func(exclude ...string) map[string]MockResponse {
+		routes := map[string]MockResponse{
+			projectPath + "/issues?page=1":                                    {StatusCode: http.StatusOK, TotalPages: 1, Body: []issue{gitLabIssue1, gitLabIssue2}},
+			projectPath + "/merge_requests?page=1":                            {StatusCode: http.StatusOK, TotalPages: 1, Body: []mergeRequest{gitLabMerge}},
+			projectPath + "/issues/1002/resource_state_events?page=1":         {StatusCode: http.StatusOK, Body: []stateEvent{gitLabEvent}},
+			projectPath + "/repository/commits/" + gitLabMerge.MergeCommitSHA: {StatusCode: http.StatusOK, Body: gitLabCommit1},
+			"/users/1": {StatusCode: http.StatusOK, Body: gitLabUser1},
+			"/users/2": {StatusCode: http.StatusOK, Body: gitLabUser2},
+			"/users/3": {StatusCode: http.StatusOK, Body: gitLabUser3},
+		}
+
+		for _, key := range exclude {
+			delete(routes, key)
+		}
+
+		return routes
 	}
 
-	issues, merges, err := r.FetchIssuesAndMerges(context.Background(), time.Now())
+	tests := []struct {
+		name           string
+		routes         map[string]MockResponse
+		ctx            context.Context
+		since          time.Time
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+		expectedError  string
+	}{
+		{
+			name:          "IssuesFails",
+			routes:        routes(projectPath + "/issues?page=1"),
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: "/issues?page=1&per_page=100&scope=all&state=closed 404: 404 Not Found",
+		},
+		{
+			name:          "MergeRequestsFails",
+			routes:        routes(projectPath + "/merge_requests?page=1"),
+			ctx:           context.Background(),
+			since:         parseGitLabTime("2020-10-01T00:00:00Z"),
+			expectedError: "/merge_requests?page=1&per_page=100&scope=all&state=merged&updated_after=2020-10-01T00%3A00%3A00Z 404: 404 Not Found",
+		},
+		{
+			name:          "EventsFails",
+			routes:        routes(projectPath + "/issues/1002/resource_state_events?page=1"),
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: "/issues/1002/resource_state_events?page=1&per_page=100 404: 404 Not Found",
+		},
+		{
+			name:          "CommitFails",
+			routes:        routes(projectPath + "/repository/commits/" + gitLabMerge.MergeCommitSHA),
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: "/repository/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e 404: 404 Not Found",
+		},
+		{
+			name:          "UserFails",
+			routes:        routes("/users/3"),
+			ctx:           context.Background(),
+			since:         time.Time{},
+			expectedError: "/users/3 404: 404 Not Found",
+		},
+		{
+			name:           "Success",
+			routes:         routes(),
+			ctx:            context.Background(),
+			since:          time.Time{},
+			expectedIssues: remote.Issues{remoteIssue2, remoteIssue1},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+	}
 
-	assert.NoError(t, err)
-	assert.NotNil(t, issues)
-	assert.NotNil(t, merges)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+			r.stores.users = remote.NewStore()
+			r.stores.commits = remote.NewStore()
+
+			issues, merges, err := r.FetchIssuesAndMerges(tc.ctx, tc.since)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, issues)
+				assert.Nil(t, merges)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIssues, issues)
+				assert.Equal(t, tc.expectedMerges, merges)
+			}
+		})
+	}
 }
 
 func TestRepo_FetchParentCommits(t *testing.T) {
-	r := &repo{
-		logger: log.New(log.None),
+	tests := []struct {
+		name            string
+		routes          map[string]MockResponse
+		ctx             context.Context
+		hash            string
+		expectedCommits remote.Commits
+		expectedError   string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: "/repository/commits?page=1&per_page=100&ref_name=c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c 404: 404 Not Found",
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				projectPath + "/repository/commits?page=1": {StatusCode: http.StatusOK, NextPage: 2, TotalPages: 2, Body: []commit{gitLabCommit2}},
+				projectPath + "/repository/commits?page=2": {StatusCode: http.StatusOK, TotalPages: 2, Body: []commit{gitLabCommit1}},
+			},
+			ctx:             context.Background(),
+			hash:            "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
 	}
 
-	commits, err := r.FetchParentCommits(context.Background(), "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+			r.stores.commits = remote.NewStore()
+
+			commits, err := r.FetchParentCommits(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, commits)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCommits, commits)
+			}
+		})
+	}
+}
 
-	assert.NoError(t, err)
-	assert.NotNil(t, commits)
+func TestRepo_FetchCommitFiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        map[string]MockResponse
+		ctx           context.Context
+		hash          string
+		expectedFiles []string
+		expectedError string
+	}{
+		{
+			name:          "Error",
+			routes:        map[string]MockResponse{},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedError: "/repository/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c/diff?page=1&per_page=100 404: 404 Not Found",
+		},
+		{
+			name: "Success",
+			routes: map[string]MockResponse{
+				projectPath + "/repository/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c/diff?page=1": {
+					StatusCode: http.StatusOK, NextPage: 2, TotalPages: 2,
+					Body: []diff{{OldPath: "api/server.go", NewPath: "api/server.go"}},
+				},
+				projectPath + "/repository/commits/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c/diff?page=2": {
+					StatusCode: http.StatusOK, TotalPages: 2,
+					Body: []diff{{OldPath: "web/index.js", NewPath: "web/app.js", RenamedFile: true}},
+				},
+			},
+			ctx:           context.Background(),
+			hash:          "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+			expectedFiles: []string{"api/server.go", "web/app.js", "web/index.js"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newMockServer(tc.routes)
+			defer ts.Close()
+
+			r := &repo{
+				logger: log.New(log.None),
+				client: ts.Client(),
+				apiURL: ts.URL,
+				path:   "octocat/Hello-World",
+			}
+
+			files, err := r.FetchCommitFiles(tc.ctx, tc.hash)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, files)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFiles, files)
+			}
+		})
+	}
 }
@@ -0,0 +1,307 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+var (
+	gitLabUser1 = user{
+		ID:          1,
+		Username:    "octocat",
+		Name:        "The Octocat",
+		State:       "active",
+		PublicEmail: "octocat@gitlab.com",
+		WebURL:      "https://gitlab.com/octocat",
+	}
+
+	gitLabUser2 = user{
+		ID:          2,
+		Username:    "octodog",
+		Name:        "The Octodog",
+		State:       "active",
+		PublicEmail: "octodog@gitlab.com",
+		WebURL:      "https://gitlab.com/octodog",
+	}
+
+	gitLabUser3 = user{
+		ID:          3,
+		Username:    "octofox",
+		Name:        "The Octofox",
+		State:       "active",
+		PublicEmail: "octofox@gitlab.com",
+		WebURL:      "https://gitlab.com/octofox",
+	}
+
+	gitLabProject = project{
+		ID:                1296269,
+		Name:              "Hello-World",
+		PathWithNamespace: "octocat/Hello-World",
+		DefaultBranch:     "main",
+		WebURL:            "https://gitlab.com/octocat/Hello-World",
+	}
+
+	gitLabCommit1 = commit{
+		ID:             "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		ShortID:        "6dcb09b5",
+		Title:          "Fix all the bugs",
+		Message:        "Fix all the bugs",
+		AuthorName:     "The Octocat",
+		AuthorEmail:    "octocat@gitlab.com",
+		AuthoredDate:   parseGitLabTime("2020-10-20T19:59:59Z"),
+		CommitterName:  "The Octocat",
+		CommitterEmail: "octocat@gitlab.com",
+		CommittedDate:  parseGitLabTime("2020-10-20T19:59:59Z"),
+		ParentIDs:      []string{},
+		WebURL:         "https://gitlab.com/octocat/Hello-World/-/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+	}
+
+	gitLabCommit2 = commit{
+		ID:             "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		ShortID:        "c3d0be41",
+		Title:          "Release v0.1.0",
+		Message:        "Release v0.1.0",
+		AuthorName:     "The Octocat",
+		AuthorEmail:    "octocat@gitlab.com",
+		AuthoredDate:   parseGitLabTime("2020-10-27T23:59:59Z"),
+		CommitterName:  "The Octocat",
+		CommitterEmail: "octocat@gitlab.com",
+		CommittedDate:  parseGitLabTime("2020-10-27T23:59:59Z"),
+		ParentIDs:      []string{"6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		WebURL:         "https://gitlab.com/octocat/Hello-World/-/commit/c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+	}
+
+	gitLabBranch = branch{
+		Name:      "main",
+		Protected: true,
+		Default:   true,
+		Commit:    gitLabCommit2,
+	}
+
+	gitLabTag = tag{
+		Name:      "v0.1.0",
+		Message:   "",
+		Target:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Protected: false,
+		Commit:    gitLabCommit2,
+	}
+
+	gitLabIssue1 = issue{
+		ID:    1,
+		IID:   1001,
+		Title: "Found a bug",
+		State: "closed",
+		Labels: []string{
+			"bug",
+		},
+		Milestone: &milestone{
+			ID:    3000,
+			IID:   1,
+			Title: "v1.0",
+			State: "active",
+		},
+		Author:    gitLabUser1,
+		ClosedBy:  &gitLabUser1,
+		CreatedAt: parseGitLabTime("2020-10-10T10:00:00Z"),
+		UpdatedAt: parseGitLabTime("2020-10-20T20:00:00Z"),
+		ClosedAt:  parseGitLabTimePtr("2020-10-20T20:00:00Z"),
+		WebURL:    "https://gitlab.com/octocat/Hello-World/-/issues/1001",
+	}
+
+	gitLabIssue2 = issue{
+		ID:    2,
+		IID:   1002,
+		Title: "Discovered a vulnerability",
+		State: "closed",
+		Labels: []string{
+			"security",
+		},
+		Milestone: nil,
+		Author:    gitLabUser1,
+		ClosedBy:  nil,
+		CreatedAt: parseGitLabTime("2020-10-11T11:00:00Z"),
+		UpdatedAt: parseGitLabTime("2020-10-21T21:00:00Z"),
+		ClosedAt:  parseGitLabTimePtr("2020-10-21T21:00:00Z"),
+		WebURL:    "https://gitlab.com/octocat/Hello-World/-/issues/1002",
+	}
+
+	gitLabMerge = mergeRequest{
+		ID:    3,
+		IID:   1003,
+		Title: "Fixed a bug",
+		State: "merged",
+		Labels: []string{
+			"bug",
+		},
+		Milestone: &milestone{
+			ID:    3000,
+			IID:   1,
+			Title: "v1.0",
+			State: "active",
+		},
+		Author:         gitLabUser2,
+		MergedBy:       &gitLabUser3,
+		MergeUser:      &gitLabUser3,
+		TargetBranch:   "main",
+		SHA:            "8e5d3a4bbf7a4a2a4e3b36f3d2a4e30d4f4b5c6d",
+		MergeCommitSHA: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		CreatedAt:      parseGitLabTime("2020-10-15T15:00:00Z"),
+		UpdatedAt:      parseGitLabTime("2020-10-22T22:00:00Z"),
+		MergedAt:       parseGitLabTimePtr("2020-10-20T20:00:00Z"),
+		WebURL:         "https://gitlab.com/octocat/Hello-World/-/merge_requests/1003",
+	}
+
+	gitLabEvent = stateEvent{
+		ID:           1,
+		User:         gitLabUser2,
+		State:        "closed",
+		ResourceType: "Issue",
+		ResourceID:   2,
+		CreatedAt:    parseGitLabTime("2020-10-21T21:00:00Z"),
+	}
+
+	remoteUser1 = remote.User{
+		Name:     "The Octocat",
+		Email:    "octocat@gitlab.com",
+		Username: "octocat",
+		WebURL:   "https://gitlab.com/octocat",
+	}
+
+	remoteUser2 = remote.User{
+		Name:     "The Octodog",
+		Email:    "octodog@gitlab.com",
+		Username: "octodog",
+		WebURL:   "https://gitlab.com/octodog",
+	}
+
+	remoteUser3 = remote.User{
+		Name:     "The Octofox",
+		Email:    "octofox@gitlab.com",
+		Username: "octofox",
+		WebURL:   "https://gitlab.com/octofox",
+	}
+
+	remoteCommit1 = remote.Commit{
+		Hash:    "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time:    parseGitLabTime("2020-10-20T19:59:59Z"),
+		Message: "Fix all the bugs",
+	}
+
+	remoteCommit2 = remote.Commit{
+		Hash:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Time:    parseGitLabTime("2020-10-27T23:59:59Z"),
+		Parents: []string{"6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		Message: "Release v0.1.0",
+	}
+
+	remoteBranch = remote.Branch{
+		Name:   "main",
+		Commit: remoteCommit2,
+	}
+
+	remoteTag = remote.Tag{
+		Name:   "v0.1.0",
+		Time:   parseGitLabTime("2020-10-27T23:59:59Z"),
+		Commit: remoteCommit2,
+		WebURL: "https://gitlab.com/octocat/Hello-World/-/tags/v0.1.0",
+	}
+
+	remoteIssue1 = remote.Issue{
+		Change: remote.Change{
+			Number:    1001,
+			Title:     "Found a bug",
+			Labels:    []string{"bug"},
+			Milestone: "v1.0",
+			Time:      parseGitLabTime("2020-10-20T20:00:00Z"),
+			Author:    remoteUser1,
+			WebURL:    "https://gitlab.com/octocat/Hello-World/-/issues/1001",
+		},
+		Closer: remoteUser1,
+	}
+
+	remoteIssue2 = remote.Issue{
+		Change: remote.Change{
+			Number:    1002,
+			Title:     "Discovered a vulnerability",
+			Labels:    []string{"security"},
+			Milestone: "",
+			Time:      parseGitLabTime("2020-10-21T21:00:00Z"),
+			Author:    remoteUser1,
+			WebURL:    "https://gitlab.com/octocat/Hello-World/-/issues/1002",
+		},
+		Closer: remoteUser2,
+	}
+
+	remoteMerge = remote.Merge{
+		Change: remote.Change{
+			Number:    1003,
+			Title:     "Fixed a bug",
+			Labels:    []string{"bug"},
+			Milestone: "v1.0",
+			Time:      parseGitLabTime("2020-10-20T19:59:59Z"),
+			Author:    remoteUser2,
+			WebURL:    "https://gitlab.com/octocat/Hello-World/-/merge_requests/1003",
+		},
+		Merger: remoteUser3,
+		Commit: remoteCommit1,
+	}
+)
+
+func parseGitLabTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func parseGitLabTimePtr(s string) *time.Time {
+	t := parseGitLabTime(s)
+	return &t
+}
+
+// MockResponse is a canned response of the fake GitLab API server.
+type MockResponse struct {
+	StatusCode int
+	NextPage   int
+	TotalPages int
+	Body       interface{}
+}
+
+// newMockServer creates a fake GitLab API server.
+// The routes are keyed by the escaped request path and, for paginated endpoints, the page number (i.e. /projects/1/repository/tags?page=2).
+// Requests to unknown routes receive a 404 Not Found response.
+func newMockServer(routes map[string]MockResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.EscapedPath()
+		if page := r.URL.Query().Get("page"); page != "" {
+			key += "?page=" + page
+		}
+
+		resp, ok := routes[key]
+		if !ok {
+			resp = MockResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       map[string]string{"message": "404 Not Found"},
+			}
+		}
+
+		if resp.NextPage > 0 {
+			w.Header().Set("X-Next-Page", strconv.Itoa(resp.NextPage))
+		}
+
+		if resp.TotalPages > 0 {
+			w.Header().Set("X-Total-Pages", strconv.Itoa(resp.TotalPages))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_ = json.NewEncoder(w).Encode(resp.Body)
+	}))
+}
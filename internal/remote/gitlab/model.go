@@ -0,0 +1,215 @@
+package gitlab
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func toUser(u user) remote.User {
+	return remote.User{
+		Name:     u.Name,
+		Email:    u.PublicEmail,
+		Username: u.Username,
+		WebURL:   u.WebURL,
+	}
+}
+
+func toCommit(c commit) remote.Commit {
+	// Copy the parent hashes (nil for root commits)
+	parents := append([]string(nil), c.ParentIDs...)
+
+	return remote.Commit{
+		Hash:    c.ID,
+		Time:    c.CommittedDate,
+		Parents: parents,
+		Message: c.Message,
+	}
+}
+
+func toBranch(b branch) remote.Branch {
+	return remote.Branch{
+		Name:   b.Name,
+		Commit: toCommit(b.Commit),
+	}
+}
+
+func toTag(t tag, webURL, path string) remote.Tag {
+	// Only annotated tags have a creation time
+	var created time.Time
+	if t.CreatedAt != nil {
+		created = *t.CreatedAt
+	}
+
+	return remote.Tag{
+		Name:    t.Name,
+		Time:    t.Commit.CommittedDate,
+		Created: created,
+		Commit:  toCommit(t.Commit),
+		WebURL:  fmt.Sprintf("%s/%s/-/tags/%s", webURL, path, t.Name),
+	}
+}
+
+func toIssue(i issue, author, closer user) remote.Issue {
+	var milestone string
+	if i.Milestone != nil {
+		milestone = i.Milestone.Title
+	}
+
+	var time time.Time
+	if i.ClosedAt != nil {
+		time = *i.ClosedAt
+	}
+
+	return remote.Issue{
+		Change: remote.Change{
+			Number:    i.IID,
+			Title:     i.Title,
+			Labels:    i.Labels,
+			Milestone: milestone,
+			Time:      time,
+			Author:    toUser(author),
+			WebURL:    i.WebURL,
+		},
+		Closer: toUser(closer),
+	}
+}
+
+func toMerge(m mergeRequest, c commit, author, merger user) remote.Merge {
+	var milestone string
+	if m.Milestone != nil {
+		milestone = m.Milestone.Title
+	}
+
+	// m.MergedAt is the time the merge request was merged on GitLab
+	// c.CommittedDate is the actual time of merge
+	time := c.CommittedDate
+
+	return remote.Merge{
+		Change: remote.Change{
+			Number:    m.IID,
+			Title:     m.Title,
+			Labels:    m.Labels,
+			Milestone: milestone,
+			Time:      time,
+			Author:    toUser(author),
+			WebURL:    m.WebURL,
+		},
+		Merger: toUser(merger),
+		Commit: toCommit(c),
+	}
+}
+
+func resolveTags(gitLabTags *remote.Store, webURL, path string) remote.Tags {
+	tags := remote.Tags{}
+
+	_ = gitLabTags.ForEach(func(k, v interface{}) error {
+		t := v.(tag)
+		tags = append(tags, toTag(t, webURL, path))
+		return nil
+	})
+
+	return tags
+}
+
+func resolveCommits(gitLabCommits *remote.Store) remote.Commits {
+	commits := remote.Commits{}
+
+	_ = gitLabCommits.ForEach(func(k, v interface{}) error {
+		c := v.(commit)
+		commits = append(commits, toCommit(c))
+		return nil
+	})
+
+	// The order of the commits should be from the most recent to the least recent
+	sort.Slice(commits, func(i, j int) bool {
+		if commits[i].Time.Equal(commits[j].Time) {
+			return commits[i].Hash < commits[j].Hash
+		}
+		return commits[i].Time.After(commits[j].Time)
+	})
+
+	return commits
+}
+
+func resolveIssuesAndMerges(gitLabIssues, gitLabMerges, gitLabEvents, gitLabCommits, gitLabUsers *remote.Store) (remote.Issues, remote.Merges) {
+	issues := remote.Issues{}
+	merges := remote.Merges{}
+
+	_ = gitLabIssues.ForEach(func(k, v interface{}) error {
+		i := v.(issue)
+
+		// If the closer is not known, the issue is skipped
+		if id, ok := closerID(i, gitLabEvents); ok {
+			v, _ := gitLabUsers.Load(i.Author.ID)
+			author := v.(user)
+
+			v, _ = gitLabUsers.Load(id)
+			closer := v.(user)
+
+			issues = append(issues, toIssue(i, author, closer))
+		}
+
+		return nil
+	})
+
+	_ = gitLabMerges.ForEach(func(k, v interface{}) error {
+		m := v.(mergeRequest)
+
+		// If the merger or the merge commit is not known, the merge request is skipped
+		if id, ok := mergerID(m, gitLabEvents); ok {
+			if v, ok := gitLabCommits.Load(m.mergeCommitSHA()); ok {
+				c := v.(commit)
+
+				v, _ = gitLabUsers.Load(m.Author.ID)
+				author := v.(user)
+
+				v, _ = gitLabUsers.Load(id)
+				merger := v.(user)
+
+				merges = append(merges, toMerge(m, c, author, merger))
+			}
+		}
+
+		return nil
+	})
+
+	issues = issues.Sort()
+	merges = merges.Sort()
+
+	return issues, merges
+}
+
+// eventKey is the key for storing state events of issues and merge requests in the same store.
+type eventKey struct {
+	resourceType string
+	iid          int
+}
+
+func closerID(i issue, gitLabEvents *remote.Store) (int, bool) {
+	if i.ClosedBy != nil {
+		return i.ClosedBy.ID, true
+	}
+
+	if v, ok := gitLabEvents.Load(eventKey{resourceIssues, i.IID}); ok {
+		e := v.(stateEvent)
+		return e.User.ID, true
+	}
+
+	return 0, false
+}
+
+func mergerID(m mergeRequest, gitLabEvents *remote.Store) (int, bool) {
+	if u := m.merger(); u != nil {
+		return u.ID, true
+	}
+
+	if v, ok := gitLabEvents.Load(eventKey{resourceMergeRequests, m.IID}); ok {
+		e := v.(stateEvent)
+		return e.User.ID, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,281 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+func TestToUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		u            user
+		expectedUser remote.User
+	}{
+		{
+			name:         "OK",
+			u:            gitLabUser1,
+			expectedUser: remoteUser1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := toUser(tc.u)
+			assert.Equal(t, tc.expectedUser, user)
+		})
+	}
+}
+
+func TestToCommit(t *testing.T) {
+	tests := []struct {
+		name           string
+		c              commit
+		expectedCommit remote.Commit
+	}{
+		{
+			name:           "OK",
+			c:              gitLabCommit1,
+			expectedCommit: remoteCommit1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commit := toCommit(tc.c)
+			assert.Equal(t, tc.expectedCommit, commit)
+		})
+	}
+}
+
+func TestToBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		b              branch
+		expectedBranch remote.Branch
+	}{
+		{
+			name:           "OK",
+			b:              gitLabBranch,
+			expectedBranch: remoteBranch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			branch := toBranch(tc.b)
+			assert.Equal(t, tc.expectedBranch, branch)
+		})
+	}
+}
+
+func TestToTag(t *testing.T) {
+	gitLabCreatedAt := parseGitLabTime("2020-10-28T10:00:00Z")
+
+	tests := []struct {
+		name        string
+		t           tag
+		webURL      string
+		path        string
+		expectedTag remote.Tag
+	}{
+		{
+			name:        "OK",
+			t:           gitLabTag,
+			webURL:      "https://gitlab.com",
+			path:        "octocat/Hello-World",
+			expectedTag: remoteTag,
+		},
+		{
+			name: "Annotated",
+			t: tag{
+				Name:      "v0.2.0",
+				Message:   "Release v0.2.0",
+				Target:    "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+				CreatedAt: &gitLabCreatedAt,
+				Commit:    gitLabCommit2,
+			},
+			webURL: "https://gitlab.com",
+			path:   "octocat/Hello-World",
+			expectedTag: remote.Tag{
+				Name:    "v0.2.0",
+				Time:    parseGitLabTime("2020-10-27T23:59:59Z"),
+				Created: gitLabCreatedAt,
+				Commit:  remoteCommit2,
+				WebURL:  "https://gitlab.com/octocat/Hello-World/-/tags/v0.2.0",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tag := toTag(tc.t, tc.webURL, tc.path)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestToIssue(t *testing.T) {
+	tests := []struct {
+		name           string
+		i              issue
+		author, closer user
+		expectedIssue  remote.Issue
+	}{
+		{
+			name:          "OK",
+			i:             gitLabIssue1,
+			author:        gitLabUser1,
+			closer:        gitLabUser1,
+			expectedIssue: remoteIssue1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issue := toIssue(tc.i, tc.author, tc.closer)
+			assert.Equal(t, tc.expectedIssue, issue)
+		})
+	}
+}
+
+func TestToMerge(t *testing.T) {
+	tests := []struct {
+		name           string
+		m              mergeRequest
+		c              commit
+		author, merger user
+		expectedMerge  remote.Merge
+	}{
+		{
+			name:          "OK",
+			m:             gitLabMerge,
+			c:             gitLabCommit1,
+			author:        gitLabUser2,
+			merger:        gitLabUser3,
+			expectedMerge: remoteMerge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merge := toMerge(tc.m, tc.c, tc.author, tc.merger)
+			assert.Equal(t, tc.expectedMerge, merge)
+		})
+	}
+}
+
+func TestResolveTags(t *testing.T) {
+	tests := []struct {
+		name         string
+		gitLabTags   *remote.Store
+		webURL       string
+		path         string
+		expectedTags remote.Tags
+	}{
+		{
+			name: "OK",
+			gitLabTags: remote.NewStoreFrom(map[interface{}]interface{}{
+				"v0.1.0": gitLabTag,
+			}),
+			webURL:       "https://gitlab.com",
+			path:         "octocat/Hello-World",
+			expectedTags: remote.Tags{remoteTag},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tags := resolveTags(tc.gitLabTags, tc.webURL, tc.path)
+			assert.Equal(t, tc.expectedTags, tags)
+		})
+	}
+}
+
+func TestResolveCommits(t *testing.T) {
+	tests := []struct {
+		name            string
+		gitLabCommits   *remote.Store
+		expectedCommits remote.Commits
+	}{
+		{
+			name: "OK",
+			gitLabCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": gitLabCommit1,
+				"c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c": gitLabCommit2,
+			}),
+			expectedCommits: remote.Commits{remoteCommit2, remoteCommit1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commits := resolveCommits(tc.gitLabCommits)
+			assert.Equal(t, tc.expectedCommits, commits)
+		})
+	}
+}
+
+func TestResolveIssuesAndMerges(t *testing.T) {
+	tests := []struct {
+		name           string
+		gitLabIssues   *remote.Store
+		gitLabMerges   *remote.Store
+		gitLabEvents   *remote.Store
+		gitLabCommits  *remote.Store
+		gitLabUsers    *remote.Store
+		expectedIssues remote.Issues
+		expectedMerges remote.Merges
+	}{
+		{
+			name: "OK",
+			gitLabIssues: remote.NewStoreFrom(map[interface{}]interface{}{
+				1001: gitLabIssue1,
+				1002: gitLabIssue2,
+			}),
+			gitLabMerges: remote.NewStoreFrom(map[interface{}]interface{}{
+				1003: gitLabMerge,
+			}),
+			gitLabEvents: remote.NewStoreFrom(map[interface{}]interface{}{
+				eventKey{resourceIssues, 1002}: gitLabEvent,
+			}),
+			gitLabCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": gitLabCommit1,
+			}),
+			gitLabUsers: remote.NewStoreFrom(map[interface{}]interface{}{
+				1: gitLabUser1,
+				2: gitLabUser2,
+				3: gitLabUser3,
+			}),
+			expectedIssues: remote.Issues{remoteIssue2, remoteIssue1},
+			expectedMerges: remote.Merges{remoteMerge},
+		},
+		{
+			name: "UnknownCloserAndMerger",
+			gitLabIssues: remote.NewStoreFrom(map[interface{}]interface{}{
+				1002: gitLabIssue2,
+			}),
+			gitLabMerges: remote.NewStoreFrom(map[interface{}]interface{}{
+				1003: mergeRequest{IID: 1003, MergeCommitSHA: "6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+			}),
+			gitLabEvents: remote.NewStore(),
+			gitLabCommits: remote.NewStoreFrom(map[interface{}]interface{}{
+				"6dcb09b5b57875f334f61aebed695e2e4193db5e": gitLabCommit1,
+			}),
+			gitLabUsers:    remote.NewStore(),
+			expectedIssues: remote.Issues{},
+			expectedMerges: remote.Merges{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issues, merges := resolveIssuesAndMerges(tc.gitLabIssues, tc.gitLabMerges, tc.gitLabEvents, tc.gitLabCommits, tc.gitLabUsers)
+
+			assert.Equal(t, tc.expectedIssues, issues)
+			assert.Equal(t, tc.expectedMerges, merges)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package hybrid
+
+import (
+	"context"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+// repo implements the remote.Repo interface by composing a local and a remote repository.
+// The commit graph (first commit, branches, tags, and parent commits) is read from the local repository,
+// and issues and merges are read from the remote repository.
+type repo struct {
+	logger     log.Logger
+	localRepo  remote.Repo
+	remoteRepo remote.Repo
+}
+
+// NewRepo creates a new repository reading the commit graph from a local repository
+// and the issues and merges from a remote repository.
+// The web links are generated by the remote repository.
+func NewRepo(logger log.Logger, localRepo, remoteRepo remote.Repo) remote.Repo {
+	return &repo{
+		logger:     logger,
+		localRepo:  localRepo,
+		remoteRepo: remoteRepo,
+	}
+}
+
+// FutureTag returns a tag that does not exist yet.
+func (r *repo) FutureTag(name string) remote.Tag {
+	return r.remoteRepo.FutureTag(name)
+}
+
+// CompareURL returns a URL for comparing two revisions.
+func (r *repo) CompareURL(base, head string) string {
+	return r.remoteRepo.CompareURL(base, head)
+}
+
+// CommitURL returns the web URL of a commit.
+func (r *repo) CommitURL(hash string) string {
+	return r.remoteRepo.CommitURL(hash)
+}
+
+// CheckPermissions ensures both the local and the remote repositories are accessible.
+func (r *repo) CheckPermissions(ctx context.Context) error {
+	if err := r.localRepo.CheckPermissions(ctx); err != nil {
+		return err
+	}
+
+	return r.remoteRepo.CheckPermissions(ctx)
+}
+
+// FetchFirstCommit retrieves the firist/initial commit from the local repository.
+func (r *repo) FetchFirstCommit(ctx context.Context) (remote.Commit, error) {
+	return r.localRepo.FetchFirstCommit(ctx)
+}
+
+// FetchBranch retrieves a branch by name from the local repository.
+func (r *repo) FetchBranch(ctx context.Context, name string) (remote.Branch, error) {
+	return r.localRepo.FetchBranch(ctx, name)
+}
+
+// FetchDefaultBranch retrieves the default branch from the local repository.
+// If the default branch cannot be determined locally (i.e. in a detached CI checkout),
+// the name of the default branch is retrieved from the remote repository.
+func (r *repo) FetchDefaultBranch(ctx context.Context) (remote.Branch, error) {
+	branch, err := r.localRepo.FetchDefaultBranch(ctx)
+	if err == nil {
+		return branch, nil
+	}
+
+	r.logger.Debugf("Falling back to the remote default branch: %s", err)
+
+	remoteBranch, err := r.remoteRepo.FetchDefaultBranch(ctx)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	return r.localRepo.FetchBranch(ctx, remoteBranch.Name)
+}
+
+// FetchTags retrieves all tags from the local repository.
+func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
+	return r.localRepo.FetchTags(ctx)
+}
+
+// FetchIssuesAndMerges retrieves closed issues and merged pull/merge requests from the remote repository.
+func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
+	return r.remoteRepo.FetchIssuesAndMerges(ctx, since)
+}
+
+// FetchParentCommits retrieves all parent commits of a given commit hash from the local repository.
+func (r *repo) FetchParentCommits(ctx context.Context, hash string) (remote.Commits, error) {
+	return r.localRepo.FetchParentCommits(ctx, hash)
+}
+
+// FetchCommitFiles retrieves the paths of files changed by a commit from the local repository.
+func (r *repo) FetchCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	return r.localRepo.FetchCommitFiles(ctx, hash)
+}
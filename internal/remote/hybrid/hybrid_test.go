@@ -0,0 +1,339 @@
+package hybrid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/internal/remote/remotetest"
+	"github.com/moorara/changelog/log"
+)
+
+var (
+	commit1 = remote.Commit{
+		Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time: parseTime("2020-10-20T19:59:59Z"),
+	}
+
+	commit2 = remote.Commit{
+		Hash: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Time: parseTime("2020-10-27T23:59:59Z"),
+	}
+
+	branch = remote.Branch{
+		Name:   "main",
+		Commit: commit2,
+	}
+
+	tag = remote.Tag{
+		Name:   "v0.1.0",
+		Time:   parseTime("2020-10-27T23:59:59Z"),
+		Commit: commit2,
+		WebURL: "https://github.com/octocat/Hello-World/tree/v0.1.0",
+	}
+
+	merge = remote.Merge{
+		Change: remote.Change{
+			Number: 1002,
+			Title:  "Fixed a bug",
+			Labels: []string{"bug"},
+			Time:   parseTime("2020-10-20T19:59:59Z"),
+			Author: remote.User{Username: "octodog"},
+			WebURL: "https://github.com/octocat/Hello-World/pull/1002",
+		},
+		Merger: remote.User{Username: "octofox"},
+		Commit: commit1,
+	}
+)
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func TestNewRepo(t *testing.T) {
+	logger := log.New(log.None)
+	localRepo := &remotetest.MockRemoteRepo{}
+	remoteRepo := &remotetest.MockRemoteRepo{}
+
+	r := NewRepo(logger, localRepo, remoteRepo)
+	assert.NotNil(t, r)
+
+	hr, ok := r.(*repo)
+	assert.True(t, ok)
+	assert.Equal(t, logger, hr.logger)
+	assert.Equal(t, localRepo, hr.localRepo)
+	assert.Equal(t, remoteRepo, hr.remoteRepo)
+}
+
+func TestRepo_FutureTag(t *testing.T) {
+	remoteRepo := &remotetest.MockRemoteRepo{
+		FutureTagMocks: []remotetest.FutureTagMock{
+			{OutTag: remote.Tag{Name: "v0.2.0", WebURL: "https://github.com/octocat/Hello-World/tree/v0.2.0"}},
+		},
+	}
+
+	r := &repo{
+		logger:     log.New(log.None),
+		localRepo:  &remotetest.MockRemoteRepo{},
+		remoteRepo: remoteRepo,
+	}
+
+	tag := r.FutureTag("v0.2.0")
+
+	assert.Equal(t, "v0.2.0", remoteRepo.FutureTagMocks[0].InName)
+	assert.Equal(t, "https://github.com/octocat/Hello-World/tree/v0.2.0", tag.WebURL)
+}
+
+func TestRepo_CompareURL(t *testing.T) {
+	remoteRepo := &remotetest.MockRemoteRepo{
+		CompareURLMocks: []remotetest.CompareURLMock{
+			{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0"},
+		},
+	}
+
+	r := &repo{
+		logger:     log.New(log.None),
+		localRepo:  &remotetest.MockRemoteRepo{},
+		remoteRepo: remoteRepo,
+	}
+
+	url := r.CompareURL("v0.1.0", "v0.2.0")
+
+	assert.Equal(t, "v0.1.0", remoteRepo.CompareURLMocks[0].InBase)
+	assert.Equal(t, "v0.2.0", remoteRepo.CompareURLMocks[0].InHead)
+	assert.Equal(t, "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0", url)
+}
+
+func TestRepo_CommitURL(t *testing.T) {
+	remoteRepo := &remotetest.MockRemoteRepo{
+		CommitURLMocks: []remotetest.CommitURLMock{
+			{OutString: "https://github.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		},
+	}
+
+	r := &repo{
+		logger:     log.New(log.None),
+		localRepo:  &remotetest.MockRemoteRepo{},
+		remoteRepo: remoteRepo,
+	}
+
+	url := r.CommitURL("6dcb09b5b57875f334f61aebed695e2e4193db5e")
+
+	assert.Equal(t, "6dcb09b5b57875f334f61aebed695e2e4193db5e", remoteRepo.CommitURLMocks[0].InHash)
+	assert.Equal(t, "https://github.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e", url)
+}
+
+func TestRepo_CheckPermissions(t *testing.T) {
+	tests := []struct {
+		name          string
+		localRepo     *remotetest.MockRemoteRepo
+		remoteRepo    *remotetest.MockRemoteRepo
+		ctx           context.Context
+		expectedError string
+	}{
+		{
+			name: "LocalFails",
+			localRepo: &remotetest.MockRemoteRepo{
+				CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+					{OutError: errors.New("reference not found")},
+				},
+			},
+			remoteRepo:    &remotetest.MockRemoteRepo{},
+			ctx:           context.Background(),
+			expectedError: "reference not found",
+		},
+		{
+			name: "RemoteFails",
+			localRepo: &remotetest.MockRemoteRepo{
+				CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+					{OutError: nil},
+				},
+			},
+			remoteRepo: &remotetest.MockRemoteRepo{
+				CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+					{OutError: errors.New("GET /repos/octocat/Hello-World 401: Bad credentials")},
+				},
+			},
+			ctx:           context.Background(),
+			expectedError: "GET /repos/octocat/Hello-World 401: Bad credentials",
+		},
+		{
+			name: "Success",
+			localRepo: &remotetest.MockRemoteRepo{
+				CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+					{OutError: nil},
+				},
+			},
+			remoteRepo: &remotetest.MockRemoteRepo{
+				CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+					{OutError: nil},
+				},
+			},
+			ctx:           context.Background(),
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:     log.New(log.None),
+				localRepo:  tc.localRepo,
+				remoteRepo: tc.remoteRepo,
+			}
+
+			err := r.CheckPermissions(tc.ctx)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestRepo_FetchDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name           string
+		localRepo      *remotetest.MockRemoteRepo
+		remoteRepo     *remotetest.MockRemoteRepo
+		ctx            context.Context
+		expectedBranch remote.Branch
+		expectedError  string
+	}{
+		{
+			name: "Local",
+			localRepo: &remotetest.MockRemoteRepo{
+				FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+					{OutBranch: branch},
+				},
+			},
+			remoteRepo:     &remotetest.MockRemoteRepo{},
+			ctx:            context.Background(),
+			expectedBranch: branch,
+		},
+		{
+			name: "RemoteFails",
+			localRepo: &remotetest.MockRemoteRepo{
+				FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+					{OutError: errors.New("cannot determine the default branch: origin/HEAD is not set and HEAD is detached")},
+				},
+			},
+			remoteRepo: &remotetest.MockRemoteRepo{
+				FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+					{OutError: errors.New("GET /repos/octocat/Hello-World 401: Bad credentials")},
+				},
+			},
+			ctx:           context.Background(),
+			expectedError: "GET /repos/octocat/Hello-World 401: Bad credentials",
+		},
+		{
+			name: "FallbackToRemote",
+			localRepo: &remotetest.MockRemoteRepo{
+				FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+					{OutError: errors.New("cannot determine the default branch: origin/HEAD is not set and HEAD is detached")},
+				},
+				FetchBranchMocks: []remotetest.FetchBranchMock{
+					{OutBranch: branch},
+				},
+			},
+			remoteRepo: &remotetest.MockRemoteRepo{
+				FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+					{OutBranch: remote.Branch{Name: "main"}},
+				},
+			},
+			ctx:            context.Background(),
+			expectedBranch: branch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &repo{
+				logger:     log.New(log.None),
+				localRepo:  tc.localRepo,
+				remoteRepo: tc.remoteRepo,
+			}
+
+			branch, err := r.FetchDefaultBranch(tc.ctx)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedBranch, branch)
+			} else {
+				assert.Empty(t, branch)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestRepo_Delegation(t *testing.T) {
+	ctx := context.Background()
+	since := parseTime("2020-10-01T00:00:00Z")
+
+	localRepo := &remotetest.MockRemoteRepo{
+		FetchFirstCommitMocks:   []remotetest.FetchFirstCommitMock{{OutCommit: commit1}},
+		FetchBranchMocks:        []remotetest.FetchBranchMock{{OutBranch: branch}},
+		FetchTagsMocks:          []remotetest.FetchTagsMock{{OutTags: remote.Tags{tag}}},
+		FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{{OutCommits: remote.Commits{commit2, commit1}}},
+		FetchCommitFilesMocks:   []remotetest.FetchCommitFilesMock{{OutFiles: []string{"README.md"}}},
+	}
+
+	remoteRepo := &remotetest.MockRemoteRepo{
+		FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{{OutIssues: remote.Issues{}, OutMerges: remote.Merges{merge}}},
+	}
+
+	r := &repo{
+		logger:     log.New(log.None),
+		localRepo:  localRepo,
+		remoteRepo: remoteRepo,
+	}
+
+	commit, err := r.FetchFirstCommit(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, commit1, commit)
+
+	b, err := r.FetchBranch(ctx, "main")
+	assert.NoError(t, err)
+	assert.Equal(t, branch, b)
+	assert.Equal(t, "main", localRepo.FetchBranchMocks[0].InName)
+
+	tags, err := r.FetchTags(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, remote.Tags{tag}, tags)
+
+	commits, err := r.FetchParentCommits(ctx, commit2.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, remote.Commits{commit2, commit1}, commits)
+	assert.Equal(t, commit2.Hash, localRepo.FetchParentCommitsMocks[0].InHash)
+
+	files, err := r.FetchCommitFiles(ctx, commit2.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md"}, files)
+	assert.Equal(t, commit2.Hash, localRepo.FetchCommitFilesMocks[0].InHash)
+
+	issues, merges, err := r.FetchIssuesAndMerges(ctx, since)
+	assert.NoError(t, err)
+	assert.Equal(t, remote.Issues{}, issues)
+	assert.Equal(t, remote.Merges{merge}, merges)
+	assert.Equal(t, since, remoteRepo.FetchIssuesAndMergesMocks[0].InSince)
+
+	// None of the commit graph calls should be made to the remote repository
+	assert.Zero(t, remoteRepo.FetchFirstCommitIndex)
+	assert.Zero(t, remoteRepo.FetchBranchIndex)
+	assert.Zero(t, remoteRepo.FetchTagsIndex)
+	assert.Zero(t, remoteRepo.FetchParentCommitsIndex)
+	assert.Zero(t, remoteRepo.FetchCommitFilesIndex)
+	assert.Zero(t, localRepo.FetchIssuesAndMergesIndex)
+}
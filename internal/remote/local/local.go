@@ -0,0 +1,423 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+const (
+	remoteName     = "origin"
+	remoteRefsPath = "refs/remotes/" + remoteName + "/"
+)
+
+// Linker generates the web links of a repository.
+// Every remote.Repo is a Linker and generating links does not require any API call.
+type Linker interface {
+	FutureTag(string) remote.Tag
+	CompareURL(string, string) string
+	CommitURL(string) string
+}
+
+// repo implements the remote.Repo interface for a local git repository.
+type repo struct {
+	logger log.Logger
+	git    *git.Repository
+	linker Linker
+}
+
+// NewRepo creates a new repository reading all data from a local git repository.
+// path can be any path inside the git repository.
+// linker is used for generating the web links of tags and revisions.
+func NewRepo(logger log.Logger, path string, linker Linker) (remote.Repo, error) {
+	g, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &repo{
+		logger: logger,
+		git:    g,
+		linker: linker,
+	}, nil
+}
+
+// resolveRef resolves a reference by name to the commit it points to.
+func (r *repo) resolveRef(name plumbing.ReferenceName) (*object.Commit, bool, error) {
+	ref, err := r.git.Reference(name, true)
+	if err == plumbing.ErrReferenceNotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	c, err := r.git.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, false, err
+	}
+
+	return c, true, nil
+}
+
+// resolveTag resolves a tag reference to the commit it points to.
+// Annotated tags are peeled to the commit they point to.
+func (r *repo) resolveTag(ref *plumbing.Reference) (*object.Commit, error) {
+	t, err := r.git.TagObject(ref.Hash())
+	switch err {
+	case nil:
+		return t.Commit()
+	case plumbing.ErrObjectNotFound:
+		// A lightweight tag points to a commit directly
+		return r.git.CommitObject(ref.Hash())
+	default:
+		return nil, err
+	}
+}
+
+// walk visits every commit reachable from the given commits exactly once.
+// The history of a shallow clone is only walked up to the shallow commits.
+func (r *repo) walk(ctx context.Context, hashes []plumbing.Hash, visit func(*object.Commit) error) error {
+	shallows, err := r.git.Storer.Shallow()
+	if err != nil {
+		return err
+	}
+
+	visited := map[plumbing.Hash]bool{}
+	queue := append([]plumbing.Hash{}, hashes...)
+
+	// The parents of shallow commits are not available
+	boundary := map[plumbing.Hash]bool{}
+	for _, h := range shallows {
+		boundary[h] = true
+	}
+
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		h := queue[0]
+		queue = queue[1:]
+
+		if visited[h] {
+			continue
+		}
+		visited[h] = true
+
+		c, err := r.git.CommitObject(h)
+		if err != nil {
+			return err
+		}
+
+		if err := visit(c); err != nil {
+			return err
+		}
+
+		if !boundary[h] {
+			queue = append(queue, c.ParentHashes...)
+		}
+	}
+
+	return nil
+}
+
+// FutureTag returns a tag that does not exist yet for a local git repository.
+func (r *repo) FutureTag(name string) remote.Tag {
+	return r.linker.FutureTag(name)
+}
+
+// CompareURL returns a URL for comparing two revisions for a local git repository.
+func (r *repo) CompareURL(base, head string) string {
+	return r.linker.CompareURL(base, head)
+}
+
+// CommitURL returns the web URL of a commit for a local git repository.
+func (r *repo) CommitURL(hash string) string {
+	return r.linker.CommitURL(hash)
+}
+
+// CheckPermissions ensures the client has all the required permissions for a local git repository.
+func (r *repo) CheckPermissions(ctx context.Context) error {
+	// Reading a local git repository does not require any permission
+	if _, err := r.git.Head(); err != nil {
+		return err
+	}
+
+	r.logger.Debug("Local git repository verified: HEAD")
+
+	return nil
+}
+
+// FetchFirstCommit retrieves the firist/initial commit for a local git repository.
+func (r *repo) FetchFirstCommit(ctx context.Context) (remote.Commit, error) {
+	r.logger.Debug("Reading the first local git commit ...")
+
+	branch, err := r.FetchDefaultBranch(ctx)
+	if err != nil {
+		return remote.Commit{}, err
+	}
+
+	// A repository can have more than one root commit (i.e. merging unrelated histories)
+	var first *object.Commit
+	err = r.walk(ctx, []plumbing.Hash{plumbing.NewHash(branch.Commit.Hash)}, func(c *object.Commit) error {
+		if c.NumParents() == 0 && (first == nil || c.Committer.When.Before(first.Committer.When)) {
+			first = c
+		}
+		return nil
+	})
+
+	if err != nil {
+		return remote.Commit{}, err
+	}
+
+	// The root commit is not available in a shallow clone
+	if first == nil {
+		return remote.Commit{}, errors.New("cannot find the first commit: the local git repository is a shallow clone")
+	}
+
+	commit := toCommit(first)
+
+	r.logger.Debugf("Read the first local git commit: %s", commit)
+
+	return commit, nil
+}
+
+// FetchBranch retrieves a branch by name for a local git repository.
+// If the branch does not exist locally, the remote-tracking branch is used (i.e. in a detached CI checkout).
+func (r *repo) FetchBranch(ctx context.Context, name string) (remote.Branch, error) {
+	for _, ref := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(name),
+		plumbing.NewRemoteReferenceName(remoteName, name),
+	} {
+		c, ok, err := r.resolveRef(ref)
+		if err != nil {
+			return remote.Branch{}, err
+		}
+
+		if ok {
+			r.logger.Debugf("Read local git branch: %s", ref)
+			return toBranch(name, c), nil
+		}
+	}
+
+	return remote.Branch{}, fmt.Errorf("local git branch not found: %s", name)
+}
+
+// FetchDefaultBranch retrieves the default branch for a local git repository.
+// The default branch is the one origin/HEAD points to, or the current branch if origin/HEAD is not known.
+func (r *repo) FetchDefaultBranch(ctx context.Context) (remote.Branch, error) {
+	var name string
+
+	if ref, err := r.git.Reference(plumbing.NewRemoteHEADReferenceName(remoteName), false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		name = strings.TrimPrefix(ref.Target().String(), remoteRefsPath)
+	} else if head, err := r.git.Reference(plumbing.HEAD, false); err == nil && head.Type() == plumbing.SymbolicReference {
+		name = head.Target().Short()
+	} else {
+		return remote.Branch{}, errors.New("cannot determine the default branch: origin/HEAD is not set and HEAD is detached")
+	}
+
+	branch, err := r.FetchBranch(ctx, name)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	r.logger.Debugf("Read local git default branch: %s", branch.Name)
+
+	return branch, nil
+}
+
+// FetchTags retrieves all tags for a local git repository.
+func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
+	r.logger.Debug("Reading local git tags ...")
+
+	iter, err := r.git.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := remote.Tags{}
+
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		c, err := r.resolveTag(ref)
+		if err == object.ErrUnsupportedObject {
+			// Tags pointing to objects other than commits (i.e. trees and blobs) are not releases
+			r.logger.Debugf("Skipped local git tag not pointing to a commit: %s", ref.Name().Short())
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		tag := toTag(ref.Name().Short(), c, r.linker)
+
+		// Annotated tags have their own creation times
+		if t, err := r.git.TagObject(ref.Hash()); err == nil {
+			tag.Created = t.Tagger.When
+		}
+
+		tags = append(tags, tag)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debugf("Local git tags are read: %d", len(tags))
+
+	return tags, nil
+}
+
+// FetchIssuesAndMerges retrieves all merges for a local git repository.
+// Merges are derived from the merge commits and squashed commits referencing a pull request number.
+// There is no issue in a local git repository.
+func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
+	if since.IsZero() {
+		r.logger.Info("Reading local git merges since the beginning ...")
+	} else {
+		r.logger.Infof("Reading local git merges since %s ...", since.Format(time.RFC3339))
+	}
+
+	// Merges can be on any branch, so all branches and tags are walked
+	iter, err := r.git.References()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := []plumbing.Hash{}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		if ref.Name().IsBranch() || ref.Name().IsRemote() {
+			hashes = append(hashes, ref.Hash())
+		} else if ref.Name().IsTag() {
+			if c, err := r.resolveTag(ref); err == nil {
+				hashes = append(hashes, c.Hash)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mergeStore := map[int]remote.Merge{}
+
+	err = r.walk(ctx, hashes, func(c *object.Commit) error {
+		if !since.IsZero() && c.Committer.When.Before(since) {
+			return nil
+		}
+
+		if m, ok := toMerge(c); ok {
+			// A pull request can be referenced by more than one commit (i.e. reverts and cherry-picks)
+			if prev, ok := mergeStore[m.Number]; !ok || m.Time.Before(prev.Time) {
+				mergeStore[m.Number] = m
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merges := remote.Merges{}
+	for _, m := range mergeStore {
+		merges = append(merges, m)
+	}
+
+	merges = merges.Sort()
+
+	r.logger.Infof("All local git merges (%d) are read", len(merges))
+
+	return remote.Issues{}, merges, nil
+}
+
+// FetchParentCommits retrieves all parent commits of a given commit hash for a local git repository.
+func (r *repo) FetchParentCommits(ctx context.Context, hash string) (remote.Commits, error) {
+	r.logger.Debugf("Reading all local git parent commits for %s ...", hash)
+
+	commits := remote.Commits{}
+
+	err := r.walk(ctx, []plumbing.Hash{plumbing.NewHash(hash)}, func(c *object.Commit) error {
+		commits = append(commits, toCommit(c))
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	commits = sortCommits(commits)
+
+	r.logger.Debugf("All local git parent commits for %s are read: %d", hash, len(commits))
+
+	return commits, nil
+}
+
+// FetchCommitFiles retrieves the paths of files changed by a commit for a local git repository.
+// The parents of shallow commits are not available, so all files of a shallow commit are considered changed.
+func (r *repo) FetchCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	r.logger.Debugf("Reading local git files changed by %s ...", hash)
+
+	c, err := r.git.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var parentTree *object.Tree
+	if len(c.ParentHashes) > 0 {
+		p, err := r.git.CommitObject(c.ParentHashes[0])
+		if err != nil && err != plumbing.ErrObjectNotFound {
+			return nil, err
+		}
+
+		if p != nil {
+			if parentTree, err = p.Tree(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	changes, err := object.DiffTreeContext(ctx, parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, change := range changes {
+		// Renamed files are changed in both paths
+		if name := change.From.Name; name != "" {
+			files = append(files, name)
+		}
+		if name := change.To.Name; name != "" && name != change.From.Name {
+			files = append(files, name)
+		}
+	}
+
+	r.logger.Debugf("Local git files changed by %s are read: %d", hash, len(files))
+
+	return files, nil
+}
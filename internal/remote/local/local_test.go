@@ -0,0 +1,339 @@
+package local
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/log"
+)
+
+type mockLinker struct{}
+
+func (l mockLinker) FutureTag(name string) remote.Tag {
+	return remote.Tag{
+		Name:   name,
+		Time:   time.Now(),
+		WebURL: "https://github.com/octocat/Hello-World/tree/" + name,
+	}
+}
+
+func (l mockLinker) CompareURL(base, head string) string {
+	return "https://github.com/octocat/Hello-World/compare/" + base + "..." + head
+}
+
+func (l mockLinker) CommitURL(hash string) string {
+	return "https://github.com/octocat/Hello-World/commit/" + hash
+}
+
+// testRepo is a local git repository with the following history:
+//
+//	c1 (initial) --- c2 (squashed, v0.1.0) --------------- c4 (merge, main, v0.2.0)
+//	                    \                                /
+//	                     c3 (feature) -----------------
+type testRepo struct {
+	path           string
+	c1, c2, c3, c4 plumbing.Hash
+}
+
+func newTestRepo(t *testing.T) testRepo {
+	path, err := ioutil.TempDir("", "changelog_local_")
+	assert.NoError(t, err)
+
+	g, err := git.PlainInit(path, false)
+	assert.NoError(t, err)
+
+	// HEAD points to main before the first commit
+	err = g.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main")))
+	assert.NoError(t, err)
+
+	w, err := g.Worktree()
+	assert.NoError(t, err)
+
+	commit := func(file, message string, when time.Time, parents ...plumbing.Hash) plumbing.Hash {
+		err := ioutil.WriteFile(filepath.Join(path, file), []byte(message), 0644)
+		assert.NoError(t, err)
+
+		_, err = w.Add(file)
+		assert.NoError(t, err)
+
+		sig := &object.Signature{Name: "The Octocat", Email: "octocat@example.com", When: when}
+		h, err := w.Commit(message, &git.CommitOptions{Author: sig, Committer: sig, Parents: parents})
+		assert.NoError(t, err)
+
+		return h
+	}
+
+	tr := testRepo{path: path}
+	tr.c1 = commit("README.md", "Initial commit", parseTime("2020-10-01T10:00:00Z"))
+	tr.c2 = commit("feature.go", "Add a feature (#2)", parseTime("2020-10-10T10:00:00Z"), tr.c1)
+	tr.c3 = commit("bug.go", "Fix a bug", parseTime("2020-10-15T10:00:00Z"), tr.c2)
+	tr.c4 = commit("bug.go", "Merge pull request #3 from octodog/fix\n\nFixed a bug\n", parseTime("2020-10-20T10:00:00Z"), tr.c2, tr.c3)
+
+	err = g.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), tr.c3))
+	assert.NoError(t, err)
+
+	_, err = g.CreateTag("v0.1.0", tr.c2, nil)
+	assert.NoError(t, err)
+
+	_, err = g.CreateTag("v0.2.0", tr.c4, &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "The Octocat", Email: "octocat@example.com", When: parseTime("2020-10-21T10:00:00Z")},
+		Message: "Release v0.2.0",
+	})
+	assert.NoError(t, err)
+
+	return tr
+}
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// hashes returns the hashes of commits for comparison.
+func hashes(commits remote.Commits) []string {
+	h := []string{}
+	for _, c := range commits {
+		h = append(h, c.Hash)
+	}
+
+	return h
+}
+
+func TestNewRepo(t *testing.T) {
+	tr := newTestRepo(t)
+	defer os.RemoveAll(tr.path)
+
+	tests := []struct {
+		name          string
+		logger        log.Logger
+		path          string
+		linker        Linker
+		expectedError string
+	}{
+		{
+			name:          "NotGitRepository",
+			logger:        log.New(log.None),
+			path:          os.TempDir(),
+			linker:        mockLinker{},
+			expectedError: "repository does not exist",
+		},
+		{
+			name:   "OK",
+			logger: log.New(log.None),
+			path:   filepath.Join(tr.path, "."),
+			linker: mockLinker{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRepo(tc.logger, tc.path, tc.linker)
+
+			if tc.expectedError != "" {
+				assert.Nil(t, r)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+
+				lr, ok := r.(*repo)
+				assert.True(t, ok)
+				assert.Equal(t, tc.logger, lr.logger)
+				assert.NotNil(t, lr.git)
+				assert.Equal(t, tc.linker, lr.linker)
+			}
+		})
+	}
+}
+
+func TestRepo_FutureTag(t *testing.T) {
+	r := &repo{
+		logger: log.New(log.None),
+		linker: mockLinker{},
+	}
+
+	tag := r.FutureTag("v0.3.0")
+
+	assert.Equal(t, "v0.3.0", tag.Name)
+	assert.Equal(t, "https://github.com/octocat/Hello-World/tree/v0.3.0", tag.WebURL)
+}
+
+func TestRepo_CompareURL(t *testing.T) {
+	r := &repo{
+		logger: log.New(log.None),
+		linker: mockLinker{},
+	}
+
+	url := r.CompareURL("v0.1.0", "v0.2.0")
+
+	assert.Equal(t, "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0", url)
+}
+
+func TestRepo_CommitURL(t *testing.T) {
+	r := &repo{
+		logger: log.New(log.None),
+		linker: mockLinker{},
+	}
+
+	url := r.CommitURL("6dcb09b5b57875f334f61aebed695e2e4193db5e")
+
+	assert.Equal(t, "https://github.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e", url)
+}
+
+func TestRepo(t *testing.T) {
+	tr := newTestRepo(t)
+	defer os.RemoveAll(tr.path)
+
+	ctx := context.Background()
+	r, err := NewRepo(log.New(log.None), tr.path, mockLinker{})
+	assert.NoError(t, err)
+
+	t.Run("CheckPermissions", func(t *testing.T) {
+		err := r.CheckPermissions(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("FetchFirstCommit", func(t *testing.T) {
+		commit, err := r.FetchFirstCommit(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, tr.c1.String(), commit.Hash)
+		assert.True(t, commit.Time.Equal(parseTime("2020-10-01T10:00:00Z")))
+	})
+
+	t.Run("FetchBranch", func(t *testing.T) {
+		branch, err := r.FetchBranch(ctx, "feature")
+		assert.NoError(t, err)
+		assert.Equal(t, "feature", branch.Name)
+		assert.Equal(t, tr.c3.String(), branch.Commit.Hash)
+
+		_, err = r.FetchBranch(ctx, "unknown")
+		assert.EqualError(t, err, "local git branch not found: unknown")
+	})
+
+	t.Run("FetchDefaultBranch", func(t *testing.T) {
+		branch, err := r.FetchDefaultBranch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "main", branch.Name)
+		assert.Equal(t, tr.c4.String(), branch.Commit.Hash)
+	})
+
+	t.Run("FetchTags", func(t *testing.T) {
+		tags, err := r.FetchTags(ctx)
+		assert.NoError(t, err)
+
+		tags = tags.Sort()
+		assert.Len(t, tags, 2)
+
+		assert.Equal(t, "v0.2.0", tags[0].Name)
+		assert.Equal(t, tr.c4.String(), tags[0].Commit.Hash)
+		assert.True(t, tags[0].Time.Equal(parseTime("2020-10-20T10:00:00Z")))
+		assert.True(t, tags[0].Created.Equal(parseTime("2020-10-21T10:00:00Z")))
+		assert.Equal(t, "https://github.com/octocat/Hello-World/tree/v0.2.0", tags[0].WebURL)
+
+		assert.Equal(t, "v0.1.0", tags[1].Name)
+		assert.Equal(t, tr.c2.String(), tags[1].Commit.Hash)
+		assert.True(t, tags[1].Time.Equal(parseTime("2020-10-10T10:00:00Z")))
+		assert.True(t, tags[1].Created.IsZero())
+		assert.Equal(t, "https://github.com/octocat/Hello-World/tree/v0.1.0", tags[1].WebURL)
+	})
+
+	t.Run("FetchIssuesAndMerges", func(t *testing.T) {
+		issues, merges, err := r.FetchIssuesAndMerges(ctx, time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, remote.Issues{}, issues)
+		assert.Len(t, merges, 2)
+
+		assert.Equal(t, 3, merges[0].Number)
+		assert.Equal(t, "Fixed a bug", merges[0].Title)
+		assert.Equal(t, "octodog", merges[0].Author.Username)
+		assert.Equal(t, "The Octocat", merges[0].Merger.Username)
+		assert.Equal(t, tr.c4.String(), merges[0].Commit.Hash)
+
+		assert.Equal(t, 2, merges[1].Number)
+		assert.Equal(t, "Add a feature", merges[1].Title)
+		assert.Equal(t, "The Octocat", merges[1].Author.Username)
+		assert.Equal(t, tr.c2.String(), merges[1].Commit.Hash)
+
+		_, merges, err = r.FetchIssuesAndMerges(ctx, parseTime("2020-10-15T00:00:00Z"))
+		assert.NoError(t, err)
+		assert.Len(t, merges, 1)
+		assert.Equal(t, 3, merges[0].Number)
+	})
+
+	t.Run("FetchParentCommits", func(t *testing.T) {
+		commits, err := r.FetchParentCommits(ctx, tr.c4.String())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{tr.c4.String(), tr.c3.String(), tr.c2.String(), tr.c1.String()}, hashes(commits))
+
+		commits, err = r.FetchParentCommits(ctx, tr.c2.String())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{tr.c2.String(), tr.c1.String()}, hashes(commits))
+
+		_, err = r.FetchParentCommits(ctx, "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378")
+		assert.EqualError(t, err, "object not found")
+	})
+
+	t.Run("FetchCommitFiles", func(t *testing.T) {
+		files, err := r.FetchCommitFiles(ctx, tr.c1.String())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"README.md"}, files)
+
+		files, err = r.FetchCommitFiles(ctx, tr.c4.String())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bug.go"}, files)
+
+		_, err = r.FetchCommitFiles(ctx, "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378")
+		assert.EqualError(t, err, "object not found")
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := r.FetchParentCommits(ctx, tr.c4.String())
+		assert.EqualError(t, err, "context canceled")
+	})
+}
+
+func TestRepo_FetchDefaultBranch_RemoteHEAD(t *testing.T) {
+	tr := newTestRepo(t)
+	defer os.RemoveAll(tr.path)
+
+	g, err := git.PlainOpen(tr.path)
+	assert.NoError(t, err)
+
+	// A CI checkout has a detached HEAD and the remote-tracking branches
+	assert.NoError(t, g.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, tr.c3)))
+	assert.NoError(t, g.Storer.SetReference(plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "develop"), tr.c2)))
+	assert.NoError(t, g.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.NewRemoteHEADReferenceName("origin"), plumbing.NewRemoteReferenceName("origin", "develop"))))
+
+	r := &repo{
+		logger: log.New(log.None),
+		git:    g,
+		linker: mockLinker{},
+	}
+
+	branch, err := r.FetchDefaultBranch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "develop", branch.Name)
+	assert.Equal(t, tr.c2.String(), branch.Commit.Hash)
+
+	// Without origin/HEAD, the default branch cannot be determined from a detached HEAD
+	assert.NoError(t, g.Storer.RemoveReference(plumbing.NewRemoteHEADReferenceName("origin")))
+
+	_, err = r.FetchDefaultBranch(context.Background())
+	assert.EqualError(t, err, "cannot determine the default branch: origin/HEAD is not set and HEAD is detached")
+}
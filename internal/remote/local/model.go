@@ -0,0 +1,118 @@
+package local
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+var (
+	// mergeRegex matches the subject of merge commits created for pull requests (i.e. Merge pull request #123 from octocat/feature).
+	mergeRegex = regexp.MustCompile(`^Merge pull request #([0-9]+) from ([^/\s]+)(?:/\S*)?`)
+	// squashRegex matches the subject of squashed or rebased commits referencing a pull request (i.e. Add a feature (#123)).
+	squashRegex = regexp.MustCompile(`^(.+) \(#([0-9]+)\)$`)
+)
+
+func toUser(s object.Signature) remote.User {
+	// Commits only have the name and email of users
+	return remote.User{
+		Name:     s.Name,
+		Email:    s.Email,
+		Username: s.Name,
+	}
+}
+
+func toCommit(c *object.Commit) remote.Commit {
+	var parents []string
+	for _, h := range c.ParentHashes {
+		parents = append(parents, h.String())
+	}
+
+	return remote.Commit{
+		Hash:    c.Hash.String(),
+		Time:    c.Committer.When,
+		Parents: parents,
+		Message: c.Message,
+	}
+}
+
+func toBranch(name string, c *object.Commit) remote.Branch {
+	return remote.Branch{
+		Name:   name,
+		Commit: toCommit(c),
+	}
+}
+
+func toTag(name string, c *object.Commit, linker Linker) remote.Tag {
+	return remote.Tag{
+		Name:   name,
+		Time:   c.Committer.When,
+		Commit: toCommit(c),
+		WebURL: linker.FutureTag(name).WebURL,
+	}
+}
+
+// splitMessage returns the subject and the body of a commit message.
+func splitMessage(message string) (string, string) {
+	message = strings.TrimSpace(message)
+	if i := strings.Index(message, "\n"); i >= 0 {
+		return strings.TrimSpace(message[:i]), strings.TrimSpace(message[i+1:])
+	}
+
+	return message, ""
+}
+
+// toMerge derives a merge from a commit referencing a pull request number.
+// The second return value is false if the commit does not reference any pull request.
+func toMerge(c *object.Commit) (remote.Merge, bool) {
+	subject, body := splitMessage(c.Message)
+
+	var number int
+	var title string
+	author := toUser(c.Author)
+
+	if m := mergeRegex.FindStringSubmatch(subject); m != nil {
+		// The title of a pull request is the first line of the merge commit body
+		number, _ = strconv.Atoi(m[1])
+		title, _ = splitMessage(body)
+		if title == "" {
+			title = subject
+		}
+		// The source branch is owned by the author of the pull request
+		author = remote.User{Username: m[2]}
+	} else if m := squashRegex.FindStringSubmatch(subject); m != nil {
+		number, _ = strconv.Atoi(m[2])
+		title = m[1]
+	} else {
+		return remote.Merge{}, false
+	}
+
+	return remote.Merge{
+		Change: remote.Change{
+			Number: number,
+			Title:  title,
+			Labels: []string{},
+			Time:   c.Committer.When,
+			Author: author,
+		},
+		Merger: toUser(c.Author),
+		Commit: toCommit(c),
+	}, true
+}
+
+func sortCommits(commits remote.Commits) remote.Commits {
+	// The order of the commits should be from the most recent to the least recent
+	sort.Slice(commits, func(i, j int) bool {
+		if commits[i].Time.Equal(commits[j].Time) {
+			return commits[i].Hash < commits[j].Hash
+		}
+		return commits[i].Time.After(commits[j].Time)
+	})
+
+	return commits
+}
@@ -0,0 +1,202 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+var (
+	octocat = object.Signature{
+		Name:  "The Octocat",
+		Email: "octocat@example.com",
+		When:  parseTime("2020-10-20T19:59:59Z"),
+	}
+
+	remoteOctocat = remote.User{
+		Name:     "The Octocat",
+		Email:    "octocat@example.com",
+		Username: "The Octocat",
+	}
+)
+
+func TestToUser(t *testing.T) {
+	user := toUser(octocat)
+	assert.Equal(t, remoteOctocat, user)
+}
+
+func TestToCommit(t *testing.T) {
+	c := &object.Commit{
+		Hash:      plumbing.NewHash("6dcb09b5b57875f334f61aebed695e2e4193db5e"),
+		Author:    octocat,
+		Committer: octocat,
+	}
+
+	commit := toCommit(c)
+
+	assert.Equal(t, remote.Commit{
+		Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time: parseTime("2020-10-20T19:59:59Z"),
+	}, commit)
+}
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name            string
+		message         string
+		expectedSubject string
+		expectedBody    string
+	}{
+		{
+			name:            "SubjectOnly",
+			message:         "Fix all the bugs\n",
+			expectedSubject: "Fix all the bugs",
+			expectedBody:    "",
+		},
+		{
+			name:            "SubjectAndBody",
+			message:         "Merge pull request #1002 from octodog/fix\n\nFixed a bug\n\nCloses #1001\n",
+			expectedSubject: "Merge pull request #1002 from octodog/fix",
+			expectedBody:    "Fixed a bug\n\nCloses #1001",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			subject, body := splitMessage(tc.message)
+
+			assert.Equal(t, tc.expectedSubject, subject)
+			assert.Equal(t, tc.expectedBody, body)
+		})
+	}
+}
+
+func TestToMerge(t *testing.T) {
+	hash := plumbing.NewHash("6dcb09b5b57875f334f61aebed695e2e4193db5e")
+	remoteCommit := remote.Commit{
+		Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time: parseTime("2020-10-20T19:59:59Z"),
+	}
+
+	tests := []struct {
+		name          string
+		c             *object.Commit
+		expectedOK    bool
+		expectedMerge remote.Merge
+	}{
+		{
+			name: "RegularCommit",
+			c: &object.Commit{
+				Hash:      hash,
+				Author:    octocat,
+				Committer: octocat,
+				Message:   "Fix all the bugs",
+			},
+			expectedOK: false,
+		},
+		{
+			name: "MergeCommit",
+			c: &object.Commit{
+				Hash:      hash,
+				Author:    octocat,
+				Committer: octocat,
+				Message:   "Merge pull request #1002 from octodog/fix\n\nFixed a bug\n",
+			},
+			expectedOK: true,
+			expectedMerge: remote.Merge{
+				Change: remote.Change{
+					Number: 1002,
+					Title:  "Fixed a bug",
+					Labels: []string{},
+					Time:   parseTime("2020-10-20T19:59:59Z"),
+					Author: remote.User{Username: "octodog"},
+				},
+				Merger: remoteOctocat,
+				Commit: remote.Commit{
+					Hash:    remoteCommit.Hash,
+					Time:    remoteCommit.Time,
+					Message: "Merge pull request #1002 from octodog/fix\n\nFixed a bug\n",
+				},
+			},
+		},
+		{
+			name: "MergeCommitWithoutBody",
+			c: &object.Commit{
+				Hash:      hash,
+				Author:    octocat,
+				Committer: octocat,
+				Message:   "Merge pull request #1002 from octodog/fix",
+			},
+			expectedOK: true,
+			expectedMerge: remote.Merge{
+				Change: remote.Change{
+					Number: 1002,
+					Title:  "Merge pull request #1002 from octodog/fix",
+					Labels: []string{},
+					Time:   parseTime("2020-10-20T19:59:59Z"),
+					Author: remote.User{Username: "octodog"},
+				},
+				Merger: remoteOctocat,
+				Commit: remote.Commit{
+					Hash:    remoteCommit.Hash,
+					Time:    remoteCommit.Time,
+					Message: "Merge pull request #1002 from octodog/fix",
+				},
+			},
+		},
+		{
+			name: "SquashedCommit",
+			c: &object.Commit{
+				Hash:      hash,
+				Author:    octocat,
+				Committer: octocat,
+				Message:   "Fixed a bug (#1002)\n\n* Fix the bug\n* Add tests\n",
+			},
+			expectedOK: true,
+			expectedMerge: remote.Merge{
+				Change: remote.Change{
+					Number: 1002,
+					Title:  "Fixed a bug",
+					Labels: []string{},
+					Time:   parseTime("2020-10-20T19:59:59Z"),
+					Author: remoteOctocat,
+				},
+				Merger: remoteOctocat,
+				Commit: remote.Commit{
+					Hash:    remoteCommit.Hash,
+					Time:    remoteCommit.Time,
+					Message: "Fixed a bug (#1002)\n\n* Fix the bug\n* Add tests\n",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merge, ok := toMerge(tc.c)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedMerge, merge)
+		})
+	}
+}
+
+func TestSortCommits(t *testing.T) {
+	commits := remote.Commits{
+		{Hash: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c", Time: parseTime("2020-10-20T19:59:59Z")},
+		{Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e", Time: parseTime("2020-10-27T23:59:59Z")},
+		{Hash: "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378", Time: parseTime("2020-10-20T19:59:59Z")},
+	}
+
+	expected := remote.Commits{
+		{Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e", Time: parseTime("2020-10-27T23:59:59Z")},
+		{Hash: "25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378", Time: parseTime("2020-10-20T19:59:59Z")},
+		{Hash: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c", Time: parseTime("2020-10-20T19:59:59Z")},
+	}
+
+	assert.Equal(t, expected, sortCommits(commits))
+}
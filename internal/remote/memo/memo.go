@@ -0,0 +1,221 @@
+package memo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/moorara/changelog/internal/remote"
+)
+
+// repo implements the remote.Repo interface by keeping the data retrieved from a repository in memory.
+// It lets the changelogs generated in one run (i.e. for the components of a monorepo) share the same API calls.
+// Only successful results are kept, so failed calls are retried.
+type repo struct {
+	sync.Mutex
+	repo remote.Repo
+
+	permissions   bool
+	firstCommit   *remote.Commit
+	defaultBranch *remote.Branch
+	tags          remote.Tags
+	branches      map[string]remote.Branch
+	since         *time.Time
+	issues        remote.Issues
+	merges        remote.Merges
+	commits       map[string]remote.Commits
+	files         map[string][]string
+}
+
+// NewRepo creates a new repository keeping the data retrieved from a given repository in memory.
+func NewRepo(r remote.Repo) remote.Repo {
+	return &repo{
+		repo:     r,
+		branches: map[string]remote.Branch{},
+		commits:  map[string]remote.Commits{},
+		files:    map[string][]string{},
+	}
+}
+
+// FutureTag returns a tag that does not exist yet.
+func (r *repo) FutureTag(name string) remote.Tag {
+	return r.repo.FutureTag(name)
+}
+
+// CompareURL returns a URL for comparing two revisions.
+func (r *repo) CompareURL(base, head string) string {
+	return r.repo.CompareURL(base, head)
+}
+
+// CommitURL returns the web URL of a commit.
+func (r *repo) CommitURL(hash string) string {
+	return r.repo.CommitURL(hash)
+}
+
+// CheckPermissions ensures the client has all the required permissions.
+// The permissions are only checked once.
+func (r *repo) CheckPermissions(ctx context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.permissions {
+		return nil
+	}
+
+	if err := r.repo.CheckPermissions(ctx); err != nil {
+		return err
+	}
+
+	r.permissions = true
+
+	return nil
+}
+
+// FetchFirstCommit retrieves the firist/initial commit.
+func (r *repo) FetchFirstCommit(ctx context.Context) (remote.Commit, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.firstCommit != nil {
+		return *r.firstCommit, nil
+	}
+
+	commit, err := r.repo.FetchFirstCommit(ctx)
+	if err != nil {
+		return remote.Commit{}, err
+	}
+
+	r.firstCommit = &commit
+
+	return commit, nil
+}
+
+// FetchBranch retrieves a branch by name.
+func (r *repo) FetchBranch(ctx context.Context, name string) (remote.Branch, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if branch, ok := r.branches[name]; ok {
+		return branch, nil
+	}
+
+	branch, err := r.repo.FetchBranch(ctx, name)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	r.branches[name] = branch
+
+	return branch, nil
+}
+
+// FetchDefaultBranch retrieves the default branch.
+func (r *repo) FetchDefaultBranch(ctx context.Context) (remote.Branch, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.defaultBranch != nil {
+		return *r.defaultBranch, nil
+	}
+
+	branch, err := r.repo.FetchDefaultBranch(ctx)
+	if err != nil {
+		return remote.Branch{}, err
+	}
+
+	r.defaultBranch = &branch
+
+	return branch, nil
+}
+
+// FetchTags retrieves all tags.
+func (r *repo) FetchTags(ctx context.Context) (remote.Tags, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.tags != nil {
+		return r.tags, nil
+	}
+
+	tags, err := r.repo.FetchTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.tags = tags
+
+	return tags, nil
+}
+
+// FetchIssuesAndMerges retrieves closed issues and merged pull/merge requests.
+// The issues and merges retrieved since a time are kept for any later time and filtered by their time,
+// so they are only retrieved again for an earlier time.
+func (r *repo) FetchIssuesAndMerges(ctx context.Context, since time.Time) (remote.Issues, remote.Merges, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.since != nil && !since.Before(*r.since) {
+		if since.Equal(*r.since) {
+			return r.issues, r.merges, nil
+		}
+
+		issues, _ := r.issues.Select(func(i remote.Issue) bool {
+			return !i.Time.Before(since)
+		})
+
+		merges, _ := r.merges.Select(func(m remote.Merge) bool {
+			return !m.Time.Before(since)
+		})
+
+		return issues, merges, nil
+	}
+
+	issues, merges, err := r.repo.FetchIssuesAndMerges(ctx, since)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.since = &since
+	r.issues = issues
+	r.merges = merges
+
+	return issues, merges, nil
+}
+
+// FetchParentCommits retrieves all parent commits of a given commit hash.
+func (r *repo) FetchParentCommits(ctx context.Context, hash string) (remote.Commits, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if commits, ok := r.commits[hash]; ok {
+		return commits, nil
+	}
+
+	commits, err := r.repo.FetchParentCommits(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	r.commits[hash] = commits
+
+	return commits, nil
+}
+
+// FetchCommitFiles retrieves the paths of files changed by a given commit hash.
+func (r *repo) FetchCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if files, ok := r.files[hash]; ok {
+		return files, nil
+	}
+
+	files, err := r.repo.FetchCommitFiles(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	r.files[hash] = files
+
+	return files, nil
+}
@@ -0,0 +1,315 @@
+package memo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/internal/remote"
+	"github.com/moorara/changelog/internal/remote/remotetest"
+)
+
+var (
+	commit1 = remote.Commit{
+		Hash: "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Time: parseTime("2020-10-20T19:59:59Z"),
+	}
+
+	commit2 = remote.Commit{
+		Hash: "c3d0be41ecbe669545ee3e94d31ed9a4bc91ee3c",
+		Time: parseTime("2020-10-27T23:59:59Z"),
+	}
+
+	branch = remote.Branch{
+		Name:   "main",
+		Commit: commit2,
+	}
+
+	tag = remote.Tag{
+		Name:   "v0.1.0",
+		Time:   parseTime("2020-10-27T23:59:59Z"),
+		Commit: commit2,
+		WebURL: "https://github.com/octocat/Hello-World/tree/v0.1.0",
+	}
+
+	issue = remote.Issue{
+		Change: remote.Change{
+			Number: 1001,
+			Title:  "Found a bug",
+			Labels: []string{"bug"},
+			Time:   parseTime("2020-10-20T19:59:59Z"),
+			Author: remote.User{Username: "octocat"},
+			WebURL: "https://github.com/octocat/Hello-World/issues/1001",
+		},
+		Closer: remote.User{Username: "octocat"},
+	}
+
+	merge = remote.Merge{
+		Change: remote.Change{
+			Number: 1002,
+			Title:  "Fixed a bug",
+			Labels: []string{"bug"},
+			Time:   parseTime("2020-10-20T19:59:59Z"),
+			Author: remote.User{Username: "octodog"},
+			WebURL: "https://github.com/octocat/Hello-World/pull/1002",
+		},
+		Merger: remote.User{Username: "octofox"},
+		Commit: commit1,
+	}
+)
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func TestNewRepo(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{}
+
+	r := NewRepo(mockRepo)
+	assert.NotNil(t, r)
+
+	mr, ok := r.(*repo)
+	assert.True(t, ok)
+	assert.Equal(t, mockRepo, mr.repo)
+}
+
+func TestRepo_URLs(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{
+		FutureTagMocks: []remotetest.FutureTagMock{
+			{OutTag: remote.Tag{Name: "v0.2.0", WebURL: "https://github.com/octocat/Hello-World/tree/v0.2.0"}},
+		},
+		CompareURLMocks: []remotetest.CompareURLMock{
+			{OutString: "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0"},
+		},
+		CommitURLMocks: []remotetest.CommitURLMock{
+			{OutString: "https://github.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e"},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+
+	assert.Equal(t, "https://github.com/octocat/Hello-World/tree/v0.2.0", r.FutureTag("v0.2.0").WebURL)
+	assert.Equal(t, "https://github.com/octocat/Hello-World/compare/v0.1.0...v0.2.0", r.CompareURL("v0.1.0", "v0.2.0"))
+	assert.Equal(t, "https://github.com/octocat/Hello-World/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e", r.CommitURL(commit1.Hash))
+}
+
+func TestRepo_CheckPermissions(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{
+		CheckPermissionsMocks: []remotetest.CheckPermissionsMock{
+			{OutError: errors.New("permission denied")},
+			{OutError: nil},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+	ctx := context.Background()
+
+	assert.EqualError(t, r.CheckPermissions(ctx), "permission denied")
+	assert.NoError(t, r.CheckPermissions(ctx))
+	assert.NoError(t, r.CheckPermissions(ctx))
+	assert.Equal(t, 2, mockRepo.CheckPermissionsIndex)
+}
+
+func TestRepo_FetchFirstCommit(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{
+		FetchFirstCommitMocks: []remotetest.FetchFirstCommitMock{
+			{OutError: errors.New("error on getting the first commit")},
+			{OutCommit: commit1},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+	ctx := context.Background()
+
+	_, err := r.FetchFirstCommit(ctx)
+	assert.EqualError(t, err, "error on getting the first commit")
+
+	for i := 0; i < 2; i++ {
+		commit, err := r.FetchFirstCommit(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, commit1, commit)
+	}
+
+	assert.Equal(t, 2, mockRepo.FetchFirstCommitIndex)
+}
+
+func TestRepo_FetchBranch(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{
+		FetchBranchMocks: []remotetest.FetchBranchMock{
+			{OutError: errors.New("error on getting the branch")},
+			{OutBranch: branch},
+			{OutBranch: remote.Branch{Name: "release/1.x", Commit: commit1}},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+	ctx := context.Background()
+
+	_, err := r.FetchBranch(ctx, "main")
+	assert.EqualError(t, err, "error on getting the branch")
+
+	for i := 0; i < 2; i++ {
+		b, err := r.FetchBranch(ctx, "main")
+		assert.NoError(t, err)
+		assert.Equal(t, branch, b)
+	}
+
+	b, err := r.FetchBranch(ctx, "release/1.x")
+	assert.NoError(t, err)
+	assert.Equal(t, "release/1.x", b.Name)
+
+	assert.Equal(t, 3, mockRepo.FetchBranchIndex)
+}
+
+func TestRepo_FetchDefaultBranch(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{
+		FetchDefaultBranchMocks: []remotetest.FetchDefaultBranchMock{
+			{OutError: errors.New("error on getting the default branch")},
+			{OutBranch: branch},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+	ctx := context.Background()
+
+	_, err := r.FetchDefaultBranch(ctx)
+	assert.EqualError(t, err, "error on getting the default branch")
+
+	for i := 0; i < 2; i++ {
+		b, err := r.FetchDefaultBranch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, branch, b)
+	}
+
+	assert.Equal(t, 2, mockRepo.FetchDefaultBranchIndex)
+}
+
+func TestRepo_FetchTags(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{
+		FetchTagsMocks: []remotetest.FetchTagsMock{
+			{OutError: errors.New("error on getting tags")},
+			{OutTags: remote.Tags{tag}},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+	ctx := context.Background()
+
+	_, err := r.FetchTags(ctx)
+	assert.EqualError(t, err, "error on getting tags")
+
+	for i := 0; i < 2; i++ {
+		tags, err := r.FetchTags(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, remote.Tags{tag}, tags)
+	}
+
+	assert.Equal(t, 2, mockRepo.FetchTagsIndex)
+}
+
+func TestRepo_FetchIssuesAndMerges(t *testing.T) {
+	since := parseTime("2020-10-20T19:59:59Z")
+	later := parseTime("2020-10-27T23:59:59Z")
+	earlier := parseTime("2020-10-01T00:00:00Z")
+
+	mockRepo := &remotetest.MockRemoteRepo{
+		FetchIssuesAndMergesMocks: []remotetest.FetchIssuesAndMergesMock{
+			{OutError: errors.New("error on getting issues and merges")},
+			{OutIssues: remote.Issues{issue}, OutMerges: remote.Merges{merge}},
+			{OutIssues: remote.Issues{issue}, OutMerges: remote.Merges{merge}},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+	ctx := context.Background()
+
+	_, _, err := r.FetchIssuesAndMerges(ctx, since)
+	assert.EqualError(t, err, "error on getting issues and merges")
+
+	for i := 0; i < 2; i++ {
+		issues, merges, err := r.FetchIssuesAndMerges(ctx, since)
+		assert.NoError(t, err)
+		assert.Equal(t, remote.Issues{issue}, issues)
+		assert.Equal(t, remote.Merges{merge}, merges)
+	}
+
+	// The issues and merges for a later time are filtered
+	issues, merges, err := r.FetchIssuesAndMerges(ctx, later)
+	assert.NoError(t, err)
+	assert.Equal(t, remote.Issues{}, issues)
+	assert.Equal(t, remote.Merges{}, merges)
+	assert.Equal(t, 2, mockRepo.FetchIssuesAndMergesIndex)
+
+	// The issues and merges for an earlier time are fetched again
+	issues, merges, err = r.FetchIssuesAndMerges(ctx, earlier)
+	assert.NoError(t, err)
+	assert.Equal(t, remote.Issues{issue}, issues)
+	assert.Equal(t, remote.Merges{merge}, merges)
+	assert.Equal(t, earlier, mockRepo.FetchIssuesAndMergesMocks[2].InSince)
+
+	issues, merges, err = r.FetchIssuesAndMerges(ctx, since)
+	assert.NoError(t, err)
+	assert.Equal(t, remote.Issues{issue}, issues)
+	assert.Equal(t, remote.Merges{merge}, merges)
+
+	assert.Equal(t, 3, mockRepo.FetchIssuesAndMergesIndex)
+}
+
+func TestRepo_FetchParentCommits(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{
+		FetchParentCommitsMocks: []remotetest.FetchParentCommitsMock{
+			{OutError: errors.New("error on getting parent commits")},
+			{OutCommits: remote.Commits{commit2, commit1}},
+			{OutCommits: remote.Commits{commit1}},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+	ctx := context.Background()
+
+	_, err := r.FetchParentCommits(ctx, commit2.Hash)
+	assert.EqualError(t, err, "error on getting parent commits")
+
+	for i := 0; i < 2; i++ {
+		commits, err := r.FetchParentCommits(ctx, commit2.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, remote.Commits{commit2, commit1}, commits)
+	}
+
+	commits, err := r.FetchParentCommits(ctx, commit1.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, remote.Commits{commit1}, commits)
+
+	assert.Equal(t, 3, mockRepo.FetchParentCommitsIndex)
+}
+
+func TestRepo_FetchCommitFiles(t *testing.T) {
+	mockRepo := &remotetest.MockRemoteRepo{
+		FetchCommitFilesMocks: []remotetest.FetchCommitFilesMock{
+			{OutError: errors.New("error on getting commit files")},
+			{OutFiles: []string{"api/server.go"}},
+		},
+	}
+
+	r := NewRepo(mockRepo)
+	ctx := context.Background()
+
+	_, err := r.FetchCommitFiles(ctx, commit1.Hash)
+	assert.EqualError(t, err, "error on getting commit files")
+
+	for i := 0; i < 2; i++ {
+		files, err := r.FetchCommitFiles(ctx, commit1.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"api/server.go"}, files)
+	}
+
+	assert.Equal(t, 2, mockRepo.FetchCommitFilesIndex)
+}
@@ -18,14 +18,18 @@ type User struct {
 }
 
 // Commit represents a commit.
+// Parents are the hashes of the parent commits, so the commits of a repository form a commit graph.
 type Commit struct {
-	Hash string
-	Time time.Time
+	Hash    string
+	Time    time.Time
+	Parents []string
+	// Message is the full commit message including the subject, the body, and the footers.
+	Message string
 }
 
 // IsZero determines if a commit is a zero commit instance.
 func (c Commit) IsZero() bool {
-	return c == Commit{}
+	return reflect.ValueOf(c).IsZero()
 }
 
 func (c Commit) String() string {
@@ -68,10 +72,13 @@ func (b Branch) String() string {
 
 // Tag represents a tag.
 type Tag struct {
-	Name   string
-	Time   time.Time
-	Commit Commit
-	WebURL string
+	Name string
+	Time time.Time
+	// Created is the time an annotated tag was created if known.
+	// It is zero for lightweight tags and for platforms not providing the tag objects.
+	Created time.Time
+	Commit  Commit
+	WebURL  string
 }
 
 // IsZero determines if a tag is a zero tag instance.
@@ -183,6 +190,19 @@ func (t Tags) Sort() Tags {
 	return sorted
 }
 
+// SortBy sorts the collection of tags from the most recent to the least recent using a custom ordering.
+// The function after reports whether or not a tag should be placed before another tag.
+// The tags that are equal in the custom ordering are sorted by their times.
+func (t Tags) SortBy(after func(Tag, Tag) bool) Tags {
+	sorted := t.Sort()
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return after(sorted[i], sorted[j])
+	})
+
+	return sorted
+}
+
 // Select returns a new list of tags that satisfies the predicate f.
 func (t Tags) Select(f func(Tag) bool) (Tags, Tags) {
 	selected := Tags{}
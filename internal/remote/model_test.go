@@ -34,8 +34,9 @@ var (
 	}
 
 	commit2 = Commit{
-		Hash: "0251a422d2038967eeaaaa5c8aa76c7067fdef05",
-		Time: t2,
+		Hash:    "0251a422d2038967eeaaaa5c8aa76c7067fdef05",
+		Time:    t2,
+		Parents: []string{"25aa2bdbaf10fa30b6db40c2c0a15d280ad9f378"},
 	}
 
 	branch = Branch{
@@ -510,6 +511,40 @@ func TestTags_Sort(t *testing.T) {
 	}
 }
 
+func TestTags_SortBy(t *testing.T) {
+	tests := []struct {
+		name         string
+		t            Tags
+		after        func(Tag, Tag) bool
+		expectedTags Tags
+	}{
+		{
+			name: "ByName",
+			t:    Tags{tag2, tag1},
+			after: func(t, u Tag) bool {
+				return t.Name < u.Name
+			},
+			expectedTags: Tags{tag1, tag2},
+		},
+		{
+			name: "Equal",
+			t:    Tags{tag1, tag2},
+			after: func(t, u Tag) bool {
+				return false
+			},
+			expectedTags: Tags{tag2, tag1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tags := tc.t.SortBy(tc.after)
+
+			assert.Equal(t, tc.expectedTags, tags)
+		})
+	}
+}
+
 func TestTags_Select(t *testing.T) {
 	tests := []struct {
 		name               string
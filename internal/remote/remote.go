@@ -11,6 +11,8 @@ type Repo interface {
 	FutureTag(string) Tag
 	// CompareURL returns a URL for comparing two revisions.
 	CompareURL(string, string) string
+	// CommitURL returns the web URL of a commit.
+	CommitURL(string) string
 	// CheckPermissions ensures the client has all the required permissions.
 	CheckPermissions(context.Context) error
 	// FetchFirstCommit retrieves the firist/initial commit.
@@ -25,4 +27,7 @@ type Repo interface {
 	FetchIssuesAndMerges(context.Context, time.Time) (Issues, Merges, error)
 	// FetchParentCommits retrieves all parent commits of a given commit hash.
 	FetchParentCommits(context.Context, string) (Commits, error)
+	// FetchCommitFiles retrieves the paths of files changed by a given commit hash.
+	// The files changed by a merge commit are the files changed compared to its first parent.
+	FetchCommitFiles(context.Context, string) ([]string, error)
 }
@@ -0,0 +1,60 @@
+package remote
+
+import "sync"
+
+// Store is a concurrent-safe in-memory store for the data retrieved from a remote repository.
+type Store struct {
+	sync.Mutex
+	m map[interface{}]interface{}
+}
+
+// NewStore creates a new empty store.
+func NewStore() *Store {
+	return &Store{
+		m: make(map[interface{}]interface{}),
+	}
+}
+
+// NewStoreFrom creates a new store holding the keys and values of a map.
+func NewStoreFrom(m map[interface{}]interface{}) *Store {
+	return &Store{m: m}
+}
+
+// Save stores a value for a key.
+func (s *Store) Save(key interface{}, val interface{}) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.m[key] = val
+}
+
+// Load returns the value stored for a key.
+func (s *Store) Load(key interface{}) (interface{}, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	val, ok := s.m[key]
+	return val, ok
+}
+
+// Len returns the number of values stored.
+func (s *Store) Len() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return len(s.m)
+}
+
+// ForEach calls a function for every key and value stored until the function returns an error.
+func (s *Store) ForEach(f func(interface{}, interface{}) error) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for k, v := range s.m {
+		if err := f(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,12 +1,10 @@
-package github
+package remote
 
 import (
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-
-	"github.com/moorara/go-github"
 )
 
 func TestStore(t *testing.T) {
@@ -18,22 +16,22 @@ func TestStore(t *testing.T) {
 		{
 			name: "Int",
 			key:  1000,
-			val: github.Issue{
-				Number: 1000,
+			val: Issue{
+				Change: Change{Number: 1000},
 			},
 		},
 		{
 			name: "String",
 			key:  "octocat",
-			val: github.User{
-				Login: "octocat",
+			val: User{
+				Username: "octocat",
 			},
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			s := newStore()
+			s := NewStore()
 			s.Save(tc.key, tc.val)
 
 			v, ok := s.Load(tc.key)
@@ -0,0 +1,430 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moorara/changelog/log"
+)
+
+const (
+	defaultMaxConcurrency = 8
+	defaultMaxRetries     = 5
+	defaultMinBackoff     = time.Second
+	defaultMaxBackoff     = time.Minute
+	defaultMaxWait        = 15 * time.Minute
+
+	// secondaryBackoff is the minimum time to wait after hitting a secondary rate limit without a Retry-After header.
+	secondaryBackoff = time.Minute
+)
+
+// rateLimit is the API rate limit status reported by a response.
+type rateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// parseRateLimit parses the rate limit headers of a response.
+// GitHub and Gitea use the X-RateLimit-* headers and GitLab uses the RateLimit-* headers.
+// The second return value is false if the response does not report any rate limit.
+func parseRateLimit(h http.Header) (rateLimit, bool) {
+	get := func(name string) string {
+		if v := h.Get("X-RateLimit-" + name); v != "" {
+			return v
+		}
+		return h.Get("RateLimit-" + name)
+	}
+
+	remaining, err := strconv.Atoi(get("Remaining"))
+	if err != nil {
+		return rateLimit{}, false
+	}
+
+	rl := rateLimit{Remaining: remaining}
+	rl.Limit, _ = strconv.Atoi(get("Limit"))
+
+	// The reset time is in UTC epoch seconds
+	if reset, err := strconv.ParseInt(get("Reset"), 10, 64); err == nil && reset > 0 {
+		rl.Reset = time.Unix(reset, 0)
+	}
+
+	return rl, true
+}
+
+// parseRetryAfter parses the Retry-After header of a response.
+// The header value can be either a number of seconds or an HTTP date.
+func parseRetryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// cacheKey returns a unique key for caching the response of a request.
+// The Accept header is part of the key, since the same URL can respond with different representations.
+func cacheKey(req *http.Request) string {
+	h := sha256.Sum256([]byte(req.Method + " " + req.URL.String() + "\n" + req.Header.Get("Accept")))
+	return hex.EncodeToString(h[:])
+}
+
+// cachedResponse is a successful response cached on disk.
+type cachedResponse struct {
+	ETag         string      `json:"etag"`
+	LastModified string      `json:"last_modified"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// Response creates a new response for a request from a cached response.
+func (c cachedResponse) Response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// isIdempotent determines whether a request can be safely retried.
+// Similar to net/http, a request with an Idempotency-Key or X-Idempotency-Key header is considered idempotent.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+
+	if _, ok := req.Header["X-Idempotency-Key"]; ok {
+		return true
+	}
+
+	return false
+}
+
+// Transport is an http.RoundTripper aware of the API rate limits of remote platforms.
+// It is meant to be shared by all API clients of a remote repository.
+//
+//   - The number of concurrent requests is throttled.
+//   - When the rate limit budget is exhausted, requests wait for the rate limit to reset.
+//   - Idempotent requests failing due to rate limits, server errors, or network errors are retried with jittered exponential backoff.
+//   - The Retry-After header and the GitHub secondary rate limits (403 Forbidden) are respected.
+//   - The remaining rate limit quota is reported via the logger.
+//   - If a cache is provided, GET responses with an ETag or Last-Modified header are cached on disk
+//     and revalidated with conditional requests (If-None-Match and If-Modified-Since).
+//     Conditional requests answered with 304 Not Modified do not count against the GitHub rate limit.
+type Transport struct {
+	logger     log.Logger
+	base       http.RoundTripper
+	cache      *Cache
+	sem        chan struct{}
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	maxWait    time.Duration
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+	lowQuota     bool
+
+	now    func() time.Time
+	sleep  func(context.Context, time.Duration) error
+	jitter func() float64
+}
+
+// NewTransport creates a new rate limit aware transport with the default settings.
+// cache can be nil for disabling the on-disk cache.
+func NewTransport(logger log.Logger, cache *Cache) *Transport {
+	return &Transport{
+		logger:     logger,
+		base:       &http.Transport{},
+		cache:      cache,
+		sem:        make(chan struct{}, defaultMaxConcurrency),
+		maxRetries: defaultMaxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		maxWait:    defaultMaxWait,
+		now:        time.Now,
+		sleep:      sleep,
+		jitter:     rand.Float64,
+	}
+}
+
+// sleep pauses for a given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff returns the jittered exponential backoff duration for a retry attempt (starting from zero).
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.minBackoff << uint(attempt)
+	if d <= 0 || d > t.maxBackoff {
+		d = t.maxBackoff
+	}
+
+	// Equal jitter: half of the duration is fixed and the other half is random
+	return d/2 + time.Duration(t.jitter()*float64(d/2))
+}
+
+// waitForReset blocks until the rate limit is reset if the budget is exhausted.
+func (t *Transport) waitForReset(ctx context.Context) error {
+	t.mu.Lock()
+	d := t.blockedUntil.Sub(t.now())
+	t.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+
+	if d > t.maxWait {
+		return fmt.Errorf("API rate limit exceeded: the rate limit resets in %s", d.Round(time.Second))
+	}
+
+	t.logger.Warnf("API rate limit exceeded, waiting %s for the rate limit to reset ...", d.Round(time.Second))
+
+	return t.sleep(ctx, d)
+}
+
+// observe records the rate limit status of a response and reports the remaining quota.
+func (t *Transport) observe(resp *http.Response) (rateLimit, bool) {
+	rl, ok := parseRateLimit(resp.Header)
+	if !ok {
+		return rl, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if rl.Remaining == 0 && rl.Reset.After(t.blockedUntil) {
+		t.blockedUntil = rl.Reset
+	}
+
+	t.logger.Debugf("API rate limit: %d of %d remaining", rl.Remaining, rl.Limit)
+
+	// Warn only once when less than 10% of the quota is remaining
+	if low := rl.Limit > 0 && rl.Remaining*10 < rl.Limit; low && !t.lowQuota {
+		t.logger.Warnf("API rate limit is running low: %d of %d remaining until %s", rl.Remaining, rl.Limit, rl.Reset.Format(time.RFC3339))
+		t.lowQuota = true
+	} else if !low {
+		t.lowQuota = false
+	}
+
+	return rl, true
+}
+
+// retryAfter determines whether a response should be retried and how long to wait before retrying it.
+func (t *Transport) retryAfter(resp *http.Response, attempt int) (time.Duration, bool) {
+	now := t.now()
+	rl, hasRateLimit := t.observe(resp)
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header, now)
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusForbidden:
+		switch {
+		case hasRetryAfter:
+			return retryAfter, true
+		case hasRateLimit && rl.Remaining == 0 && !rl.Reset.IsZero():
+			// The next attempt waits for the rate limit to reset
+			return 0, true
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return t.backoff(attempt), true
+		case isSecondaryRateLimit(resp):
+			if d := t.backoff(attempt); d > secondaryBackoff {
+				return d, true
+			}
+			return secondaryBackoff, true
+		}
+
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if hasRetryAfter {
+			return retryAfter, true
+		}
+		return t.backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// isSecondaryRateLimit determines whether a 403 Forbidden response is due to a GitHub secondary rate limit.
+// The response body is read and replaced, so it can be read again.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	if err != nil {
+		return false
+	}
+
+	msg := strings.ToLower(string(b))
+
+	return strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse detection")
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cache == nil || req.Method != "GET" || req.Header.Get("Range") != "" {
+		return t.roundTrip(req)
+	}
+
+	key := "http/" + cacheKey(req)
+	entry := cachedResponse{}
+	cached := t.cache.Load(key, &entry)
+
+	if cached {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		t.logger.Debugf("%s %s is not modified, using the cached response", req.Method, req.URL.Path)
+		return entry.Response(req), nil
+
+	case resp.StatusCode == http.StatusOK && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""):
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+		entry = cachedResponse{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Header:       resp.Header,
+			Body:         b,
+		}
+
+		if err := t.cache.Save(key, entry); err != nil {
+			t.logger.Warnf("Failed to cache the response of %s %s: %s", req.Method, req.URL.Path, err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// Throttle the number of concurrent requests
+	select {
+	case t.sem <- struct{}{}:
+		defer func() { <-t.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	retriable := isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		if err := t.waitForReset(ctx); err != nil {
+			return nil, err
+		}
+
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if err != nil {
+			if ctx.Err() != nil || !retriable || attempt >= t.maxRetries {
+				return nil, err
+			}
+
+			d := t.backoff(attempt)
+			t.logger.Debugf("%s %s failed, retrying in %s: %s", req.Method, req.URL.Path, d.Round(time.Millisecond), err)
+			if err := t.sleep(ctx, d); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		d, retry := t.retryAfter(resp, attempt)
+		if !retry || !retriable || attempt >= t.maxRetries {
+			return resp, nil
+		}
+
+		if d > t.maxWait {
+			t.logger.Warnf("%s %s is rate limited for %s, giving up", req.Method, req.URL.Path, d.Round(time.Second))
+			return resp, nil
+		}
+
+		// Drain and close the body, so the connection can be reused
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		if d > 0 {
+			t.logger.Infof("%s %s responded %d, retrying in %s ...", req.Method, req.URL.Path, resp.StatusCode, d.Round(time.Millisecond))
+			if err := t.sleep(ctx, d); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,515 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moorara/changelog/log"
+)
+
+var now = time.Date(2020, 10, 20, 20, 0, 0, 0, time.UTC)
+
+// newTestTransport creates a transport that records the sleeps instead of actually sleeping.
+func newTestTransport() (*Transport, *[]time.Duration) {
+	var mu sync.Mutex
+	sleeps := []time.Duration{}
+
+	t := NewTransport(log.New(log.None), nil)
+	t.now = func() time.Time { return now }
+	t.jitter = func() float64 { return 0.5 }
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+
+	return t, &sleeps
+}
+
+type mockRoundTripper func(*http.Request) (*http.Response, error)
+
+func (m mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return m(req)
+}
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name              string
+		header            http.Header
+		expectedOK        bool
+		expectedRateLimit rateLimit
+	}{
+		{
+			name:       "NoRateLimit",
+			header:     http.Header{},
+			expectedOK: false,
+		},
+		{
+			name: "GitHub",
+			header: http.Header{
+				"X-Ratelimit-Limit":     []string{"5000"},
+				"X-Ratelimit-Remaining": []string{"4999"},
+				"X-Ratelimit-Reset":     []string{"1603227600"},
+			},
+			expectedOK: true,
+			expectedRateLimit: rateLimit{
+				Limit:     5000,
+				Remaining: 4999,
+				Reset:     time.Unix(1603227600, 0),
+			},
+		},
+		{
+			name: "GitLab",
+			header: http.Header{
+				"Ratelimit-Limit":     []string{"2000"},
+				"Ratelimit-Remaining": []string{"0"},
+				"Ratelimit-Reset":     []string{"1603227600"},
+			},
+			expectedOK: true,
+			expectedRateLimit: rateLimit{
+				Limit:     2000,
+				Remaining: 0,
+				Reset:     time.Unix(1603227600, 0),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rl, ok := parseRateLimit(tc.header)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedRateLimit, rl)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name             string
+		header           http.Header
+		expectedOK       bool
+		expectedDuration time.Duration
+	}{
+		{
+			name:       "NoRetryAfter",
+			header:     http.Header{},
+			expectedOK: false,
+		},
+		{
+			name:       "Invalid",
+			header:     http.Header{"Retry-After": []string{"soon"}},
+			expectedOK: false,
+		},
+		{
+			name:             "Seconds",
+			header:           http.Header{"Retry-After": []string{"30"}},
+			expectedOK:       true,
+			expectedDuration: 30 * time.Second,
+		},
+		{
+			name:             "HTTPDate",
+			header:           http.Header{"Retry-After": []string{"Tue, 20 Oct 2020 20:01:00 GMT"}},
+			expectedOK:       true,
+			expectedDuration: time.Minute,
+		},
+		{
+			name:             "PastHTTPDate",
+			header:           http.Header{"Retry-After": []string{"Tue, 20 Oct 2020 19:00:00 GMT"}},
+			expectedOK:       true,
+			expectedDuration: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ok := parseRetryAfter(tc.header, now)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedDuration, d)
+		})
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	get, _ := http.NewRequest("GET", "https://api.github.com/user", nil)
+	post, _ := http.NewRequest("POST", "https://api.github.com/graphql", nil)
+	keyed, _ := http.NewRequest("POST", "https://api.github.com/graphql", nil)
+	keyed.Header["X-Idempotency-Key"] = nil
+
+	assert.True(t, isIdempotent(get))
+	assert.False(t, isIdempotent(post))
+	assert.True(t, isIdempotent(keyed))
+}
+
+func TestTransport_backoff(t *testing.T) {
+	tr, _ := newTestTransport()
+
+	assert.Equal(t, 750*time.Millisecond, tr.backoff(0))
+	assert.Equal(t, 1500*time.Millisecond, tr.backoff(1))
+	assert.Equal(t, 3*time.Second, tr.backoff(2))
+	assert.Equal(t, 45*time.Second, tr.backoff(10))
+	assert.Equal(t, 45*time.Second, tr.backoff(100))
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	reset := strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10)
+
+	type mockResponse struct {
+		statusCode int
+		header     map[string]string
+		body       string
+	}
+
+	tests := []struct {
+		name               string
+		method             string
+		responses          []mockResponse
+		expectedStatusCode int
+		expectedBody       string
+		expectedRequests   int
+		expectedSleeps     []time.Duration
+	}{
+		{
+			name:   "Success",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 200, header: map[string]string{"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "4999", "X-RateLimit-Reset": reset}, body: "{}"},
+			},
+			expectedStatusCode: 200,
+			expectedBody:       "{}",
+			expectedRequests:   1,
+			expectedSleeps:     []time.Duration{},
+		},
+		{
+			name:   "NotFound",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 404, body: `{"message": "Not Found"}`},
+			},
+			expectedStatusCode: 404,
+			expectedBody:       `{"message": "Not Found"}`,
+			expectedRequests:   1,
+			expectedSleeps:     []time.Duration{},
+		},
+		{
+			name:   "RateLimitExceeded",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 403, header: map[string]string{"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}, body: `{"message": "API rate limit exceeded"}`},
+				{statusCode: 200, body: "{}"},
+			},
+			expectedStatusCode: 200,
+			expectedBody:       "{}",
+			expectedRequests:   2,
+			expectedSleeps:     []time.Duration{10 * time.Minute},
+		},
+		{
+			name:   "RetryAfter",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 429, header: map[string]string{"Retry-After": "30"}, body: "Too Many Requests"},
+				{statusCode: 200, body: "{}"},
+			},
+			expectedStatusCode: 200,
+			expectedBody:       "{}",
+			expectedRequests:   2,
+			expectedSleeps:     []time.Duration{30 * time.Second},
+		},
+		{
+			name:   "SecondaryRateLimit",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 403, body: `{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`},
+				{statusCode: 200, body: "{}"},
+			},
+			expectedStatusCode: 200,
+			expectedBody:       "{}",
+			expectedRequests:   2,
+			expectedSleeps:     []time.Duration{time.Minute},
+		},
+		{
+			name:   "Forbidden",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 403, body: `{"message": "Resource not accessible by integration"}`},
+			},
+			expectedStatusCode: 403,
+			expectedBody:       `{"message": "Resource not accessible by integration"}`,
+			expectedRequests:   1,
+			expectedSleeps:     []time.Duration{},
+		},
+		{
+			name:   "ServerError",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 502, body: "Bad Gateway"},
+				{statusCode: 503, body: "Service Unavailable"},
+				{statusCode: 200, body: "{}"},
+			},
+			expectedStatusCode: 200,
+			expectedBody:       "{}",
+			expectedRequests:   3,
+			expectedSleeps:     []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond},
+		},
+		{
+			name:   "MaxRetries",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 503, body: "Service Unavailable"},
+				{statusCode: 503, body: "Service Unavailable"},
+				{statusCode: 503, body: "Service Unavailable"},
+				{statusCode: 503, body: "Service Unavailable"},
+				{statusCode: 503, body: "Service Unavailable"},
+				{statusCode: 503, body: "Service Unavailable"},
+			},
+			expectedStatusCode: 503,
+			expectedBody:       "Service Unavailable",
+			expectedRequests:   6,
+			expectedSleeps:     []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond, 3 * time.Second, 6 * time.Second, 12 * time.Second},
+		},
+		{
+			name:   "NotIdempotent",
+			method: "POST",
+			responses: []mockResponse{
+				{statusCode: 503, body: "Service Unavailable"},
+			},
+			expectedStatusCode: 503,
+			expectedBody:       "Service Unavailable",
+			expectedRequests:   1,
+			expectedSleeps:     []time.Duration{},
+		},
+		{
+			name:   "WaitTooLong",
+			method: "GET",
+			responses: []mockResponse{
+				{statusCode: 429, header: map[string]string{"Retry-After": "3600"}, body: "Too Many Requests"},
+			},
+			expectedStatusCode: 429,
+			expectedBody:       "Too Many Requests",
+			expectedRequests:   1,
+			expectedSleeps:     []time.Duration{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt32(&requests, 1) - 1
+				resp := tc.responses[i]
+				for k, v := range resp.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(resp.statusCode)
+				_, _ = w.Write([]byte(resp.body))
+			}))
+			defer ts.Close()
+
+			tr, sleeps := newTestTransport()
+			client := &http.Client{Transport: tr}
+
+			req, err := http.NewRequest(tc.method, ts.URL+"/repos/octocat/Hello-World", nil)
+			assert.NoError(t, err)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			b, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			assert.Equal(t, tc.expectedBody, string(b))
+			assert.Equal(t, tc.expectedRequests, int(requests))
+			assert.Equal(t, tc.expectedSleeps, *sleeps)
+		})
+	}
+}
+
+func TestTransport_RoundTrip_ResetTooFar(t *testing.T) {
+	var requests int
+	tr, sleeps := newTestTransport()
+	tr.base = mockRoundTripper(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"X-Ratelimit-Limit":     []string{"5000"},
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+			},
+			Body:    http.NoBody,
+			Request: req,
+		}, nil
+	})
+
+	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+	assert.NoError(t, err)
+
+	// The last request exhausts the rate limit
+	resp, err := tr.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The next request should not wait for an hour
+	resp, err = tr.RoundTrip(req)
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, "API rate limit exceeded: the rate limit resets in 1h0m0s")
+	assert.Equal(t, 1, requests)
+	assert.Empty(t, *sleeps)
+}
+
+func TestTransport_RoundTrip_RetryBody(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, `{"query": "{ viewer { login } }"}`, string(b))
+
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tr, _ := newTestTransport()
+	client := &http.Client{Transport: tr}
+
+	req, err := http.NewRequest("POST", ts.URL+"/graphql", strings.NewReader(`{"query": "{ viewer { login } }"}`))
+	assert.NoError(t, err)
+	req.Header["X-Idempotency-Key"] = nil
+
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), requests)
+}
+
+func TestTransport_RoundTrip_NetworkError(t *testing.T) {
+	var requests int
+	tr, sleeps := newTestTransport()
+	tr.base = mockRoundTripper(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if requests < 3 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+	})
+
+	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+	assert.NoError(t, err)
+
+	resp, err := tr.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond}, *sleeps)
+}
+
+func TestTransport_RoundTrip_Concurrency(t *testing.T) {
+	var active, peak int32
+	tr, _ := newTestTransport()
+	tr.sem = make(chan struct{}, 2)
+	tr.base = mockRoundTripper(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "https://api.github.com/user", nil)
+			_, err := tr.RoundTrip(req)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak)
+}
+
+func TestTransport_RoundTrip_ContextCanceled(t *testing.T) {
+	tr, _ := newTestTransport()
+	tr.sem = make(chan struct{}, 1)
+	tr.sem <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
+	assert.NoError(t, err)
+
+	resp, err := tr.RoundTrip(req)
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, "context canceled")
+}
+
+func TestTransport_RoundTrip_Cache(t *testing.T) {
+	var requests, notModified int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Link", `<https://api.github.com/repositories/1/tags?page=2>; rel="next"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[{"name": "v0.1.0"}]`))
+	}))
+	defer ts.Close()
+
+	tr, _ := newTestTransport()
+	tr.cache = NewCache(t.TempDir(), "github.com", "octocat/Hello-World")
+	client := &http.Client{Transport: tr}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(ts.URL + "/repos/octocat/Hello-World/tags")
+		assert.NoError(t, err)
+
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `[{"name": "v0.1.0"}]`, string(b))
+		assert.Equal(t, `<https://api.github.com/repositories/1/tags?page=2>; rel="next"`, resp.Header.Get("Link"))
+	}
+
+	// Requests with other methods are not cached
+	resp, err := client.Head(ts.URL + "/repos/octocat/Hello-World/tags")
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, int32(3), requests)
+	assert.Equal(t, int32(1), notModified)
+}
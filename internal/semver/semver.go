@@ -0,0 +1,234 @@
+// Package semver parses and increments semantic versions in git tag names.
+// See https://semver.org/spec/v2.0.0.html
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// versionRegex matches a semantic version with an optional prefix (i.e. v1.2.0, api/v2.0.0-rc.1, or 1.0.0+build.5).
+	versionRegex = regexp.MustCompile(`^(.*?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+	// counterRegex matches the trailing counter of a pre-release (i.e. rc.1 or beta2).
+	counterRegex = regexp.MustCompile(`^(.*?)(\d+)$`)
+)
+
+// Bump is the kind of increment for a semantic version.
+type Bump int
+
+const (
+	// BumpNone does not increment a version.
+	BumpNone Bump = iota
+	// BumpPatch increments the patch version for backward-compatible bug fixes.
+	BumpPatch
+	// BumpMinor increments the minor version for backward-compatible features.
+	BumpMinor
+	// BumpMajor increments the major version for backward-incompatible changes.
+	BumpMajor
+)
+
+// String returns a string representation of a bump.
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// Version is a semantic version in a git tag name.
+type Version struct {
+	// Prefix is anything before the version in a tag name (i.e. v or api/v).
+	Prefix     string
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+// Parse parses a tag name as a semantic version.
+func Parse(tag string) (Version, bool) {
+	sm := versionRegex.FindStringSubmatch(tag)
+	if len(sm) != 7 {
+		return Version{}, false
+	}
+
+	major, err1 := strconv.Atoi(sm[2])
+	minor, err2 := strconv.Atoi(sm[3])
+	patch, err3 := strconv.Atoi(sm[4])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return Version{}, false
+	}
+
+	return Version{
+		Prefix:     sm[1],
+		Major:      major,
+		Minor:      minor,
+		Patch:      patch,
+		Prerelease: sm[5],
+		Build:      sm[6],
+	}, true
+}
+
+// String returns the tag name for a semantic version.
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+
+	return s
+}
+
+// Compare compares two versions by their precedence.
+// It returns -1 if v is lower than u, 1 if v is higher than u, and 0 if they have the same precedence.
+// A pre-release has a lower precedence than its release and build metadata is ignored.
+func (v Version) Compare(u Version) int {
+	if c := compareInt(v.Major, u.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, u.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, u.Patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case v.Prerelease == u.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case u.Prerelease == "":
+		return -1
+	}
+
+	vIDs, uIDs := strings.Split(v.Prerelease, "."), strings.Split(u.Prerelease, ".")
+	for i := 0; i < len(vIDs) && i < len(uIDs); i++ {
+		if c := compareIdentifier(vIDs[i], uIDs[i]); c != 0 {
+			return c
+		}
+	}
+
+	// A larger set of pre-release identifiers has a higher precedence
+	return compareInt(len(vIDs), len(uIDs))
+}
+
+// released returns the bump that a version has been released with.
+// It is only meaningful for pre-releases, since their changes are not released yet.
+func (v Version) released() Bump {
+	switch {
+	case v.Minor == 0 && v.Patch == 0:
+		return BumpMajor
+	case v.Patch == 0:
+		return BumpMinor
+	default:
+		return BumpPatch
+	}
+}
+
+// Next returns the next version for a given bump.
+// Before the first major version (0.y.z), breaking changes only increment the minor version.
+// The next version of a pre-release is a pre-release on the same channel (i.e. v1.2.0-rc.1 to v1.2.0-rc.2),
+// unless the bump is greater than the bump that the pre-release is for (i.e. v1.2.1-rc.1 to v1.3.0-rc.1 for a feature).
+// Build metadata is never carried over to the next version.
+func (v Version) Next(b Bump) Version {
+	next := Version{
+		Prefix: v.Prefix,
+		Major:  v.Major,
+		Minor:  v.Minor,
+		Patch:  v.Patch,
+	}
+
+	if b == BumpNone {
+		next.Prerelease = v.Prerelease
+		return next
+	}
+
+	if b == BumpMajor && v.Major == 0 {
+		b = BumpMinor
+	}
+
+	if v.Prerelease != "" {
+		if b <= v.released() {
+			next.Prerelease = nextPrerelease(v.Prerelease)
+			return next
+		}
+		next.Prerelease = channel(v.Prerelease) + "1"
+	}
+
+	switch b {
+	case BumpMajor:
+		next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+	case BumpMinor:
+		next.Minor, next.Patch = v.Minor+1, 0
+	case BumpPatch:
+		next.Patch = v.Patch + 1
+	}
+
+	return next
+}
+
+// compareIdentifier compares two pre-release identifiers.
+// Numeric identifiers are compared numerically and have a lower precedence than alphanumeric identifiers.
+func compareIdentifier(a, b string) int {
+	m, errA := strconv.Atoi(a)
+	n, errB := strconv.Atoi(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return compareInt(m, n)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareInt(m, n int) int {
+	switch {
+	case m < n:
+		return -1
+	case m > n:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// channel returns a pre-release without its counter (i.e. rc. for rc.1).
+func channel(prerelease string) string {
+	if sm := counterRegex.FindStringSubmatch(prerelease); len(sm) == 3 {
+		return sm[1]
+	}
+
+	return prerelease + "."
+}
+
+// nextPrerelease increments the counter of a pre-release (i.e. rc.1 to rc.2).
+// A pre-release without a counter gets one (i.e. beta to beta.1).
+func nextPrerelease(prerelease string) string {
+	sm := counterRegex.FindStringSubmatch(prerelease)
+	if len(sm) != 3 {
+		return prerelease + ".1"
+	}
+
+	n, _ := strconv.Atoi(sm[2])
+
+	return fmt.Sprintf("%s%d", sm[1], n+1)
+}
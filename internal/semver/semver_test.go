@@ -0,0 +1,285 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBump_String(t *testing.T) {
+	tests := []struct {
+		name           string
+		b              Bump
+		expectedString string
+	}{
+		{
+			name:           "None",
+			b:              BumpNone,
+			expectedString: "none",
+		},
+		{
+			name:           "Patch",
+			b:              BumpPatch,
+			expectedString: "patch",
+		},
+		{
+			name:           "Minor",
+			b:              BumpMinor,
+			expectedString: "minor",
+		},
+		{
+			name:           "Major",
+			b:              BumpMajor,
+			expectedString: "major",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedString, tc.b.String())
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name            string
+		tag             string
+		expectedOK      bool
+		expectedVersion Version
+	}{
+		{
+			name:       "Invalid",
+			tag:        "release-2020",
+			expectedOK: false,
+		},
+		{
+			name:       "LeadingZero",
+			tag:        "v1.02.0",
+			expectedOK: false,
+		},
+		{
+			name:            "NoPrefix",
+			tag:             "1.2.0",
+			expectedOK:      true,
+			expectedVersion: Version{Major: 1, Minor: 2, Patch: 0},
+		},
+		{
+			name:            "Prefix",
+			tag:             "v1.2.3",
+			expectedOK:      true,
+			expectedVersion: Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3},
+		},
+		{
+			name:            "PrereleaseAndBuild",
+			tag:             "api/v2.0.0-rc.1+build.5",
+			expectedOK:      true,
+			expectedVersion: Version{Prefix: "api/v", Major: 2, Minor: 0, Patch: 0, Prerelease: "rc.1", Build: "build.5"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, ok := Parse(tc.tag)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedVersion, v)
+		})
+	}
+}
+
+func TestVersion_String(t *testing.T) {
+	tests := []struct {
+		name           string
+		v              Version
+		expectedString string
+	}{
+		{
+			name:           "Release",
+			v:              Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3},
+			expectedString: "v1.2.3",
+		},
+		{
+			name:           "PrereleaseAndBuild",
+			v:              Version{Prefix: "api/v", Major: 2, Prerelease: "rc.1", Build: "build.5"},
+			expectedString: "api/v2.0.0-rc.1+build.5",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedString, tc.v.String())
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		name           string
+		v, u           string
+		expectedResult int
+	}{
+		{
+			name:           "Equal",
+			v:              "v1.2.3+build.1",
+			u:              "v1.2.3+build.2",
+			expectedResult: 0,
+		},
+		{
+			name:           "Major",
+			v:              "v1.4.3",
+			u:              "v2.0.0",
+			expectedResult: -1,
+		},
+		{
+			name:           "Minor",
+			v:              "v1.10.0",
+			u:              "v1.9.0",
+			expectedResult: 1,
+		},
+		{
+			name:           "Patch",
+			v:              "v1.4.2",
+			u:              "v1.4.3",
+			expectedResult: -1,
+		},
+		{
+			name:           "PrereleaseBeforeRelease",
+			v:              "v2.0.0-rc.1",
+			u:              "v2.0.0",
+			expectedResult: -1,
+		},
+		{
+			name:           "ReleaseAfterPrerelease",
+			v:              "v2.0.0",
+			u:              "v2.0.0-rc.1",
+			expectedResult: 1,
+		},
+		{
+			name:           "NumericIdentifiers",
+			v:              "v2.0.0-rc.10",
+			u:              "v2.0.0-rc.9",
+			expectedResult: 1,
+		},
+		{
+			name:           "AlphanumericIdentifiers",
+			v:              "v2.0.0-alpha.1",
+			u:              "v2.0.0-beta.1",
+			expectedResult: -1,
+		},
+		{
+			name:           "NumericBeforeAlphanumeric",
+			v:              "v2.0.0-1",
+			u:              "v2.0.0-alpha",
+			expectedResult: -1,
+		},
+		{
+			name:           "MoreIdentifiers",
+			v:              "v2.0.0-alpha.1",
+			u:              "v2.0.0-alpha",
+			expectedResult: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, ok := Parse(tc.v)
+			assert.True(t, ok)
+
+			u, ok := Parse(tc.u)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.expectedResult, v.Compare(u))
+		})
+	}
+}
+
+func TestVersion_Next(t *testing.T) {
+	tests := []struct {
+		name        string
+		tag         string
+		b           Bump
+		expectedTag string
+	}{
+		{
+			name:        "None",
+			tag:         "v1.2.3+build.5",
+			b:           BumpNone,
+			expectedTag: "v1.2.3",
+		},
+		{
+			name:        "Patch",
+			tag:         "v1.2.3",
+			b:           BumpPatch,
+			expectedTag: "v1.2.4",
+		},
+		{
+			name:        "Minor",
+			tag:         "v1.2.3",
+			b:           BumpMinor,
+			expectedTag: "v1.3.0",
+		},
+		{
+			name:        "Major",
+			tag:         "v1.2.3",
+			b:           BumpMajor,
+			expectedTag: "v2.0.0",
+		},
+		{
+			name:        "MajorBeforeFirstMajor",
+			tag:         "v0.2.3",
+			b:           BumpMajor,
+			expectedTag: "v0.3.0",
+		},
+		{
+			name:        "PrereleasePatch",
+			tag:         "v1.3.0-rc.1",
+			b:           BumpPatch,
+			expectedTag: "v1.3.0-rc.2",
+		},
+		{
+			name:        "PrereleaseMinor",
+			tag:         "v1.3.0-rc.9",
+			b:           BumpMinor,
+			expectedTag: "v1.3.0-rc.10",
+		},
+		{
+			name:        "PrereleaseMajor",
+			tag:         "v1.3.0-rc.1",
+			b:           BumpMajor,
+			expectedTag: "v2.0.0-rc.1",
+		},
+		{
+			name:        "PrereleaseMajorBeforeFirstMajor",
+			tag:         "v0.3.0-beta2",
+			b:           BumpMajor,
+			expectedTag: "v0.3.0-beta3",
+		},
+		{
+			name:        "PrereleasePatchToMinor",
+			tag:         "v1.2.1-rc.1",
+			b:           BumpMinor,
+			expectedTag: "v1.3.0-rc.1",
+		},
+		{
+			name:        "PrereleaseWithoutCounter",
+			tag:         "v2.0.0-beta",
+			b:           BumpMinor,
+			expectedTag: "v2.0.0-beta.1",
+		},
+		{
+			name:        "PrereleaseWithoutCounterToMinor",
+			tag:         "v1.2.1-beta",
+			b:           BumpMinor,
+			expectedTag: "v1.3.0-beta.1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, ok := Parse(tc.tag)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedTag, v.Next(tc.b).String())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package spec
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -24,6 +25,15 @@ const helpTemplate = `
   Supported Remote Repositories:
 
     • GitHub (github.com)
+    • GitLab (gitlab.com)
+    • Gitea and Forgejo (gitea.com, codeberg.org)
+    • Bitbucket Cloud (bitbucket.org)
+    • Bitbucket Server and Data Center
+    • Azure DevOps Services (dev.azure.com) and Azure DevOps Server
+
+  GitHub Enterprise Server, self-managed GitLab, self-hosted Gitea/Forgejo, Bitbucket Server, and Azure DevOps Server instances can be added to the hosts section of changelog.yaml.
+  Release lines (i.e. maintenance branches) with their own tags and changelog files can be added to the lines section of changelog.yaml.
+  Components of a monorepo with their own tag prefixes, paths, and changelog files can be added to the components section of changelog.yaml.
 
   Usage: changelog [flags]
 
@@ -34,19 +44,44 @@ const helpTemplate = `
 
     {{ yellow "-access-token                 The OAuth access token for making API calls" }}
     {{ yellow "                              The default value is read from the CHANGELOG_ACCESS_TOKEN environment variable" }}
+    -platform                     The platform of the remote repository (values: github.com|gitlab.com|gitea|bitbucket|bitbucket-server|azure-devops) (default: resolved from the remote domain)
+    -mode                         Where the repository data is read from (values: remote|local|hybrid) (default: {{.Repo.Mode}})
+                                  In local mode, no API call is made and merges are derived from the local git history
+                                  In hybrid mode, the commit graph is read from the local git history and only issues and merges from the API
+    -github-api                   The GitHub API used for fetching issues and pull requests (values: rest|graphql) (default: {{.Repo.GitHubAPI}})
+                                  The GraphQL API fetches them in batches and makes far fewer API calls
 
     -file                         The output file for the generated changelog (default: {{.General.File}})
+    -format                       The format of the changelog file (values: markdown|keep-a-changelog|json|yaml) (default: resolved from the file extension)
     -base                         An optional file for appending the generated changelog to it {{if .General.Base}}(default: {{.General.Base}}){{end}}
                                   This option can only be used when generating the changelog for the first time
     -print                        Print the generated changelong to STDOUT (default: {{.General.Print}})
                                   If this option is enabled, all logs will be disabled
+    -check                        Check whether the changelog is up-to-date without writing it (default: {{.General.Check}})
+                                  If the changelog is out of date, the changes are printed as a unified diff and the exit code is non-zero
+    -release-notes                Print the release notes of an existing release on changelog for this tag without generating the changelog
+                                  If this option is enabled, all logs will be disabled
+    -release-notes-format         The format of the release notes (values: markdown|text) (default: {{.General.ReleaseNotesFormat}})
+    -next-version                 Print the next version suggested by the unreleased changes without generating the changelog
+                                  If this option is enabled, all logs will be disabled
     -verbose                      Show the vervbosity logs (default: {{.General.Verbose}})
+    -no-cache                     Disable the on-disk cache of remote API data (default: {{.General.NoCache}})
+    -cache-dir                    The directory for caching remote API data between runs (default: $XDG_CACHE_HOME/changelog)
 
     -from-tag                     Changelog will be generated for all changes after this tag (default: last tag on changelog)
     -to-tag                       Changelog will be generated for all changes before this tag (default: last git tag)
     -future-tag                   A future tag for all unreleased changes (changes after the last git tag) {{if .Tags.Future}}(default: {{.Tags.Future ","}}){{end}}
+                                  Use {next} for the next version suggested by the unreleased changes (breaking changes, features, or other changes)
     -exclude-tags                 These tags will be excluded from changelog {{if .Tags.Exclude}}(default: {{Join .Tags.Exclude ","}}){{end}}
     -exclude-tags-regex           A POSIX-compliant regex for excluding certain tags from changelog {{if .Tags.ExcludeRegex}}(default: {{.Tags.ExcludeRegex}}){{end}}
+    -include-tags-regex           A POSIX-compliant regex for including only certain tags in changelog (changes released in other tags are not included) {{if .Tags.IncludeRegex}}(default: {{.Tags.IncludeRegex}}){{end}}
+    -tags-prefix                  Only tags with this prefix are considered (i.e. api/ for api/v1.3.0) {{if .Tags.Prefix}}(default: {{.Tags.Prefix}}){{end}}
+    -trim-tags-prefix             Show tag names without the prefix on changelog (default: {{.Tags.TrimPrefix}})
+    -tags-ordering                Ordering of tags from the most recent to the least recent (values: commit-time|tag-time|semver|calver) (default: {{.Tags.Ordering}})
+                                  Use semver or calver for interleaved release lines (i.e. a hotfix on a maintenance branch tagged after a new major version)
+    -regenerate-from              Existing releases on changelog will be generated again in place from this tag (default: first tag on changelog)
+    -regenerate-to                Existing releases on changelog will be generated again in place up to this tag (default: last tag on changelog)
+    -regenerate-all               Generate all existing releases on changelog again in place (default: {{.Tags.RegenerateAll}})
 
     -issues-selection             Include closed issues in changelog (values: none|all|labeled) (default: {{.Issues.Selection}})
     -issues-include-labels        Include issues with these labels {{if .Issues.IncludeLabels}}(default: {{Join .Issues.IncludeLabels ","}}){{end}}
@@ -63,6 +98,8 @@ const helpTemplate = `
 
     -merges-selection             Include merged pull/merge requests in changelog (values: none|all|labeled) (default: {{.Merges.Selection}})
     -merges-branch                Include pull/merge requests merged into this branch (default: default remote branch)
+    -merges-paths                 Include merges changing files in these paths (i.e. api/ for a component of a monorepo) {{if .Merges.Paths}}(default: {{Join .Merges.Paths ","}}){{end}}
+                                  Commits following the Conventional Commits specification are not filtered by paths
     -merges-include-labels        Include merges with these labels {{if .Merges.IncludeLabels}}(default: {{Join .Merges.IncludeLabels ","}}){{end}}
     -merges-exclude-labels        Exclude merges with these labels {{if .Merges.ExcludeLabels}}(default: {{Join .Merges.ExcludeLabels ","}}){{end}}
     -merges-grouping              Grouping style for pull/merge requests (values: simple|milestone|label) (default: {{.Merges.Grouping}})
@@ -75,7 +112,14 @@ const helpTemplate = `
     -merges-bug-labels            Labels for bug group {{if .Merges.BugLabels}}(default: {{Join .Merges.BugLabels ","}}){{end}}
     -merges-security-labels       Labels for security group {{if .Merges.SecurityLabels}}(default: {{Join .Merges.SecurityLabels ","}}){{end}}
 
+    -commits-selection            Include commits following the Conventional Commits specification in changelog (values: none|all) (default: {{.Commits.Selection}})
+    -commits-groups               Map commit types to the label groups (values: summary|removed|breaking|deprecated|feature|enhancement|bug|security) {{if .Commits.Groups}}(default: {{Join .Commits.Groups ","}}){{end}}
+                                  Only commits with a mapped type and breaking changes are included (i.e. feat:feature,fix:bug)
+
     -release-url                  An external release URL with the '{tag}' placeholder for the release tag
+    -header-template              A text/template file for the header of a new Markdown changelog
+    -release-template             A text/template file for every release in the Markdown changelog
+                                  The template should start with the release heading: ## [{{"{{"}}.TagName{{"}}"}}]({{"{{"}}.TagURL{{"}}"}}) ({{"{{"}}time .TagTime{{"}}"}})
 
   Examples:
 
@@ -83,6 +127,8 @@ const helpTemplate = `
     changelog -access-token=<your-access-token>
     changelog -access-token=<your-access-token> -base=HISTORY.md
     changelog -access-token=<your-access-token> -future-tag=v0.1.0
+    changelog -mode=local
+    changelog -release-notes=v0.1.0
 
 `
 
@@ -90,19 +136,38 @@ const format = `
 Specifications
 Repo:
   Platform:           %s
+  Mode:               %s
+  GitHubAPI:          %s
+  Domain:             %s
   Path:               %s
+  APIURL:             %s
+  WebURL:             %s
   AccessToken:        %s
 General:
   File:               %s
+  Format:             %s
   Base:               %s
   Print:              %t
+  Check:              %t
+  ReleaseNotes:       %s
+  ReleaseNotesFormat: %s
+  NextVersion:        %t
   Verbose:            %t
+  NoCache:            %t
+  CacheDir:           %s
 Tags:
   From:               %s
   To:                 %s
   Future:             %s
   Exclude:            %s
   ExcludeRegex:       %s
+  IncludeRegex:       %s
+  Prefix:             %s
+  TrimPrefix:         %t
+  Ordering:           %s
+  RegenerateFrom:     %s
+  RegenerateTo:       %s
+  RegenerateAll:      %t
 Issues:
   Selection:          %s
   IncludeLabels:      %s
@@ -119,6 +184,7 @@ Issues:
 Merges:
   Selection:          %s
   Branch:             %s
+  Paths:              %s
   IncludeLabels:      %s
   ExcludeLabels:      %s
   Grouping:           %s
@@ -130,8 +196,13 @@ Merges:
   EnhancementLabels:  %s
   BugLabels:          %s
   SecurityLabels:     %s
+Commits:
+  Selection:          %s
+  Groups:             %s
 Content:
   ReleaseURL:         %s
+  HeaderTemplate:     %s
+  ReleaseTemplate:    %s
 `
 
 // Platform is the platform for managing a Git remote repository.
@@ -142,23 +213,189 @@ const (
 	PlatformGitHub Platform = "github.com"
 	// PlatformGitLab represents the GitLab platform.
 	PlatformGitLab Platform = "gitlab.com"
+	// PlatformGitea represents the Gitea platform.
+	// Forgejo is a fork of Gitea with the same API and uses this platform too.
+	PlatformGitea Platform = "gitea"
+	// PlatformBitbucket represents the Bitbucket Cloud platform.
+	PlatformBitbucket Platform = "bitbucket"
+	// PlatformBitbucketServer represents the Bitbucket Server and Bitbucket Data Center platform.
+	PlatformBitbucketServer Platform = "bitbucket-server"
+	// PlatformAzureDevOps represents the Azure DevOps Services and Azure DevOps Server platform.
+	PlatformAzureDevOps Platform = "azure-devops"
+)
+
+// normalize returns the platform with the short names github and gitlab resolved to github.com and gitlab.com.
+func (p Platform) normalize() Platform {
+	switch p {
+	case "github":
+		return PlatformGitHub
+	case "gitlab":
+		return PlatformGitLab
+	default:
+		return p
+	}
+}
+
+// Format determines the file format of a changelog.
+type Format string
+
+const (
+	// FormatMarkdown is the human-readable Markdown format.
+	FormatMarkdown Format = "markdown"
+	// FormatKeepAChangelog is the Markdown format following the Keep a Changelog convention (https://keepachangelog.com).
+	FormatKeepAChangelog Format = "keep-a-changelog"
+	// FormatJSON is a machine-readable JSON format with a versioned schema.
+	FormatJSON Format = "json"
+	// FormatYAML is a machine-readable YAML format with the same versioned schema as the JSON format.
+	FormatYAML Format = "yaml"
 )
 
+// NotesFormat determines the format of release notes.
+type NotesFormat string
+
+const (
+	// NotesFormatMarkdown outputs release notes in Markdown format as they are on a Markdown changelog.
+	NotesFormatMarkdown NotesFormat = "markdown"
+	// NotesFormatText outputs release notes in plain text format without any Markdown syntax.
+	NotesFormatText NotesFormat = "text"
+)
+
+// Mode determines where the data of a repository is read from.
+type Mode string
+
+const (
+	// ModeRemote reads all data from the API of the remote platform.
+	ModeRemote Mode = "remote"
+	// ModeLocal reads all data from the local git repository without any API call.
+	// Only merges are derived from the merge commits and squashed commits (no issues).
+	ModeLocal Mode = "local"
+	// ModeHybrid reads the commit graph (first commit, branches, tags, and parent commits) from the local git repository
+	// and issues and merges from the API of the remote platform.
+	ModeHybrid Mode = "hybrid"
+)
+
+// API determines which API of a platform is used.
+type API string
+
+const (
+	// APIREST uses the REST API of the platform.
+	APIREST API = "rest"
+	// APIGraphQL uses the GraphQL API of the platform.
+	APIGraphQL API = "graphql"
+)
+
+// Host maps the domain of a Git remote repository to a platform.
+// It is used for self-hosted instances such as GitHub Enterprise Server, self-managed GitLab, and Gitea.
+type Host struct {
+	Domain   string   `yaml:"domain"`
+	Platform Platform `yaml:"platform"`
+	APIURL   string   `yaml:"api-url"`
+	WebURL   string   `yaml:"web-url"`
+}
+
+// defaultHosts are the hosts known without any configuration.
+var defaultHosts = []Host{
+	{
+		Domain:   "github.com",
+		Platform: PlatformGitHub,
+		APIURL:   "https://api.github.com",
+		WebURL:   "https://github.com",
+	},
+	{
+		Domain:   "gitlab.com",
+		Platform: PlatformGitLab,
+		APIURL:   "https://gitlab.com/api/v4",
+		WebURL:   "https://gitlab.com",
+	},
+	{
+		Domain:   "gitea.com",
+		Platform: PlatformGitea,
+		APIURL:   "https://gitea.com/api/v1",
+		WebURL:   "https://gitea.com",
+	},
+	{
+		Domain:   "codeberg.org",
+		Platform: PlatformGitea,
+		APIURL:   "https://codeberg.org/api/v1",
+		WebURL:   "https://codeberg.org",
+	},
+	{
+		Domain:   "bitbucket.org",
+		Platform: PlatformBitbucket,
+		APIURL:   "https://api.bitbucket.org/2.0",
+		WebURL:   "https://bitbucket.org",
+	},
+	{
+		Domain:   "dev.azure.com",
+		Platform: PlatformAzureDevOps,
+		APIURL:   "https://dev.azure.com",
+		WebURL:   "https://dev.azure.com",
+	},
+}
+
+// GetAPIURL returns the base URL for making API calls to a host.
+// If no API URL is specified, the default API URL of the platform on the host domain will be returned.
+func (h Host) GetAPIURL() string {
+	if h.APIURL != "" {
+		return strings.TrimSuffix(h.APIURL, "/")
+	}
+
+	switch h.Platform {
+	case PlatformGitHub:
+		return "https://" + h.Domain + "/api/v3"
+	case PlatformGitLab:
+		return "https://" + h.Domain + "/api/v4"
+	case PlatformGitea:
+		return "https://" + h.Domain + "/api/v1"
+	case PlatformBitbucket:
+		return "https://api." + h.Domain + "/2.0"
+	case PlatformBitbucketServer:
+		return "https://" + h.Domain + "/rest/api/1.0"
+	default:
+		return "https://" + h.Domain
+	}
+}
+
+// GetWebURL returns the base URL for generating links to a host.
+// If no web URL is specified, it defaults to the host domain.
+func (h Host) GetWebURL() string {
+	if h.WebURL != "" {
+		return strings.TrimSuffix(h.WebURL, "/")
+	}
+
+	return "https://" + h.Domain
+}
+
 // Repo has the specifications for a git repository.
 type Repo struct {
-	Platform    Platform `yaml:"-"`
+	Platform    Platform `yaml:"-" flag:"platform"`
+	Mode        Mode     `yaml:"-" flag:"mode"`
+	GitHubAPI   API      `yaml:"-" flag:"github-api"`
+	Domain      string   `yaml:"-"`
 	Path        string   `yaml:"-"`
+	APIURL      string   `yaml:"-"`
+	WebURL      string   `yaml:"-"`
 	AccessToken string   `yaml:"-" flag:"access-token"`
 }
 
 // General has the general specifications.
 type General struct {
-	File    string `yaml:"file" flag:"file"`
-	Base    string `yaml:"base" flag:"base"`
-	Print   bool   `yaml:"print" flag:"print"`
-	Verbose bool   `yaml:"verbose" flag:"verbose"`
+	File               string      `yaml:"file" flag:"file"`
+	Format             Format      `yaml:"format" flag:"format"`
+	Base               string      `yaml:"base" flag:"base"`
+	Print              bool        `yaml:"print" flag:"print"`
+	Check              bool        `yaml:"-" flag:"check"`
+	ReleaseNotes       string      `yaml:"-" flag:"release-notes"`
+	ReleaseNotesFormat NotesFormat `yaml:"release-notes-format" flag:"release-notes-format"`
+	NextVersion        bool        `yaml:"-" flag:"next-version"`
+	Verbose            bool        `yaml:"verbose" flag:"verbose"`
+	NoCache            bool        `yaml:"no-cache" flag:"no-cache"`
+	CacheDir           string      `yaml:"cache-dir" flag:"cache-dir"`
 }
 
+// FutureTagNext is the future tag that is resolved to the next version suggested by the unreleased changes.
+const FutureTagNext = "{next}"
+
 // Tags has the specifications for identifying git tags.
 type Tags struct {
 	From         string   `yaml:"-" flag:"from-tag"`
@@ -166,8 +403,33 @@ type Tags struct {
 	Future       string   `yaml:"-" flag:"future-tag"`
 	Exclude      []string `yaml:"exclude" flag:"exclude-tags"`
 	ExcludeRegex string   `yaml:"exclude-regex" flag:"exclude-tags-regex"`
+	// Changes released in the tags not included are not assigned to the included tags.
+	IncludeRegex string `yaml:"include-regex" flag:"include-tags-regex"`
+	// Tags without the prefix are ignored entirely (i.e. the tags of other components in a monorepo).
+	Prefix     string   `yaml:"prefix" flag:"tags-prefix"`
+	TrimPrefix bool     `yaml:"trim-prefix" flag:"trim-tags-prefix"`
+	Ordering   Ordering `yaml:"ordering" flag:"tags-ordering"`
+	// Existing releases in the range are generated again and replaced in place.
+	RegenerateFrom string `yaml:"-" flag:"regenerate-from"`
+	RegenerateTo   string `yaml:"-" flag:"regenerate-to"`
+	RegenerateAll  bool   `yaml:"-" flag:"regenerate-all"`
 }
 
+// Ordering determines how tags are ordered from the most recent to the least recent.
+type Ordering string
+
+const (
+	// OrderingCommitTime orders tags by the committer times of the commits they point to.
+	OrderingCommitTime = Ordering("commit-time")
+	// OrderingTagTime orders tags by the times they are created.
+	// The committer times are used for lightweight tags and for platforms not providing the creation times.
+	OrderingTagTime = Ordering("tag-time")
+	// OrderingSemVer orders tags by the precedence of their semantic versions.
+	OrderingSemVer = Ordering("semver")
+	// OrderingCalVer orders tags by their calendar versions (i.e. 2020.10.1).
+	OrderingCalVer = Ordering("calver")
+)
+
 // Selection determines how changes should be selected for a changelog.
 type Selection string
 
@@ -281,6 +543,7 @@ func (i Issues) LabelGroups() []LabelGroup {
 type Merges struct {
 	Selection         Selection `yaml:"selection" flag:"merges-selection"`
 	Branch            string    `yaml:"branch" flag:"merges-branch"`
+	Paths             []string  `yaml:"paths" flag:"merges-paths"`
 	IncludeLabels     []string  `yaml:"include-labels" flag:"merges-include-labels"`
 	ExcludeLabels     []string  `yaml:"exclude-labels" flag:"merges-exclude-labels"`
 	Grouping          Grouping  `yaml:"grouping" flag:"merges-grouping"`
@@ -357,9 +620,64 @@ func (m Merges) LabelGroups() []LabelGroup {
 	return groups
 }
 
+// Commits has the specifications for fetching, flitering, and grouping commits following the Conventional Commits specification.
+// See https://www.conventionalcommits.org
+// Commits are grouped the same way as merges by labels, and the type of a commit is mapped to one of the label groups (i.e. feat:feature).
+type Commits struct {
+	Selection Selection `yaml:"selection" flag:"commits-selection"`
+	Groups    []string  `yaml:"groups" flag:"commits-groups"`
+}
+
+// commitGroups are the names of the label groups that commit types can be mapped to.
+var commitGroups = []string{"summary", "removed", "breaking", "deprecated", "feature", "enhancement", "bug", "security"}
+
+// Types returns the commit types mapped to every label group.
+func (c Commits) Types() (map[string][]string, error) {
+	types := map[string][]string{}
+
+	for _, g := range c.Groups {
+		valid := false
+
+		if i := strings.Index(g, ":"); i > 0 {
+			for _, group := range commitGroups {
+				if g[i+1:] == group {
+					types[group] = append(types[group], strings.ToLower(g[:i]))
+					valid = true
+				}
+			}
+		}
+
+		if !valid {
+			return nil, fmt.Errorf("invalid commit group %q: expected type:group with a group from %s", g, strings.Join(commitGroups, "|"))
+		}
+	}
+
+	return types, nil
+}
+
+// LabelGroups returns the label groups for commits.
+// They are the label groups of merges with the mapped commit types as their labels.
+// Breaking changes are always in the breaking group.
+func (c Commits) LabelGroups() []LabelGroup {
+	types, _ := c.Types()
+
+	return Merges{
+		SummaryLabels:     types["summary"],
+		RemovedLabels:     types["removed"],
+		BreakingLabels:    append([]string{"breaking"}, types["breaking"]...),
+		DeprecatedLabels:  types["deprecated"],
+		FeatureLabels:     types["feature"],
+		EnhancementLabels: types["enhancement"],
+		BugLabels:         types["bug"],
+		SecurityLabels:    types["security"],
+	}.LabelGroups()
+}
+
 // Content has the specifications for the content of changelogs.
 type Content struct {
-	ReleaseURL string `yaml:"release-url" flag:"release-url"`
+	ReleaseURL      string `yaml:"release-url" flag:"release-url"`
+	HeaderTemplate  string `yaml:"header-template" flag:"header-template"`
+	ReleaseTemplate string `yaml:"release-template" flag:"release-template"`
 }
 
 // GetReleaseURL returns the actual release url for a tag/release.
@@ -367,16 +685,36 @@ func (c Content) GetReleaseURL(tag string) string {
 	return strings.Replace(c.ReleaseURL, "{tag}", tag, 1)
 }
 
+// Line is a release line with its own branch, tags, and changelog file (i.e. a maintenance branch).
+type Line struct {
+	Branch    string `yaml:"branch"`
+	TagsRegex string `yaml:"tags-regex"`
+	FutureTag string `yaml:"future-tag"`
+	File      string `yaml:"file"`
+}
+
+// Component is a component of a monorepo with its own tags, paths, and changelog file.
+type Component struct {
+	TagPrefix  string   `yaml:"tag-prefix"`
+	TrimPrefix bool     `yaml:"trim-prefix"`
+	Paths      []string `yaml:"paths"`
+	File       string   `yaml:"file"`
+}
+
 // Spec has all the specifications required for generating a changelog.
 type Spec struct {
-	Help    bool    `yaml:"-" flag:"help"`
-	Version bool    `yaml:"-" flag:"version"`
-	Repo    Repo    `yaml:"-"`
-	General General `yaml:"general"`
-	Tags    Tags    `yaml:"tags"`
-	Issues  Issues  `yaml:"issues"`
-	Merges  Merges  `yaml:"merges"`
-	Content Content `yaml:"content"`
+	Help       bool        `yaml:"-" flag:"help"`
+	Version    bool        `yaml:"-" flag:"version"`
+	Repo       Repo        `yaml:"-"`
+	Hosts      []Host      `yaml:"hosts"`
+	Lines      []Line      `yaml:"lines"`
+	Components []Component `yaml:"components"`
+	General    General     `yaml:"general"`
+	Tags       Tags        `yaml:"tags"`
+	Issues     Issues      `yaml:"issues"`
+	Merges     Merges      `yaml:"merges"`
+	Commits    Commits     `yaml:"commits"`
+	Content    Content     `yaml:"content"`
 }
 
 // Default returns specfications with default values.
@@ -387,21 +725,43 @@ func Default() Spec {
 		Version: false,
 		Repo: Repo{
 			Platform:    Platform(""),
+			Mode:        ModeRemote,
+			GitHubAPI:   APIREST,
+			Domain:      "",
 			Path:        "",
+			APIURL:      "",
+			WebURL:      "",
 			AccessToken: os.Getenv(envVarName),
 		},
+		Hosts:      []Host{},
+		Lines:      []Line{},
+		Components: []Component{},
 		General: General{
-			File:    "CHANGELOG.md",
-			Base:    "",
-			Print:   false,
-			Verbose: false,
+			File:               "CHANGELOG.md",
+			Format:             Format(""), // Resolved from the file extension
+			Base:               "",
+			Print:              false,
+			Check:              false,
+			ReleaseNotes:       "",
+			ReleaseNotesFormat: NotesFormatMarkdown,
+			NextVersion:        false,
+			Verbose:            false,
+			NoCache:            false,
+			CacheDir:           "", // $XDG_CACHE_HOME/changelog
 		},
 		Tags: Tags{
-			From:         "",
-			To:           "",
-			Future:       "",
-			Exclude:      []string{},
-			ExcludeRegex: "",
+			From:           "",
+			To:             "",
+			Future:         "",
+			Exclude:        []string{},
+			ExcludeRegex:   "",
+			IncludeRegex:   "",
+			Prefix:         "",
+			TrimPrefix:     false,
+			Ordering:       OrderingCommitTime,
+			RegenerateFrom: "",
+			RegenerateTo:   "",
+			RegenerateAll:  false,
 		},
 		Issues: Issues{
 			Selection:         SelectionAll,
@@ -420,6 +780,7 @@ func Default() Spec {
 		Merges: Merges{
 			Selection:         SelectionAll,
 			Branch:            "",  // Default branch
+			Paths:             nil, // All paths
 			IncludeLabels:     nil, // All labels
 			ExcludeLabels:     nil, // No label excluded
 			Grouping:          GroupingSimple,
@@ -432,8 +793,14 @@ func Default() Spec {
 			BugLabels:         []string{},
 			SecurityLabels:    []string{},
 		},
+		Commits: Commits{
+			Selection: SelectionNone,
+			Groups:    []string{"feat:feature", "perf:enhancement", "refactor:enhancement", "fix:bug", "security:security"},
+		},
 		Content: Content{
-			ReleaseURL: "",
+			ReleaseURL:      "",
+			HeaderTemplate:  "",
+			ReleaseTemplate: "",
 		},
 	}
 }
@@ -461,14 +828,163 @@ func (s Spec) FromFile() (Spec, error) {
 }
 
 // WithRepo adds populates Repo sepcs and returns a new spec object.
+// The platform and the base URLs are resolved from the hosts matching the domain.
+// An explicitly specified platform takes precedence over the platform of the matching host.
+// If the domain does not match any of the hosts or the default ones and no platform is specified, the platform will be left empty.
 func (s Spec) WithRepo(domain, path string) Spec {
-	// Leave s.Repo.AccessToken unchanged
-	s.Repo.Platform = Platform(domain)
+	// Leave s.Repo.Platform and s.Repo.AccessToken unchanged
+	s.Repo.Domain = domain
 	s.Repo.Path = path
+	s.Repo.APIURL = ""
+	s.Repo.WebURL = ""
+
+	// User-defined hosts take precedence over the default ones
+	hosts := append(append([]Host{}, s.Hosts...), defaultHosts...)
+
+	host := Host{Domain: domain}
+	for _, h := range hosts {
+		if h.Domain == domain {
+			host = h
+			break
+		}
+	}
+
+	s.Repo.Platform = s.Repo.Platform.normalize()
+	host.Platform = host.Platform.normalize()
+
+	if s.Repo.Platform == "" {
+		s.Repo.Platform = host.Platform
+	} else if s.Repo.Platform != host.Platform {
+		// The base URLs of the host are not applicable to a different platform
+		host = Host{Domain: domain, Platform: s.Repo.Platform}
+	}
+
+	if s.Repo.Platform != "" {
+		s.Repo.APIURL = host.GetAPIURL()
+		s.Repo.WebURL = host.GetWebURL()
+	}
 
 	return s
 }
 
+// LineSpecs returns a new spec object for every release line.
+// The changelog of a release line only includes the tags matching its regex and the merges into its branch.
+// A future tag is only used for the release line declaring it, but the {next} future tag is used for every release line.
+// If there is no release line, the spec itself is returned.
+func (s Spec) LineSpecs() ([]Spec, error) {
+	if len(s.Lines) == 0 {
+		return []Spec{s}, nil
+	}
+
+	if s.Tags.Future != "" && s.Tags.Future != FutureTagNext {
+		return nil, fmt.Errorf("future tag %s cannot be used for all release lines: use %s or future-tag for every line", s.Tags.Future, FutureTagNext)
+	}
+
+	specs := []Spec{}
+	files := map[string]bool{}
+
+	for i, l := range s.Lines {
+		if l.File == "" {
+			return nil, fmt.Errorf("release line %d: file is required", i+1)
+		}
+		if files[l.File] {
+			return nil, fmt.Errorf("release line %d: duplicate file %s", i+1, l.File)
+		}
+		files[l.File] = true
+
+		ls := s
+		ls.Lines = nil
+		ls.General.File = l.File
+		ls.Tags.IncludeRegex = l.TagsRegex
+		ls.Merges.Branch = l.Branch
+		if l.FutureTag != "" {
+			ls.Tags.Future = l.FutureTag
+		}
+		specs = append(specs, ls)
+	}
+
+	return specs, nil
+}
+
+// ComponentSpecs returns a new spec object for every component of a monorepo.
+// The changelog of a component only includes the tags with its prefix and the merges changing files in its paths.
+// If there is no component, the spec itself is returned.
+func (s Spec) ComponentSpecs() ([]Spec, error) {
+	if len(s.Components) == 0 {
+		return []Spec{s}, nil
+	}
+
+	if len(s.Lines) > 0 {
+		return nil, errors.New("components and release lines cannot be used together")
+	}
+
+	specs := []Spec{}
+	files := map[string]bool{}
+
+	for i, c := range s.Components {
+		if c.TagPrefix == "" {
+			return nil, fmt.Errorf("component %d: tag prefix is required", i+1)
+		}
+		if c.File == "" {
+			return nil, fmt.Errorf("component %d: file is required", i+1)
+		}
+		if files[c.File] {
+			return nil, fmt.Errorf("component %d: duplicate file %s", i+1, c.File)
+		}
+		files[c.File] = true
+
+		cs := s
+		cs.Components = nil
+		cs.General.File = c.File
+		cs.Tags.Prefix = c.TagPrefix
+		cs.Tags.TrimPrefix = c.TrimPrefix
+		cs.Merges.Paths = c.Paths
+		specs = append(specs, cs)
+	}
+
+	return specs, nil
+}
+
+// ChangelogSpecs returns a new spec object for every changelog file.
+// Every component of a monorepo or every release line has its own changelog file.
+// If there is no component or release line, the spec itself is returned.
+func (s Spec) ChangelogSpecs() ([]Spec, error) {
+	if len(s.Components) > 0 {
+		return s.ComponentSpecs()
+	}
+
+	return s.LineSpecs()
+}
+
+// ChangelogNames returns a name for every changelog file in the same order as ChangelogSpecs.
+// A component is named by its tag prefix (i.e. api for api/) and a release line by its branch.
+// A release line without a branch (the default branch) is named by its changelog file.
+// If there is no component or release line, the spec itself is named by an empty name.
+func (s Spec) ChangelogNames() []string {
+	names := []string{}
+
+	switch {
+	case len(s.Components) > 0:
+		for _, c := range s.Components {
+			names = append(names, strings.TrimSuffix(c.TagPrefix, "/"))
+		}
+
+	case len(s.Lines) > 0:
+		for _, l := range s.Lines {
+			if l.Branch != "" {
+				names = append(names, l.Branch)
+			} else {
+				names = append(names, l.File)
+			}
+		}
+
+	default:
+		names = append(names, "")
+	}
+
+	return names
+}
+
 // PrintHelp prints the help text.
 func (s Spec) PrintHelp() error {
 	blue := color.New(color.FgBlue)
@@ -501,13 +1017,14 @@ func (s Spec) PrintHelp() error {
 
 func (s Spec) String() string {
 	return fmt.Sprintf(format,
-		s.Repo.Platform, s.Repo.Path, strings.Repeat("*", len(s.Repo.AccessToken)),
-		s.General.File, s.General.Base, s.General.Print, s.General.Verbose,
-		s.Tags.From, s.Tags.To, s.Tags.Future, s.Tags.Exclude, s.Tags.ExcludeRegex,
+		s.Repo.Platform, s.Repo.Mode, s.Repo.GitHubAPI, s.Repo.Domain, s.Repo.Path, s.Repo.APIURL, s.Repo.WebURL, strings.Repeat("*", len(s.Repo.AccessToken)),
+		s.General.File, s.General.Format, s.General.Base, s.General.Print, s.General.Check, s.General.ReleaseNotes, s.General.ReleaseNotesFormat, s.General.NextVersion, s.General.Verbose, s.General.NoCache, s.General.CacheDir,
+		s.Tags.From, s.Tags.To, s.Tags.Future, s.Tags.Exclude, s.Tags.ExcludeRegex, s.Tags.IncludeRegex, s.Tags.Prefix, s.Tags.TrimPrefix, s.Tags.Ordering, s.Tags.RegenerateFrom, s.Tags.RegenerateTo, s.Tags.RegenerateAll,
 		s.Issues.Selection, s.Issues.IncludeLabels, s.Issues.ExcludeLabels,
 		s.Issues.Grouping, s.Issues.SummaryLabels, s.Issues.RemovedLabels, s.Issues.BreakingLabels, s.Issues.DeprecatedLabels, s.Issues.FeatureLabels, s.Issues.EnhancementLabels, s.Issues.BugLabels, s.Issues.SecurityLabels,
-		s.Merges.Selection, s.Merges.Branch, s.Merges.IncludeLabels, s.Merges.ExcludeLabels,
+		s.Merges.Selection, s.Merges.Branch, s.Merges.Paths, s.Merges.IncludeLabels, s.Merges.ExcludeLabels,
 		s.Merges.Grouping, s.Merges.SummaryLabels, s.Merges.RemovedLabels, s.Merges.BreakingLabels, s.Merges.DeprecatedLabels, s.Merges.FeatureLabels, s.Merges.EnhancementLabels, s.Merges.BugLabels, s.Merges.SecurityLabels,
-		s.Content.ReleaseURL,
+		s.Commits.Selection, s.Commits.Groups,
+		s.Content.ReleaseURL, s.Content.HeaderTemplate, s.Content.ReleaseTemplate,
 	)
 }
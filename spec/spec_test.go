@@ -87,6 +87,92 @@ func TestMerges_LabelGroups(t *testing.T) {
 	}
 }
 
+func TestCommits_Types(t *testing.T) {
+	tests := []struct {
+		name          string
+		commits       Commits
+		expectedTypes map[string][]string
+		expectedError string
+	}{
+		{
+			name: "OK",
+			commits: Commits{
+				Groups: []string{"feat:feature", "Perf:enhancement", "refactor:enhancement"},
+			},
+			expectedTypes: map[string][]string{
+				"feature":     {"feat"},
+				"enhancement": {"perf", "refactor"},
+			},
+		},
+		{
+			name: "NoType",
+			commits: Commits{
+				Groups: []string{":feature"},
+			},
+			expectedError: `invalid commit group ":feature": expected type:group with a group from summary|removed|breaking|deprecated|feature|enhancement|bug|security`,
+		},
+		{
+			name: "InvalidGroup",
+			commits: Commits{
+				Groups: []string{"feat:features"},
+			},
+			expectedError: `invalid commit group "feat:features": expected type:group with a group from summary|removed|breaking|deprecated|feature|enhancement|bug|security`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			types, err := tc.commits.Types()
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTypes, types)
+			} else {
+				assert.Nil(t, types)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestCommits_LabelGroups(t *testing.T) {
+	tests := []struct {
+		name                string
+		commits             Commits
+		expectedLabelGroups []LabelGroup
+	}{
+		{
+			name:    "NoGroup",
+			commits: Commits{},
+			expectedLabelGroups: []LabelGroup{
+				{Title: "Breaking Changes", Labels: []string{"breaking"}},
+			},
+		},
+		{
+			name: "OK",
+			commits: Commits{
+				Groups: []string{"revert:removed", "feat:feature", "perf:enhancement", "refactor:enhancement", "fix:bug", "security:security"},
+			},
+			expectedLabelGroups: []LabelGroup{
+				{Title: "Removed", Labels: []string{"revert"}},
+				{Title: "Breaking Changes", Labels: []string{"breaking"}},
+				{Title: "New Features", Labels: []string{"feat"}},
+				{Title: "Enhancements", Labels: []string{"perf", "refactor"}},
+				{Title: "Fixed Bugs", Labels: []string{"fix"}},
+				{Title: "Security Fixes", Labels: []string{"security"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			labelGroups := tc.commits.LabelGroups()
+
+			assert.Equal(t, tc.expectedLabelGroups, labelGroups)
+		})
+	}
+}
+
 func TestFormat_GetReleaseURL(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -123,17 +209,39 @@ func TestDefault(t *testing.T) {
 
 	assert.NotNil(t, spec)
 	assert.Equal(t, Platform(""), spec.Repo.Platform)
+	assert.Equal(t, ModeRemote, spec.Repo.Mode)
+	assert.Equal(t, APIREST, spec.Repo.GitHubAPI)
+	assert.Equal(t, "", spec.Repo.Domain)
 	assert.Equal(t, "", spec.Repo.Path)
+	assert.Equal(t, "", spec.Repo.APIURL)
+	assert.Equal(t, "", spec.Repo.WebURL)
 	assert.Equal(t, "access-token", spec.Repo.AccessToken)
+	assert.Equal(t, []Host{}, spec.Hosts)
+	assert.Equal(t, []Line{}, spec.Lines)
+	assert.Equal(t, []Component{}, spec.Components)
 	assert.Equal(t, "CHANGELOG.md", spec.General.File)
+	assert.Equal(t, Format(""), spec.General.Format)
 	assert.Equal(t, "", spec.General.Base)
 	assert.Equal(t, false, spec.General.Print)
+	assert.Equal(t, false, spec.General.Check)
+	assert.Equal(t, "", spec.General.ReleaseNotes)
+	assert.Equal(t, NotesFormatMarkdown, spec.General.ReleaseNotesFormat)
+	assert.Equal(t, false, spec.General.NextVersion)
 	assert.Equal(t, false, spec.General.Verbose)
+	assert.Equal(t, false, spec.General.NoCache)
+	assert.Equal(t, "", spec.General.CacheDir)
 	assert.Equal(t, "", spec.Tags.From)
 	assert.Equal(t, "", spec.Tags.To)
 	assert.Equal(t, "", spec.Tags.Future)
 	assert.Equal(t, []string{}, spec.Tags.Exclude)
 	assert.Equal(t, "", spec.Tags.ExcludeRegex)
+	assert.Equal(t, "", spec.Tags.IncludeRegex)
+	assert.Equal(t, "", spec.Tags.Prefix)
+	assert.Equal(t, false, spec.Tags.TrimPrefix)
+	assert.Equal(t, OrderingCommitTime, spec.Tags.Ordering)
+	assert.Equal(t, "", spec.Tags.RegenerateFrom)
+	assert.Equal(t, "", spec.Tags.RegenerateTo)
+	assert.Equal(t, false, spec.Tags.RegenerateAll)
 	assert.Equal(t, SelectionAll, spec.Issues.Selection)
 	assert.Nil(t, spec.Issues.IncludeLabels)
 	assert.Equal(t, []string{"duplicate", "invalid", "question", "wontfix"}, spec.Issues.ExcludeLabels)
@@ -148,6 +256,7 @@ func TestDefault(t *testing.T) {
 	assert.Equal(t, []string{"security"}, spec.Issues.SecurityLabels)
 	assert.Equal(t, SelectionAll, spec.Merges.Selection)
 	assert.Equal(t, "", spec.Merges.Branch)
+	assert.Nil(t, spec.Merges.Paths)
 	assert.Nil(t, spec.Merges.IncludeLabels)
 	assert.Nil(t, spec.Merges.ExcludeLabels)
 	assert.Equal(t, GroupingSimple, spec.Merges.Grouping)
@@ -159,7 +268,11 @@ func TestDefault(t *testing.T) {
 	assert.Equal(t, []string{}, spec.Merges.EnhancementLabels)
 	assert.Equal(t, []string{}, spec.Merges.BugLabels)
 	assert.Equal(t, []string{}, spec.Merges.SecurityLabels)
+	assert.Equal(t, SelectionNone, spec.Commits.Selection)
+	assert.Equal(t, []string{"feat:feature", "perf:enhancement", "refactor:enhancement", "fix:bug", "security:security"}, spec.Commits.Groups)
 	assert.Equal(t, "", spec.Content.ReleaseURL)
+	assert.Equal(t, "", spec.Content.HeaderTemplate)
+	assert.Equal(t, "", spec.Content.ReleaseTemplate)
 }
 
 func TestSpec_FromFile(t *testing.T) {
@@ -197,14 +310,23 @@ func TestSpec_FromFile(t *testing.T) {
 				Version: false,
 				Repo: Repo{
 					Platform:    Platform(""),
+					Mode:        ModeRemote,
+					GitHubAPI:   APIREST,
 					Path:        "",
 					AccessToken: "",
 				},
+				Hosts:      []Host{},
+				Lines:      []Line{},
+				Components: []Component{},
 				General: General{
-					File:    "CHANGELOG.md",
-					Base:    "",
-					Print:   true,
-					Verbose: false,
+					File:               "CHANGELOG.md",
+					Format:             Format(""),
+					Base:               "",
+					Print:              true,
+					ReleaseNotesFormat: NotesFormatMarkdown,
+					Verbose:            false,
+					NoCache:            false,
+					CacheDir:           "",
 				},
 				Tags: Tags{
 					From:         "",
@@ -212,6 +334,7 @@ func TestSpec_FromFile(t *testing.T) {
 					Future:       "",
 					Exclude:      []string{},
 					ExcludeRegex: "",
+					Ordering:     OrderingCommitTime,
 				},
 				Issues: Issues{
 					Selection:         SelectionLabeled,
@@ -242,8 +365,14 @@ func TestSpec_FromFile(t *testing.T) {
 					BugLabels:         []string{},
 					SecurityLabels:    []string{},
 				},
+				Commits: Commits{
+					Selection: SelectionNone,
+					Groups:    []string{"feat:feature", "perf:enhancement", "refactor:enhancement", "fix:bug", "security:security"},
+				},
 				Content: Content{
-					ReleaseURL: "",
+					ReleaseURL:      "",
+					HeaderTemplate:  "",
+					ReleaseTemplate: "",
 				},
 			},
 		},
@@ -256,14 +385,62 @@ func TestSpec_FromFile(t *testing.T) {
 				Version: false,
 				Repo: Repo{
 					Platform:    Platform(""),
+					Mode:        ModeRemote,
+					GitHubAPI:   APIREST,
 					Path:        "",
 					AccessToken: "",
 				},
+				Hosts: []Host{
+					{
+						Domain:   "github.example.com",
+						Platform: PlatformGitHub,
+						APIURL:   "https://github.example.com/api/v3",
+						WebURL:   "https://github.example.com",
+					},
+					{
+						Domain:   "gitlab.example.com",
+						Platform: PlatformGitLab,
+					},
+					{
+						Domain:   "git.example.com",
+						Platform: PlatformGitea,
+					},
+				},
+				Lines: []Line{
+					{
+						Branch:    "release/1.x",
+						TagsRegex: `^v1\.`,
+						FutureTag: "v1.4.4",
+						File:      "CHANGELOG-1.x.md",
+					},
+					{
+						Branch:    "main",
+						TagsRegex: `^v2\.`,
+						File:      "CHANGELOG.md",
+					},
+				},
+				Components: []Component{
+					{
+						TagPrefix:  "api/",
+						TrimPrefix: true,
+						Paths:      []string{"api/", "proto/"},
+						File:       "api/CHANGELOG.md",
+					},
+					{
+						TagPrefix: "web/",
+						Paths:     []string{"web/"},
+						File:      "web/CHANGELOG.md",
+					},
+				},
 				General: General{
-					File:    "RELEASE-NOTES.md",
-					Base:    "SUMMARY-NOTES.md",
-					Print:   true,
-					Verbose: true,
+					File:               "RELEASE-NOTES.md",
+					Format:             FormatMarkdown,
+					Base:               "SUMMARY-NOTES.md",
+					Print:              true,
+					ReleaseNotesFormat: NotesFormatText,
+					Verbose:            true,
+					NoCache:            true,
+					CacheDir:           "/tmp/changelog",
 				},
 				Tags: Tags{
 					From:         "",
@@ -271,6 +448,7 @@ func TestSpec_FromFile(t *testing.T) {
 					Future:       "",
 					Exclude:      []string{"prerelease", "candidate"},
 					ExcludeRegex: `(.*)-(alpha|beta)`,
+					Ordering:     OrderingSemVer,
 				},
 				Issues: Issues{
 					Selection:         SelectionLabeled,
@@ -301,8 +479,14 @@ func TestSpec_FromFile(t *testing.T) {
 					BugLabels:         []string{"bug", "defect"},
 					SecurityLabels:    []string{"security", "privacy"},
 				},
+				Commits: Commits{
+					Selection: SelectionAll,
+					Groups:    []string{"revert:removed", "deprecate:deprecated", "feat:feature", "perf:enhancement", "fix:bug", "deps:security"},
+				},
 				Content: Content{
-					ReleaseURL: "https://storage.artifactory.com/project/releases/{tag}",
+					ReleaseURL:      "https://storage.artifactory.com/project/releases/{tag}",
+					HeaderTemplate:  "header.tmpl",
+					ReleaseTemplate: "release.tmpl",
 				},
 			},
 		},
@@ -324,7 +508,94 @@ func TestSpec_FromFile(t *testing.T) {
 	}
 }
 
+func TestHost_GetAPIURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		h              Host
+		expectedAPIURL string
+	}{
+		{
+			name:           "Specified",
+			h:              Host{Domain: "github.example.com", Platform: PlatformGitHub, APIURL: "https://api.github.example.com/"},
+			expectedAPIURL: "https://api.github.example.com",
+		},
+		{
+			name:           "GitHub",
+			h:              Host{Domain: "github.example.com", Platform: PlatformGitHub},
+			expectedAPIURL: "https://github.example.com/api/v3",
+		},
+		{
+			name:           "GitLab",
+			h:              Host{Domain: "gitlab.example.com", Platform: PlatformGitLab},
+			expectedAPIURL: "https://gitlab.example.com/api/v4",
+		},
+		{
+			name:           "Gitea",
+			h:              Host{Domain: "gitea.example.com", Platform: PlatformGitea},
+			expectedAPIURL: "https://gitea.example.com/api/v1",
+		},
+		{
+			name:           "Bitbucket",
+			h:              Host{Domain: "bitbucket.org", Platform: PlatformBitbucket},
+			expectedAPIURL: "https://api.bitbucket.org/2.0",
+		},
+		{
+			name:           "BitbucketServer",
+			h:              Host{Domain: "bitbucket.example.com", Platform: PlatformBitbucketServer},
+			expectedAPIURL: "https://bitbucket.example.com/rest/api/1.0",
+		},
+		{
+			name:           "AzureDevOpsServer",
+			h:              Host{Domain: "tfs.example.com", Platform: PlatformAzureDevOps},
+			expectedAPIURL: "https://tfs.example.com",
+		},
+		{
+			name:           "Unknown",
+			h:              Host{Domain: "git.example.com"},
+			expectedAPIURL: "https://git.example.com",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedAPIURL, tc.h.GetAPIURL())
+		})
+	}
+}
+
+func TestHost_GetWebURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		h              Host
+		expectedWebURL string
+	}{
+		{
+			name:           "Specified",
+			h:              Host{Domain: "github.example.com", Platform: PlatformGitHub, WebURL: "http://github.example.com/"},
+			expectedWebURL: "http://github.example.com",
+		},
+		{
+			name:           "Default",
+			h:              Host{Domain: "gitlab.example.com", Platform: PlatformGitLab},
+			expectedWebURL: "https://gitlab.example.com",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedWebURL, tc.h.GetWebURL())
+		})
+	}
+}
+
 func TestSpec_WithRepo(t *testing.T) {
+	hosts := []Host{
+		{Domain: "github.example.com", Platform: PlatformGitHub},
+		{Domain: "gitlab.example.com", Platform: PlatformGitLab, APIURL: "https://gitlab.example.com:8443/api/v4", WebURL: "https://gitlab.example.com:8443"},
+		{Domain: "gitea.example.com", Platform: PlatformGitea},
+		{Domain: "bitbucket.example.com", Platform: PlatformBitbucketServer},
+	}
+
 	tests := []struct {
 		name         string
 		spec         Spec
@@ -333,17 +604,254 @@ func TestSpec_WithRepo(t *testing.T) {
 		expectedSpec Spec
 	}{
 		{
-			name:   "OK",
+			name:   "GitHub",
 			spec:   Spec{},
 			domain: "github.com",
 			path:   "octocat/Hello-World",
 			expectedSpec: Spec{
 				Repo: Repo{
-					Platform: Platform("github.com"),
+					Platform: PlatformGitHub,
+					Domain:   "github.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://api.github.com",
+					WebURL:   "https://github.com",
+				},
+			},
+		},
+		{
+			name:   "GitLab",
+			spec:   Spec{},
+			domain: "gitlab.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitLab,
+					Domain:   "gitlab.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://gitlab.com/api/v4",
+					WebURL:   "https://gitlab.com",
+				},
+			},
+		},
+		{
+			name:   "Gitea",
+			spec:   Spec{},
+			domain: "gitea.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitea,
+					Domain:   "gitea.com",
 					Path:     "octocat/Hello-World",
+					APIURL:   "https://gitea.com/api/v1",
+					WebURL:   "https://gitea.com",
+				},
+			},
+		},
+		{
+			name:   "Codeberg",
+			spec:   Spec{},
+			domain: "codeberg.org",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitea,
+					Domain:   "codeberg.org",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://codeberg.org/api/v1",
+					WebURL:   "https://codeberg.org",
+				},
+			},
+		},
+		{
+			name:   "Bitbucket",
+			spec:   Spec{},
+			domain: "bitbucket.org",
+			path:   "octocat/hello-world",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformBitbucket,
+					Domain:   "bitbucket.org",
+					Path:     "octocat/hello-world",
+					APIURL:   "https://api.bitbucket.org/2.0",
+					WebURL:   "https://bitbucket.org",
+				},
+			},
+		},
+		{
+			name:   "AzureDevOps",
+			spec:   Spec{},
+			domain: "dev.azure.com",
+			path:   "octocat/hello-world/_git/hello-world",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformAzureDevOps,
+					Domain:   "dev.azure.com",
+					Path:     "octocat/hello-world/_git/hello-world",
+					APIURL:   "https://dev.azure.com",
+					WebURL:   "https://dev.azure.com",
 				},
 			},
 		},
+		{
+			name:   "GitHubEnterprise",
+			spec:   Spec{Hosts: hosts},
+			domain: "github.example.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitHub,
+					Domain:   "github.example.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://github.example.com/api/v3",
+					WebURL:   "https://github.example.com",
+				},
+				Hosts: hosts,
+			},
+		},
+		{
+			name:   "SelfManagedGitLab",
+			spec:   Spec{Hosts: hosts},
+			domain: "gitlab.example.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitLab,
+					Domain:   "gitlab.example.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://gitlab.example.com:8443/api/v4",
+					WebURL:   "https://gitlab.example.com:8443",
+				},
+				Hosts: hosts,
+			},
+		},
+		{
+			name:   "SelfHostedGitea",
+			spec:   Spec{Hosts: hosts},
+			domain: "gitea.example.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitea,
+					Domain:   "gitea.example.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://gitea.example.com/api/v1",
+					WebURL:   "https://gitea.example.com",
+				},
+				Hosts: hosts,
+			},
+		},
+		{
+			name:   "BitbucketServer",
+			spec:   Spec{Hosts: hosts},
+			domain: "bitbucket.example.com",
+			path:   "scm/octo/hello-world",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformBitbucketServer,
+					Domain:   "bitbucket.example.com",
+					Path:     "scm/octo/hello-world",
+					APIURL:   "https://bitbucket.example.com/rest/api/1.0",
+					WebURL:   "https://bitbucket.example.com",
+				},
+				Hosts: hosts,
+			},
+		},
+		{
+			name: "ExplicitPlatform",
+			spec: Spec{
+				Repo: Repo{
+					Platform: PlatformBitbucketServer,
+				},
+			},
+			domain: "git.example.com",
+			path:   "octo/hello-world",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformBitbucketServer,
+					Domain:   "git.example.com",
+					Path:     "octo/hello-world",
+					APIURL:   "https://git.example.com/rest/api/1.0",
+					WebURL:   "https://git.example.com",
+				},
+			},
+		},
+		{
+			name: "ExplicitPlatformOverridesHost",
+			spec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitea,
+				},
+				Hosts: hosts,
+			},
+			domain: "gitlab.example.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitea,
+					Domain:   "gitlab.example.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://gitlab.example.com/api/v1",
+					WebURL:   "https://gitlab.example.com",
+				},
+				Hosts: hosts,
+			},
+		},
+		{
+			name: "ShortPlatformName",
+			spec: Spec{
+				Repo: Repo{
+					Platform: Platform("gitlab"),
+				},
+			},
+			domain: "git.example.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitLab,
+					Domain:   "git.example.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://git.example.com/api/v4",
+					WebURL:   "https://git.example.com",
+				},
+			},
+		},
+		{
+			name: "ShortPlatformNameForHost",
+			spec: Spec{
+				Hosts: []Host{
+					{Domain: "github.example.com", Platform: Platform("github")},
+				},
+			},
+			domain: "github.example.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: PlatformGitHub,
+					Domain:   "github.example.com",
+					Path:     "octocat/Hello-World",
+					APIURL:   "https://github.example.com/api/v3",
+					WebURL:   "https://github.example.com",
+				},
+				Hosts: []Host{
+					{Domain: "github.example.com", Platform: Platform("github")},
+				},
+			},
+		},
+		{
+			name:   "UnknownDomain",
+			spec:   Spec{Hosts: hosts},
+			domain: "git.example.com",
+			path:   "octocat/Hello-World",
+			expectedSpec: Spec{
+				Repo: Repo{
+					Platform: Platform(""),
+					Domain:   "git.example.com",
+					Path:     "octocat/Hello-World",
+				},
+				Hosts: hosts,
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -355,6 +863,324 @@ func TestSpec_WithRepo(t *testing.T) {
 	}
 }
 
+func TestSpec_LineSpecs(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          Spec
+		expectedError string
+		expectedSpecs []Spec
+	}{
+		{
+			name: "NoLine",
+			spec: Spec{
+				General: General{File: "CHANGELOG.md"},
+			},
+			expectedSpecs: []Spec{
+				{
+					General: General{File: "CHANGELOG.md"},
+				},
+			},
+		},
+		{
+			name: "NoFile",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "release/1.x", TagsRegex: `^v1\.`},
+				},
+			},
+			expectedError: "release line 1: file is required",
+		},
+		{
+			name: "DuplicateFile",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "release/1.x", TagsRegex: `^v1\.`, File: "CHANGELOG.md"},
+					{Branch: "main", TagsRegex: `^v2\.`, File: "CHANGELOG.md"},
+				},
+			},
+			expectedError: "release line 2: duplicate file CHANGELOG.md",
+		},
+		{
+			name: "FutureTagForAllLines",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "release/1.x", TagsRegex: `^v1\.`, File: "CHANGELOG-1.x.md"},
+					{Branch: "main", TagsRegex: `^v2\.`, File: "CHANGELOG.md"},
+				},
+				Tags: Tags{Future: "v2.1.0"},
+			},
+			expectedError: "future tag v2.1.0 cannot be used for all release lines: use {next} or future-tag for every line",
+		},
+		{
+			name: "FutureTags",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "release/1.x", TagsRegex: `^v1\.`, FutureTag: "v1.4.4", File: "CHANGELOG-1.x.md"},
+					{Branch: "main", TagsRegex: `^v2\.`, File: "CHANGELOG.md"},
+				},
+				General: General{File: "CHANGELOG.md"},
+				Tags:    Tags{Future: FutureTagNext},
+			},
+			expectedSpecs: []Spec{
+				{
+					General: General{File: "CHANGELOG-1.x.md"},
+					Tags:    Tags{Future: "v1.4.4", IncludeRegex: `^v1\.`},
+					Merges:  Merges{Branch: "release/1.x"},
+				},
+				{
+					General: General{File: "CHANGELOG.md"},
+					Tags:    Tags{Future: FutureTagNext, IncludeRegex: `^v2\.`},
+					Merges:  Merges{Branch: "main"},
+				},
+			},
+		},
+		{
+			name: "OK",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "release/1.x", TagsRegex: `^v1\.`, File: "CHANGELOG-1.x.md"},
+					{Branch: "main", TagsRegex: `^v2\.`, File: "CHANGELOG.md"},
+				},
+				General: General{File: "CHANGELOG.md"},
+				Tags:    Tags{Exclude: []string{"nightly"}},
+			},
+			expectedSpecs: []Spec{
+				{
+					General: General{File: "CHANGELOG-1.x.md"},
+					Tags:    Tags{Exclude: []string{"nightly"}, IncludeRegex: `^v1\.`},
+					Merges:  Merges{Branch: "release/1.x"},
+				},
+				{
+					General: General{File: "CHANGELOG.md"},
+					Tags:    Tags{Exclude: []string{"nightly"}, IncludeRegex: `^v2\.`},
+					Merges:  Merges{Branch: "main"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			specs, err := tc.spec.LineSpecs()
+
+			if tc.expectedError != "" {
+				assert.Nil(t, specs)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedSpecs, specs)
+			}
+		})
+	}
+}
+
+func TestSpec_ComponentSpecs(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          Spec
+		expectedError string
+		expectedSpecs []Spec
+	}{
+		{
+			name: "NoComponent",
+			spec: Spec{
+				General: General{File: "CHANGELOG.md"},
+			},
+			expectedSpecs: []Spec{
+				{
+					General: General{File: "CHANGELOG.md"},
+				},
+			},
+		},
+		{
+			name: "WithLines",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "main", TagsRegex: `^v2\.`, File: "CHANGELOG.md"},
+				},
+				Components: []Component{
+					{TagPrefix: "api/", File: "api/CHANGELOG.md"},
+				},
+			},
+			expectedError: "components and release lines cannot be used together",
+		},
+		{
+			name: "NoTagPrefix",
+			spec: Spec{
+				Components: []Component{
+					{Paths: []string{"api/"}, File: "api/CHANGELOG.md"},
+				},
+			},
+			expectedError: "component 1: tag prefix is required",
+		},
+		{
+			name: "NoFile",
+			spec: Spec{
+				Components: []Component{
+					{TagPrefix: "api/", Paths: []string{"api/"}},
+				},
+			},
+			expectedError: "component 1: file is required",
+		},
+		{
+			name: "DuplicateFile",
+			spec: Spec{
+				Components: []Component{
+					{TagPrefix: "api/", Paths: []string{"api/"}, File: "CHANGELOG.md"},
+					{TagPrefix: "web/", Paths: []string{"web/"}, File: "CHANGELOG.md"},
+				},
+			},
+			expectedError: "component 2: duplicate file CHANGELOG.md",
+		},
+		{
+			name: "OK",
+			spec: Spec{
+				Components: []Component{
+					{TagPrefix: "api/", TrimPrefix: true, Paths: []string{"api/", "proto/"}, File: "api/CHANGELOG.md"},
+					{TagPrefix: "web/", Paths: []string{"web/"}, File: "web/CHANGELOG.md"},
+				},
+				General: General{File: "CHANGELOG.md"},
+				Tags:    Tags{Exclude: []string{"nightly"}},
+			},
+			expectedSpecs: []Spec{
+				{
+					General: General{File: "api/CHANGELOG.md"},
+					Tags:    Tags{Exclude: []string{"nightly"}, Prefix: "api/", TrimPrefix: true},
+					Merges:  Merges{Paths: []string{"api/", "proto/"}},
+				},
+				{
+					General: General{File: "web/CHANGELOG.md"},
+					Tags:    Tags{Exclude: []string{"nightly"}, Prefix: "web/"},
+					Merges:  Merges{Paths: []string{"web/"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			specs, err := tc.spec.ComponentSpecs()
+
+			if tc.expectedError != "" {
+				assert.Nil(t, specs)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedSpecs, specs)
+			}
+		})
+	}
+}
+
+func TestSpec_ChangelogSpecs(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          Spec
+		expectedError string
+		expectedFiles []string
+	}{
+		{
+			name: "Default",
+			spec: Spec{
+				General: General{File: "CHANGELOG.md"},
+			},
+			expectedFiles: []string{"CHANGELOG.md"},
+		},
+		{
+			name: "Components",
+			spec: Spec{
+				Components: []Component{
+					{TagPrefix: "api/", Paths: []string{"api/"}, File: "api/CHANGELOG.md"},
+					{TagPrefix: "web/", Paths: []string{"web/"}, File: "web/CHANGELOG.md"},
+				},
+			},
+			expectedFiles: []string{"api/CHANGELOG.md", "web/CHANGELOG.md"},
+		},
+		{
+			name: "Lines",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "release/1.x", TagsRegex: `^v1\.`, File: "CHANGELOG-1.x.md"},
+					{Branch: "main", TagsRegex: `^v2\.`, File: "CHANGELOG.md"},
+				},
+			},
+			expectedFiles: []string{"CHANGELOG-1.x.md", "CHANGELOG.md"},
+		},
+		{
+			name: "ComponentsAndLines",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "main", TagsRegex: `^v2\.`, File: "CHANGELOG.md"},
+				},
+				Components: []Component{
+					{TagPrefix: "api/", File: "api/CHANGELOG.md"},
+				},
+			},
+			expectedError: "components and release lines cannot be used together",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			specs, err := tc.spec.ChangelogSpecs()
+
+			if tc.expectedError != "" {
+				assert.Nil(t, specs)
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+
+				var files []string
+				for _, s := range specs {
+					files = append(files, s.General.File)
+				}
+				assert.Equal(t, tc.expectedFiles, files)
+			}
+		})
+	}
+}
+
+func TestSpec_ChangelogNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          Spec
+		expectedNames []string
+	}{
+		{
+			name: "Default",
+			spec: Spec{
+				General: General{File: "CHANGELOG.md"},
+			},
+			expectedNames: []string{""},
+		},
+		{
+			name: "Components",
+			spec: Spec{
+				Components: []Component{
+					{TagPrefix: "api/", Paths: []string{"api/"}, File: "api/CHANGELOG.md"},
+					{TagPrefix: "web-", Paths: []string{"web/"}, File: "web/CHANGELOG.md"},
+				},
+			},
+			expectedNames: []string{"api", "web-"},
+		},
+		{
+			name: "Lines",
+			spec: Spec{
+				Lines: []Line{
+					{Branch: "release/1.x", TagsRegex: `^v1\.`, File: "CHANGELOG-1.x.md"},
+					{TagsRegex: `^v2\.`, File: "CHANGELOG.md"},
+				},
+			},
+			expectedNames: []string{"release/1.x", "CHANGELOG.md"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedNames, tc.spec.ChangelogNames())
+		})
+	}
+}
 func TestSpec_PrintHelp(t *testing.T) {
 	s := new(Spec)
 	err := s.PrintHelp()